@@ -18,6 +18,7 @@ import (
 
 	root "github.com/matterpoll/matterpoll"
 	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/store"
 )
 
 const (
@@ -25,6 +26,37 @@ const (
 
 	addOptionKey = "answerOption"
 	questionKey  = "question"
+	// rankKeyPrefix is the prefix of the dialog elements used to rank the answer options, e.g. "rank1".
+	rankKeyPrefix = "rank"
+	// pointsKeyPrefix is the prefix of the dialog elements used to spread points, e.g. "points0" for the first answer option.
+	pointsKeyPrefix = "points"
+	// availabilityKeyPrefix is the prefix of the dialog elements used to answer a scheduling poll, e.g. "availability0" for the first answer option.
+	availabilityKeyPrefix = "availability"
+	// responseKey is the name of the dialog element used to answer a free-text poll.
+	responseKey = "response"
+	// surveyTitleKey and surveyQuestionsKey are the names of the dialog elements used to create a survey.
+	surveyTitleKey     = "title"
+	surveyQuestionsKey = "questions"
+	// manageOptionKeyPrefix and managePositionKeyPrefix are the prefixes of the dialog elements used to manage the answer options,
+	// e.g. "option0" and "position0" for the first answer option.
+	manageOptionKeyPrefix   = "option"
+	managePositionKeyPrefix = "position"
+	// maxVotesKey is the name of the dialog element used to set the number of votes, matching the one of the create poll dialog.
+	maxVotesKey = "setting-multi"
+	// rerunChannelKey is the name of the dialog element used to choose the channel a poll is run again in.
+	rerunChannelKey = "channel"
+	// templateKey is the name of the dialog element used to create a poll from a template.
+	templateKey = "template"
+	// surveyAnswerKeyPrefix is the prefix of the dialog elements used to answer a survey, e.g. "answer0" for the first question.
+	surveyAnswerKeyPrefix = "answer"
+	// delegateKey is the name of the dialog element used to choose the delegate of a vote.
+	delegateKey = "delegate"
+	// proxyVoterKey and proxyAnswerKey are the names of the dialog elements used to vote on behalf of someone else.
+	proxyVoterKey  = "voter"
+	proxyAnswerKey = "answer"
+
+	// channelMembersPerPage is the page size used to fetch the members of a channel.
+	channelMembersPerPage = 200
 
 	infoMessage = "Thanks for using Matterpoll v"
 )
@@ -43,6 +75,124 @@ var (
 		ID:    "response.vote.updated",
 		Other: "Your vote has been updated.",
 	}
+	responseVoteNotEligible = &i18n.Message{
+		ID:    "response.vote.notEligible",
+		Other: "You are not allowed to vote in this poll.",
+	}
+	responseVoteWaitlisted = &i18n.Message{
+		ID:    "response.vote.waitlisted",
+		Other: "**{{.Option}}** is full. You are number {{.Position}} on its waitlist and get a spot automatically once someone leaves.",
+	}
+	responseWaitlistPromoted = &i18n.Message{
+		ID:    "response.waitlist.promoted",
+		Other: "A spot opened up in the poll [{{.Question}}]({{.Link}}). You have been moved from the waitlist to **{{.Option}}**.",
+	}
+	responseProxyVoteNotAllowed = &i18n.Message{
+		ID:    "response.proxyVote.notAllowed",
+		Other: "This poll doesn't allow proxy votes.",
+	}
+	responseProxyVoteInvalidPermission = &i18n.Message{
+		ID:    "response.proxyVote.invalidPermission",
+		Other: "Only the creator of a poll, System Admins and users, to whom someone delegated their vote, are allowed to vote by proxy.",
+	}
+	responseProxyVoteSelf = &i18n.Message{
+		ID:    "response.proxyVote.self",
+		Other: "Use the buttons of the poll to vote for yourself.",
+	}
+	responseProxyVoteVoterNoPermission = &i18n.Message{
+		ID:    "response.proxyVote.voterNoPermission",
+		Other: "This user can't read the channel of the poll.",
+	}
+	responseProxyVoteVoterNotEligible = &i18n.Message{
+		ID:    "response.proxyVote.voterNotEligible",
+		Other: "This user is not allowed to vote in this poll.",
+	}
+	responseProxyVoteVoterVoted = &i18n.Message{
+		ID:    "response.proxyVote.voterVoted",
+		Other: "This user has already voted by themselves. Only their proxy votes can be changed.",
+	}
+	responseProxyVoteCounted = &i18n.Message{
+		ID:    "response.proxyVote.counted",
+		Other: "The proxy vote has been counted.",
+	}
+	responseProxyVoteNotification = &i18n.Message{
+		ID:    "response.proxyVote.notification",
+		Other: "**{{.Proxy}}** voted for **{{.Option}}** on your behalf in the poll [{{.Question}}]({{.Link}}).",
+	}
+	responseDelegateVoteSuccess = &i18n.Message{
+		ID:    "response.delegateVote.success",
+		Other: "Your vote has been delegated. Your delegate can now vote on your behalf.",
+	}
+	responseDelegateVoteRevoked = &i18n.Message{
+		ID:    "response.delegateVote.revoked",
+		Other: "Your vote isn't delegated anymore.",
+	}
+	responseDelegateVoteNoPermission = &i18n.Message{
+		ID:    "response.delegateVote.noPermission",
+		Other: "This user can't read the channel of the poll.",
+	}
+	responseDelegateVoteNotification = &i18n.Message{
+		ID:    "response.delegateVote.notification",
+		Other: "**{{.User}}** delegated their vote in the poll [{{.Question}}]({{.Link}}) to you. Use **Vote by Proxy** to vote on their behalf.",
+	}
+	responseExcludedGuest = &i18n.Message{
+		ID:    "response.excluded.guest",
+		Other: "Guests are not allowed to take part in this poll.",
+	}
+	responseExcludedBot = &i18n.Message{
+		ID:    "response.excluded.bot",
+		Other: "Bots are not allowed to take part in this poll.",
+	}
+	responseRankingCounted = &i18n.Message{
+		ID:    "response.ranking.counted",
+		Other: "Your ranking has been counted.",
+	}
+	responseRankingUpdated = &i18n.Message{
+		ID:    "response.ranking.updated",
+		Other: "Your ranking has been updated.",
+	}
+	responseVotePoints = &i18n.Message{
+		ID:    "response.vote.points",
+		One:   "Your point has been counted. You have {{.Remains}} point left.",
+		Few:   "Your point has been counted. You have {{.Remains}} points left.",
+		Many:  "Your point has been counted. You have {{.Remains}} points left.",
+		Other: "Your point has been counted. You have {{.Remains}} points left.",
+	}
+	responseEditPointsSuccess = &i18n.Message{
+		ID:    "response.editPoints.success",
+		Other: "Your points have been updated.",
+	}
+	responseAvailabilitySaved = &i18n.Message{
+		ID:    "response.availability.saved",
+		Other: "Your availability has been saved.",
+	}
+	responseFreeTextSaved = &i18n.Message{
+		ID:    "response.freeText.saved",
+		Other: "Your answer has been saved.",
+	}
+	responseFreeTextUpdated = &i18n.Message{
+		ID:    "response.freeText.updated",
+		Other: "Your answer has been updated.",
+	}
+	responseSurveySaved = &i18n.Message{
+		ID:    "response.survey.saved",
+		Other: "Your answers have been saved.",
+	}
+	responseSurveyUpdated = &i18n.Message{
+		ID:    "response.survey.updated",
+		Other: "Your answers have been updated.",
+	}
+	responseQuizCorrect = &i18n.Message{
+		ID:    "response.quiz.correct",
+		Other: "Correct! Your answer has been counted.",
+	}
+	responseQuizWrong = &i18n.Message{
+		ID:    "response.quiz.wrong",
+		One:   "Sorry, that's wrong. The correct answer is {{.Answers}}.",
+		Few:   "Sorry, that's wrong. The correct answers are {{.Answers}}.",
+		Many:  "Sorry, that's wrong. The correct answers are {{.Answers}}.",
+		Other: "Sorry, that's wrong. The correct answers are {{.Answers}}.",
+	}
 	responseAddOptionSuccess = &i18n.Message{
 		ID:    "response.addOption.success",
 		Other: "Successfully added the option.",
@@ -51,15 +201,99 @@ var (
 		ID:    "response.addOption.invalidPermission",
 		Other: "Only the creator of a poll and System Admins are allowed to add options.",
 	}
+	responseAddOptionNotEligible = &i18n.Message{
+		ID:    "response.addOption.notEligible",
+		Other: "You are not allowed to add options to this poll, as you are not allowed to vote in it.",
+	}
+	responseManageOptionsSuccess = &i18n.Message{
+		ID:    "response.manageOptions.success",
+		Other: "Successfully updated the options.",
+	}
+	responseManageOptionsInvalidPermission = &i18n.Message{
+		ID:    "response.manageOptions.invalidPermission",
+		Other: "Only the creator of a poll and System Admins are allowed to manage its options.",
+	}
+
+	responseEditPollSuccess = &i18n.Message{
+		ID:    "response.editPoll.success",
+		Other: "Successfully edited the poll.",
+	}
+	responseEditPollInvalidPermission = &i18n.Message{
+		ID:    "response.editPoll.invalidPermission",
+		Other: "Only the creator of a poll and System Admins are allowed to edit it.",
+	}
+
+	responseRerunPollSuccess = &i18n.Message{
+		ID:    "response.rerunPoll.success",
+		Other: "Successfully ran the poll again.",
+	}
+	responseRerunPollInvalidPermission = &i18n.Message{
+		ID:    "response.rerunPoll.invalidPermission",
+		Other: "Only the creator of a poll and System Admins are allowed to run it again.",
+	}
+	responseRerunPollNoPostPermission = &i18n.Message{
+		ID:    "response.rerunPoll.noPostPermission",
+		Other: "You are not allowed to post in this channel.",
+	}
+
+	responseViewHistoryInvalidPermission = &i18n.Message{
+		ID:    "response.viewHistory.invalidPermission",
+		Other: "Only the creator of a poll and System Admins are allowed to view its vote history.",
+	}
+	responseViewHistoryAnonymous = &i18n.Message{
+		ID:    "response.viewHistory.anonymous",
+		Other: "Anonymous polls have no vote history.",
+	}
 
 	responseEndPollSuccessfully = &i18n.Message{
 		ID:    "response.endPoll.successfully",
 		Other: "The poll **{{.Question}}** has ended and the original post has been updated. You can jump to it by pressing [here]({{.Link}}).",
 	}
+	responseEndPollClosedAutomatically = &i18n.Message{
+		ID:    "response.endPoll.closedAutomatically",
+		Other: "Your vote has been counted. Enough users have voted, so the poll has ended.",
+	}
 	responseEndPollInvalidPermission = &i18n.Message{
 		ID:    "response.endPoll.invalidPermission",
 		Other: "Only the creator of a poll and System Admins are allowed to end it.",
 	}
+	responseEndPollWinner = &i18n.Message{
+		ID:    "response.endPoll.winner",
+		One:   "The winner is {{.Answers}}.",
+		Few:   "It's a tie between {{.Answers}}.",
+		Many:  "It's a tie between {{.Answers}}.",
+		Other: "It's a tie between {{.Answers}}.",
+	}
+	responseEndPollTieBreak = &i18n.Message{
+		ID:    "response.endPoll.tieBreak",
+		Other: "The creator of the poll picks the winner via the buttons below.",
+	}
+	responseEndPollTieBroken = &i18n.Message{
+		ID:    "response.endPoll.tieBroken",
+		Other: "The creator of the poll broke the tie.",
+	}
+	responseEndPollRunoff = &i18n.Message{
+		ID:    "response.endPoll.runoff",
+		Other: "A runoff poll between the tied options has been started. You can jump to it by pressing [here]({{.Link}}).",
+	}
+
+	responseBreakTieSuccess = &i18n.Message{
+		ID:    "response.breakTie.success",
+		Other: "**{{.Answer}}** is the winner of the poll.",
+	}
+	responseBreakTieInvalidPermission = &i18n.Message{
+		ID:    "response.breakTie.invalidPermission",
+		Other: "Only the creator of a poll and System Admins are allowed to break a tie.",
+	}
+
+	responseExportPollInvalidPermission = &i18n.Message{
+		ID:    "response.exportPoll.invalidPermission",
+		Other: "Only the creator of a poll and System Admins are allowed to export its results.",
+	}
+	responseExportPollNotSupported = &i18n.Message{
+		ID:    "response.exportPoll.notSupported",
+		Other: "The results of surveys can't be exported.",
+	}
 
 	responseDeletePollSuccess = &i18n.Message{
 		ID:    "response.deletePoll.success",
@@ -82,16 +316,41 @@ func (p *MatterpollPlugin) InitAPI() *mux.Router {
 	apiV1.HandleFunc("/configuration", p.handlePluginConfiguration).Methods(http.MethodGet)
 
 	apiV1.HandleFunc("/polls/create", p.handleSubmitDialogRequest(p.handleCreatePoll)).Methods(http.MethodPost)
+	apiV1.HandleFunc("/polls/survey/create", p.handleSubmitDialogRequest(p.handleCreateSurvey)).Methods(http.MethodPost)
+	apiV1.HandleFunc("/polls/export", p.handleExportPolls).Methods(http.MethodGet)
+	apiV1.HandleFunc("/polls/import", p.handleImportPolls).Methods(http.MethodPost)
 	pollRouter := apiV1.PathPrefix("/polls/{id:[a-z0-9]+}").Subrouter()
 	pollRouter.HandleFunc("/vote/{optionNumber:[0-9]+}", p.handlePostActionIntegrationRequest(p.handleVote)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/rank", p.handleSubmitDialogRequest(p.handleRankConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/points/request", p.handlePostActionIntegrationRequest(p.handleEditPoints)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/points", p.handleSubmitDialogRequest(p.handleEditPointsConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/availability", p.handleSubmitDialogRequest(p.handleAvailabilityConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/response/request", p.handlePostActionIntegrationRequest(p.handleResponse)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/response", p.handleSubmitDialogRequest(p.handleResponseConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/survey/request", p.handlePostActionIntegrationRequest(p.handleAnswerSurvey)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/survey", p.handleSubmitDialogRequest(p.handleAnswerSurveyConfirm)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/votes/reset", p.handlePostActionIntegrationRequest(p.handleResetVotes)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/delegate/request", p.handlePostActionIntegrationRequest(p.handleDelegateVote)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/delegate", p.handleSubmitDialogRequest(p.handleDelegateVoteConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/proxy/request", p.handlePostActionIntegrationRequest(p.handleProxyVote)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/proxy", p.handleSubmitDialogRequest(p.handleProxyVoteConfirm)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/option/add/request", p.handlePostActionIntegrationRequest(p.handleAddOption)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/option/add", p.handleSubmitDialogRequest(p.handleAddOptionConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/option/manage/request", p.handlePostActionIntegrationRequest(p.handleManageOptions)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/option/manage", p.handleSubmitDialogRequest(p.handleManageOptionsConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/edit/request", p.handlePostActionIntegrationRequest(p.handleEditPoll)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/edit", p.handleSubmitDialogRequest(p.handleEditPollConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/history/request", p.handlePostActionIntegrationRequest(p.handleViewHistory)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/rerun/request", p.handlePostActionIntegrationRequest(p.handleRerunPoll)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/rerun", p.handleSubmitDialogRequest(p.handleRerunPollConfirm)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/end", p.handlePostActionIntegrationRequest(p.handleEndPoll)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/end/confirm", p.handleSubmitDialogRequest(p.handleEndPollConfirm)).Methods(http.MethodPost)
+	pollRouter.HandleFunc("/tiebreak/{optionNumber:[0-9]+}", p.handlePostActionIntegrationRequest(p.handleBreakTie)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/delete", p.handlePostActionIntegrationRequest(p.handleDeletePoll)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/delete/confirm", p.handleSubmitDialogRequest(p.handleDeletePollConfirm)).Methods(http.MethodPost)
 	pollRouter.HandleFunc("/metadata", p.handlePollMetadata).Methods(http.MethodGet)
+	pollRouter.HandleFunc("/history", p.handleVoteHistory).Methods(http.MethodGet)
+	pollRouter.HandleFunc("/export.csv", p.handleExportPoll).Methods(http.MethodGet)
 	return r
 }
 
@@ -155,7 +414,7 @@ func (p *MatterpollPlugin) handlePostActionIntegrationRequest(handler postAction
 
 		vars := mux.Vars(r)
 		pollID := vars["id"]
-		poll, err := p.Store.Poll().Get(pollID)
+		poll, err := p.getActiveOrEndedPoll(pollID)
 		if err != nil {
 			http.Error(w, "failed to get poll", http.StatusInternalServerError)
 			return
@@ -189,9 +448,21 @@ func (p *MatterpollPlugin) handlePostActionIntegrationRequest(handler postAction
 
 		userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
 
-		lc, update, err := handler(mux.Vars(r), request)
+		excludedMsg, err := p.getExclusionMessage(poll, request.UserId)
 		if err != nil {
-			p.API.LogWarn("failed to handle PostActionIntegrationRequest", "error", err.Error())
+			http.Error(w, "failed to get user", http.StatusInternalServerError)
+			return
+		}
+
+		var lc *i18n.LocalizeConfig
+		var update *model.Post
+		if excludedMsg != nil {
+			lc = &i18n.LocalizeConfig{DefaultMessage: excludedMsg}
+		} else {
+			lc, update, err = handler(mux.Vars(r), request)
+			if err != nil {
+				p.API.LogWarn("failed to handle PostActionIntegrationRequest", "error", err.Error())
+			}
 		}
 
 		if lc != nil {
@@ -228,17 +499,19 @@ func (p *MatterpollPlugin) handleSubmitDialogRequest(handler submitDialogHandler
 		}
 
 		var rootID string
+		var requestedPoll *poll.Poll
 
 		vars := mux.Vars(r)
 		pollID := vars["id"]
 		if pollID != "" {
-			poll, err := p.Store.Poll().Get(pollID)
+			var err error
+			requestedPoll, err = p.getActiveOrEndedPoll(pollID)
 			if err != nil {
 				http.Error(w, "failed to get poll", http.StatusInternalServerError)
 				return
 			}
 
-			postID := poll.PostID
+			postID := requestedPoll.PostID
 			if postID != "" {
 				post, appEerr := p.API.GetPost(postID)
 				if appEerr != nil {
@@ -264,9 +537,21 @@ func (p *MatterpollPlugin) handleSubmitDialogRequest(handler submitDialogHandler
 			return
 		}
 
-		msg, response, err := handler(vars, request)
-		if err != nil {
-			p.API.LogWarn("failed to handle SubmitDialogRequest", "error", err.Error())
+		var msg *i18n.Message
+		var response *model.SubmitDialogResponse
+		var err error
+		if requestedPoll != nil {
+			msg, err = p.getExclusionMessage(requestedPoll, request.UserId)
+			if err != nil {
+				http.Error(w, "failed to get user", http.StatusInternalServerError)
+				return
+			}
+		}
+		if msg == nil {
+			msg, response, err = handler(vars, request)
+			if err != nil {
+				p.API.LogWarn("failed to handle SubmitDialogRequest", "error", err.Error())
+			}
 		}
 
 		if msg != nil {
@@ -288,6 +573,27 @@ func (p *MatterpollPlugin) handleSubmitDialogRequest(handler submitDialogHandler
 func (p *MatterpollPlugin) handleCreatePoll(_ map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
 	creatorID := request.UserId
 
+	// The template element is only part of the dialog if the user has templates.
+	if _, hasTemplates := request.Submission[templateKey]; hasTemplates {
+		if templateID, _ := request.Submission[templateKey].(string); templateID != "" {
+			return p.createPollFromTemplate(templateID, request)
+		}
+		// Without a template, the question and the first two options are required.
+		userLocalizer := p.bundle.GetUserLocalizer(creatorID)
+		fieldErrors := map[string]string{}
+		for _, key := range []string{questionKey, "option1", "option2"} {
+			if value, _ := request.Submission[key].(string); value == "" {
+				fieldErrors[key] = p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.createPoll.required",
+					Other: "This field is required, unless you choose a template.",
+				})
+			}
+		}
+		if len(fieldErrors) > 0 {
+			return nil, &model.SubmitDialogResponse{Errors: fieldErrors}, nil
+		}
+	}
+
 	question, ok := request.Submission[questionKey].(string)
 	if !ok {
 		return commandErrorGeneric, nil, errors.Errorf("failed to get question key. Value is: %v", request.Submission[questionKey])
@@ -313,7 +619,16 @@ func (p *MatterpollPlugin) handleCreatePoll(_ map[string]string, request *model.
 
 	userLocalizer := p.bundle.GetUserLocalizer(creatorID)
 
-	settings := poll.NewSettingsFromSubmission(request.Submission)
+	settings, errMsg := poll.NewSettingsFromSubmission(request.Submission, p.pf.Millis(), p.getUserLocation(creatorID))
+	if errMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				"setting-" + poll.SettingKeyEnd: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			},
+		}
+		return nil, response, nil
+	}
+
 	poll, errMsg := p.pf.NewPoll(creatorID, question, answerOptions, settings)
 	if errMsg != nil {
 		response := &model.SubmitDialogResponse{
@@ -322,36 +637,74 @@ func (p *MatterpollPlugin) handleCreatePoll(_ map[string]string, request *model.
 		return nil, response, nil
 	}
 
-	displayName, appErr := p.ConvertCreatorIDToDisplayName(creatorID)
-	if appErr != nil {
-		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	if _, err := p.createPollPost(poll, request.ChannelId, request.CallbackId); err != nil {
+		return commandErrorGeneric, nil, err
 	}
 
-	actions := poll.ToPostActions(p.bundle, root.Manifest.Id, displayName)
-	post := &model.Post{
-		UserId:    p.botUserID,
-		ChannelId: request.ChannelId,
-		RootId:    request.CallbackId,
-		Type:      MatterpollPostType,
-		Props: map[string]interface{}{
-			"poll_id": poll.ID,
-		},
+	return nil, nil, nil
+}
+
+// createPollFromTemplate creates a poll from the template with a given id, ignoring the other elements of the create poll dialog.
+func (p *MatterpollPlugin) createPollFromTemplate(templateID string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	template, err := p.Store.Template().Get(templateID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get template")
+	}
+	if !template.CanUse(request.UserId, request.TeamId) {
+		return commandErrorGeneric, nil, errors.Errorf("user %s is not allowed to use template %s", request.UserId, templateID)
 	}
 
-	model.ParseMessageAttachment(post, actions)
-	if poll.Settings.Progress {
-		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	newPoll, errMsg := p.pf.Rerun(request.UserId, template.Poll)
+	if errMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Error: p.bundle.LocalizeErrorMessage(p.bundle.GetUserLocalizer(request.UserId), errMsg),
+		}
+		return nil, response, nil
 	}
 
-	rPost, appErr := p.API.CreatePost(post)
-	if appErr != nil {
-		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to create poll post")
+	if _, err := p.createPollPost(newPoll, request.ChannelId, request.CallbackId); err != nil {
+		return commandErrorGeneric, nil, err
 	}
 
-	poll.PostID = rPost.Id
+	return nil, nil, nil
+}
 
-	if err := p.Store.Poll().Insert(poll); err != nil {
-		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+func (p *MatterpollPlugin) handleCreateSurvey(_ map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	creatorID := request.UserId
+
+	title, ok := request.Submission[surveyTitleKey].(string)
+	if !ok {
+		return commandErrorGeneric, nil, errors.Errorf("failed to get title key. Value is: %v", request.Submission[surveyTitleKey])
+	}
+	questions, ok := request.Submission[surveyQuestionsKey].(string)
+	if !ok {
+		return commandErrorGeneric, nil, errors.Errorf("failed to get questions key. Value is: %v", request.Submission[surveyQuestionsKey])
+	}
+
+	userLocalizer := p.bundle.GetUserLocalizer(creatorID)
+
+	settings, errMsg := poll.NewSettingsFromSubmission(request.Submission, p.pf.Millis(), p.getUserLocation(creatorID))
+	if errMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				"setting-" + poll.SettingKeyEnd: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			},
+		}
+		return nil, response, nil
+	}
+
+	survey, errMsg := p.pf.NewSurvey(creatorID, title, strings.Split(questions, "\n"), settings)
+	if errMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				surveyQuestionsKey: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			},
+		}
+		return nil, response, nil
+	}
+
+	if _, err := p.createPollPost(survey, request.ChannelId, request.CallbackId); err != nil {
+		return commandErrorGeneric, nil, err
 	}
 
 	return nil, nil, nil
@@ -367,14 +720,33 @@ func (p *MatterpollPlugin) handleVote(vars map[string]string, request *model.Pos
 		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
 	}
 
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return &i18n.LocalizeConfig{DefaultMessage: responseVoteNotEligible}, nil, nil
+	}
+
+	if poll.IsRanked() {
+		return p.openRankingDialog(poll, optionNumber, request)
+	}
+	if poll.IsScheduling() {
+		return p.openAvailabilityDialog(poll, optionNumber, request)
+	}
+
 	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
 	if appErr != nil {
 		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to get display name for creator")
 	}
 
 	prev := poll.Copy()
-	previouslyVoted := poll.HasVoted(userID)
-	msg, err := poll.UpdateVote(userID, optionNumber)
+	voterID := poll.VoterID(p.getSecretBallotKey(), userID)
+	if poll.Settings.Waitlist && poll.IsFull(optionNumber, voterID) {
+		return p.joinWaitlist(poll, voterID, optionNumber)
+	}
+	previouslyVoted := poll.HasVoted(voterID)
+	msg, err := poll.UpdateVote(voterID, optionNumber)
 	if msg != nil {
 		return &i18n.LocalizeConfig{DefaultMessage: msg}, nil, nil
 	}
@@ -382,23 +754,62 @@ func (p *MatterpollPlugin) handleVote(vars map[string]string, request *model.Pos
 		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to update poll")
 	}
 
-	if err = p.Store.Poll().Update(prev, poll); err != nil {
+	if err = p.saveVotes(prev, poll, voterID); err != nil {
 		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to save poll")
 	}
+	p.notifyPromotions(prev, poll)
+
+	postID := poll.PostID
+	if postID == "" {
+		// Legacy check if polls created without a postID
+		postID = request.PostId
+	}
+	closed, err := p.closePollIfComplete(poll, postID, request.ChannelId)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if closed {
+		return &i18n.LocalizeConfig{DefaultMessage: responseEndPollClosedAutomatically}, nil, nil
+	}
 
 	go p.publishPollMetadata(poll, userID)
+	p.sendResultsAfterVote(poll, userID, request.ChannelId)
 
 	post := &model.Post{}
+	p.ignoreExcludedVoters(poll)
 	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
 	post.AddProp("poll_id", poll.ID)
 	if poll.Settings.Progress {
 		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
 	}
 
+	// Point-Budget Mode
+	if poll.HasPointBudget() {
+		remains := poll.GetRemainingPoints(voterID)
+		return &i18n.LocalizeConfig{
+			DefaultMessage: responseVotePoints,
+			TemplateData:   map[string]interface{}{"Remains": remains},
+			PluralCount:    remains,
+		}, post, nil
+	}
+
+	// Quiz Mode
+	if poll.IsQuiz() {
+		if poll.AnsweredCorrectly(voterID) {
+			return &i18n.LocalizeConfig{DefaultMessage: responseQuizCorrect}, post, nil
+		}
+		correctAnswers := poll.GetCorrectAnswers()
+		return &i18n.LocalizeConfig{
+			DefaultMessage: responseQuizWrong,
+			TemplateData:   map[string]interface{}{"Answers": strings.Join(correctAnswers, ", ")},
+			PluralCount:    len(correctAnswers),
+		}, post, nil
+	}
+
 	// Multi Answer Mode
 	if poll.IsMultiVote() {
 		var remains int
-		votedAnswers := poll.GetVotedAnswers(userID)
+		votedAnswers := poll.GetVotedAnswers(voterID)
 		if poll.Settings.MaxVotes == 0 {
 			remains = len(poll.AnswerOptions) - len(votedAnswers)
 		} else {
@@ -424,159 +835,420 @@ func (p *MatterpollPlugin) handleVote(vars map[string]string, request *model.Pos
 	return &i18n.LocalizeConfig{DefaultMessage: responseVoteCounted}, post, nil
 }
 
-func (p *MatterpollPlugin) publishPollMetadata(poll *poll.Poll, userID string) {
-	canManagePoll, appErr := p.CanManagePoll(poll, userID)
-	if appErr != nil {
-		p.API.LogWarn("Failed to check permission to manage poll", "userID", userID, "pollID", poll.ID, "error", appErr.Error())
-		canManagePoll = false
-	}
-	metadata := poll.GetMetadata(userID, canManagePoll)
-	p.API.PublishWebSocketEvent("has_voted", metadata.ToMap(), &model.WebsocketBroadcast{UserId: userID})
-}
-
-func (p *MatterpollPlugin) handleResetVotes(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
-	pollID := vars["id"]
-	userID := request.UserId
-
-	poll, err := p.Store.Poll().Get(pollID)
-	if err != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
-	}
-
-	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
-	if appErr != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to get display name for creator")
-	}
-
-	votedAnswers := poll.GetVotedAnswers(userID)
-	if len(votedAnswers) == 0 {
-		return &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
-			ID:    "response.resetVotes.noVotes",
-			Other: "There are no votes to reset.",
-		}}, nil, nil
-	}
-
-	prev := poll.Copy()
-
-	poll.ResetVotes(userID)
-
-	if err = p.Store.Poll().Update(prev, poll); err != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to save poll")
-	}
-
-	go p.publishPollMetadata(poll, userID)
+// openRankingDialog opens a dialog for ranked-choice polls, in which a user ranks the answer options.
+// The dialog is prefilled with the previous ballot of the user or, if there is none, with the clicked option as first choice.
+func (p *MatterpollPlugin) openRankingDialog(poll *poll.Poll, optionNumber int, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
 
-	post := &model.Post{}
-	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
-	post.AddProp("poll_id", poll.ID)
-	if poll.Settings.Progress {
-		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	ballot := poll.GetBallot(request.UserId)
+	if len(ballot) == 0 && optionNumber < len(poll.AnswerOptions) {
+		ballot = []int{optionNumber}
 	}
 
-	return &i18n.LocalizeConfig{
-		DefaultMessage: &i18n.Message{
-			ID:    "response.resetVotes.success",
-			Other: "All votes are cleared. Your previous votes were [{{.ClearedVotes}}].",
-		},
-		TemplateData: map[string]interface{}{"ClearedVotes": strings.Join(votedAnswers, ", ")},
-	}, post, nil
-}
-
-func (p *MatterpollPlugin) handleAddOption(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
-	pollID := vars["id"]
-	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
-
-	poll, err := p.Store.Poll().Get(pollID)
-	if err != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	options := make([]*model.PostActionOptions, len(poll.AnswerOptions))
+	for i, o := range poll.AnswerOptions {
+		options[i] = &model.PostActionOptions{
+			Text:  o.Answer,
+			Value: strconv.Itoa(i),
+		}
 	}
 
-	if !poll.Settings.PublicAddOption {
-		canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
-		if appErr != nil {
-			return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	elements := make([]model.DialogElement, len(poll.AnswerOptions))
+	for i := range poll.AnswerOptions {
+		var defaultValue string
+		if i < len(ballot) {
+			defaultValue = strconv.Itoa(ballot[i])
 		}
-		if !canManagePoll {
-			return &i18n.LocalizeConfig{DefaultMessage: responseAddOptionInvalidPermission}, nil, nil
+		elements[i] = model.DialogElement{
+			DisplayName: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "dialog.rank.element.displayName",
+					Other: "Choice {{.Rank}}",
+				},
+				TemplateData: map[string]interface{}{"Rank": i + 1},
+			}),
+			Name:     fmt.Sprintf("%s%d", rankKeyPrefix, i+1),
+			Type:     "select",
+			Options:  options,
+			Default:  defaultValue,
+			Optional: i != 0,
 		}
 	}
 
 	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
 	dialog := model.OpenDialogRequest{
 		TriggerId: request.TriggerId,
-		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/add", root.Manifest.Id, pollID),
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/rank", root.Manifest.Id, poll.ID),
 		Dialog: model.Dialog{
 			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
-				ID:    "dialog.addOption.title",
-				Other: "Add Option",
+				ID:    "dialog.rank.title",
+				Other: "Rank Options",
+			}),
+			IntroductionText: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.rank.introductionText",
+				Other: "Rank the options from most to least preferred. You don't have to rank all of them.",
 			}),
 			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
 			CallbackId: request.PostId,
 			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
-				ID:    "dialog.addOption.submitLabel",
-				Other: "Add",
+				ID:    "dialog.rank.submitLabel",
+				Other: "Vote",
 			}),
-			Elements: []model.DialogElement{{
-				DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
-					ID:    "dialog.addOption.element.displayName",
-					Other: "Option",
-				}),
-				Name:    addOptionKey,
-				Type:    "text",
-				SubType: "text",
-			}},
+			Elements: elements,
 		},
 	}
 
 	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open add option dialog")
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open ranking dialog")
 	}
 	return nil, nil, nil
 }
 
-func (p *MatterpollPlugin) handleAddOptionConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+func (p *MatterpollPlugin) handleRankConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
 	pollID := vars["id"]
+	userID := request.UserId
 
 	poll, err := p.Store.Poll().Get(pollID)
 	if err != nil {
 		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
 	}
 
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return responseVoteNotEligible, nil, nil
+	}
+
 	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
 	if appErr != nil {
 		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
 	}
 
-	var postID string
-	if poll.PostID != "" {
-		postID = poll.PostID
-	} else {
-		// Legacy check if polls created without a postID
-		postID = request.CallbackId
-	}
-
-	post, appErr := p.API.GetPost(postID)
+	// Ranked-choice polls are always created with a postID
+	post, appErr := p.API.GetPost(poll.PostID)
 	if appErr != nil {
 		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
 	}
 
-	answerOption, ok := request.Submission[addOptionKey].(string)
-	if !ok {
-		return commandErrorGeneric, nil, errors.Errorf("failed to get submission key: %s", addOptionKey)
+	var ranking []int
+	for i := range poll.AnswerOptions {
+		value, ok := request.Submission[fmt.Sprintf("%s%d", rankKeyPrefix, i+1)].(string)
+		if !ok || value == "" {
+			continue
+		}
+		index, err := strconv.Atoi(value)
+		if err != nil {
+			return commandErrorGeneric, nil, errors.Wrapf(err, "failed to parse rank %d", i+1)
+		}
+		ranking = append(ranking, index)
 	}
 
 	prev := poll.Copy()
-	userLocalizer := p.bundle.GetUserLocalizer(poll.Creator)
-
-	if errMsg := poll.AddAnswerOption(answerOption); errMsg != nil {
+	previouslyVoted := poll.HasVoted(userID)
+	msg, err := poll.SubmitBallot(userID, ranking)
+	if msg != nil {
+		userLocalizer := p.bundle.GetUserLocalizer(userID)
 		response := &model.SubmitDialogResponse{
-			Errors: map[string]string{
-				addOptionKey: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			Error: p.bundle.LocalizeDefaultMessage(userLocalizer, msg),
+		}
+		return nil, response, nil
+	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to submit ballot")
+	}
+
+	if err = p.saveVotes(prev, poll, userID); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	closed, err := p.closePollIfComplete(poll, poll.PostID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if closed {
+		return responseEndPollClosedAutomatically, nil, nil
+	}
+
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	go p.publishPollMetadata(poll, userID)
+	p.sendResultsAfterVote(poll, userID, request.ChannelId)
+
+	if previouslyVoted {
+		return responseRankingUpdated, nil, nil
+	}
+	return responseRankingCounted, nil, nil
+}
+
+// openAvailabilityDialog opens a dialog for scheduling polls, in which a user answers yes, maybe or no for every answer option.
+// The dialog is prefilled with the previous answers of the user. If the user hasn't answered for the clicked option yet, it's prefilled with yes.
+func (p *MatterpollPlugin) openAvailabilityDialog(poll *poll.Poll, optionNumber int, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	options := p.availabilityDialogOptions(userLocalizer)
+	elements := make([]model.DialogElement, len(poll.AnswerOptions))
+	for i, o := range poll.AnswerOptions {
+		defaultValue := string(o.Availability[request.UserId])
+		if defaultValue == "" && i == optionNumber {
+			defaultValue = options[0].Value
+		}
+		elements[i] = model.DialogElement{
+			DisplayName: o.Answer,
+			Name:        fmt.Sprintf("%s%d", availabilityKeyPrefix, i),
+			Type:        "radio",
+			Options:     options,
+			Default:     defaultValue,
+			Optional:    true,
+		}
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/availability", root.Manifest.Id, poll.ID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.availability.title",
+				Other: "Your Availability",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.availability.submitLabel",
+				Other: "Save",
+			}),
+			Elements: elements,
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open availability dialog")
+	}
+	return nil, nil, nil
+}
+
+// availabilityDialogOptions returns the options of the radio buttons in the availability dialog. The first one is yes.
+func (p *MatterpollPlugin) availabilityDialogOptions(userLocalizer *i18n.Localizer) []*model.PostActionOptions {
+	return []*model.PostActionOptions{{
+		Text: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "dialog.availability.option.yes",
+			Other: "Yes",
+		}),
+		Value: string(poll.AvailabilityYes),
+	}, {
+		Text: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "dialog.availability.option.maybe",
+			Other: "Maybe",
+		}),
+		Value: string(poll.AvailabilityMaybe),
+	}, {
+		Text: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "dialog.availability.option.no",
+			Other: "No",
+		}),
+		Value: string(poll.AvailabilityNo),
+	}}
+}
+
+// availabilitiesFromSubmission reads the answers of the availability dialog for n answer options.
+func availabilitiesFromSubmission(submission map[string]any, n int) []poll.Availability {
+	answers := make([]poll.Availability, n)
+	for i := range answers {
+		// Unanswered options are submitted as nil
+		if value, ok := submission[fmt.Sprintf("%s%d", availabilityKeyPrefix, i)].(string); ok {
+			answers[i] = poll.Availability(value)
+		}
+	}
+	return answers
+}
+
+func (p *MatterpollPlugin) handleAvailabilityConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return responseVoteNotEligible, nil, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	// Scheduling polls are always created with a postID
+	post, appErr := p.API.GetPost(poll.PostID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	prev := poll.Copy()
+	if err = poll.SetAvailability(userID, availabilitiesFromSubmission(request.Submission, len(poll.AnswerOptions))); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to set availability")
+	}
+
+	if err = p.saveVotes(prev, poll, userID); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	closed, err := p.closePollIfComplete(poll, poll.PostID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if closed {
+		return responseEndPollClosedAutomatically, nil, nil
+	}
+
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	go p.publishPollMetadata(poll, userID)
+	p.sendResultsAfterVote(poll, userID, request.ChannelId)
+
+	return responseAvailabilitySaved, nil, nil
+}
+
+func (p *MatterpollPlugin) handleResponse(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return &i18n.LocalizeConfig{DefaultMessage: responseVoteNotEligible}, nil, nil
+	}
+
+	if !poll.IsFreeText() {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.New("poll isn't a free-text poll")
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/response", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.response.title",
+				Other: "Your Answer",
+			}),
+			IntroductionText: poll.Question,
+			IconURL:          fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId:       request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.response.submitLabel",
+				Other: "Submit",
+			}),
+			Elements: []model.DialogElement{{
+				DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.response.element.displayName",
+					Other: "Answer",
+				}),
+				Name:      responseKey,
+				Type:      "textarea",
+				Default:   poll.GetResponse(request.UserId),
+				MaxLength: 3000,
+			}},
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open response dialog")
+	}
+	return nil, nil, nil
+}
+
+func (p *MatterpollPlugin) handleResponseConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return responseVoteNotEligible, nil, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	// Free-text polls are always created with a postID
+	post, appErr := p.API.GetPost(poll.PostID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	// Empty submissions are submitted as nil
+	text, _ := request.Submission[responseKey].(string)
+
+	previouslyAnswered := poll.GetResponse(userID) != ""
+	prev := poll.Copy()
+	msg, err := poll.SetResponse(userID, text)
+	if msg != nil {
+		userLocalizer := p.bundle.GetUserLocalizer(userID)
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				responseKey: p.bundle.LocalizeDefaultMessage(userLocalizer, msg),
 			},
 		}
 		return nil, response, nil
 	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to set response")
+	}
+
+	if err = p.saveVotes(prev, poll, userID); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
 
+	closed, err := p.closePollIfComplete(poll, poll.PostID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if closed {
+		return responseEndPollClosedAutomatically, nil, nil
+	}
+
+	p.ignoreExcludedVoters(poll)
 	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
 	if poll.Settings.Progress {
 		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
@@ -586,111 +1258,1964 @@ func (p *MatterpollPlugin) handleAddOptionConfirm(vars map[string]string, reques
 		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
 	}
 
-	if err = p.Store.Poll().Update(prev, poll); err != nil {
-		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get save poll")
+	go p.publishPollMetadata(poll, userID)
+	p.sendResultsAfterVote(poll, userID, request.ChannelId)
+
+	if previouslyAnswered {
+		return responseFreeTextUpdated, nil, nil
+	}
+	return responseFreeTextSaved, nil, nil
+}
+
+func (p *MatterpollPlugin) handleAnswerSurvey(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	survey, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	if !survey.IsSurvey() {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.New("poll isn't a survey")
+	}
+
+	answers := survey.GetSurveyAnswers(request.UserId)
+	elements := make([]model.DialogElement, len(survey.Questions))
+	for i, q := range survey.Questions {
+		options := make([]*model.PostActionOptions, len(q.AnswerOptions))
+		for j, o := range q.AnswerOptions {
+			options[j] = &model.PostActionOptions{Text: o.Answer, Value: strconv.Itoa(j)}
+		}
+		defaults := make([]string, len(answers[i]))
+		for j, index := range answers[i] {
+			defaults[j] = strconv.Itoa(index)
+		}
+
+		elements[i] = model.DialogElement{
+			DisplayName: q.Question,
+			Name:        fmt.Sprintf("%s%d", surveyAnswerKeyPrefix, i),
+			Type:        "radio",
+			Options:     options,
+			Default:     strings.Join(defaults, ","),
+			Optional:    true,
+		}
+		if q.Settings.MaxVotes != 1 {
+			elements[i].Type = "select"
+			elements[i].MultiSelect = true
+			elements[i].HelpText = p.surveyAnswerHelpText(userLocalizer, q.Settings.MaxVotes)
+		}
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/survey", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title:      survey.Question,
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.answerSurvey.submitLabel",
+				Other: "Submit",
+			}),
+			Elements: elements,
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open survey dialog")
+	}
+	return nil, nil, nil
+}
+
+// surveyAnswerHelpText returns the help text of a survey question with multiple answers.
+// maxVotes is 0 if a user can choose all options.
+func (p *MatterpollPlugin) surveyAnswerHelpText(userLocalizer *i18n.Localizer, maxVotes int) string {
+	if maxVotes == 0 {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "dialog.answerSurvey.help.unlimited",
+			Other: "Choose as many options as you like.",
+		})
+	}
+	return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "dialog.answerSurvey.help.maxVotes",
+			Other: "Choose up to {{.MaxVotes}} options.",
+		},
+		TemplateData: map[string]interface{}{"MaxVotes": maxVotes},
+	})
+}
+
+// surveyAnswersFromSubmission reads the answers of the survey dialog for n questions.
+// Multiselect answers are submitted either as a list or as a comma-separated string, unanswered questions as nil.
+func surveyAnswersFromSubmission(submission map[string]any, n int) ([][]int, error) {
+	answers := make([][]int, n)
+	for i := range answers {
+		var values []string
+		switch v := submission[fmt.Sprintf("%s%d", surveyAnswerKeyPrefix, i)].(type) {
+		case string:
+			if v != "" {
+				values = strings.Split(v, ",")
+			}
+		case []any:
+			for _, value := range v {
+				s, ok := value.(string)
+				if !ok {
+					return nil, errors.Errorf("invalid answer: %v", value)
+				}
+				values = append(values, s)
+			}
+		}
+
+		answers[i] = []int{}
+		for _, value := range values {
+			index, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid answer")
+			}
+			answers[i] = append(answers[i], index)
+		}
+	}
+	return answers, nil
+}
+
+func (p *MatterpollPlugin) handleAnswerSurveyConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+
+	survey, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(survey.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	// Surveys are always created with a postID
+	post, appErr := p.API.GetPost(survey.PostID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	answers, err := surveyAnswersFromSubmission(request.Submission, len(survey.Questions))
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to read answers")
+	}
+
+	previouslyAnswered := survey.HasVoted(userID)
+	prev := survey.Copy()
+	errMsgs, err := survey.SubmitSurvey(userID, answers)
+	if errMsgs != nil {
+		userLocalizer := p.bundle.GetUserLocalizer(userID)
+		response := &model.SubmitDialogResponse{Errors: map[string]string{}}
+		for i, errMsg := range errMsgs {
+			response.Errors[fmt.Sprintf("%s%d", surveyAnswerKeyPrefix, i)] = p.bundle.LocalizeErrorMessage(userLocalizer, errMsg)
+		}
+		return nil, response, nil
+	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to submit survey")
+	}
+
+	if err = p.saveVotes(prev, survey, userID); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	closed, err := p.closePollIfComplete(survey, survey.PostID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if closed {
+		return responseEndPollClosedAutomatically, nil, nil
+	}
+
+	p.ignoreExcludedVoters(survey)
+	model.ParseMessageAttachment(post, survey.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if survey.Settings.Progress {
+		post.AddProp("card", survey.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	go p.publishPollMetadata(survey, userID)
+	p.sendResultsAfterVote(survey, userID, request.ChannelId)
+
+	if previouslyAnswered {
+		return responseSurveyUpdated, nil, nil
+	}
+	return responseSurveySaved, nil, nil
+}
+
+func (p *MatterpollPlugin) handleEditPoints(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return &i18n.LocalizeConfig{DefaultMessage: responseVoteNotEligible}, nil, nil
+	}
+
+	if !poll.HasPointBudget() {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.New("poll has no point budget")
+	}
+
+	elements := make([]model.DialogElement, len(poll.AnswerOptions))
+	for i, o := range poll.AnswerOptions {
+		elements[i] = model.DialogElement{
+			DisplayName: o.Answer,
+			Name:        fmt.Sprintf("%s%d", pointsKeyPrefix, i),
+			Type:        "text",
+			SubType:     "number",
+			Default:     strconv.Itoa(o.Points[request.UserId]),
+			Optional:    true,
+		}
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/points", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.editPoints.title",
+				Other: "Edit Your Points",
+			}),
+			IntroductionText: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "dialog.editPoints.introductionText",
+					One:   "You have {{.Points}} point to spread across the options.",
+					Few:   "You have {{.Points}} points to spread across the options.",
+					Many:  "You have {{.Points}} points to spread across the options.",
+					Other: "You have {{.Points}} points to spread across the options.",
+				},
+				TemplateData: map[string]interface{}{"Points": poll.Settings.Points},
+				PluralCount:  poll.Settings.Points,
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.editPoints.submitLabel",
+				Other: "Save",
+			}),
+			Elements: elements,
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open edit points dialog")
+	}
+	return nil, nil, nil
+}
+
+func (p *MatterpollPlugin) handleEditPointsConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return responseVoteNotEligible, nil, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	// Point-budget polls are always created with a postID
+	post, appErr := p.API.GetPost(poll.PostID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	points := make([]int, len(poll.AnswerOptions))
+	for i := range poll.AnswerOptions {
+		// Empty fields are submitted as nil
+		if f, ok := request.Submission[fmt.Sprintf("%s%d", pointsKeyPrefix, i)].(float64); ok {
+			points[i] = int(f)
+		}
+	}
+
+	prev := poll.Copy()
+	errMsg, err := poll.SetPoints(userID, points)
+	if errMsg != nil {
+		userLocalizer := p.bundle.GetUserLocalizer(userID)
+		response := &model.SubmitDialogResponse{
+			Error: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+		}
+		return nil, response, nil
+	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to set points")
+	}
+
+	if err = p.saveVotes(prev, poll, userID); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	closed, err := p.closePollIfComplete(poll, poll.PostID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if closed {
+		return responseEndPollClosedAutomatically, nil, nil
+	}
+
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	go p.publishPollMetadata(poll, userID)
+	p.sendResultsAfterVote(poll, userID, request.ChannelId)
+
+	return responseEditPointsSuccess, nil, nil
+}
+
+func (p *MatterpollPlugin) publishPollMetadata(poll *poll.Poll, userID string) {
+	canManagePoll, appErr := p.CanManagePoll(poll, userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to check permission to manage poll", "userID", userID, "pollID", poll.ID, "error", appErr.Error())
+		canManagePoll = false
+	}
+	metadata := poll.GetMetadata(poll.VoterID(p.getSecretBallotKey(), userID), canManagePoll)
+	metadata.UserID = userID
+	p.API.PublishWebSocketEvent("has_voted", metadata.ToMap(), &model.WebsocketBroadcast{UserId: userID})
+}
+
+// sendResultsAfterVote sends the current results of a poll with the results-after-vote setting to a user who has voted.
+func (p *MatterpollPlugin) sendResultsAfterVote(poll *poll.Poll, userID, channelID string) {
+	if !poll.Settings.ResultsAfterVote || !poll.HasVoted(poll.VoterID(p.getSecretBallotKey(), userID)) {
+		return
+	}
+
+	var rootID string
+	if poll.PostID != "" {
+		post, appErr := p.API.GetPost(poll.PostID)
+		if appErr != nil {
+			p.API.LogWarn("Failed to get post to send the results", "pollID", poll.ID, "error", appErr.Error())
+			return
+		}
+		rootID = post.RootId
+		if rootID == "" {
+			rootID = post.Id
+		}
+	}
+
+	results := poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName)
+	if results == "" {
+		p.API.LogWarn("Failed to render the results", "pollID", poll.ID)
+		return
+	}
+	p.SendEphemeralPost(channelID, userID, rootID, results)
+}
+
+func (p *MatterpollPlugin) handleResetVotes(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	if poll.IsQuiz() {
+		return &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+			ID:    "response.resetVotes.quiz",
+			Other: "Answers to a quiz can't be reset.",
+		}}, nil, nil
+	}
+	if poll.IsFinal() {
+		return &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+			ID:    "response.resetVotes.final",
+			Other: "Votes of this poll are final and can't be reset.",
+		}}, nil, nil
+	}
+
+	voterID := poll.VoterID(p.getSecretBallotKey(), userID)
+	votedAnswers := poll.GetVotedAnswers(voterID)
+	if !poll.HasVoted(voterID) && !poll.IsWaiting(voterID) {
+		return &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+			ID:    "response.resetVotes.noVotes",
+			Other: "There are no votes to reset.",
+		}}, nil, nil
+	}
+
+	prev := poll.Copy()
+
+	poll.ResetVotes(voterID)
+
+	if err = p.saveVotes(prev, poll, voterID); err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to save poll")
+	}
+	p.notifyPromotions(prev, poll)
+
+	go p.publishPollMetadata(poll, userID)
+
+	post := &model.Post{}
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	post.AddProp("poll_id", poll.ID)
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	return &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "response.resetVotes.success",
+			Other: "All votes are cleared. Your previous votes were [{{.ClearedVotes}}].",
+		},
+		TemplateData: map[string]interface{}{"ClearedVotes": strings.Join(votedAnswers, ", ")},
+	}, post, nil
+}
+
+func (p *MatterpollPlugin) handleDelegateVote(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	if !poll.AllowsProxyVotes() {
+		return &i18n.LocalizeConfig{DefaultMessage: responseProxyVoteNotAllowed}, nil, nil
+	}
+
+	eligible, err := p.IsEligible(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return &i18n.LocalizeConfig{DefaultMessage: responseVoteNotEligible}, nil, nil
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/delegate", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.delegateVote.title",
+				Other: "Delegate Your Vote",
+			}),
+			IntroductionText: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.delegateVote.introductionText",
+				Other: "Choose a user, who can vote on your behalf. Every vote they cast for you is marked as a proxy vote.",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.delegateVote.submitLabel",
+				Other: "Save",
+			}),
+			Elements: []model.DialogElement{{
+				DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.delegateVote.element.displayName",
+					Other: "Delegate",
+				}),
+				Name:       delegateKey,
+				Type:       "select",
+				DataSource: "users",
+				Default:    poll.GetDelegate(request.UserId),
+				Optional:   true,
+				HelpText: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.delegateVote.element.helpText",
+					Other: "Leave empty to take back your delegation.",
+				}),
+			}},
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open delegate vote dialog")
+	}
+	return nil, nil, nil
+}
+
+func (p *MatterpollPlugin) handleDelegateVoteConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+	userLocalizer := p.bundle.GetUserLocalizer(userID)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	if !poll.AllowsProxyVotes() {
+		return responseProxyVoteNotAllowed, nil, nil
+	}
+
+	eligible, err := p.IsEligible(poll, userID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to check eligibility")
+	}
+	if !eligible {
+		return responseVoteNotEligible, nil, nil
+	}
+
+	// Empty fields are submitted as nil
+	delegateID, _ := request.Submission[delegateKey].(string)
+	if delegateID != "" && !p.API.HasPermissionToChannel(delegateID, request.ChannelId, model.PermissionReadChannel) {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				delegateKey: p.bundle.LocalizeDefaultMessage(userLocalizer, responseDelegateVoteNoPermission),
+			},
+		}
+		return nil, response, nil
+	}
+
+	prev := poll.Copy()
+	errMsg, err := poll.Delegate(userID, delegateID)
+	if errMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				delegateKey: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			},
+		}
+		return nil, response, nil
+	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to delegate vote")
+	}
+
+	if err = p.Store.Poll().Update(prev, poll); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	if delegateID == "" {
+		return responseDelegateVoteRevoked, nil, nil
+	}
+	if delegateID != prev.GetDelegate(userID) {
+		p.notifyDelegate(poll, userID, delegateID)
+	}
+	return responseDelegateVoteSuccess, nil, nil
+}
+
+// notifyDelegate sends a direct message to a user, to whom another user delegated their vote in a given poll.
+func (p *MatterpollPlugin) notifyDelegate(poll *poll.Poll, userID, delegateID string) {
+	displayName, appErr := p.ConvertUserIDToDisplayName(userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to notify delegate", "details", "failed to get display name", "error", appErr.Error())
+		return
+	}
+
+	err := p.sendDirectMessage(delegateID, &i18n.LocalizeConfig{
+		DefaultMessage: responseDelegateVoteNotification,
+		TemplateData: map[string]interface{}{
+			"User":     displayName,
+			"Question": poll.Question,
+			"Link":     p.getPermalink(poll.PostID),
+		},
+	})
+	if err != nil {
+		p.API.LogWarn("Failed to notify delegate", "error", err.Error())
+	}
+}
+
+func (p *MatterpollPlugin) handleProxyVote(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	if !poll.AllowsProxyVotes() {
+		return &i18n.LocalizeConfig{DefaultMessage: responseProxyVoteNotAllowed}, nil, nil
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+
+	voterElement := model.DialogElement{
+		DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "dialog.proxyVote.element.voter.displayName",
+			Other: "Vote on behalf of",
+		}),
+		Name: proxyVoterKey,
+		Type: "select",
+	}
+	if canManagePoll {
+		// Poll managers can vote on behalf of everyone
+		voterElement.DataSource = "users"
+	} else {
+		delegators := poll.GetDelegators(request.UserId)
+		if len(delegators) == 0 {
+			return &i18n.LocalizeConfig{DefaultMessage: responseProxyVoteInvalidPermission}, nil, nil
+		}
+		for _, delegatorID := range delegators {
+			displayName, appErr := p.ConvertUserIDToDisplayName(delegatorID)
+			if appErr != nil {
+				return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to get display name for delegator")
+			}
+			voterElement.Options = append(voterElement.Options, &model.PostActionOptions{
+				Text:  displayName,
+				Value: delegatorID,
+			})
+		}
+	}
+
+	options := make([]*model.PostActionOptions, len(poll.AnswerOptions))
+	for i, o := range poll.AnswerOptions {
+		options[i] = &model.PostActionOptions{
+			Text:  o.Answer,
+			Value: strconv.Itoa(i),
+		}
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/proxy", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.proxyVote.title",
+				Other: "Vote by Proxy",
+			}),
+			IntroductionText: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.proxyVote.introductionText",
+				Other: "The vote is attributed to the user you vote for and marked as a proxy vote. The user gets notified about it.",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.proxyVote.submitLabel",
+				Other: "Vote",
+			}),
+			Elements: []model.DialogElement{voterElement, {
+				DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.proxyVote.element.answer.displayName",
+					Other: "Option",
+				}),
+				Name:    proxyAnswerKey,
+				Type:    "select",
+				Options: options,
+			}},
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open proxy vote dialog")
+	}
+	return nil, nil, nil
+}
+
+func (p *MatterpollPlugin) handleProxyVoteConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userID := request.UserId
+	userLocalizer := p.bundle.GetUserLocalizer(userID)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	if !poll.AllowsProxyVotes() {
+		return responseProxyVoteNotAllowed, nil, nil
+	}
+
+	voterID, ok := request.Submission[proxyVoterKey].(string)
+	if !ok || voterID == "" {
+		return commandErrorGeneric, nil, errors.Errorf("failed to get submission key: %s", proxyVoterKey)
+	}
+	answer, ok := request.Submission[proxyAnswerKey].(string)
+	if !ok {
+		return commandErrorGeneric, nil, errors.Errorf("failed to get submission key: %s", proxyAnswerKey)
+	}
+	index, err := strconv.Atoi(answer)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to parse answer")
+	}
+
+	if voterID == userID {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				proxyVoterKey: p.bundle.LocalizeDefaultMessage(userLocalizer, responseProxyVoteSelf),
+			},
+		}
+		return nil, response, nil
+	}
+
+	if poll.GetDelegate(voterID) != userID {
+		canManagePoll, appErr := p.CanManagePoll(poll, userID)
+		if appErr != nil {
+			return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to check permission")
+		}
+		if !canManagePoll {
+			return responseProxyVoteInvalidPermission, nil, nil
+		}
+	}
+
+	// The vote is attributed to the voter, so they must be allowed to vote by themselves
+	voterMsg := responseProxyVoteVoterNoPermission
+	if p.API.HasPermissionToChannel(voterID, request.ChannelId, model.PermissionReadChannel) {
+		voterMsg, err = p.getExclusionMessage(poll, voterID)
+		if err != nil {
+			return commandErrorGeneric, nil, err
+		}
+	}
+	if voterMsg == nil {
+		eligible, err := p.IsEligible(poll, voterID, request.ChannelId)
+		if err != nil {
+			return commandErrorGeneric, nil, errors.Wrap(err, "failed to check eligibility")
+		}
+		if !eligible {
+			voterMsg = responseProxyVoteVoterNotEligible
+		}
+	}
+	if voterMsg == nil && poll.HasVotedByThemselves(voterID) {
+		voterMsg = responseProxyVoteVoterVoted
+	}
+	if voterMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				proxyVoterKey: p.bundle.LocalizeDefaultMessage(userLocalizer, voterMsg),
+			},
+		}
+		return nil, response, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	var postID string
+	if poll.PostID != "" {
+		postID = poll.PostID
+	} else {
+		// Legacy check if polls created without a postID
+		postID = request.CallbackId
+	}
+
+	post, appErr := p.API.GetPost(postID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	prev := poll.Copy()
+	msg, err := poll.UpdateVoteByProxy(userID, voterID, index)
+	if msg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				proxyAnswerKey: p.bundle.LocalizeDefaultMessage(userLocalizer, msg),
+			},
+		}
+		return nil, response, nil
+	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to update poll")
+	}
+
+	if err = p.saveVotesByProxy(prev, poll, voterID, userID); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+	p.notifyPromotions(prev, poll)
+
+	closed, err := p.closePollIfComplete(poll, postID, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to close poll")
+	}
+	if !closed {
+		p.ignoreExcludedVoters(poll)
+		model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+		if poll.Settings.Progress {
+			post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+		}
+
+		if _, appErr = p.API.UpdatePost(post); appErr != nil {
+			return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+		}
+
+		go p.publishPollMetadata(poll, voterID)
+	}
+
+	p.notifyProxyVoter(poll, userID, voterID, index, postID)
+
+	if closed {
+		return responseEndPollClosedAutomatically, nil, nil
+	}
+	return responseProxyVoteCounted, nil, nil
+}
+
+// notifyProxyVoter sends a direct message to a user, on whose behalf another user voted for the answer option with a given index.
+func (p *MatterpollPlugin) notifyProxyVoter(poll *poll.Poll, proxyID, voterID string, index int, postID string) {
+	displayName, appErr := p.ConvertUserIDToDisplayName(proxyID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to notify proxy voter", "details", "failed to get display name", "error", appErr.Error())
+		return
+	}
+
+	err := p.sendDirectMessage(voterID, &i18n.LocalizeConfig{
+		DefaultMessage: responseProxyVoteNotification,
+		TemplateData: map[string]interface{}{
+			"Proxy":    displayName,
+			"Option":   poll.AnswerOptions[index].Answer,
+			"Question": poll.Question,
+			"Link":     p.getPermalink(postID),
+		},
+	})
+	if err != nil {
+		p.API.LogWarn("Failed to notify proxy voter", "error", err.Error())
+	}
+}
+
+func (p *MatterpollPlugin) handleAddOption(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	if !poll.Settings.PublicAddOption {
+		canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+		if appErr != nil {
+			return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+		}
+		if !canManagePoll {
+			return &i18n.LocalizeConfig{DefaultMessage: responseAddOptionInvalidPermission}, nil, nil
+		}
+	}
+
+	canAddOption, err := p.canAddOptionToRestrictedPoll(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, err
+	}
+	if !canAddOption {
+		return &i18n.LocalizeConfig{DefaultMessage: responseAddOptionNotEligible}, nil, nil
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/add", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.addOption.title",
+				Other: "Add Option",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.addOption.submitLabel",
+				Other: "Add",
+			}),
+			Elements: []model.DialogElement{{
+				DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.addOption.element.displayName",
+					Other: "Option",
+				}),
+				Name:    addOptionKey,
+				Type:    "text",
+				SubType: "text",
+			}},
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open add option dialog")
+	}
+	return nil, nil, nil
+}
+
+// canAddOptionToRestrictedPoll checks if a given user can add options to a poll, that restricts who is allowed to vote.
+// Only users who can manage the poll or are allowed to vote in it can.
+func (p *MatterpollPlugin) canAddOptionToRestrictedPoll(poll *poll.Poll, userID, channelID string) (bool, error) {
+	if !poll.IsRestricted() {
+		return true, nil
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, userID)
+	if appErr != nil {
+		return false, errors.Wrap(appErr, "failed to check permission")
+	}
+	if canManagePoll {
+		return true, nil
+	}
+
+	eligible, err := p.IsEligible(poll, userID, channelID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check eligibility")
+	}
+	return eligible, nil
+}
+
+func (p *MatterpollPlugin) handleAddOptionConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canAddOption, err := p.canAddOptionToRestrictedPoll(poll, request.UserId, request.ChannelId)
+	if err != nil {
+		return commandErrorGeneric, nil, err
+	}
+	if !canAddOption {
+		return responseAddOptionNotEligible, nil, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	var postID string
+	if poll.PostID != "" {
+		postID = poll.PostID
+	} else {
+		// Legacy check if polls created without a postID
+		postID = request.CallbackId
+	}
+
+	post, appErr := p.API.GetPost(postID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	answerOption, ok := request.Submission[addOptionKey].(string)
+	if !ok {
+		return commandErrorGeneric, nil, errors.Errorf("failed to get submission key: %s", addOptionKey)
+	}
+
+	prev := poll.Copy()
+	userLocalizer := p.bundle.GetUserLocalizer(poll.Creator)
+
+	if errMsg := poll.AddAnswerOption(answerOption); errMsg != nil {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				addOptionKey: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			},
+		}
+		return nil, response, nil
+	}
+
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	if err = p.Store.Poll().Update(prev, poll); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get save poll")
+	}
+
+	return responseAddOptionSuccess, nil, nil
+}
+
+func (p *MatterpollPlugin) handleManageOptions(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return &i18n.LocalizeConfig{DefaultMessage: responseManageOptionsInvalidPermission}, nil, nil
+	}
+
+	positions := make([]*model.PostActionOptions, len(poll.AnswerOptions))
+	for i := range positions {
+		positions[i] = &model.PostActionOptions{Text: strconv.Itoa(i + 1), Value: strconv.Itoa(i + 1)}
+	}
+	elements := []model.DialogElement{}
+	for i, o := range poll.AnswerOptions {
+		number := map[string]interface{}{"Number": i + 1}
+		elements = append(elements, model.DialogElement{
+			DisplayName: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "dialog.manageOptions.element.option",
+					Other: "Option {{.Number}}",
+				},
+				TemplateData: number,
+			}),
+			Name:    fmt.Sprintf("%s%d", manageOptionKeyPrefix, i),
+			Type:    "text",
+			SubType: "text",
+			Default: o.Answer,
+			HelpText: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.manageOptions.element.option.helpText",
+				Other: "Rename the option or clear it to delete the option and its votes.",
+			}),
+			Optional: true,
+		}, model.DialogElement{
+			DisplayName: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "dialog.manageOptions.element.position",
+					Other: "Position of option {{.Number}}",
+				},
+				TemplateData: number,
+			}),
+			Name:    fmt.Sprintf("%s%d", managePositionKeyPrefix, i),
+			Type:    "select",
+			Options: positions,
+			Default: strconv.Itoa(i + 1),
+		})
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/manage", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.manageOptions.title",
+				Other: "Manage Options",
+			}),
+			IntroductionText: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.manageOptions.introduction",
+				Other: "Votes for renamed or moved options are kept. Voters of renamed and deleted options get notified.",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.manageOptions.submitLabel",
+				Other: "Save",
+			}),
+			Elements: elements,
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open manage options dialog")
+	}
+	return nil, nil, nil
+}
+
+// optionChangesFromSubmission reads the changes of the manage options dialog for n answer options.
+func optionChangesFromSubmission(submission map[string]any, n int) ([]poll.OptionChange, error) {
+	changes := make([]poll.OptionChange, n)
+	for i := range changes {
+		// Cleared options are submitted as nil
+		answer, _ := submission[fmt.Sprintf("%s%d", manageOptionKeyPrefix, i)].(string)
+		position, ok := submission[fmt.Sprintf("%s%d", managePositionKeyPrefix, i)].(string)
+		if !ok {
+			return nil, errors.Errorf("failed to get position of option %d", i)
+		}
+		number, err := strconv.Atoi(position)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid position of option %d", i)
+		}
+		changes[i] = poll.OptionChange{Answer: answer, Position: number}
+	}
+	return changes, nil
+}
+
+func (p *MatterpollPlugin) handleManageOptionsConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return responseManageOptionsInvalidPermission, nil, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	var postID string
+	if poll.PostID != "" {
+		postID = poll.PostID
+	} else {
+		// Legacy check if polls created without a postID
+		postID = request.CallbackId
+	}
+
+	post, appErr := p.API.GetPost(postID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	changes, err := optionChangesFromSubmission(request.Submission, len(poll.AnswerOptions))
+	if err != nil {
+		return commandErrorGeneric, nil, err
+	}
+
+	prev := poll.Copy()
+	changed, errMsg, err := poll.ManageAnswerOptions(changes)
+	if errMsg != nil {
+		return nil, &model.SubmitDialogResponse{Error: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg)}, nil
+	}
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to manage options")
+	}
+
+	if err = p.Store.Poll().Update(prev, poll); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	rootID := post.RootId
+	if rootID == "" {
+		rootID = post.Id
+	}
+	p.notifyVotersOfChangedOptions(poll, changed, post.ChannelId, rootID)
+
+	return responseManageOptionsSuccess, nil, nil
+}
+
+// notifyVotersOfChangedOptions tells the voters of renamed and deleted answer options what happened to their votes.
+func (p *MatterpollPlugin) notifyVotersOfChangedOptions(poll *poll.Poll, changed []*poll.ChangedAnswerOption, channelID, rootID string) {
+	for _, o := range changed {
+		message := &i18n.Message{
+			ID:    "response.manageOptions.renamed",
+			Other: `The option "{{.Answer}}" of the poll **{{.Question}}** was renamed to "{{.NewAnswer}}". Your vote for it was kept.`,
+		}
+		if o.NewAnswer == "" {
+			message = &i18n.Message{
+				ID:    "response.manageOptions.deleted",
+				Other: `The option "{{.Answer}}" of the poll **{{.Question}}** was deleted. Your vote for it was removed.`,
+			}
+		}
+		for _, userID := range o.Voters {
+			userLocalizer := p.bundle.GetUserLocalizer(userID)
+			p.SendEphemeralPost(channelID, userID, rootID, p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: message,
+				TemplateData: map[string]interface{}{
+					"Answer":    o.Answer,
+					"NewAnswer": o.NewAnswer,
+					"Question":  poll.Question,
+				},
+			}))
+		}
+	}
+}
+
+func (p *MatterpollPlugin) handleEditPoll(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return &i18n.LocalizeConfig{DefaultMessage: responseEditPollInvalidPermission}, nil, nil
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/edit", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.editPoll.title",
+				Other: "Edit Poll",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.editPoll.submitLabel",
+				Other: "Save",
+			}),
+			Elements: p.getEditPollDialogElements(userLocalizer, poll),
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open edit poll dialog")
+	}
+	return nil, nil, nil
+}
+
+// getEditPollDialogElements returns the elements of the edit poll dialog, pre-filled with the current values of a poll.
+// Only settings, that apply to the kind of poll, are part of the dialog.
+func (p *MatterpollPlugin) getEditPollDialogElements(l *i18n.Localizer, toEdit *poll.Poll) []model.DialogElement {
+	elements := []model.DialogElement{{
+		DisplayName: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+			ID:    "dialog.createPoll.question",
+			Other: "Question",
+		}),
+		Name:    questionKey,
+		Type:    "text",
+		SubType: "text",
+		Default: toEdit.Question,
+	}}
+	if toEdit.CanEditMaxVotes() {
+		elements = append(elements, model.DialogElement{
+			DisplayName: "Number of Votes",
+			Name:        maxVotesKey,
+			Type:        "text",
+			SubType:     "number",
+			Default:     strconv.Itoa(toEdit.Settings.MaxVotes),
+			HelpText: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+				ID:    "dialog.editPoll.setting.multi",
+				Other: "The number of options that a user can vote on. It can't be lower than the number of votes a user already cast. 0 means that users can vote for all options.",
+			}),
+		})
+	}
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Progress",
+		Name:        "setting-" + poll.SettingKeyProgress,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingProgress),
+		Default:     strconv.FormatBool(toEdit.Settings.Progress),
+		Optional:    true,
+	})
+	if !toEdit.IsSurvey() {
+		elements = append(elements, model.DialogElement{
+			DisplayName: "Results after vote",
+			Name:        "setting-" + poll.SettingKeyResultsAfterVote,
+			Type:        "bool",
+			Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingResultsAfterVote),
+			Default:     strconv.FormatBool(toEdit.Settings.ResultsAfterVote),
+			Optional:    true,
+		})
+	}
+	if !toEdit.IsSurvey() && !toEdit.IsFreeText() {
+		elements = append(elements, model.DialogElement{
+			DisplayName: "Public Add Option",
+			Name:        "setting-" + poll.SettingKeyPublicAddOption,
+			Type:        "bool",
+			Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingPublicAddOption),
+			Default:     strconv.FormatBool(toEdit.Settings.PublicAddOption),
+			Optional:    true,
+		})
+	}
+
+	return elements
+}
+
+// pollEditFromSubmission reads the edit poll dialog. Fields, that aren't part of the dialog, keep their current value.
+func pollEditFromSubmission(p *poll.Poll, submission map[string]any) poll.Edit {
+	e := p.NewEdit()
+	if question, ok := submission[questionKey].(string); ok {
+		e.Question = question
+	}
+	if maxVotes, ok := submission[maxVotesKey].(float64); ok {
+		e.MaxVotes = int(maxVotes)
+	}
+	for key, value := range map[string]*bool{
+		poll.SettingKeyProgress:         &e.Progress,
+		poll.SettingKeyResultsAfterVote: &e.ResultsAfterVote,
+		poll.SettingKeyPublicAddOption:  &e.PublicAddOption,
+	} {
+		if b, ok := submission["setting-"+key].(bool); ok {
+			*value = b
+		}
+	}
+	return e
+}
+
+func (p *MatterpollPlugin) handleEditPollConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return responseEditPollInvalidPermission, nil, nil
+	}
+
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	var postID string
+	if poll.PostID != "" {
+		postID = poll.PostID
+	} else {
+		// Legacy check if polls created without a postID
+		postID = request.CallbackId
+	}
+
+	post, appErr := p.API.GetPost(postID)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get post")
+	}
+
+	prev := poll.Copy()
+	if errMsg := poll.ApplyEdit(pollEditFromSubmission(poll, request.Submission), p.pf.Millis()); errMsg != nil {
+		return nil, &model.SubmitDialogResponse{Error: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg)}, nil
+	}
+
+	if err = p.Store.Poll().Update(prev, poll); err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	p.ignoreExcludedVoters(poll)
+	model.ParseMessageAttachment(post, poll.ToPostActions(p.bundle, root.Manifest.Id, displayName))
+	if poll.Settings.Progress {
+		post.AddProp("card", poll.ToCard(p.bundle, p.ConvertUserIDToDisplayName))
+	} else {
+		post.DelProp("card")
+	}
+
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	}
+
+	return responseEditPollSuccess, nil, nil
+}
+
+func (p *MatterpollPlugin) handleViewHistory(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.getActiveOrEndedPoll(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return &i18n.LocalizeConfig{DefaultMessage: responseViewHistoryInvalidPermission}, nil, nil
+	}
+	if !poll.HasVoteHistory() {
+		return &i18n.LocalizeConfig{DefaultMessage: responseViewHistoryAnonymous}, nil, nil
+	}
+
+	history, err := p.Store.VoteHistory().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get vote history")
+	}
+
+	user, appErr := p.API.GetUser(request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to get user")
+	}
+
+	message, appErr := history.ToMarkdown(p.bundle, userLocalizer, getLocation(user), p.ConvertUserIDToDisplayName)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to render vote history")
+	}
+	return &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "response.viewHistory.success",
+			Other: "#### Vote history of {{.Question}}\n{{.History}}",
+		},
+		TemplateData: map[string]interface{}{
+			"Question": poll.Question,
+			"History":  message,
+		},
+	}, nil, nil
+}
+
+func (p *MatterpollPlugin) handleRerunPoll(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.getActiveOrEndedPoll(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return &i18n.LocalizeConfig{DefaultMessage: responseRerunPollInvalidPermission}, nil, nil
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/rerun", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.rerunPoll.title",
+				Other: "Run Poll Again",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			IntroductionText: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "dialog.rerunPoll.introductionText",
+					Other: "A new poll with the same question, options and settings is posted. Votes aren't copied. You can also type `/{{.Trigger}} rerun {{.ID}}`.",
+				},
+				TemplateData: map[string]interface{}{
+					"Trigger": p.getConfiguration().Trigger,
+					"ID":      pollID,
+				},
+			}),
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.rerunPoll.submitLabel",
+				Other: "Run",
+			}),
+			Elements: []model.DialogElement{{
+				DisplayName: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+					ID:    "dialog.rerunPoll.channel",
+					Other: "Channel",
+				}),
+				Name:       rerunChannelKey,
+				Type:       "select",
+				DataSource: "channels",
+				Default:    request.ChannelId,
+			}},
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open rerun poll dialog")
+	}
+	return nil, nil, nil
+}
+
+func (p *MatterpollPlugin) handleRerunPollConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.getActiveOrEndedPoll(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return responseRerunPollInvalidPermission, nil, nil
+	}
+
+	channelID, ok := request.Submission[rerunChannelKey].(string)
+	if !ok || channelID == "" {
+		channelID = request.ChannelId
+	}
+	if !p.API.HasPermissionToChannel(request.UserId, channelID, model.PermissionCreatePost) {
+		response := &model.SubmitDialogResponse{
+			Errors: map[string]string{
+				rerunChannelKey: p.bundle.LocalizeDefaultMessage(userLocalizer, responseRerunPollNoPostPermission),
+			},
+		}
+		return nil, response, nil
+	}
+
+	errMsg, err := p.rerunPoll(poll, request.UserId, channelID)
+	if err != nil {
+		return commandErrorGeneric, nil, err
+	}
+	if errMsg != nil {
+		return nil, &model.SubmitDialogResponse{Error: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg)}, nil
+	}
+
+	return responseRerunPollSuccess, nil, nil
+}
+
+// getActiveOrEndedPoll returns the poll for a given id. If there is no active poll with this id, the ended polls are searched.
+func (p *MatterpollPlugin) getActiveOrEndedPoll(id string) (*poll.Poll, error) {
+	activePoll, err := p.Store.Poll().Get(id)
+	if err == nil {
+		return activePoll, nil
+	}
+
+	endedPoll, endedErr := p.Store.Poll().GetEnded(id)
+	if endedErr != nil {
+		return nil, err
+	}
+	return endedPoll, nil
+}
+
+func (p *MatterpollPlugin) handleEndPoll(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return &i18n.LocalizeConfig{DefaultMessage: responseEndPollInvalidPermission}, nil, nil
+	}
+
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+	dialog := model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/end/confirm", root.Manifest.Id, pollID),
+		Dialog: model.Dialog{
+			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.end.title",
+				Other: "Confirm Poll End",
+			}),
+			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+			CallbackId: request.PostId,
+			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "dialog.end.submitLabel",
+				Other: "End",
+			}),
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open end poll dialog")
+	}
+	return nil, nil, nil
+}
+
+func (p *MatterpollPlugin) handleEndPollConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
+	pollID := vars["id"]
+
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+	}
+
+	var postID string
+	if poll.PostID != "" {
+		postID = poll.PostID
+	} else {
+		// Legacy check if polls created without a postID
+		postID = request.CallbackId
+	}
+
+	if err := p.endPoll(poll, postID, request.ChannelId); err != nil {
+		return commandErrorGeneric, nil, err
+	}
+
+	p.cancelEndPoll(poll)
+
+	return nil, nil, nil
+}
+
+// endPoll replaces the poll post with the results, moves the poll to the ended polls and announces the end in the channel.
+// If the poll is tied and the tie-break rule asks for it, a runoff poll between the tied answer options is started.
+// If the poll was ended in the meantime, e.g. manually and by its end time at once, nothing is done.
+func (p *MatterpollPlugin) endPoll(poll *poll.Poll, postID, channelID string) error {
+	if err := p.Store.Poll().End(poll); err != nil {
+		if errors.Is(err, store.ErrPollAlreadyEnded) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to end poll")
+	}
+
+	if err := p.updateEndPollPost(poll, postID, channelID); err != nil {
+		// Reopen the poll, so that ending it can be retried
+		if saveErr := p.Store.Poll().Save(poll); saveErr != nil {
+			p.API.LogWarn("Failed to reopen poll", "pollID", poll.ID, "error", saveErr.Error())
+		} else if deleteErr := p.Store.Poll().DeleteEnded(poll); deleteErr != nil {
+			p.API.LogWarn("Failed to delete ended poll", "pollID", poll.ID, "error", deleteErr.Error())
+		}
+		return err
+	}
+
+	if poll.IsQuiz() {
+		if err := p.addQuizResultsToLeaderboard(poll, channelID); err != nil {
+			p.API.LogWarn("Failed to add quiz results to the leaderboard", "pollID", poll.ID, "error", err.Error())
+		}
+	}
+
+	var runoffPost *model.Post
+	if poll.NeedsRunoff() {
+		runoffPost = p.startRunoff(poll, channelID)
+	}
+
+	p.postEndPollAnnouncement(poll, channelID, postID, runoffPost)
+
+	return nil
+}
+
+// updateEndPollPost replaces the post of a poll with its results and attaches a chart of them.
+func (p *MatterpollPlugin) updateEndPollPost(poll *poll.Poll, postID, channelID string) error {
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	post, appErr := poll.ToEndPollPost(p.bundle, root.Manifest.Id, displayName, p.ConvertUserIDToDisplayName)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to get convert to end poll post")
+	}
+
+	post.Id = postID
+	if fileID := p.uploadResultsChart(poll, channelID); fileID != "" {
+		post.FileIds = model.StringArray{fileID}
+	}
+	if _, appErr = p.API.UpdatePost(post); appErr != nil {
+		return errors.Wrap(appErr, "failed to update post")
+	}
+	return nil
+}
+
+// uploadResultsChart renders the results of an ended poll as chart and uploads it to a given channel.
+// Returns the id of the uploaded file. As the chart is optional, failures are only logged and an empty string is returned.
+func (p *MatterpollPlugin) uploadResultsChart(poll *poll.Poll, channelID string) string {
+	if !poll.HasChart() {
+		return ""
+	}
+
+	chart, err := poll.ToChart(p.bundle, p.ConvertUserIDToDisplayName)
+	if err != nil {
+		p.API.LogWarn("Failed to render results chart", "pollID", poll.ID, "error", err.Error())
+		return ""
+	}
+
+	fileInfo, appErr := p.API.UploadFile(chart, channelID, "results.png")
+	if appErr != nil {
+		p.API.LogWarn("Failed to upload results chart", "pollID", poll.ID, "error", appErr.Error())
+		return ""
+	}
+	return fileInfo.Id
+}
+
+// startRunoff posts a runoff poll between the tied answer options of an ended poll and returns its post.
+// As the poll has already ended at this point, failures are only logged and nil is returned.
+func (p *MatterpollPlugin) startRunoff(endedPoll *poll.Poll, channelID string) *model.Post {
+	runoff, errMsg := p.pf.Runoff(endedPoll)
+	if errMsg != nil {
+		p.API.LogWarn("Failed to create runoff poll", "pollID", endedPoll.ID, "error", errMsg.Message.Other)
+		return nil
+	}
+
+	post, err := p.createPollPost(runoff, channelID, "")
+	if err != nil {
+		p.API.LogWarn("Failed to post runoff poll", "pollID", endedPoll.ID, "error", err.Error())
+		return nil
+	}
+	return post
+}
+
+func (p *MatterpollPlugin) handleBreakTie(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
+	pollID := vars["id"]
+	optionNumber, _ := strconv.Atoi(vars["optionNumber"])
+
+	poll, err := p.Store.Poll().GetEnded(pollID)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get ended poll")
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
+	if appErr != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	}
+	if !canManagePoll {
+		return &i18n.LocalizeConfig{DefaultMessage: responseBreakTieInvalidPermission}, nil, nil
+	}
+
+	msg, err := poll.BreakTie(optionNumber)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to break tie")
+	}
+	if msg != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: msg}, nil, nil
+	}
+
+	if err := p.Store.Poll().SaveEnded(poll); err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to save ended poll")
+	}
+
+	if err := p.updateEndPollPost(poll, poll.PostID, request.ChannelId); err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, err
+	}
+
+	return &i18n.LocalizeConfig{
+		DefaultMessage: responseBreakTieSuccess,
+		TemplateData:   map[string]interface{}{"Answer": poll.TieBreakWinner},
+	}, p.makeEndPollAnnouncement(poll, request.ChannelId, poll.PostID, nil), nil
+}
+
+// addQuizResultsToLeaderboard scores the answers of an ended quiz on the leaderboard of the channel it was posted in.
+func (p *MatterpollPlugin) addQuizResultsToLeaderboard(poll *poll.Poll, channelID string) error {
+	if err := p.Store.Leaderboard().AddQuizResults(channelID, poll); err != nil {
+		return errors.Wrap(err, "failed to add quiz results to leaderboard")
 	}
+	return nil
+}
 
-	return responseAddOptionSuccess, nil, nil
+// saveVotes stores a poll, whose votes were changed by a given user, and adds the change to the vote history of the poll.
+// Users, who got a spot from a waitlist because of the change, are added to the history as well.
+func (p *MatterpollPlugin) saveVotes(prev, updated *poll.Poll, userID string) error {
+	return p.saveVotesByProxy(prev, updated, userID, "")
 }
 
-func (p *MatterpollPlugin) handleEndPoll(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
-	pollID := vars["id"]
-	userLocalizer := p.bundle.GetUserLocalizer(request.UserId)
+// saveVotesByProxy stores the changed votes of a given user like saveVotes.
+// A non-empty proxyID marks the vote event of the user as cast by the proxy.
+func (p *MatterpollPlugin) saveVotesByProxy(prev, updated *poll.Poll, userID, proxyID string) error {
+	if !updated.HasVoteHistory() {
+		return p.Store.Poll().Update(prev, updated)
+	}
 
-	poll, err := p.Store.Poll().Get(pollID)
-	if err != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to get poll")
+	userIDs := []string{userID}
+	for _, promotion := range updated.GetPromotions(prev) {
+		userIDs = append(userIDs, promotion.UserID)
 	}
 
-	canManagePoll, appErr := p.CanManagePoll(poll, request.UserId)
-	if appErr != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to check permission")
+	now := p.pf.Millis()
+	for _, id := range userIDs {
+		updated.UpdateVoteTimes(prev, id, now)
 	}
-	if !canManagePoll {
-		return &i18n.LocalizeConfig{DefaultMessage: responseEndPollInvalidPermission}, nil, nil
+	if err := p.Store.Poll().Update(prev, updated); err != nil {
+		return err
 	}
 
-	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
-	dialog := model.OpenDialogRequest{
-		TriggerId: request.TriggerId,
-		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/end/confirm", root.Manifest.Id, pollID),
-		Dialog: model.Dialog{
-			Title: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
-				ID:    "dialog.end.title",
-				Other: "Confirm Poll End",
-			}),
-			IconURL:    fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
-			CallbackId: request.PostId,
-			SubmitLabel: p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
-				ID:    "dialog.end.submitLabel",
-				Other: "End",
-			}),
-		},
+	for _, id := range userIDs {
+		event := poll.NewVoteEvent(id, prev.GetVoteSummary(id), updated.GetVoteSummary(id), now)
+		if event == nil {
+			continue
+		}
+		if id == userID {
+			event.ProxyID = proxyID
+		}
+		if err := p.Store.VoteHistory().Append(updated.ID, event); err != nil {
+			p.API.LogWarn("Failed to add vote event to the history", "pollID", updated.ID, "error", err.Error())
+		}
 	}
+	return nil
+}
 
-	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
-		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(appErr, "failed to open end poll dialog")
+// joinWaitlist adds a given user to the waitlist of the full answer option with a given index.
+func (p *MatterpollPlugin) joinWaitlist(poll *poll.Poll, userID string, index int) (*i18n.LocalizeConfig, *model.Post, error) {
+	prev := poll.Copy()
+	position, err := poll.JoinWaitlist(userID, index)
+	if err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to join waitlist")
+	}
+	if err := p.Store.Poll().Update(prev, poll); err != nil {
+		return &i18n.LocalizeConfig{DefaultMessage: commandErrorGeneric}, nil, errors.Wrap(err, "failed to save poll")
 	}
-	return nil, nil, nil
-}
 
-func (p *MatterpollPlugin) handleEndPollConfirm(vars map[string]string, request *model.SubmitDialogRequest) (*i18n.Message, *model.SubmitDialogResponse, error) {
-	pollID := vars["id"]
+	return &i18n.LocalizeConfig{
+		DefaultMessage: responseVoteWaitlisted,
+		TemplateData: map[string]interface{}{
+			"Option":   poll.AnswerOptions[index].Answer,
+			"Position": position,
+		},
+	}, nil, nil
+}
 
-	poll, err := p.Store.Poll().Get(pollID)
-	if err != nil {
-		return commandErrorGeneric, nil, errors.Wrap(err, "failed to get poll")
+// notifyPromotions sends a direct message to every user, who got a spot from a waitlist since a given previous state of a poll.
+func (p *MatterpollPlugin) notifyPromotions(prev, updated *poll.Poll) {
+	for _, promotion := range updated.GetPromotions(prev) {
+		err := p.sendDirectMessage(promotion.UserID, &i18n.LocalizeConfig{
+			DefaultMessage: responseWaitlistPromoted,
+			TemplateData: map[string]interface{}{
+				"Question": updated.Question,
+				"Option":   promotion.Answer,
+				"Link":     p.getPermalink(updated.PostID),
+			},
+		})
+		if err != nil {
+			p.API.LogWarn("Failed to notify promoted user", "error", err.Error())
+		}
 	}
+}
 
-	displayName, appErr := p.ConvertCreatorIDToDisplayName(poll.Creator)
+// sendDirectMessage sends a given message from the bot to a given user in the locale of the user.
+func (p *MatterpollPlugin) sendDirectMessage(userID string, message *i18n.LocalizeConfig) error {
+	channel, appErr := p.API.GetDirectChannel(userID, p.botUserID)
 	if appErr != nil {
-		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get display name for creator")
+		return errors.Wrap(appErr, "failed to get direct channel")
 	}
 
-	post, appErr := poll.ToEndPollPost(p.bundle, displayName, p.ConvertUserIDToDisplayName)
-	if appErr != nil {
-		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to get convert to end poll post")
+	userLocalizer := p.bundle.GetUserLocalizer(userID)
+	post := &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: channel.Id,
+		Message:   p.bundle.LocalizeWithConfig(userLocalizer, message),
 	}
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return errors.Wrap(appErr, "failed to create post")
+	}
+	return nil
+}
 
-	var postID string
-	if poll.PostID != "" {
-		postID = poll.PostID
-	} else {
-		// Legacy check if polls created without a postID
-		postID = request.CallbackId
+// getPermalink returns the link to a given post.
+func (p *MatterpollPlugin) getPermalink(postID string) string {
+	return fmt.Sprintf("%s/_redirect/pl/%s", *p.ServerConfig.ServiceSettings.SiteURL, postID)
+}
+
+// closePollIfComplete ends a poll with the close-at or close-when-all-voted setting once enough users voted.
+// It returns true if the poll has been ended.
+func (p *MatterpollPlugin) closePollIfComplete(poll *poll.Poll, postID, channelID string) (bool, error) {
+	if !poll.ClosesAutomatically() {
+		return false, nil
 	}
 
-	post.Id = postID
-	if _, appErr = p.API.UpdatePost(post); appErr != nil {
-		return commandErrorGeneric, nil, errors.Wrap(appErr, "failed to update post")
+	complete := poll.Settings.CloseAt > 0 && len(poll.GetVoters()) >= poll.Settings.CloseAt
+	if !complete && poll.Settings.CloseWhenAllVoted {
+		var err error
+		if complete, err = p.allChannelMembersVoted(poll, channelID); err != nil {
+			return false, err
+		}
+	}
+	if !complete {
+		return false, nil
 	}
 
-	if err := p.Store.Poll().Delete(poll); err != nil {
-		return commandErrorGeneric, nil, errors.Wrap(err, "failed to delete poll")
+	if err := p.endPoll(poll, postID, channelID); err != nil {
+		return false, err
 	}
+	p.cancelEndPoll(poll)
+	return true, nil
+}
 
-	p.postEndPollAnnouncement(request.ChannelId, post.Id, poll.Question)
+// allChannelMembersVoted returns true if every member of a channel has voted in a poll. Bots, deactivated users and excluded guests are ignored.
+func (p *MatterpollPlugin) allChannelMembersVoted(poll *poll.Poll, channelID string) (bool, error) {
+	for page := 0; ; page++ {
+		users, appErr := p.API.GetUsersInChannel(channelID, model.ChannelSortByUsername, page, channelMembersPerPage)
+		if appErr != nil {
+			return false, errors.Wrap(appErr, "failed to get channel members")
+		}
+		for _, user := range users {
+			if user.IsBot || user.DeleteAt != 0 || p.isExcluded(poll, user) {
+				continue
+			}
+			if !poll.HasVoted(poll.VoterID(p.getSecretBallotKey(), user.Id)) {
+				return false, nil
+			}
+		}
+		if len(users) < channelMembersPerPage {
+			return true, nil
+		}
+	}
+}
 
-	return nil, nil, nil
+// postEndPollAnnouncement announces the end of a poll in the thread of its post.
+func (p *MatterpollPlugin) postEndPollAnnouncement(poll *poll.Poll, channelID, postID string, runoffPost *model.Post) {
+	endPost := p.makeEndPollAnnouncement(poll, channelID, postID, runoffPost)
+	if _, err := p.API.CreatePost(endPost); err != nil {
+		p.API.LogWarn("Failed to post the end poll announcement", "details", "failed to CreatePost", "error", err.Error())
+	}
 }
 
-func (p *MatterpollPlugin) postEndPollAnnouncement(channelID, postID, question string) {
+// makeEndPollAnnouncement returns the announcement of the end of a poll, which names the winners and describes how a tie gets broken.
+// If the creator has to break a tie, it contains a button for every tied answer option.
+func (p *MatterpollPlugin) makeEndPollAnnouncement(poll *poll.Poll, channelID, postID string, runoffPost *model.Post) *model.Post {
+	localizer := p.bundle.GetServerLocalizer()
+	siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+
+	lines := []string{p.bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+		DefaultMessage: responseEndPollSuccessfully,
+		TemplateData: map[string]interface{}{
+			"Question": poll.Question,
+			"Link":     fmt.Sprintf("%s/_redirect/pl/%s", siteURL, postID),
+		}})}
+
+	winners := poll.GetWinners()
+	if len(winners) > 0 {
+		answers := make([]string, 0, len(winners))
+		for _, i := range winners {
+			answers = append(answers, "**"+poll.AnswerOptions[i].Answer+"**")
+		}
+		lines = append(lines, p.bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: responseEndPollWinner,
+			TemplateData:   map[string]interface{}{"Answers": strings.Join(answers, ", ")},
+			PluralCount:    len(winners),
+		}))
+	}
+	switch {
+	case poll.IsTieBroken():
+		lines = append(lines, p.bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: responseEndPollTieBroken}))
+	case poll.NeedsTieBreak():
+		lines = append(lines, p.bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: responseEndPollTieBreak}))
+	case runoffPost != nil:
+		lines = append(lines, p.bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: responseEndPollRunoff,
+			TemplateData:   map[string]interface{}{"Link": fmt.Sprintf("%s/_redirect/pl/%s", siteURL, runoffPost.Id)},
+		}))
+	}
+
 	endPost := &model.Post{
 		UserId:    p.botUserID,
 		ChannelId: channelID,
 		RootId:    postID,
-		Message: p.bundle.LocalizeWithConfig(p.bundle.GetServerLocalizer(), &i18n.LocalizeConfig{
-			DefaultMessage: responseEndPollSuccessfully,
-			TemplateData: map[string]interface{}{
-				"Question": question,
-				"Link":     fmt.Sprintf("%s/_redirect/pl/%s", *p.ServerConfig.ServiceSettings.SiteURL, postID),
-			}}),
-		Type: model.PostTypeDefault,
-	}
-
-	if _, err := p.API.CreatePost(endPost); err != nil {
-		p.API.LogWarn("Failed to post the end poll announcement", "details", "failed to CreatePost", "error", err.Error())
+		Message:   strings.Join(lines, "\n"),
+		Type:      model.PostTypeDefault,
+	}
+
+	if poll.NeedsTieBreak() {
+		actions := make([]*model.PostAction, 0, len(winners))
+		for _, i := range winners {
+			actions = append(actions, &model.PostAction{
+				Id:   fmt.Sprintf("breakTie%d", i),
+				Name: poll.AnswerOptions[i].Answer,
+				Type: model.PostActionTypeButton,
+				Integration: &model.PostActionIntegration{
+					URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/tiebreak/%d", root.Manifest.Id, poll.ID, i),
+				},
+			})
+		}
+		model.ParseMessageAttachment(endPost, []*model.MessageAttachment{{Actions: actions}})
 	}
+	return endPost
 }
 
 func (p *MatterpollPlugin) handleDeletePoll(vars map[string]string, request *model.PostActionIntegrationRequest) (*i18n.LocalizeConfig, *model.Post, error) {
@@ -759,6 +3284,11 @@ func (p *MatterpollPlugin) handleDeletePollConfirm(vars map[string]string, reque
 		return commandErrorGeneric, nil, errors.Wrap(err, "failed to delete poll")
 	}
 
+	p.cancelEndPoll(poll)
+	if err := p.Store.VoteHistory().Delete(poll.ID); err != nil {
+		p.API.LogWarn("Failed to delete the vote history", "pollID", poll.ID, "error", err.Error())
+	}
+
 	return responseDeletePollSuccess, nil, nil
 }
 
@@ -779,9 +3309,291 @@ func (p *MatterpollPlugin) handlePollMetadata(w http.ResponseWriter, r *http.Req
 		p.API.LogWarn("Failed to check permission", "userID", userID, "error", appErr.Error())
 		canManagePoll = false
 	}
-	metadata := poll.GetMetadata(userID, canManagePoll)
+	metadata := poll.GetMetadata(poll.VoterID(p.getSecretBallotKey(), userID), canManagePoll)
+	metadata.UserID = userID
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(metadata); err != nil {
 		p.API.LogWarn("failed to write response", "error", err.Error())
 	}
 }
+
+func (p *MatterpollPlugin) handleVoteHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pollID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	poll, err := p.getActiveOrEndedPoll(pollID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		p.API.LogWarn("failed to get poll", "error", err.Error())
+		return
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to check permission", "userID", userID, "error", appErr.Error())
+		canManagePoll = false
+	}
+	if !canManagePoll || !poll.HasVoteHistory() {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	history, err := p.Store.VoteHistory().Get(pollID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		p.API.LogWarn("failed to get vote history", "error", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		p.API.LogWarn("failed to write response", "error", err.Error())
+	}
+}
+
+func (p *MatterpollPlugin) handleExportPoll(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pollID := vars["id"]
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	poll, err := p.getActiveOrEndedPoll(pollID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		p.API.LogWarn("failed to get poll", "error", err.Error())
+		return
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(poll, userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to check permission", "userID", userID, "error", appErr.Error())
+		canManagePoll = false
+	}
+	if !canManagePoll || !poll.CanExport() {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data, err := poll.ToCSV(p.bundle, p.ConvertUserIDToNames)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		p.API.LogWarn("failed to convert poll to CSV", "error", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFilename(poll)))
+	if _, err := w.Write(data); err != nil {
+		p.API.LogWarn("failed to write response", "error", err.Error())
+	}
+}
+
+// importResult is the response of an import of polls. Errors contains the polls, that couldn't be imported.
+type importResult struct {
+	Imported int            `json:"imported"`
+	Errors   []*importError `json:"errors"`
+}
+
+type importError struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+func (p *MatterpollPlugin) handleExportPolls(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	isAdmin, appErr := p.IsSystemAdmin(userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to check permission", "userID", userID, "error", appErr.Error())
+		isAdmin = false
+	}
+	if !isAdmin {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	activePolls, err := p.Store.Poll().List()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		p.API.LogWarn("failed to list polls", "error", err.Error())
+		return
+	}
+	endedPolls, err := p.Store.Poll().ListEnded()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		p.API.LogWarn("failed to list ended polls", "error", err.Error())
+		return
+	}
+
+	key := p.getSecretBallotKey()
+	archive := &poll.Archive{
+		Version:           poll.ArchiveVersion,
+		SecretBallotKeyID: poll.SecretBallotKeyID(key),
+		Polls:             []*poll.ArchivedPoll{},
+	}
+	for _, activePoll := range activePolls {
+		if archived := p.archivePoll(activePoll, false, key); archived != nil {
+			archive.Polls = append(archive.Polls, archived)
+		}
+	}
+	for _, endedPoll := range endedPolls {
+		if archived := p.archivePoll(endedPoll, true, key); archived != nil {
+			archive.Polls = append(archive.Polls, archived)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="matterpoll-export.json"`)
+	if err := json.NewEncoder(w).Encode(archive); err != nil {
+		p.API.LogWarn("failed to write response", "error", err.Error())
+	}
+}
+
+// archivePoll converts a given poll into its archive format, in which users are referred to by their username.
+// It returns nil if a user of the poll doesn't exist anymore, as the poll couldn't be imported.
+func (p *MatterpollPlugin) archivePoll(poll *poll.Poll, ended bool, key []byte) *poll.ArchivedPoll {
+	archived := poll.ToArchive(p.getPollChannelID(poll), ended, key)
+	if errMsg := archived.ReplaceUsers(p.ConvertUserIDToUsername); errMsg != nil {
+		p.API.LogWarn("skipping poll with unknown user", "pollID", poll.ID, "error", errMsg.Message.Other)
+		return nil
+	}
+	return archived
+}
+
+// getPollChannelID returns the id of the channel a given poll was posted in.
+// It returns an empty string if the post of the poll doesn't exist anymore.
+func (p *MatterpollPlugin) getPollChannelID(poll *poll.Poll) string {
+	post, appErr := p.API.GetPost(poll.PostID)
+	if appErr != nil {
+		p.API.LogWarn("failed to get post of poll", "pollID", poll.ID, "error", appErr.Error())
+		return ""
+	}
+	return post.ChannelId
+}
+
+func (p *MatterpollPlugin) handleImportPolls(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	isAdmin, appErr := p.IsSystemAdmin(userID)
+	if appErr != nil {
+		p.API.LogWarn("Failed to check permission", "userID", userID, "error", appErr.Error())
+		isAdmin = false
+	}
+	if !isAdmin {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var archive poll.Archive
+	if err := json.NewDecoder(r.Body).Decode(&archive); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		p.API.LogWarn("failed to decode archive", "error", err.Error())
+		return
+	}
+	if archive.Version != poll.ArchiveVersion {
+		w.WriteHeader(http.StatusBadRequest)
+		p.API.LogWarn("unsupported archive version", "version", strconv.Itoa(archive.Version))
+		return
+	}
+	createPosts := r.URL.Query().Get("create_posts") == "true"
+
+	// Voter tokens only identify the voters on this server, if they were derived with the same key
+	sameKey := archive.SecretBallotKeyID == poll.SecretBallotKeyID(p.getSecretBallotKey())
+
+	userLocalizer := p.bundle.GetUserLocalizer(userID)
+	result := &importResult{Errors: []*importError{}}
+	for _, archived := range archive.Polls {
+		if errMsg := archived.ReplaceUsers(p.ConvertUsernameToUserID); errMsg != nil {
+			result.Errors = append(result.Errors, &importError{ID: archived.ID, Error: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg)})
+			continue
+		}
+		importedPoll, errMsg := archived.ToPoll(sameKey)
+		if errMsg != nil {
+			result.Errors = append(result.Errors, &importError{ID: archived.ID, Error: p.bundle.LocalizeErrorMessage(userLocalizer, errMsg)})
+			continue
+		}
+
+		if err := p.importPoll(importedPoll, archived.ChannelID, archived.Ended, createPosts); err != nil {
+			p.API.LogWarn("failed to import poll", "pollID", archived.ID, "error", err.Error())
+			result.Errors = append(result.Errors, &importError{ID: archived.ID, Error: err.Error()})
+			continue
+		}
+		result.Imported++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		p.API.LogWarn("failed to write response", "error", err.Error())
+	}
+}
+
+// importPoll stores an imported poll. If createPosts is true and the channel of the poll is known, the post of the poll is created again.
+// Otherwise the poll keeps the id of its original post, if the post exists on this server. Running polls can't be imported without a post.
+func (p *MatterpollPlugin) importPoll(importedPoll *poll.Poll, channelID string, ended, createPosts bool) error {
+	if _, err := p.getActiveOrEndedPoll(importedPoll.ID); err == nil {
+		return errors.New("poll already exists")
+	}
+	createPost := createPosts && channelID != ""
+	if !createPost && importedPoll.PostID != "" {
+		if _, appErr := p.API.GetPost(importedPoll.PostID); appErr != nil {
+			// The poll was posted on another server
+			importedPoll.PostID = ""
+		}
+	}
+	if !ended && !createPost && importedPoll.PostID == "" {
+		return errors.New("post of running poll doesn't exist on this server, import it with create_posts=true")
+	}
+
+	if !ended {
+		if createPost {
+			_, err := p.createPollPost(importedPoll, channelID, "")
+			return err
+		}
+		if err := p.Store.Poll().Insert(importedPoll); err != nil {
+			return errors.Wrap(err, "failed to save poll")
+		}
+		return p.scheduleEndPoll(importedPoll)
+	}
+
+	if createPost {
+		postID, err := p.createEndPollPost(importedPoll, channelID)
+		if err != nil {
+			return err
+		}
+		importedPoll.PostID = postID
+	}
+	if err := p.Store.Poll().Insert(importedPoll); err != nil {
+		return errors.Wrap(err, "failed to save poll")
+	}
+	if err := p.Store.Poll().End(importedPoll); err != nil {
+		return errors.Wrap(err, "failed to end poll")
+	}
+	return nil
+}
+
+// createEndPollPost posts the results of an ended poll in a given channel and returns the id of the new post.
+func (p *MatterpollPlugin) createEndPollPost(endedPoll *poll.Poll, channelID string) (string, error) {
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(endedPoll.Creator)
+	if appErr != nil {
+		return "", errors.Wrap(appErr, "failed to get display name for creator")
+	}
+
+	post, appErr := endedPoll.ToEndPollPost(p.bundle, root.Manifest.Id, displayName, p.ConvertUserIDToDisplayName)
+	if appErr != nil {
+		return "", errors.Wrap(appErr, "failed to get convert to end poll post")
+	}
+
+	post.UserId = p.botUserID
+	post.ChannelId = channelID
+	post.Type = MatterpollPostType
+	post.AddProp("poll_id", endedPoll.ID)
+	if fileID := p.uploadResultsChart(endedPoll, channelID); fileID != "" {
+		post.FileIds = model.StringArray{fileID}
+	}
+
+	rPost, appErr := p.API.CreatePost(post)
+	if appErr != nil {
+		return "", errors.Wrap(appErr, "failed to create end poll post")
+	}
+	return rPost.Id, nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,6 +21,7 @@ import (
 
 	root "github.com/matterpoll/matterpoll"
 	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/store"
 	"github.com/matterpoll/matterpoll/server/store/mockstore"
 	"github.com/matterpoll/matterpoll/server/utils/testutils"
 )
@@ -229,6 +231,7 @@ func TestHandleCreatePoll(t *testing.T) {
 	model.ParseMessageAttachment(expectedPostTwoOptions, pollWithTwoOptions.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
 	pollWithSettings := testutils.GetPollWithSettings(poll.Settings{Progress: true, Anonymous: true, PublicAddOption: true, MaxVotes: 3})
+	pollWithSettings.SecretBallot = true
 	expectedPostWithSettings := &model.Post{
 		UserId:    testutils.GetBotUserID(),
 		ChannelId: channelID,
@@ -362,6 +365,115 @@ func TestHandleCreatePoll(t *testing.T) {
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
+		"Valid request, from template": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+
+				rPost := expectedPost.Clone()
+				rPost.Id = "postID1"
+				api.On("CreatePost", expectedPost).Return(rPost, nil)
+
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				template := &poll.Template{ID: "templateID1", Name: "standup", TeamID: "teamID1", Poll: testutils.GetPollWithoutPostID()}
+				template.Poll.Creator = "userID2"
+				store.TemplateStore.On("Get", "templateID1").Return(template, nil)
+				store.PollStore.On("Insert", expectedPoll).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				TeamId:     "teamID1",
+				Submission: map[string]interface{}{
+					"template": "templateID1",
+					"question": nil,
+					"option1":  nil,
+					"option2":  nil,
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Invalid request, neither template nor question set": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"template": nil,
+					"question": nil,
+					"option1":  expectedPoll.AnswerOptions[0].Answer,
+					"option2":  nil,
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{
+					"question": "This field is required, unless you choose a template.",
+					"option2":  "This field is required, unless you choose a template.",
+				},
+			},
+			ExpectedMsg: "",
+		},
+		"Invalid request, template of other user": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				template := &poll.Template{ID: "templateID1", Name: "standup", Poll: testutils.GetPollWithoutPostID()}
+				template.Poll.Creator = "userID2"
+				store.TemplateStore.On("Get", "templateID1").Return(template, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				TeamId:     "teamID1",
+				Submission: map[string]interface{}{
+					"template": "templateID1",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, template not found": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("Get", "templateID1").Return(nil, &model.AppError{})
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				TeamId:     "teamID1",
+				Submission: map[string]interface{}{
+					"template": "templateID1",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
 		"Invalid request, option 1 not set": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
@@ -497,6 +609,32 @@ func TestHandleCreatePoll(t *testing.T) {
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
+		"Invalid request, invalid end setting": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"question":    expectedPoll.Question,
+					"option1":     expectedPoll.AnswerOptions[0].Answer,
+					"option2":     expectedPoll.AnswerOptions[1].Answer,
+					"setting-end": "someday",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{
+					"setting-end": `Invalid end time "someday". Use a duration like "90m", "2h" or "3d", or a time like "2024-12-24T18:00" in your time zone.`,
+				},
+			},
+			ExpectedMsg: "",
+		},
 		"Invalid request, without permission to read channel": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(false)
@@ -578,6 +716,153 @@ func TestHandleCreatePoll(t *testing.T) {
 	}
 }
 
+func TestHandleCreateSurvey(t *testing.T) {
+	userID := "userID1"
+	channelID := model.NewId()
+	rootID := model.NewId()
+
+	expectedSurvey := testutils.GetSurveyWithAnswers()
+	for _, q := range expectedSurvey.Questions {
+		for _, o := range q.AnswerOptions {
+			o.Voter = []string{}
+		}
+	}
+	expectedPost := &model.Post{
+		UserId:    testutils.GetBotUserID(),
+		ChannelId: channelID,
+		RootId:    rootID,
+		Type:      MatterpollPostType,
+		Props: model.StringInterface{
+			"poll_id": testutils.GetPollID(),
+		},
+	}
+	model.ParseMessageAttachment(expectedPost, expectedSurvey.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+	questions := "\"Question 1\" \"Answer 1\" \"Answer 2\"\n\"Question 2\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --votes=2 --anonymous\n"
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+
+				rPost := expectedPost.Clone()
+				rPost.Id = "postID1"
+				api.On("CreatePost", expectedPost).Return(rPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Insert", expectedSurvey).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"title":     "Survey",
+					"questions": questions,
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Invalid request, invalid question": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"title":     "Survey",
+					"questions": "\"Question 1\" \"Answer 1\" \"Answer 2\" --ranked",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{
+					"questions": "The questions of a survey only support the anonymous and votes setting. Invalid question: Question 1",
+				},
+			},
+			ExpectedMsg: "",
+		},
+		"Invalid request, questions missing": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: rootID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"title": "Survey",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetNewID(testutils.GetPollID)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := "/api/v1/polls/survey/create"
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+		})
+	}
+}
+
 func TestHandleVote(t *testing.T) {
 	converter := func(userID string) (string, *model.AppError) {
 		switch userID {
@@ -612,11 +897,22 @@ func TestHandleVote(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
 	})
 
+	restrictedSettings := poll.Settings{MaxVotes: 1, Voters: &poll.Voters{Names: []string{"user1"}}}
+	restrictedIn := testutils.GetPollWithSettings(restrictedSettings)
+	restrictedOut := restrictedIn.Copy()
+	msg, err := restrictedOut.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	restrictedOut.UpdateVoteTimes(restrictedIn, "userID1", testutils.GetMillis())
+	expectedRestrictedPost := &model.Post{}
+	model.ParseMessageAttachment(expectedRestrictedPost, restrictedOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
 	poll1In := testutils.GetPoll()
 	poll1Out := poll1In.Copy()
-	msg, err := poll1Out.UpdateVote("userID1", 0)
+	msg, err = poll1Out.UpdateVote("userID1", 0)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll1Out.UpdateVoteTimes(poll1In, "userID1", testutils.GetMillis())
 	expectedPost1 := &model.Post{}
 	model.ParseMessageAttachment(expectedPost1, poll1Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
@@ -628,6 +924,7 @@ func TestHandleVote(t *testing.T) {
 	msg, err = poll2Out.UpdateVote("userID1", 1)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll2Out.UpdateVoteTimes(poll2In, "userID1", testutils.GetMillis())
 	expectedPost2 := &model.Post{}
 	model.ParseMessageAttachment(expectedPost2, poll2Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
@@ -636,6 +933,7 @@ func TestHandleVote(t *testing.T) {
 	msg, err = poll3Out.UpdateVote("userID2", 0)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll3Out.UpdateVoteTimes(poll3In, "userID2", testutils.GetMillis())
 	expectedPost3 := &model.Post{}
 	model.ParseMessageAttachment(expectedPost3, poll3Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
@@ -647,6 +945,7 @@ func TestHandleVote(t *testing.T) {
 	msg, err = poll4Out.UpdateVote("userID1", 1)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll4Out.UpdateVoteTimes(poll4In, "userID1", testutils.GetMillis())
 	expectedPost4 := &model.Post{}
 	model.ParseMessageAttachment(expectedPost4, poll4Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
@@ -663,6 +962,7 @@ func TestHandleVote(t *testing.T) {
 	msg, err = poll6Out.UpdateVote("userID2", 1)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll6Out.UpdateVoteTimes(poll6In, "userID2", testutils.GetMillis())
 	expectedPost6 := &model.Post{}
 	model.ParseMessageAttachment(expectedPost6, poll6Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
@@ -674,6 +974,7 @@ func TestHandleVote(t *testing.T) {
 	msg, err = poll7Out.UpdateVote("userID1", 1)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll7Out.UpdateVoteTimes(poll7In, "userID1", testutils.GetMillis())
 	expectedPost7 := &model.Post{}
 	expectedPost7.AddProp("card", poll7Out.ToCard(testutils.GetBundle(), converter))
 	model.ParseMessageAttachment(expectedPost7, poll7Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
@@ -683,19 +984,123 @@ func TestHandleVote(t *testing.T) {
 	msg, err = poll8Out.UpdateVote("userID2", 0)
 	require.Nil(t, msg)
 	require.Nil(t, err)
+	poll8Out.UpdateVoteTimes(poll8In, "userID2", testutils.GetMillis())
 	expectedPost8 := &model.Post{}
 	model.ParseMessageAttachment(expectedPost8, poll8Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
-	post := &model.Post{
-		ChannelId: "channelID1",
+	pointsPollOut := testutils.GetPointsPollWithVotes()
+	msg, err = pointsPollOut.UpdateVote("userID2", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	pointsPollOut.UpdateVoteTimes(testutils.GetPointsPollWithVotes(), "userID2", testutils.GetMillis())
+	expectedPointsPost := &model.Post{}
+	model.ParseMessageAttachment(expectedPointsPost, pointsPollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	quizCorrectOut := testutils.GetQuizPollWithVotes()
+	msg, err = quizCorrectOut.UpdateVote("userID4", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	quizCorrectOut.UpdateVoteTimes(testutils.GetQuizPollWithVotes(), "userID4", testutils.GetMillis())
+	expectedQuizCorrectPost := &model.Post{}
+	model.ParseMessageAttachment(expectedQuizCorrectPost, quizCorrectOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	quizWrongOut := testutils.GetQuizPollWithVotes()
+	msg, err = quizWrongOut.UpdateVote("userID4", 2)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	quizWrongOut.UpdateVoteTimes(testutils.GetQuizPollWithVotes(), "userID4", testutils.GetMillis())
+	expectedQuizWrongPost := &model.Post{}
+	model.ParseMessageAttachment(expectedQuizWrongPost, quizWrongOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	rankedOptions := []*model.PostActionOptions{
+		{Text: "Answer 1", Value: "0"},
+		{Text: "Answer 2", Value: "1"},
+		{Text: "Answer 3", Value: "2"},
+	}
+	rankingDialog := func(defaults ...string) model.OpenDialogRequest {
+		elements := []model.DialogElement{}
+		for i := 0; i < 3; i++ {
+			var defaultValue string
+			if i < len(defaults) {
+				defaultValue = defaults[i]
+			}
+			elements = append(elements, model.DialogElement{
+				DisplayName: fmt.Sprintf("Choice %d", i+1),
+				Name:        fmt.Sprintf("rank%d", i+1),
+				Type:        "select",
+				Options:     rankedOptions,
+				Default:     defaultValue,
+				Optional:    i != 0,
+			})
+		}
+		return model.OpenDialogRequest{
+			TriggerId: "triggerID1",
+			URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/rank", root.Manifest.Id, testutils.GetPollID()),
+			Dialog: model.Dialog{
+				Title:            "Rank Options",
+				IntroductionText: "Rank the options from most to least preferred. You don't have to rank all of them.",
+				IconURL:          fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+				CallbackId:       "postID1",
+				SubmitLabel:      "Vote",
+				Elements:         elements,
+			},
+		}
 	}
 
-	for name, test := range map[string]struct {
-		SetupAPI           func(*plugintest.API) *plugintest.API
-		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.PostActionIntegrationRequest
-		VoteIndex          int
-		ExpectedStatusCode int
+	availabilityOptions := []*model.PostActionOptions{
+		{Text: "Yes", Value: "yes"},
+		{Text: "Maybe", Value: "maybe"},
+		{Text: "No", Value: "no"},
+	}
+	availabilityDialog := func(defaults ...string) model.OpenDialogRequest {
+		elements := []model.DialogElement{}
+		for i := 0; i < 3; i++ {
+			elements = append(elements, model.DialogElement{
+				DisplayName: fmt.Sprintf("Answer %d", i+1),
+				Name:        fmt.Sprintf("availability%d", i),
+				Type:        "radio",
+				Options:     availabilityOptions,
+				Default:     defaults[i],
+				Optional:    true,
+			})
+		}
+		return model.OpenDialogRequest{
+			TriggerId: "triggerID1",
+			URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/availability", root.Manifest.Id, testutils.GetPollID()),
+			Dialog: model.Dialog{
+				Title:       "Your Availability",
+				IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+				CallbackId:  "postID1",
+				SubmitLabel: "Save",
+				Elements:    elements,
+			},
+		}
+	}
+
+	fullPollIn := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1})
+	fullPollIn.AnswerOptions[0].Capacity = 1
+	msg, err = fullPollIn.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	waitlistPollIn := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Waitlist: true})
+	waitlistPollIn.AnswerOptions[0].Capacity = 1
+	msg, err = waitlistPollIn.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	waitlistPollOut := waitlistPollIn.Copy()
+	_, err = waitlistPollOut.JoinWaitlist("userID2", 0)
+	require.Nil(t, err)
+
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		VoteIndex          int
+		ExpectedStatusCode int
 		ExpectedResponse   *model.PostActionIntegrationResponse
 		ExpectedMsg        string
 	}{
@@ -705,18 +1110,21 @@ func TestHandleVote(t *testing.T) {
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"voted_answers":             []string{"Answer 1"},
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"can_manage_poll":           true,
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"voted_answers":              []string{"Answer 1"},
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"can_manage_poll":            true,
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
 				store.PollStore.On("Update", poll1In, poll1Out).Return(nil)
+				store.VoteHistoryStore.On("Append", testutils.GetPollID(), &poll.VoteEvent{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1"}, At: testutils.GetMillis()}).Return(nil)
 				return store
 			},
 			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
@@ -730,12 +1138,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"voted_answers":             []string{"Answer 1"},
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"can_manage_poll":           true,
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"voted_answers":              []string{"Answer 1"},
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"can_manage_poll":            true,
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
 				return api
 			},
@@ -761,12 +1171,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"can_manage_poll":           false,
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID2",
-					"voted_answers":             []string{"Answer 1"},
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"can_manage_poll":            false,
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID2",
+					"voted_answers":              []string{"Answer 1"},
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID2"}).Return()
 				return api
 			},
@@ -787,12 +1199,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"can_manage_poll":           true,
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"voted_answers":             []string{"Answer 1", "Answer 2"},
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"can_manage_poll":            true,
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"voted_answers":              []string{"Answer 1", "Answer 2"},
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
 				return api
 			},
@@ -831,12 +1245,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"can_manage_poll":           false,
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID2",
-					"voted_answers":             []string{"Answer 1"},
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"can_manage_poll":            false,
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID2",
+					"voted_answers":              []string{"Answer 1"},
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID2"}).Return()
 				return api
 			},
@@ -857,12 +1273,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"voted_answers":             []string{"Answer 2"},
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"can_manage_poll":           true,
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"voted_answers":              []string{"Answer 2"},
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"can_manage_poll":            true,
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
 				return api
 			},
@@ -883,12 +1301,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe", Username: "jhDoe"}, nil)
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"voted_answers":             []string{"Answer 2"},
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"can_manage_poll":           true,
-					"setting_progress":          true,
-					"setting_public_add_option": false,
+					"voted_answers":              []string{"Answer 2"},
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"can_manage_poll":            true,
+					"setting_progress":           true,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
 				return api
 			},
@@ -916,6 +1336,7 @@ func TestHandleVote(t *testing.T) {
 				msg, err := pollOut.UpdateVote("userID1", 0)
 				require.Nil(t, msg)
 				require.Nil(t, err)
+				pollOut.UpdateVoteTimes(pollIn, "userID1", testutils.GetMillis())
 
 				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
 				store.PollStore.On("Update", pollIn, pollOut).Return(&model.AppError{})
@@ -935,12 +1356,14 @@ func TestHandleVote(t *testing.T) {
 				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
 				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 7)...).Return().Maybe()
 				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"voted_answers":             []string{"Answer 2"},
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID2",
-					"can_manage_poll":           false,
-					"setting_progress":          false,
-					"setting_public_add_option": false,
+					"voted_answers":              []string{"Answer 2"},
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID2",
+					"can_manage_poll":            false,
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
 				}, &model.WebsocketBroadcast{UserId: "userID2"}).Return()
 				return api
 			},
@@ -993,6 +1416,7 @@ func TestHandleVote(t *testing.T) {
 			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
 				return store
 			},
 			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
@@ -1012,350 +1436,384 @@ func TestHandleVote(t *testing.T) {
 			VoteIndex:          0,
 			ExpectedStatusCode: http.StatusInternalServerError,
 		},
-		"Invalid request, post with invalid channelID": {
+		"Valid request, points poll": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := post.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", "postID1").Return(p, nil)
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID2"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPointsPollWithVotes(), nil)
+				store.PollStore.On("Update", testutils.GetPointsPollWithVotes(), pointsPollOut).Return(nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
 			VoteIndex:          0,
-			ExpectedStatusCode: http.StatusUnauthorized,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPointsPost},
+			ExpectedMsg:        "Your point has been counted. You have 3 points left.",
 		},
-		"Invalid request, without permission to read channel": {
+		"Valid request, quiz, correct answer": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				api.On("HasPermissionToChannel", "userID4", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID4"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetQuizPollWithVotes(), nil)
+				store.PollStore.On("Update", testutils.GetQuizPollWithVotes(), quizCorrectOut).Return(nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
-			VoteIndex:          0,
-			ExpectedStatusCode: http.StatusUnauthorized,
-		},
-		"Invalid request": {
-			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID4", ChannelId: "channelID1", PostId: "postID1"},
 			VoteIndex:          0,
-			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "",
-		},
-	} {
-		t.Run(name, func(t *testing.T) {
-			assert := assert.New(t)
-
-			api := test.SetupAPI(&plugintest.API{})
-			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
-			if test.ExpectedMsg != "" {
-				ephemeralPost := &model.Post{
-					ChannelId: test.Request.ChannelId,
-					RootId:    post.Id,
-					UserId:    testutils.GetBotUserID(),
-					Message:   test.ExpectedMsg,
-				}
-				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
-			}
-			defer api.AssertExpectations(t)
-
-			store := test.SetupStore(&mockstore.Store{})
-			defer store.AssertExpectations(t)
-
-			p := setupTestPlugin(t, api, store)
-
-			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/vote/%d", testutils.GetPollID(), test.VoteIndex)
-			b, err := json.Marshal(test.Request)
-			require.Nil(t, err)
-			body := bytes.NewReader(b)
-			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
-			}
-			p.ServeHTTP(nil, w, r)
-
-			result := w.Result()
-			require.NotNil(t, result)
-			defer closeBody(t, result.Body)
-
-			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
-
-			var response *model.PostActionIntegrationResponse
-			// Don't check if the response typed error is nil in order to do additional assertions.
-			_ = json.NewDecoder(result.Body).Decode(&response)
-
-			if result.StatusCode == http.StatusOK {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
-				require.NotNil(t, response)
-				assert.Equal(test.ExpectedResponse.EphemeralText, response.EphemeralText)
-				if test.ExpectedResponse.Update != nil {
-					assert.Equal(test.ExpectedResponse.Update.Props["card"], response.Update.Props["card"])
-					assert.Equal(test.ExpectedResponse.Update.Attachments(), response.Update.Attachments())
-				}
-			} else {
-				assert.Equal(test.ExpectedResponse, response)
-			}
-		})
-	}
-}
-
-func TestHandleResetVotes(t *testing.T) {
-	converter := func(userID string) (string, *model.AppError) {
-		switch userID {
-		case "userID1":
-			return "@jhDoe", nil
-		case "userID2":
-			return "@jhDoe2", nil
-		default:
-			return "", &model.AppError{}
-		}
-	}
-
-	t.Run("not-authorized", func(t *testing.T) {
-		api := &plugintest.API{}
-		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-		defer api.AssertExpectations(t)
-		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.PostActionIntegrationRequest{UserId: "userID1", TeamId: "teamID1"}
-
-		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/votes/reset", testutils.GetPollID())
-		b, err := json.Marshal(request)
-		require.Nil(t, err)
-		body := bytes.NewReader(b)
-		r := httptest.NewRequest(http.MethodPost, url, body)
-		p.ServeHTTP(nil, w, r)
-
-		result := w.Result()
-		require.NotNil(t, result)
-		defer closeBody(t, result.Body)
-
-		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
-	})
-
-	pollEmptyWithProgress := &poll.Poll{
-		ID:      testutils.GetPollID(),
-		Creator: "userID1",
-		AnswerOptions: []*poll.AnswerOption{
-			{Answer: "Answer 1", Voter: []string{}},
-			{Answer: "Answer 2", Voter: []string{}},
-			{Answer: "Answer 3", Voter: []string{}},
-		},
-		Settings: poll.Settings{Progress: true, MaxVotes: 3},
-	}
-
-	poll2WithVotesWithProgress := pollEmptyWithProgress.Copy()
-	msg, err := poll2WithVotesWithProgress.UpdateVote("userID1", 0)
-	require.Nil(t, msg)
-	require.Nil(t, err)
-
-	poll := &poll.Poll{
-		ID:      testutils.GetPollID(),
-		Creator: "userID1",
-		AnswerOptions: []*poll.AnswerOption{
-			{Answer: "Answer 1", Voter: []string{}},
-			{Answer: "Answer 2", Voter: []string{}},
-			{Answer: "Answer 3", Voter: []string{}},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedQuizCorrectPost},
+			ExpectedMsg:        "Correct! Your answer has been counted.",
 		},
-		Settings: poll.Settings{MaxVotes: 3},
-	}
-
-	expectedPost := &model.Post{}
-	model.ParseMessageAttachment(expectedPost, poll.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
-
-	expectedPostWithProgress := &model.Post{}
-	expectedPostWithProgress.AddProp("card", pollEmptyWithProgress.ToCard(testutils.GetBundle(), converter))
-	model.ParseMessageAttachment(expectedPostWithProgress, pollEmptyWithProgress.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
-
-	poll2WithVotes := poll.Copy()
-	msg, err = poll2WithVotes.UpdateVote("userID1", 0)
-	require.Nil(t, msg)
-	require.Nil(t, err)
-
-	poll3WithVotes := poll.Copy()
-	msg, err = poll3WithVotes.UpdateVote("userID1", 0)
-	require.Nil(t, msg)
-	require.Nil(t, err)
-	msg, err = poll3WithVotes.UpdateVote("userID1", 1)
-	require.Nil(t, msg)
-	require.Nil(t, err)
-	msg, err = poll3WithVotes.UpdateVote("userID1", 2)
-	require.Nil(t, msg)
-	require.Nil(t, err)
-
-	poll4WithVotes := poll.Copy()
-	msg, err = poll4WithVotes.UpdateVote("userID1", 0)
-	require.Nil(t, msg)
-	require.Nil(t, err)
-
-	for name, test := range map[string]struct {
-		SetupAPI           func(*plugintest.API) *plugintest.API
-		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.PostActionIntegrationRequest
-		ExpectedStatusCode int
-		ExpectedResponse   *model.PostActionIntegrationResponse
-		ExpectedMsg        string
-	}{
-		"Valid request with no votes": {
+		"Valid request, quiz, wrong answer": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID4", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID4"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll.Copy(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetQuizPollWithVotes(), nil)
+				store.PollStore.On("Update", testutils.GetQuizPollWithVotes(), quizWrongOut).Return(nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID4", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          2,
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   &model.PostActionIntegrationResponse{},
-			ExpectedMsg:        "There are no votes to reset.",
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedQuizWrongPost},
+			ExpectedMsg:        "Sorry, that's wrong. The correct answer is Answer 1.",
 		},
-		"Valid request, reset 1 vote": {
+		"Valid request, quiz, already answered": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"can_manage_poll":           true,
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"voted_answers":             []string{},
-					"setting_progress":          false,
-					"setting_public_add_option": false,
-				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2WithVotes.Copy(), nil)
-				store.PollStore.On("Update", poll2WithVotes, poll).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetQuizPollWithVotes(), nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID3", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPost},
-			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1].",
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "You've already answered this quiz.",
 		},
-		"Valid request, reset 1 vote, with Settings Progress to true": {
+		"Valid request, ranked poll without ballot": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe", Username: "jhDoe"}, nil)
-				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"can_manage_poll":           true,
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"voted_answers":             []string{},
-					"setting_progress":          true,
-					"setting_public_add_option": false,
-				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user"}, nil)
+				api.On("OpenInteractiveDialog", rankingDialog("1")).Return(nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2WithVotesWithProgress.Copy(), nil)
-				store.PollStore.On("Update", poll2WithVotesWithProgress, pollEmptyWithProgress).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetRankedPollWithBallots(), nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID5", ChannelId: "channelID1", PostId: "postID1", TriggerId: "triggerID1"},
+			VoteIndex:          1,
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPostWithProgress},
-			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1].",
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "",
 		},
-		"Valid request, reset multi votes": {
+		"Valid request, ranked poll with ballot": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
-					"can_manage_poll":           true,
-					"poll_id":                   testutils.GetPollID(),
-					"user_id":                   "userID1",
-					"voted_answers":             []string{},
-					"setting_progress":          false,
-					"setting_public_add_option": false,
-				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user"}, nil)
+				api.On("OpenInteractiveDialog", rankingDialog("2", "1")).Return(nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll3WithVotes.Copy(), nil)
-				store.PollStore.On("Update", poll3WithVotes, poll).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetRankedPollWithBallots(), nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID3", ChannelId: "channelID1", PostId: "postID1", TriggerId: "triggerID1"},
+			VoteIndex:          0,
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPost},
-			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1, Answer 2, Answer 3].",
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "",
 		},
-		"Failed to get poll": {
+		"Valid request, ranked poll, OpenInteractiveDialog fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user"}, nil)
+				api.On("OpenInteractiveDialog", rankingDialog("0")).Return(&model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetRankedPollWithBallots(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID5", ChannelId: "channelID1", PostId: "postID1", TriggerId: "triggerID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, scheduling poll without answers": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user"}, nil)
+				api.On("OpenInteractiveDialog", availabilityDialog("", "", "yes")).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSchedulingPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID5", ChannelId: "channelID1", PostId: "postID1", TriggerId: "triggerID1"},
+			VoteIndex:          2,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "",
+		},
+		"Valid request, scheduling poll with answers": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user"}, nil)
+				api.On("OpenInteractiveDialog", availabilityDialog("no", "yes", "maybe")).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSchedulingPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1", TriggerId: "triggerID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "",
+		},
+		"Valid request, scheduling poll, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user"}, nil)
+				api.On("OpenInteractiveDialog", availabilityDialog("yes", "", "")).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSchedulingPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID5", ChannelId: "channelID1", PostId: "postID1", TriggerId: "triggerID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, post with invalid channelID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := post.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", "postID1").Return(p, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
 				return store
 			},
 			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request, without permission to read channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusBadRequest,
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "",
 		},
-		"Failed to get display name": {
+		"Valid request, eligible voter": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
+					"voted_answers":              []string{"Answer 1"},
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"can_manage_poll":            true,
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
+				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll.Copy(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(restrictedIn.Copy(), nil)
+				store.PollStore.On("Update", restrictedIn, restrictedOut).Return(nil)
 				return store
 			},
 			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedRestrictedPost},
+			ExpectedMsg:        "Your vote has been counted.",
+		},
+		"Valid request, ineligible voter": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetGroupsForUser", "userID2").Return([]*model.Group{{Name: model.NewPointer("developers")}}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(restrictedIn.Copy(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   &model.PostActionIntegrationResponse{},
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			ExpectedMsg:        "You are not allowed to vote in this poll.",
 		},
-		"invalid user id": {
+		"Valid request, checking eligibility fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetGroupsForUser", "userID2").Return(nil, &model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll.Copy(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(restrictedIn.Copy(), nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   &model.PostActionIntegrationResponse{},
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"failed to save poll": {
+		"Valid request, option is full": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll4WithVotes.Copy(), nil)
-				store.PollStore.On("Update", poll4WithVotes, poll).Return(&model.AppError{})
+				store.PollStore.On("Get", testutils.GetPollID()).Return(fullPollIn.Copy(), nil)
 				return store
 			},
-			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "This option is full. Please choose another one.",
+		},
+		"Valid request, option is full, join waitlist": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(waitlistPollIn.Copy(), nil)
+				store.PollStore.On("Update", waitlistPollIn, waitlistPollOut).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "**Answer 1** is full. You are number 1 on its waitlist and get a spot automatically once someone leaves.",
+		},
+		"Valid request, option is full, joining waitlist fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(waitlistPollIn.Copy(), nil)
+				store.PollStore.On("Update", waitlistPollIn, waitlistPollOut).Return(&model.AppError{})
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   &model.PostActionIntegrationResponse{},
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
+		"Valid request, excluded guest": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Id: "userID2", Roles: model.SystemGuestRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, ExcludeGuests: true}), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Guests are not allowed to take part in this poll.",
+		},
+		"Valid request, checking exclusion fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, ExcludeGuests: true}), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			VoteIndex:          0,
+			ExpectedStatusCode: http.StatusInternalServerError,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -1366,6 +1824,7 @@ func TestHandleResetVotes(t *testing.T) {
 			if test.ExpectedMsg != "" {
 				ephemeralPost := &model.Post{
 					ChannelId: test.Request.ChannelId,
+					RootId:    post.Id,
 					UserId:    testutils.GetBotUserID(),
 					Message:   test.ExpectedMsg,
 				}
@@ -1374,12 +1833,14 @@ func TestHandleResetVotes(t *testing.T) {
 			defer api.AssertExpectations(t)
 
 			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
 			defer store.AssertExpectations(t)
 
 			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/votes/reset", testutils.GetPollID())
+			url := fmt.Sprintf("/api/v1/polls/%s/vote/%d", testutils.GetPollID(), test.VoteIndex)
 			b, err := json.Marshal(test.Request)
 			require.Nil(t, err)
 			body := bytes.NewReader(b)
@@ -1417,259 +1878,152 @@ func TestHandleResetVotes(t *testing.T) {
 		})
 	}
 }
-func TestHandleAddOption(t *testing.T) {
-	userID := testutils.GetPollWithVotes().Creator
-	triggerID := model.NewId()
 
-	t.Run("not-authorized", func(t *testing.T) {
-		api := &plugintest.API{}
-		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-		defer api.AssertExpectations(t)
-		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.PostActionIntegrationRequest{UserId: userID, PostId: "postID1", TriggerId: triggerID}
+func TestHandleVoteClosePoll(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		return "@" + userID, nil
+	}
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+	request := &model.PostActionIntegrationRequest{UserId: "userID5", PostId: "postID1", ChannelId: "channelID1", TeamId: "teamID1"}
+	msgClosed := "Your vote has been counted. Enough users have voted, so the poll has ended."
 
-		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/option/add/request", testutils.GetPollID())
-		b, err := json.Marshal(request)
-		require.Nil(t, err)
-		body := bytes.NewReader(b)
-		r := httptest.NewRequest(http.MethodPost, url, body)
-		p.ServeHTTP(nil, w, r)
+	closeAtIn := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, CloseAt: 5})
+	closeAtOut := closeAtIn.Copy()
+	msg, err := closeAtOut.UpdateVote("userID5", 1)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	closeAtOut.UpdateVoteTimes(closeAtIn, "userID5", testutils.GetMillis())
+	expectedCloseAtPost, err := closeAtOut.ToEndPollPost(testutils.GetBundle(), root.Manifest.Id, "John Doe", converter)
+	require.Nil(t, err)
+	expectedCloseAtPost.Id = "postID1"
+	expectedCloseAtPost.FileIds = model.StringArray{"fileID1"}
 
-		result := w.Result()
-		require.NotNil(t, result)
-		defer closeBody(t, result.Body)
+	notReachedIn := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, CloseAt: 6})
+	notReachedOut := notReachedIn.Copy()
+	msg, err = notReachedOut.UpdateVote("userID5", 1)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	notReachedOut.UpdateVoteTimes(notReachedIn, "userID5", testutils.GetMillis())
 
-		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
-	})
+	allVotedIn := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, CloseWhenAllVoted: true})
+	allVotedOut := allVotedIn.Copy()
+	msg, err = allVotedOut.UpdateVote("userID5", 1)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	allVotedOut.UpdateVoteTimes(allVotedIn, "userID5", testutils.GetMillis())
+	expectedAllVotedPost, err := allVotedOut.ToEndPollPost(testutils.GetBundle(), root.Manifest.Id, "John Doe", converter)
+	require.Nil(t, err)
+	expectedAllVotedPost.Id = "postID1"
+	expectedAllVotedPost.FileIds = model.StringArray{"fileID1"}
 
-	dialogRequest := model.OpenDialogRequest{
-		TriggerId: triggerID,
-		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/add", root.Manifest.Id, testutils.GetPollID()),
-		Dialog: model.Dialog{
-			Title:       "Add Option",
-			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
-			CallbackId:  "postID1",
-			SubmitLabel: "Add",
-			Elements: []model.DialogElement{{
-				DisplayName: "Option",
-				Name:        "answerOption",
-				Type:        "text",
-				SubType:     "text",
-			},
-			},
-		},
+	channelMembers := []*model.User{
+		{Id: "userID1"}, {Id: "userID2"}, {Id: "userID3"}, {Id: "userID4"}, {Id: "userID5"},
+		{Id: "botID1", IsBot: true},
+		{Id: "userID6", DeleteAt: 1},
 	}
-	post := &model.Post{
-		ChannelId: "channelID1",
+
+	setupVoters := func(api *plugintest.API) {
+		api.On("GetPost", "postID1").Return(post, nil)
+		api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+		api.On("GetUser", "userID1").Return(&model.User{Username: "userID1", FirstName: "John", LastName: "Doe"}, nil)
+		api.On("GetUser", "userID5").Return(&model.User{Username: "userID5"}, nil)
+	}
+	setupEndPoll := func(api *plugintest.API, expectedPost *model.Post) {
+		api.On("GetUser", "userID2").Return(&model.User{Username: "userID2"}, nil)
+		api.On("GetUser", "userID3").Return(&model.User{Username: "userID3"}, nil)
+		api.On("GetUser", "userID4").Return(&model.User{Username: "userID4"}, nil)
+		api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+		api.On("UpdatePost", expectedPost).Return(nil, nil)
+		api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+	}
+	// The metadata of a poll that is still running is published asynchronously
+	setupPublish := func(api *plugintest.API) {
+		api.On("PublishWebSocketEvent", "has_voted", mock.Anything, mock.Anything).Return().Maybe()
 	}
 
 	for name, test := range map[string]struct {
-		SetupAPI           func(*plugintest.API) *plugintest.API
-		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.PostActionIntegrationRequest
-		ExpectedStatusCode int
-		ExpectedMsg        string
+		SetupAPI       func(*plugintest.API) *plugintest.API
+		SetupStore     func(*mockstore.Store) *mockstore.Store
+		ExpectedMsg    string
+		ExpectedUpdate bool
 	}{
-		"Valid request": {
+		"close-at reached": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				setupVoters(api)
+				setupEndPoll(api, expectedCloseAtPost)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(closeAtIn.Copy(), nil)
+				store.PollStore.On("Update", closeAtIn, closeAtOut).Return(nil)
+				store.PollStore.On("End", closeAtOut).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
-		},
-		"Valid request, poll without postID": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
-		},
-		"Valid request, issuer is system admin": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(&model.User{
-					Username: "user2",
-					Roles:    model.SystemAdminRoleId + " " + model.SystemUserRoleId,
-				}, nil)
-				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
-		},
-		"Valid request, Invalid permission": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to add options.",
-		},
-		"Valid request, GetUser fails for issuer": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			ExpectedMsg:    msgClosed,
+			ExpectedUpdate: false,
 		},
-		"Valid request, OpenInteractiveDialog fails": {
+		"close-at not reached": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
+				setupVoters(api)
+				setupPublish(api)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
-		},
-		"Invalid request, PollStore.Get fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("Get", testutils.GetPollID()).Return(notReachedIn.Copy(), nil)
+				store.PollStore.On("Update", notReachedIn, notReachedOut).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			ExpectedMsg:    "Your vote has been counted.",
+			ExpectedUpdate: true,
 		},
-		"Invalid request, GetPost fails": {
+		"close-when-all-voted, all members voted": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				setupVoters(api)
+				api.On("GetUsersInChannel", "channelID1", model.ChannelSortByUsername, 0, channelMembersPerPage).Return(channelMembers, nil)
+				setupEndPoll(api, expectedAllVotedPost)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(allVotedIn.Copy(), nil)
+				store.PollStore.On("Update", allVotedIn, allVotedOut).Return(nil)
+				store.PollStore.On("End", allVotedOut).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			ExpectedMsg:    msgClosed,
+			ExpectedUpdate: false,
 		},
-		"Invalid request, post with invalid channelID": {
+		"close-when-all-voted, a member didn't vote": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := post.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", "postID1").Return(p, nil)
+				setupVoters(api)
+				setupPublish(api)
+				members := append([]*model.User{{Id: "userID7"}}, channelMembers...)
+				api.On("GetUsersInChannel", "channelID1", model.ChannelSortByUsername, 0, channelMembersPerPage).Return(members, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(allVotedIn.Copy(), nil)
+				store.PollStore.On("Update", allVotedIn, allVotedOut).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			ExpectedMsg:    "Your vote has been counted.",
+			ExpectedUpdate: true,
 		},
-		"Invalid request, without permission to read channel": {
+		"close-when-all-voted, GetUsersInChannel fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(false)
+				setupVoters(api)
+				api.On("GetUsersInChannel", "channelID1", model.ChannelSortByUsername, 0, channelMembersPerPage).Return(nil, &model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(allVotedIn.Copy(), nil)
+				store.PollStore.On("Update", allVotedIn, allVotedOut).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    userID,
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
-		},
-		"Empty request": {
-			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
-			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedMsg:        "",
+			ExpectedMsg:    "Something went wrong. Please try again later.",
+			ExpectedUpdate: false,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -1678,81 +2032,159 @@ func TestHandleAddOption(t *testing.T) {
 			api := test.SetupAPI(&plugintest.API{})
 			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
 			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
-			if test.ExpectedMsg != "" {
-				ephemeralPost := &model.Post{
-					ChannelId: test.Request.ChannelId,
-					UserId:    testutils.GetBotUserID(),
-					Message:   test.ExpectedMsg,
-				}
-				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			ephemeralPost := &model.Post{
+				ChannelId: request.ChannelId,
+				RootId:    post.Id,
+				UserId:    testutils.GetBotUserID(),
+				Message:   test.ExpectedMsg,
 			}
+			api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
 			defer api.AssertExpectations(t)
 
 			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
 			defer store.AssertExpectations(t)
 
 			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/option/add/request", testutils.GetPollID())
-			b, err := json.Marshal(test.Request)
+			url := fmt.Sprintf("/api/v1/polls/%s/vote/1", testutils.GetPollID())
+			b, err := json.Marshal(request)
 			require.Nil(t, err)
-			body := bytes.NewReader(b)
-			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
-			}
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			r.Header.Add("Mattermost-User-ID", request.UserId)
 			p.ServeHTTP(nil, w, r)
 
 			result := w.Result()
 			require.NotNil(t, result)
 			defer closeBody(t, result.Body)
 
-			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			assert.Equal(http.StatusOK, result.StatusCode)
 
 			var response *model.PostActionIntegrationResponse
-			// Don't check if the response typed error is nil in order to do additional assertions.
-			_ = json.NewDecoder(result.Body).Decode(&response)
-
-			if result.StatusCode == http.StatusOK {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
-				assert.Equal(response, &model.PostActionIntegrationResponse{})
-			} else {
-				assert.Nil(response)
-			}
+			require.Nil(t, json.NewDecoder(result.Body).Decode(&response))
+			require.NotNil(t, response)
+			assert.Equal(test.ExpectedUpdate, response.Update != nil)
 		})
 	}
 }
 
-func TestHandleAddOptionConfirm(t *testing.T) {
-	converter := func(userID string) (string, *model.AppError) {
-		switch userID {
-		case "userID1":
-			return "@jhDoe", nil
-		case "userID2":
-			return "@jhDoe2", nil
-		case "userID3":
-			return "@jhDoe3", nil
-		case "userID4":
-			return "@jhDoe4", nil
-		default:
-			return "", &model.AppError{}
-		}
+func TestHandleVoteSecretBallot(t *testing.T) {
+	key := []byte("secretBallotKey")
+	request := &model.PostActionIntegrationRequest{UserId: "userID5", PostId: "postID1", ChannelId: "channelID1", TeamId: "teamID1"}
+
+	firstIn := testutils.GetPollWithSettings(poll.Settings{Anonymous: true, MaxVotes: 1})
+	firstIn.ConvertToSecretBallot(key)
+	firstOut := firstIn.Copy()
+	msg, err := firstOut.UpdateVote(firstOut.VoterID(key, "userID5"), 1)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	changeIn := firstOut.Copy()
+	changeOut := changeIn.Copy()
+	msg, err = changeOut.UpdateVote(changeOut.VoterID(key, "userID5"), 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	multiIn := testutils.GetPollWithSettings(poll.Settings{Anonymous: true, MaxVotes: 2})
+	multiIn.ConvertToSecretBallot(key)
+	msg, err = multiIn.UpdateVote(multiIn.VoterID(key, "userID5"), 1)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	for name, test := range map[string]struct {
+		In             *poll.Poll
+		Out            *poll.Poll
+		Option         int
+		ExpectedMsg    string
+		ExpectedUpdate bool
+	}{
+		"first vote": {
+			In:             firstIn,
+			Option:         1,
+			Out:            firstOut,
+			ExpectedMsg:    "Your vote has been counted.",
+			ExpectedUpdate: true,
+		},
+		"changed vote": {
+			In:             changeIn,
+			Out:            changeOut,
+			Option:         0,
+			ExpectedMsg:    "Your vote has been updated.",
+			ExpectedUpdate: true,
+		},
+		"same option voted twice": {
+			In:             multiIn,
+			Option:         1,
+			ExpectedMsg:    "You've already voted for this option.",
+			ExpectedUpdate: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := &plugintest.API{}
+			api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+			api.On("GetUser", "userID1").Return(&model.User{Username: "userID1", FirstName: "John", LastName: "Doe"}, nil)
+			api.On("GetUser", "userID5").Return(&model.User{Username: "userID5"}, nil)
+			api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1", ChannelId: "channelID1"}, nil)
+			api.On("PublishWebSocketEvent", "has_voted", mock.Anything, mock.Anything).Return().Maybe()
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			ephemeralPost := &model.Post{
+				ChannelId: request.ChannelId,
+				RootId:    "postID1",
+				UserId:    testutils.GetBotUserID(),
+				Message:   test.ExpectedMsg,
+			}
+			api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
+			defer api.AssertExpectations(t)
+
+			store := &mockstore.Store{}
+			store.PollStore.On("Get", testutils.GetPollID()).Return(test.In.Copy(), nil)
+			if test.Out != nil {
+				store.PollStore.On("Update", test.In, test.Out).Return(nil)
+			}
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+			p.secretBallotKey = key
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/vote/%d", testutils.GetPollID(), test.Option)
+			b, err := json.Marshal(request)
+			require.Nil(t, err)
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			r.Header.Add("Mattermost-User-ID", request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(http.StatusOK, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			require.Nil(t, json.NewDecoder(result.Body).Decode(&response))
+			require.NotNil(t, response)
+			assert.Equal(test.ExpectedUpdate, response.Update != nil)
+			if test.Out != nil {
+				assert.NotContains(string(test.Out.EncodeToByte()), "userID5")
+			}
+		})
 	}
+}
 
+func TestHandleRankConfirm(t *testing.T) {
 	t.Run("not-authorized", func(t *testing.T) {
 		api := &plugintest.API{}
 		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
 		defer api.AssertExpectations(t)
 		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.PostActionIntegrationRequest{UserId: "userID1", TeamId: "teamID1"}
+		request := &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1"}
 
 		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/option/add", testutils.GetPollID())
+		url := fmt.Sprintf("/api/v1/polls/%s/rank", testutils.GetPollID())
 		b, err := json.Marshal(request)
 		require.Nil(t, err)
 		body := bytes.NewReader(b)
@@ -1764,37 +2196,41 @@ func TestHandleAddOptionConfirm(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
 	})
 
-	userID := testutils.GetPollWithVotes().Creator
-	channelID := model.NewId()
-	postID := model.NewId()
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
 
-	poll1In := testutils.GetPollWithVotes()
-	poll1In.PostID = postID
+	poll1In := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Ranked: true})
 	poll1Out := poll1In.Copy()
-	err := poll1Out.AddAnswerOption("New Option")
+	msg, err := poll1Out.SubmitBallot("userID1", []int{2, 0})
+	require.Nil(t, msg)
 	require.Nil(t, err)
-	expectedPost1 := &model.Post{
-		ChannelId: channelID,
-	}
+	poll1Out.UpdateVoteTimes(poll1In, "userID1", testutils.GetMillis())
+	expectedPost1 := post.Clone()
 	model.ParseMessageAttachment(expectedPost1, poll1Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
-	poll2In := testutils.GetPollWithoutPostID()
+	poll2In := testutils.GetRankedPollWithBallots()
 	poll2Out := poll2In.Copy()
-	err = poll2Out.AddAnswerOption("New Option")
+	msg, err = poll2Out.SubmitBallot("userID1", []int{1})
+	require.Nil(t, msg)
 	require.Nil(t, err)
-	expectedPost2 := &model.Post{}
+	poll2Out.UpdateVoteTimes(poll2In, "userID1", testutils.GetMillis())
+	expectedPost2 := post.Clone()
 	model.ParseMessageAttachment(expectedPost2, poll2Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
-	poll3In := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 2})
-	poll3In.PostID = postID
-	poll3Out := poll3In.Copy()
-	err = poll3Out.AddAnswerOption("New Option")
-	require.Nil(t, err)
-	expectedPost3 := &model.Post{
-		ChannelId: channelID,
+	metadata := func(votedAnswers ...string) map[string]interface{} {
+		return map[string]interface{}{
+			"voted_answers":              votedAnswers,
+			"poll_id":                    testutils.GetPollID(),
+			"user_id":                    "userID1",
+			"can_manage_poll":            true,
+			"setting_progress":           false,
+			"setting_public_add_option":  false,
+			"setting_results_after_vote": false,
+			"results":                    []int(nil),
+		}
 	}
-	expectedPost3.AddProp("card", poll3Out.ToCard(testutils.GetBundle(), converter))
-	model.ParseMessageAttachment(expectedPost3, poll3Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
 
 	for name, test := range map[string]struct {
 		SetupAPI           func(*plugintest.API) *plugintest.API
@@ -1804,12 +2240,13 @@ func TestHandleAddOptionConfirm(t *testing.T) {
 		ExpectedResponse   *model.SubmitDialogResponse
 		ExpectedMsg        string
 	}{
-		"Valid request": {
+		"Valid request, first ballot": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				api.On("UpdatePost", expectedPost1).Return(expectedPost1, nil)
+				api.On("PublishWebSocketEvent", "has_voted", metadata("Answer 3", "Answer 1"), &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
@@ -1818,293 +2255,275 @@ func TestHandleAddOptionConfirm(t *testing.T) {
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": "New Option",
+					"rank1": "2",
+					"rank2": "0",
+					"rank3": nil,
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
-			ExpectedMsg:        "Successfully added the option.",
+			ExpectedMsg:        "Your ranking has been counted.",
 		},
-		"Valid request, with Progress settings": {
+		"Valid request, updated ballot": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost3, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe", Username: "jhDoe"}, nil)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "jhDoe2"}, nil)
-				api.On("GetUser", "userID3").Return(&model.User{Username: "jhDoe3"}, nil)
-				api.On("GetUser", "userID4").Return(&model.User{Username: "jhDoe4"}, nil)
-				api.On("UpdatePost", expectedPost3).Return(expectedPost3, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPost2).Return(expectedPost2, nil)
+				api.On("PublishWebSocketEvent", "has_voted", metadata("Answer 2"), &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll3In.Copy(), nil)
-				store.PollStore.On("Update", poll3In, poll3Out).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2In.Copy(), nil)
+				store.PollStore.On("Update", poll2In, poll2Out).Return(nil)
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption":      "New Option",
-					"settings-progress": true,
+					"rank1": "1",
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
-			ExpectedMsg:        "Successfully added the option.",
+			ExpectedMsg:        "Your ranking has been updated.",
 		},
-		"Valid request, poll without postID": {
+		"Invalid request, option ranked twice": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetPost", postID).Return(&model.Post{}, nil)
-				api.On("UpdatePost", expectedPost2).Return(expectedPost2, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2In.Copy(), nil)
-				store.PollStore.On("Update", poll2In, poll2Out).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": "New Option",
+					"rank1": "2",
+					"rank2": "2",
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Successfully added the option.",
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Error: "You can rank every option only once.",
+			},
+			ExpectedMsg: "",
 		},
-		"Valid request, GetUser fails": {
+		"Invalid request, invalid option": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = postID
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": "New Option",
+					"rank1": "first",
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Invalid request with integer": {
+		"Valid request, Store.Update fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = postID
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
+				store.PollStore.On("Update", poll1In, poll1Out).Return(&model.AppError{})
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": 1,
+					"rank1": "2",
+					"rank2": "0",
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Valid request, duplicate new answeroption": {
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					RootId:    post.Id,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/rank", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+		})
+	}
+}
+
+func TestHandleAvailabilityConfirm(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/availability", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+		result := w.Result()
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+
+	pollIn := testutils.GetSchedulingPollWithVotes()
+	pollOut := pollIn.Copy()
+	require.Nil(t, pollOut.SetAvailability("userID1", []poll.Availability{poll.AvailabilityNo, poll.AvailabilityMaybe, ""}))
+	pollOut.UpdateVoteTimes(pollIn, "userID1", testutils.GetMillis())
+	expectedPost := post.Clone()
+	model.ParseMessageAttachment(expectedPost, pollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPost).Return(expectedPost, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = postID
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(nil)
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": poll1In.AnswerOptions[0].Answer,
+					"availability0": "no",
+					"availability1": "maybe",
+					"availability2": nil,
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse: &model.SubmitDialogResponse{
-				Errors: map[string]string{
-					"answerOption": "Duplicate option: Answer 1",
-				},
-			},
-			ExpectedMsg: "",
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Your availability has been saved.",
 		},
-		"Valid request, UpdatePost fails": {
+		"Invalid request, invalid availability": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetPost", postID).Return(&model.Post{}, nil)
-				api.On("UpdatePost", expectedPost1).Return(nil, &model.AppError{})
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = postID
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": "New Option",
+					"availability0": "perhaps",
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Valid request, PollStore.Save fails": {
+		"Valid request, Store.Update fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
-				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetPost", postID).Return(&model.Post{}, nil)
-				api.On("UpdatePost", expectedPost1).Return(expectedPost1, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
-				store.PollStore.On("Update", poll1In, poll1Out).Return(errors.New(""))
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(&model.AppError{})
 				return store
 			},
 			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
+				UserId:    "userID1",
+				ChannelId: "channelID1",
 				Submission: map[string]interface{}{
-					"answerOption": "New Option",
+					"availability0": "no",
+					"availability1": "maybe",
 				},
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Invalid request, PollStore.Get fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
-				return store
-			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
-				Submission: map[string]interface{}{
-					"answerOption": "New Option",
-				},
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
-		},
-		"Invalid request, GetPost fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(nil, &model.AppError{})
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = postID
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
-				return store
-			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
-				Submission: map[string]interface{}{
-					"answerOption": "New Option",
-				},
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
-		},
-		"Invalid request, post with invalid channelId": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := expectedPost1.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", postID).Return(p, nil)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = postID
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
-				return store
-			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
-				Submission: map[string]interface{}{
-					"answerOption": "New Option",
-				},
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
-		},
-		"Invalid request, without permission to read channel": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", postID).Return(expectedPost1, nil)
-				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(false)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
-				return store
-			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     userID,
-				CallbackId: postID,
-				ChannelId:  channelID,
-				Submission: map[string]interface{}{
-					"answerOption": "New Option",
-				},
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
-		},
-		"Empty request": {
-			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
-			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "",
-		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -2115,6 +2534,7 @@ func TestHandleAddOptionConfirm(t *testing.T) {
 			if test.ExpectedMsg != "" {
 				ephemeralPost := &model.Post{
 					ChannelId: test.Request.ChannelId,
+					RootId:    post.Id,
 					UserId:    testutils.GetBotUserID(),
 					Message:   test.ExpectedMsg,
 				}
@@ -2122,20 +2542,18 @@ func TestHandleAddOptionConfirm(t *testing.T) {
 			}
 			defer api.AssertExpectations(t)
 			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
 			defer store.AssertExpectations(t)
 			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/option/add", testutils.GetPollID())
+			url := fmt.Sprintf("/api/v1/polls/%s/availability", testutils.GetPollID())
 			b, err := json.Marshal(test.Request)
 			require.Nil(t, err)
 			body := bytes.NewReader(b)
 			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
-			}
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
 			p.ServeHTTP(nil, w, r)
 
 			result := w.Result()
@@ -2149,261 +2567,249 @@ func TestHandleAddOptionConfirm(t *testing.T) {
 			_ = json.NewDecoder(result.Body).Decode(&response)
 
 			assert.Equal(test.ExpectedResponse, response)
-			if test.ExpectedResponse != nil {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
-			}
 		})
 	}
 }
 
-func TestHandleEndPoll(t *testing.T) {
-	t.Run("not-authorized", func(t *testing.T) {
-		api := &plugintest.API{}
-		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-		defer api.AssertExpectations(t)
-		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"}
-
-		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/end", testutils.GetPollID())
-		b, err := json.Marshal(request)
-		require.Nil(t, err)
-		body := bytes.NewReader(b)
-		r := httptest.NewRequest(http.MethodPost, url, body)
-		p.ServeHTTP(nil, w, r)
-
-		result := w.Result()
-		require.NotNil(t, result)
-		defer closeBody(t, result.Body)
-
-		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
-	})
-
-	triggerID := model.NewId()
-	dialog := model.OpenDialogRequest{
-		TriggerId: triggerID,
-		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/end/confirm", root.Manifest.Id, testutils.GetPollID()),
+func TestHandleResponse(t *testing.T) {
+	dialogRequest := model.OpenDialogRequest{
+		TriggerId: "triggerID1",
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/response", root.Manifest.Id, testutils.GetPollID()),
 		Dialog: model.Dialog{
-			Title:       "Confirm Poll End",
-			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
-			CallbackId:  "postID1",
-			SubmitLabel: "End",
+			Title:            "Your Answer",
+			IntroductionText: "Question",
+			IconURL:          fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:       "postID1",
+			SubmitLabel:      "Submit",
+			Elements: []model.DialogElement{{
+				DisplayName: "Answer",
+				Name:        "response",
+				Type:        "textarea",
+				Default:     "First response",
+				MaxLength:   3000,
+			}},
 		},
 	}
-
 	post := &model.Post{
+		Id:        "postID1",
 		ChannelId: "channelID1",
 	}
 
 	for name, test := range map[string]struct {
 		SetupAPI           func(*plugintest.API) *plugintest.API
 		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.PostActionIntegrationRequest
 		ExpectedStatusCode int
 		ExpectedMsg        string
 	}{
-		"Valid request with no votes": {
+		"Valid request": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetFreeTextPollWithResponses(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedMsg:        "",
 		},
-		"Valid request, poll without postID": {
+		"Valid request, OpenInteractiveDialog fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetFreeTextPollWithResponses(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
+			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Valid request with no votes, issuer is system admin": {
+		"Invalid request, poll isn't a free-text poll": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(&model.User{
-					Username: "user2",
-					Roles:    model.SystemAdminRoleId + " " + model.SystemUserRoleId,
-				}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
+			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Valid request, GetUser fails for issuer": {
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			request := &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: "triggerID1",
+			}
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: request.ChannelId,
+					RootId:    post.Id,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/response/request", testutils.GetPollID())
+			b, err := json.Marshal(request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			r.Header.Add("Mattermost-User-ID", request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestHandleResponseConfirm(t *testing.T) {
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+
+	pollIn := testutils.GetFreeTextPollWithResponses()
+	pollUpdated := pollIn.Copy()
+	msg, err := pollUpdated.SetResponse("userID1", "Changed response")
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	pollUpdated.UpdateVoteTimes(pollIn, "userID1", testutils.GetMillis())
+	expectedPostUpdated := post.Clone()
+	model.ParseMessageAttachment(expectedPostUpdated, pollUpdated.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	pollSaved := pollIn.Copy()
+	msg, err = pollSaved.SetResponse("userID3", "Third response")
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	pollSaved.UpdateVoteTimes(pollIn, "userID3", testutils.GetMillis())
+	expectedPostSaved := post.Clone()
+	model.ParseMessageAttachment(expectedPostSaved, pollSaved.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request, first answer": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("UpdatePost", expectedPostSaved).Return(expectedPostSaved, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID3"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollSaved).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID3",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"response": "Third response"},
 			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Your answer has been saved.",
 		},
-		"Valid request, Invalid permission": {
+		"Valid request, changed answer": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPostUpdated).Return(expectedPostUpdated, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollUpdated).Return(nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"response": "Changed response"},
 			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to end it.",
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Your answer has been updated.",
 		},
-		"Valid request, OpenInteractiveDialog fails": {
+		"Invalid request, empty answer": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(&model.AppError{})
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"response": nil},
 			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
-		},
-		"Invalid request, Store.Get fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
-		},
-		"Invalid request, GetPost fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, &model.AppError{})
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
-		},
-		"Invalid request, post with invalid channelID": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := post.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", "postID1").Return(p, nil)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
-				return store
-			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"response": "Please enter an answer."},
 			},
-			ExpectedStatusCode: http.StatusUnauthorized,
 		},
-		"Invalid request, without permission to read channel": {
+		"Valid request, Store.Update fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollUpdated).Return(&model.AppError{})
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"response": "Changed response"},
 			},
-			ExpectedStatusCode: http.StatusUnauthorized,
-		},
-		"Invalid request": {
-			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
-			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedMsg:        "",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -2415,29 +2821,26 @@ func TestHandleEndPoll(t *testing.T) {
 			if test.ExpectedMsg != "" {
 				ephemeralPost := &model.Post{
 					ChannelId: test.Request.ChannelId,
+					RootId:    post.Id,
 					UserId:    testutils.GetBotUserID(),
 					Message:   test.ExpectedMsg,
 				}
 				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
 			}
 			defer api.AssertExpectations(t)
-
 			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
 			defer store.AssertExpectations(t)
-
 			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/end", testutils.GetPollID())
+			url := fmt.Sprintf("/api/v1/polls/%s/response", testutils.GetPollID())
 			b, err := json.Marshal(test.Request)
 			require.Nil(t, err)
 			body := bytes.NewReader(b)
 			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
-			}
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
 			p.ServeHTTP(nil, w, r)
 
 			result := w.Result()
@@ -2446,249 +2849,301 @@ func TestHandleEndPoll(t *testing.T) {
 
 			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
 
-			var response *model.PostActionIntegrationResponse
+			var response *model.SubmitDialogResponse
 			// Don't check if the response typed error is nil in order to do additional assertions.
 			_ = json.NewDecoder(result.Body).Decode(&response)
 
-			if result.StatusCode == http.StatusOK {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
-				assert.Equal(response, &model.PostActionIntegrationResponse{})
-			} else {
-				assert.Nil(response)
-			}
+			assert.Equal(test.ExpectedResponse, response)
 		})
 	}
 }
 
-func TestHandleEndPollConfirm(t *testing.T) {
-	t.Run("not-authorized", func(t *testing.T) {
-		api := &plugintest.API{}
-		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-		defer api.AssertExpectations(t)
-		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.SubmitDialogRequest{}
-
-		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/end/confirm", testutils.GetPollID())
-		b, err := json.Marshal(request)
-		require.Nil(t, err)
-		body := bytes.NewReader(b)
-		r := httptest.NewRequest(http.MethodPost, url, body)
-		p.ServeHTTP(nil, w, r)
-
-		result := w.Result()
-		require.NotNil(t, result)
-		defer closeBody(t, result.Body)
-
-		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
-	})
-
-	converter := func(userID string) (string, *model.AppError) {
-		switch userID {
-		case "userID1":
-			return "@user1", nil
-		case "userID2":
-			return "@user2", nil
-		case "userID3":
-			return "@user3", nil
-		case "userID4":
-			return "@user4", nil
-		default:
-			return "", &model.AppError{}
-		}
+func TestHandleAnswerSurvey(t *testing.T) {
+	answerOptions := []*model.PostActionOptions{
+		{Text: "Answer 1", Value: "0"},
+		{Text: "Answer 2", Value: "1"},
+	}
+	dialogRequest := model.OpenDialogRequest{
+		TriggerId: "triggerID1",
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/survey", root.Manifest.Id, testutils.GetPollID()),
+		Dialog: model.Dialog{
+			Title:       "Survey",
+			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:  "postID1",
+			SubmitLabel: "Submit",
+			Elements: []model.DialogElement{{
+				DisplayName: "Question 1",
+				Name:        "answer0",
+				Type:        "radio",
+				Options:     answerOptions,
+				Default:     "0",
+				Optional:    true,
+			}, {
+				DisplayName: "Question 2",
+				Name:        "answer1",
+				Type:        "select",
+				Options:     append(answerOptions, &model.PostActionOptions{Text: "Answer 3", Value: "2"}),
+				Default:     "0,1",
+				HelpText:    "Choose up to 2 options.",
+				Optional:    true,
+				MultiSelect: true,
+			}},
+		},
 	}
-	expectedPost, err := testutils.GetPollWithVotes().ToEndPollPost(testutils.GetBundle(), "John Doe", converter)
-	require.Nil(t, err)
-	expectedPost.Id = "postID1"
-
 	post := &model.Post{
+		Id:        "postID1",
 		ChannelId: "channelID1",
 	}
 
 	for name, test := range map[string]struct {
 		SetupAPI           func(*plugintest.API) *plugintest.API
 		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.SubmitDialogRequest
 		ExpectedStatusCode int
-		ExpectedResponse   *model.SubmitDialogResponse
 		ExpectedMsg        string
 	}{
-		"Valid request with votes": {
+		"Valid request": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
-				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
-				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
-				api.On("UpdatePost", expectedPost).Return(nil, nil)
-				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				store.PollStore.On("Delete", testutils.GetPollWithVotes()).Return(nil)
-				return store
-			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "",
-		},
-		"Valid request, poll without postID": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
-				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
-				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
-				api.On("UpdatePost", expectedPost).Return(nil, nil)
-				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				poll := testutils.GetPollWithVotes().Copy()
-				poll.PostID = ""
-				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
-				store.PollStore.On("Delete", poll).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSurveyWithAnswers(), nil)
 				return store
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
 			ExpectedMsg:        "",
 		},
-		"Valid request, GetUser fails for poll creator": {
+		"Valid request, OpenInteractiveDialog fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				return store
-			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
-		},
-		"Valid request, GetUser fails for voter": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
-				return api
-			},
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				return store
-			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
-		},
-		"Valid request, UpdatePost fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
-				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
-				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
-				api.On("UpdatePost", expectedPost).Return(nil, &model.AppError{})
+				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSurveyWithAnswers(), nil)
 				return store
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Valid request, PollStore.Delete fails": {
+		"Invalid request, poll isn't a survey": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
-				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
-				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
-				api.On("UpdatePost", expectedPost).Return(nil, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
-				store.PollStore.On("Delete", testutils.GetPollWithVotes()).Return(&model.AppError{})
 				return store
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
 			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Invalid request, PollStore.Get fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
-				return store
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			request := &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: "triggerID1",
+			}
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: request.ChannelId,
+					RootId:    post.Id,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/survey/request", testutils.GetPollID())
+			b, err := json.Marshal(request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			r.Header.Add("Mattermost-User-ID", request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestHandleAnswerSurveyConfirm(t *testing.T) {
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+
+	surveyIn := testutils.GetSurveyWithAnswers()
+	surveyUpdated := surveyIn.Copy()
+	errMsgs, err := surveyUpdated.SubmitSurvey("userID1", [][]int{{1}, {2}})
+	require.Nil(t, errMsgs)
+	require.Nil(t, err)
+	surveyUpdated.UpdateVoteTimes(surveyIn, "userID1", testutils.GetMillis())
+	expectedPostUpdated := post.Clone()
+	model.ParseMessageAttachment(expectedPostUpdated, surveyUpdated.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	surveySaved := surveyIn.Copy()
+	errMsgs, err = surveySaved.SubmitSurvey("userID4", [][]int{{0}, {0, 2}})
+	require.Nil(t, errMsgs)
+	require.Nil(t, err)
+	surveySaved.UpdateVoteTimes(surveyIn, "userID4", testutils.GetMillis())
+	expectedPostSaved := post.Clone()
+	model.ParseMessageAttachment(expectedPostSaved, surveySaved.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request, first answers": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID4", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UpdatePost", expectedPostSaved).Return(expectedPostSaved, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID4"}).Return().Maybe()
+				return api
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(surveyIn.Copy(), nil)
+				store.PollStore.On("Update", surveyIn, surveySaved).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:    "userID4",
+				ChannelId: "channelID1",
+				Submission: map[string]interface{}{
+					"answer0": "0",
+					"answer1": []interface{}{"0", "2"},
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Your answers have been saved.",
 		},
-		"Invalid request, GetPost fails": {
+		"Valid request, changed answers": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPostUpdated).Return(expectedPostUpdated, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(surveyIn.Copy(), nil)
+				store.PollStore.On("Update", surveyIn, surveyUpdated).Return(nil)
 				return store
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			Request: &model.SubmitDialogRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				Submission: map[string]interface{}{
+					"answer0": "1",
+					"answer1": "2",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Your answers have been updated.",
 		},
-		"Invalid request, post with invalid channelID": {
+		"Invalid request, too many options": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := post.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", "postID1").Return(p, nil)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(surveyIn.Copy(), nil)
 				return store
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			Request: &model.SubmitDialogRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				Submission: map[string]interface{}{
+					"answer0": nil,
+					"answer1": "0,1,2",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"answer1": "You can choose at most 2 options for this question."},
+			},
 		},
-		"Invalid request, without permission to read channel": {
+		"Invalid request, invalid answer": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(surveyIn.Copy(), nil)
 				return store
 			},
-			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			Request: &model.SubmitDialogRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				Submission: map[string]interface{}{
+					"answer0": "Answer 1",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Invalid request": {
-			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
-			ExpectedStatusCode: http.StatusBadRequest,
+		"Valid request, Store.Update fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(surveyIn.Copy(), nil)
+				store.PollStore.On("Update", surveyIn, surveyUpdated).Return(&model.AppError{})
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				Submission: map[string]interface{}{
+					"answer0": "1",
+					"answer1": "2",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   nil,
-			ExpectedMsg:        "",
+			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -2700,29 +3155,26 @@ func TestHandleEndPollConfirm(t *testing.T) {
 			if test.ExpectedMsg != "" {
 				ephemeralPost := &model.Post{
 					ChannelId: test.Request.ChannelId,
+					RootId:    post.Id,
 					UserId:    testutils.GetBotUserID(),
 					Message:   test.ExpectedMsg,
 				}
 				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
 			}
 			defer api.AssertExpectations(t)
-
 			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
 			defer store.AssertExpectations(t)
-
 			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/end/confirm", testutils.GetPollID())
+			url := fmt.Sprintf("/api/v1/polls/%s/survey", testutils.GetPollID())
 			b, err := json.Marshal(test.Request)
 			require.Nil(t, err)
 			body := bytes.NewReader(b)
 			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
-			}
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
 			p.ServeHTTP(nil, w, r)
 
 			result := w.Result()
@@ -2736,142 +3188,4928 @@ func TestHandleEndPollConfirm(t *testing.T) {
 			_ = json.NewDecoder(result.Body).Decode(&response)
 
 			assert.Equal(test.ExpectedResponse, response)
-			if test.ExpectedResponse != nil {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
-			}
-		})
-	}
-}
-
-func TestPostEndPollAnnouncement(t *testing.T) {
-	for name, test := range map[string]struct {
-		SetupAPI func(*plugintest.API) *plugintest.API
-	}{
-		"Valid request": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("CreatePost", &model.Post{
-					UserId:    testutils.GetBotUserID(),
-					ChannelId: "channelID1",
-					RootId:    "postID1",
-					Message: "The poll **Question** has ended and the original post has been updated. " +
-						"You can jump to it by pressing [here](https://example.org/_redirect/pl/postID1).",
-					Type: model.PostTypeDefault,
-				}).Return(nil, nil)
-				return api
-			},
-		},
-		"Valid request, CreatePost fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, &model.AppError{})
-				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
-				return api
-			},
-		},
-	} {
-		t.Run(name, func(t *testing.T) {
-			api := test.SetupAPI(&plugintest.API{})
-
-			p := setupTestPlugin(t, api, &mockstore.Store{})
-			p.postEndPollAnnouncement("channelID1", "postID1", "Question")
 		})
 	}
 }
-func TestHandleDeletePoll(t *testing.T) {
-	t.Run("not-authorized", func(t *testing.T) {
-		api := &plugintest.API{}
-		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-		defer api.AssertExpectations(t)
-		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.PostActionIntegrationRequest{}
-
-		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/delete", testutils.GetPollID())
-		b, err := json.Marshal(request)
-		require.Nil(t, err)
-		body := bytes.NewReader(b)
-		r := httptest.NewRequest(http.MethodPost, url, body)
-		p.ServeHTTP(nil, w, r)
-
-		result := w.Result()
-		require.NotNil(t, result)
-		defer closeBody(t, result.Body)
 
-		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
-	})
-
-	triggerID := model.NewId()
-	dialog := model.OpenDialogRequest{
-		TriggerId: triggerID,
-		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/delete/confirm", root.Manifest.Id, testutils.GetPollID()),
+func TestHandleEditPoints(t *testing.T) {
+	dialogRequest := model.OpenDialogRequest{
+		TriggerId: "triggerID1",
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/points", root.Manifest.Id, testutils.GetPollID()),
 		Dialog: model.Dialog{
-			Title:       "Confirm Poll Delete",
-			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
-			CallbackId:  "postID1",
-			SubmitLabel: "Delete",
+			Title:            "Edit Your Points",
+			IntroductionText: "You have 5 points to spread across the options.",
+			IconURL:          fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:       "postID1",
+			SubmitLabel:      "Save",
+			Elements: []model.DialogElement{{
+				DisplayName: "Answer 1",
+				Name:        "points0",
+				Type:        "text",
+				SubType:     "number",
+				Default:     "3",
+				Optional:    true,
+			}, {
+				DisplayName: "Answer 2",
+				Name:        "points1",
+				Type:        "text",
+				SubType:     "number",
+				Default:     "2",
+				Optional:    true,
+			}, {
+				DisplayName: "Answer 3",
+				Name:        "points2",
+				Type:        "text",
+				SubType:     "number",
+				Default:     "0",
+				Optional:    true,
+			}},
 		},
 	}
 	post := &model.Post{
+		Id:        "postID1",
 		ChannelId: "channelID1",
 	}
 
 	for name, test := range map[string]struct {
 		SetupAPI           func(*plugintest.API) *plugintest.API
 		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.PostActionIntegrationRequest
 		ExpectedStatusCode int
 		ExpectedMsg        string
 	}{
-		"Valid request with no votes": {
+		"Valid request": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPointsPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedMsg:        "",
 		},
-		"Valid request, poll without postID": {
+		"Valid request, OpenInteractiveDialog fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
 				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPointsPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
+			ExpectedMsg:        "Something went wrong. Please try again later.",
 		},
-		"Valid request with no votes, issuer is system admin": {
+		"Invalid request, poll without point budget": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(&model.User{
-					Username: "user2",
-					Roles:    model.SystemAdminRoleId + " " + model.SystemUserRoleId,
-				}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			request := &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: "triggerID1",
+			}
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: request.ChannelId,
+					RootId:    post.Id,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/points/request", testutils.GetPollID())
+			b, err := json.Marshal(request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			r.Header.Add("Mattermost-User-ID", request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestHandleEditPointsConfirm(t *testing.T) {
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+
+	pollOut := testutils.GetPointsPollWithVotes()
+	errMsg, err := pollOut.SetPoints("userID1", []int{1, 0, 4})
+	require.Nil(t, errMsg)
+	require.Nil(t, err)
+	pollOut.UpdateVoteTimes(testutils.GetPointsPollWithVotes(), "userID1", testutils.GetMillis())
+	expectedPost := post.Clone()
+	model.ParseMessageAttachment(expectedPost, pollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Submission         map[string]interface{}
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPost).Return(expectedPost, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPointsPollWithVotes(), nil)
+				store.PollStore.On("Update", testutils.GetPointsPollWithVotes(), pollOut).Return(nil)
+				return store
+			},
+			Submission: map[string]interface{}{
+				"points0": float64(1),
+				"points1": nil,
+				"points2": float64(4),
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Your points have been updated.",
+		},
+		"Invalid request, exceeds budget": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPointsPollWithVotes(), nil)
+				return store
+			},
+			Submission: map[string]interface{}{
+				"points0": float64(3),
+				"points1": float64(3),
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Error: "You spent 6 points, but only have 5.",
+			},
+			ExpectedMsg: "",
+		},
+		"Valid request, Store.Update fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPointsPollWithVotes(), nil)
+				store.PollStore.On("Update", testutils.GetPointsPollWithVotes(), pollOut).Return(&model.AppError{})
+				return store
+			},
+			Submission: map[string]interface{}{
+				"points0": float64(1),
+				"points2": float64(4),
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			request := &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				ChannelId:  "channelID1",
+				Submission: test.Submission,
+			}
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: request.ChannelId,
+					RootId:    post.Id,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/points", testutils.GetPollID())
+			b, err := json.Marshal(request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			r.Header.Add("Mattermost-User-ID", request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+		})
+	}
+}
+
+func TestSendResultsAfterVote(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		return "@" + userID, nil
+	}
+	resultsPoll := testutils.GetPollWithVotesAndSettings(poll.Settings{ResultsAfterVote: true, MaxVotes: 1})
+	resultsPollWithoutPostID := resultsPoll.Copy()
+	resultsPollWithoutPostID.PostID = ""
+	expectedMsg := resultsPoll.ToCard(testutils.GetBundle(), converter)
+
+	setupVoters := func(api *plugintest.API) {
+		for _, userID := range []string{"userID1", "userID2", "userID3", "userID4"} {
+			api.On("GetUser", userID).Return(&model.User{Username: userID}, nil)
+		}
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI func(*plugintest.API) *plugintest.API
+		Poll     *poll.Poll
+		UserID   string
+	}{
+		"Voted": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1", ChannelId: "channelID1"}, nil)
+				setupVoters(api)
+				api.On("SendEphemeralPost", "userID2", &model.Post{
+					ChannelId: "channelID1",
+					RootId:    "postID1",
+					UserId:    testutils.GetBotUserID(),
+					Message:   expectedMsg,
+				}).Return(nil)
+				return api
+			},
+			Poll:   resultsPoll,
+			UserID: "userID2",
+		},
+		"Voted, poll in a thread": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1", RootId: "rootID1", ChannelId: "channelID1"}, nil)
+				setupVoters(api)
+				api.On("SendEphemeralPost", "userID2", &model.Post{
+					ChannelId: "channelID1",
+					RootId:    "rootID1",
+					UserId:    testutils.GetBotUserID(),
+					Message:   expectedMsg,
+				}).Return(nil)
+				return api
+			},
+			Poll:   resultsPoll,
+			UserID: "userID2",
+		},
+		"Voted, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupVoters(api)
+				api.On("SendEphemeralPost", "userID2", &model.Post{
+					ChannelId: "channelID1",
+					UserId:    testutils.GetBotUserID(),
+					Message:   expectedMsg,
+				}).Return(nil)
+				return api
+			},
+			Poll:   resultsPollWithoutPostID,
+			UserID: "userID2",
+		},
+		"Voted, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			Poll:   resultsPoll,
+			UserID: "userID2",
+		},
+		"Not voted": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			Poll:     resultsPoll,
+			UserID:   "userID5",
+		},
+		"Without results-after-vote setting": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			Poll:     testutils.GetPollWithVotes(),
+			UserID:   "userID2",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, &mockstore.Store{})
+			p.sendResultsAfterVote(test.Poll, test.UserID, "channelID1")
+		})
+	}
+}
+
+func TestHandleResetVotes(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		switch userID {
+		case "userID1":
+			return "@jhDoe", nil
+		case "userID2":
+			return "@jhDoe2", nil
+		default:
+			return "", &model.AppError{}
+		}
+	}
+
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: "userID1", TeamId: "teamID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/votes/reset", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	pollEmptyWithProgress := &poll.Poll{
+		ID:      testutils.GetPollID(),
+		Creator: "userID1",
+		AnswerOptions: []*poll.AnswerOption{
+			{Answer: "Answer 1", Voter: []string{}},
+			{Answer: "Answer 2", Voter: []string{}},
+			{Answer: "Answer 3", Voter: []string{}},
+		},
+		Settings: poll.Settings{Progress: true, MaxVotes: 3},
+	}
+
+	poll2WithVotesWithProgress := pollEmptyWithProgress.Copy()
+	msg, err := poll2WithVotesWithProgress.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	waitlistPollIn := &poll.Poll{
+		ID:       testutils.GetPollID(),
+		PostID:   "postID1",
+		Question: "Question",
+		Creator:  "userID1",
+		AnswerOptions: []*poll.AnswerOption{
+			{Answer: "Answer 1", Voter: []string{"userID1"}, Capacity: 1, Waitlist: []string{"userID2"}},
+			{Answer: "Answer 2", Voter: []string{}},
+		},
+		Settings: poll.Settings{MaxVotes: 1, Waitlist: true},
+	}
+	waitlistPollOut := waitlistPollIn.Copy()
+	waitlistPollOut.ResetVotes("userID1")
+	waitlistPollOut.UpdateVoteTimes(waitlistPollIn, "userID1", testutils.GetMillis())
+	waitlistPollOut.UpdateVoteTimes(waitlistPollIn, "userID2", testutils.GetMillis())
+	expectedWaitlistPost := &model.Post{}
+	model.ParseMessageAttachment(expectedWaitlistPost, waitlistPollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+	waitlistLeftPollOut := waitlistPollIn.Copy()
+	waitlistLeftPollOut.ResetVotes("userID2")
+	expectedWaitlistLeftPost := &model.Post{}
+	model.ParseMessageAttachment(expectedWaitlistLeftPost, waitlistLeftPollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	poll := &poll.Poll{
+		ID:      testutils.GetPollID(),
+		Creator: "userID1",
+		AnswerOptions: []*poll.AnswerOption{
+			{Answer: "Answer 1", Voter: []string{}},
+			{Answer: "Answer 2", Voter: []string{}},
+			{Answer: "Answer 3", Voter: []string{}},
+		},
+		Settings: poll.Settings{MaxVotes: 3},
+	}
+
+	expectedPost := &model.Post{}
+	model.ParseMessageAttachment(expectedPost, poll.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	expectedPostWithProgress := &model.Post{}
+	expectedPostWithProgress.AddProp("card", pollEmptyWithProgress.ToCard(testutils.GetBundle(), converter))
+	model.ParseMessageAttachment(expectedPostWithProgress, pollEmptyWithProgress.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	poll2WithVotes := poll.Copy()
+	msg, err = poll2WithVotes.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	poll3WithVotes := poll.Copy()
+	msg, err = poll3WithVotes.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	msg, err = poll3WithVotes.UpdateVote("userID1", 1)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+	msg, err = poll3WithVotes.UpdateVote("userID1", 2)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	poll4WithVotes := poll.Copy()
+	msg, err = poll4WithVotes.UpdateVote("userID1", 0)
+	require.Nil(t, msg)
+	require.Nil(t, err)
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.PostActionIntegrationResponse
+		ExpectedMsg        string
+	}{
+		"Valid request, quiz": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				quizWithoutPostID := testutils.GetQuizPollWithVotes()
+				quizWithoutPostID.PostID = ""
+				store.PollStore.On("Get", testutils.GetPollID()).Return(quizWithoutPostID, nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Answers to a quiz can't be reset.",
+		},
+		"Valid request, final setting": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				finalWithoutPostID := testutils.GetPollWithVotes()
+				finalWithoutPostID.PostID = ""
+				finalWithoutPostID.Settings.Final = true
+				store.PollStore.On("Get", testutils.GetPollID()).Return(finalWithoutPostID, nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Votes of this poll are final and can't be reset.",
+		},
+		"Valid request with no votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll.Copy(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "There are no votes to reset.",
+		},
+		"Valid request, reset 1 vote": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
+					"can_manage_poll":            true,
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"voted_answers":              []string{},
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
+				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2WithVotes.Copy(), nil)
+				store.PollStore.On("Update", poll2WithVotes, poll).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPost},
+			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1].",
+		},
+		"Valid request, reset 1 vote, with Settings Progress to true": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe", Username: "jhDoe"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
+					"can_manage_poll":            true,
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"voted_answers":              []string{},
+					"setting_progress":           true,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
+				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2WithVotesWithProgress.Copy(), nil)
+				store.PollStore.On("Update", poll2WithVotesWithProgress, pollEmptyWithProgress).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPostWithProgress},
+			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1].",
+		},
+		"Valid request, reset multi votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", map[string]interface{}{
+					"can_manage_poll":            true,
+					"poll_id":                    testutils.GetPollID(),
+					"user_id":                    "userID1",
+					"voted_answers":              []string{},
+					"setting_progress":           false,
+					"setting_public_add_option":  false,
+					"setting_results_after_vote": false,
+					"results":                    []int(nil),
+				}, &model.WebsocketBroadcast{UserId: "userID1"}).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll3WithVotes.Copy(), nil)
+				store.PollStore.On("Update", poll3WithVotes, poll).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedPost},
+			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1, Answer 2, Answer 3].",
+		},
+		"Valid request, reset vote promotes from waitlist": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{}, nil)
+				api.On("GetDirectChannel", "userID2", testutils.GetBotUserID()).Return(&model.Channel{Id: "channelID2"}, nil)
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID2",
+					Message:   fmt.Sprintf("A spot opened up in the poll [Question](%s/_redirect/pl/postID1). You have been moved from the waitlist to **Answer 1**.", testutils.GetSiteURL()),
+				}).Return(nil, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID1"}).Return().Maybe()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(waitlistPollIn.Copy(), nil)
+				store.PollStore.On("Update", waitlistPollIn, waitlistPollOut).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedWaitlistPost},
+			ExpectedMsg:        "All votes are cleared. Your previous votes were [Answer 1].",
+		},
+		"Valid request, leave waitlist": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{}, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID2"}).Return().Maybe()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(waitlistPollIn.Copy(), nil)
+				store.PollStore.On("Update", waitlistPollIn, waitlistLeftPollOut).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedWaitlistLeftPost},
+			ExpectedMsg:        "All votes are cleared. Your previous votes were [].",
+		},
+		"Failed to get poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusInternalServerError,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Failed to get display name": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll.Copy(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"invalid user id": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll.Copy(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"failed to save poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll4WithVotes.Copy(), nil)
+				store.PollStore.On("Update", poll4WithVotes, poll).Return(&model.AppError{})
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil).Maybe()
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/votes/reset", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				require.NotNil(t, response)
+				assert.Equal(test.ExpectedResponse.EphemeralText, response.EphemeralText)
+				if test.ExpectedResponse.Update != nil {
+					assert.Equal(test.ExpectedResponse.Update.Props["card"], response.Update.Props["card"])
+					assert.Equal(test.ExpectedResponse.Update.Attachments(), response.Update.Attachments())
+				}
+			} else {
+				assert.Equal(test.ExpectedResponse, response)
+			}
+		})
+	}
+}
+func TestHandleDelegateVoteConfirm(t *testing.T) {
+	pollIn := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+	pollOut := pollIn.Copy()
+	pollOut.Delegates = map[string]string{"userID2": "userID3"}
+
+	delegatedPollIn := pollOut.Copy()
+	revokedPollOut := delegatedPollIn.Copy()
+	revokedPollOut.Delegates = nil
+
+	for name, test := range map[string]struct {
+		SetupAPI         func(*plugintest.API) *plugintest.API
+		SetupStore       func(*mockstore.Store) *mockstore.Store
+		Request          *model.SubmitDialogRequest
+		ExpectedResponse *model.SubmitDialogResponse
+		ExpectedMsg      string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetDirectChannel", "userID3", testutils.GetBotUserID()).Return(&model.Channel{Id: "channelID3"}, nil)
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID3",
+					Message:   fmt.Sprintf("**@user2** delegated their vote in the poll [Question](%s/_redirect/pl/postID1) to you. Use **Vote by Proxy** to vote on their behalf.", testutils.GetSiteURL()),
+				}).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"delegate": "userID3"},
+			},
+			ExpectedResponse: nil,
+			ExpectedMsg:      "Your vote has been delegated. Your delegate can now vote on your behalf.",
+		},
+		"Valid request, revoke delegation": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(delegatedPollIn.Copy(), nil)
+				store.PollStore.On("Update", delegatedPollIn, revokedPollOut).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedResponse: nil,
+			ExpectedMsg:      "Your vote isn't delegated anymore.",
+		},
+		"Delegate to themselves": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"delegate": "userID2"},
+			},
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"delegate": "You can't delegate your vote to yourself."},
+			},
+		},
+		"Delegate can't read the channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(false)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"delegate": "userID3"},
+			},
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"delegate": "This user can't read the channel of the poll."},
+			},
+		},
+		"Poll doesn't allow proxy votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{"delegate": "userID3"},
+			},
+			ExpectedResponse: nil,
+			ExpectedMsg:      "This poll doesn't allow proxy votes.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/delegate", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(http.StatusOK, result.StatusCode)
+			var response *model.SubmitDialogResponse
+			_ = json.NewDecoder(result.Body).Decode(&response)
+			assert.Equal(test.ExpectedResponse, response)
+		})
+	}
+}
+
+func TestHandleProxyVote(t *testing.T) {
+	proxyPoll := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+	proxyPoll.Delegates = map[string]string{"userID5": "userID2"}
+
+	dialogRequest := func(voterElement model.DialogElement) model.OpenDialogRequest {
+		return model.OpenDialogRequest{
+			TriggerId: "triggerID1",
+			URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/proxy", root.Manifest.Id, testutils.GetPollID()),
+			Dialog: model.Dialog{
+				Title:            "Vote by Proxy",
+				IntroductionText: "The vote is attributed to the user you vote for and marked as a proxy vote. The user gets notified about it.",
+				IconURL:          fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+				CallbackId:       "postID1",
+				SubmitLabel:      "Vote",
+				Elements: []model.DialogElement{voterElement, {
+					DisplayName: "Option",
+					Name:        "answer",
+					Type:        "select",
+					Options: []*model.PostActionOptions{
+						{Text: "Answer 1", Value: "0"},
+						{Text: "Answer 2", Value: "1"},
+						{Text: "Answer 3", Value: "2"},
+					},
+				}},
+			},
+		}
+	}
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI    func(*plugintest.API) *plugintest.API
+		SetupStore  func(*mockstore.Store) *mockstore.Store
+		UserID      string
+		ExpectedMsg string
+	}{
+		"Valid request, poll manager": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest(model.DialogElement{
+					DisplayName: "Vote on behalf of",
+					Name:        "voter",
+					Type:        "select",
+					DataSource:  "users",
+				})).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(proxyPoll.Copy(), nil)
+				return store
+			},
+			UserID:      "userID1",
+			ExpectedMsg: "",
+		},
+		"Valid request, delegate": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user5"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest(model.DialogElement{
+					DisplayName: "Vote on behalf of",
+					Name:        "voter",
+					Type:        "select",
+					Options:     []*model.PostActionOptions{{Text: "@user5", Value: "userID5"}},
+				})).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(proxyPoll.Copy(), nil)
+				return store
+			},
+			UserID:      "userID2",
+			ExpectedMsg: "",
+		},
+		"Neither poll manager nor delegate": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3", Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(proxyPoll.Copy(), nil)
+				return store
+			},
+			UserID:      "userID3",
+			ExpectedMsg: "Only the creator of a poll, System Admins and users, to whom someone delegated their vote, are allowed to vote by proxy.",
+		},
+		"Poll doesn't allow proxy votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			UserID:      "userID1",
+			ExpectedMsg: "This poll doesn't allow proxy votes.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			request := &model.PostActionIntegrationRequest{
+				UserId:    test.UserID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: "triggerID1",
+			}
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: request.ChannelId,
+					RootId:    post.Id,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/proxy/request", testutils.GetPollID())
+			b, err := json.Marshal(request)
+			require.Nil(t, err)
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			r.Header.Add("Mattermost-User-ID", request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(http.StatusOK, result.StatusCode)
+		})
+	}
+}
+
+func TestHandleProxyVoteConfirm(t *testing.T) {
+	pollIn := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+	pollIn.Delegates = map[string]string{"userID5": "userID2"}
+
+	managerPollOut := pollIn.Copy()
+	_, err := managerPollOut.UpdateVoteByProxy("userID1", "userID5", 2)
+	require.NoError(t, err)
+	managerPollOut.UpdateVoteTimes(pollIn, "userID5", testutils.GetMillis())
+	expectedManagerPost := &model.Post{ChannelId: "channelID1"}
+	model.ParseMessageAttachment(expectedManagerPost, managerPollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	delegatePollOut := pollIn.Copy()
+	_, err = delegatePollOut.UpdateVoteByProxy("userID2", "userID5", 2)
+	require.NoError(t, err)
+	delegatePollOut.UpdateVoteTimes(pollIn, "userID5", testutils.GetMillis())
+	expectedDelegatePost := &model.Post{ChannelId: "channelID1"}
+	model.ParseMessageAttachment(expectedDelegatePost, delegatePollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	request := func(userID, voterID string) *model.SubmitDialogRequest {
+		return &model.SubmitDialogRequest{
+			UserId:     userID,
+			ChannelId:  "channelID1",
+			Submission: map[string]interface{}{"voter": voterID, "answer": "2"},
+		}
+	}
+	voteEvent := func(proxyID string) *poll.VoteEvent {
+		return &poll.VoteEvent{Type: poll.VoteEventVote, UserID: "userID5", Answers: []string{"Answer 3"}, At: testutils.GetMillis(), ProxyID: proxyID}
+	}
+	notification := func(proxy string) *model.Post {
+		return &model.Post{
+			UserId:    testutils.GetBotUserID(),
+			ChannelId: "channelID5",
+			Message:   fmt.Sprintf("**%s** voted for **Answer 3** on your behalf in the poll [Question](%s/_redirect/pl/postID1).", proxy, testutils.GetSiteURL()),
+		}
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI         func(*plugintest.API) *plugintest.API
+		SetupStore       func(*mockstore.Store) *mockstore.Store
+		Request          *model.SubmitDialogRequest
+		ExpectedResponse *model.SubmitDialogResponse
+		ExpectedMsg      string
+	}{
+		"Valid request, poll manager": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user5"}, nil)
+				api.On("UpdatePost", expectedManagerPost).Return(expectedManagerPost, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID5"}).Return().Maybe()
+				api.On("GetDirectChannel", "userID5", testutils.GetBotUserID()).Return(&model.Channel{Id: "channelID5"}, nil)
+				api.On("CreatePost", notification("@user1")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, managerPollOut).Return(nil)
+				store.VoteHistoryStore.On("Append", testutils.GetPollID(), voteEvent("userID1")).Return(nil)
+				return store
+			},
+			Request:          request("userID1", "userID5"),
+			ExpectedResponse: nil,
+			ExpectedMsg:      "The proxy vote has been counted.",
+		},
+		"Valid request, delegate": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user5"}, nil)
+				api.On("UpdatePost", expectedDelegatePost).Return(expectedDelegatePost, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID5"}).Return().Maybe()
+				api.On("GetDirectChannel", "userID5", testutils.GetBotUserID()).Return(&model.Channel{Id: "channelID5"}, nil)
+				api.On("CreatePost", notification("@user2")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, delegatePollOut).Return(nil)
+				store.VoteHistoryStore.On("Append", testutils.GetPollID(), voteEvent("userID2")).Return(nil)
+				return store
+			},
+			Request:          request("userID2", "userID5"),
+			ExpectedResponse: nil,
+			ExpectedMsg:      "The proxy vote has been counted.",
+		},
+		"Neither poll manager nor delegate": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID3", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3", Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request:          request("userID3", "userID5"),
+			ExpectedResponse: nil,
+			ExpectedMsg:      "Only the creator of a poll, System Admins and users, to whom someone delegated their vote, are allowed to vote by proxy.",
+		},
+		"Vote for themselves": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: request("userID1", "userID1"),
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"voter": "Use the buttons of the poll to vote for yourself."},
+			},
+		},
+		"Voter can't read the channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID6", "channelID1", model.PermissionReadChannel).Return(false)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: request("userID1", "userID6"),
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"voter": "This user can't read the channel of the poll."},
+			},
+		},
+		"Voter isn't eligible": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user5"}, nil)
+				api.On("GetGroupsForUser", "userID5").Return([]*model.Group{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				restrictedPoll := pollIn.Copy()
+				restrictedPoll.Settings.Voters = &poll.Voters{Names: []string{"user1"}}
+				store.PollStore.On("Get", testutils.GetPollID()).Return(restrictedPoll, nil)
+				return store
+			},
+			Request: request("userID1", "userID5"),
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"voter": "This user is not allowed to vote in this poll."},
+			},
+		},
+		"Voter already voted by themselves": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				votedPoll := pollIn.Copy()
+				_, err := votedPoll.UpdateVote("userID5", 0)
+				require.NoError(t, err)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(votedPoll, nil)
+				return store
+			},
+			Request: request("userID1", "userID5"),
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"voter": "This user has already voted by themselves. Only their proxy votes can be changed."},
+			},
+		},
+		"Valid request, replace earlier proxy vote": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", "userID5", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID5").Return(&model.User{Username: "user5"}, nil)
+				api.On("UpdatePost", expectedManagerPost).Return(expectedManagerPost, nil)
+				api.On("PublishWebSocketEvent", "has_voted", mock.Anything, &model.WebsocketBroadcast{UserId: "userID5"}).Return().Maybe()
+				api.On("GetDirectChannel", "userID5", testutils.GetBotUserID()).Return(&model.Channel{Id: "channelID5"}, nil)
+				api.On("CreatePost", notification("@user1")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				proxyPoll := pollIn.Copy()
+				_, err := proxyPoll.UpdateVoteByProxy("userID2", "userID5", 0)
+				require.NoError(t, err)
+				proxyPoll.UpdateVoteTimes(pollIn, "userID5", testutils.GetMillis())
+				store.PollStore.On("Get", testutils.GetPollID()).Return(proxyPoll.Copy(), nil)
+				store.PollStore.On("Update", proxyPoll, managerPollOut).Return(nil)
+				store.VoteHistoryStore.On("Append", testutils.GetPollID(), mock.AnythingOfType("*poll.VoteEvent")).Return(nil)
+				return store
+			},
+			Request:          request("userID1", "userID5"),
+			ExpectedResponse: nil,
+			ExpectedMsg:      "The proxy vote has been counted.",
+		},
+		"Poll doesn't allow proxy votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(&model.Post{ChannelId: "channelID1"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request:          request("userID1", "userID5"),
+			ExpectedResponse: nil,
+			ExpectedMsg:      "This poll doesn't allow proxy votes.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/proxy", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(http.StatusOK, result.StatusCode)
+			var response *model.SubmitDialogResponse
+			_ = json.NewDecoder(result.Body).Decode(&response)
+			assert.Equal(test.ExpectedResponse, response)
+		})
+	}
+}
+
+func TestHandleAddOption(t *testing.T) {
+	userID := testutils.GetPollWithVotes().Creator
+	triggerID := model.NewId()
+
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: userID, PostId: "postID1", TriggerId: triggerID}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/option/add/request", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	dialogRequest := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/add", root.Manifest.Id, testutils.GetPollID()),
+		Dialog: model.Dialog{
+			Title:       "Add Option",
+			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:  "postID1",
+			SubmitLabel: "Add",
+			Elements: []model.DialogElement{{
+				DisplayName: "Option",
+				Name:        "answerOption",
+				Type:        "text",
+				SubType:     "text",
+			},
+			},
+		},
+	}
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, issuer is system admin": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{
+					Username: "user2",
+					Roles:    model.SystemAdminRoleId + " " + model.SystemUserRoleId,
+				}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to add options.",
+		},
+		"Valid request, public add option, ineligible user": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				api.On("GetGroupsForUser", "userID2").Return([]*model.Group{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithSettings(poll.Settings{
+					PublicAddOption: true,
+					MaxVotes:        1,
+					Voters:          &poll.Voters{Names: []string{"user1"}},
+				}), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "You are not allowed to add options to this poll, as you are not allowed to vote in it.",
+		},
+		"Valid request, GetUser fails for issuer": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, post with invalid channelID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := post.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", "postID1").Return(p, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request, without permission to read channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/option/add/request", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				assert.Equal(response, &model.PostActionIntegrationResponse{})
+			} else {
+				assert.Nil(response)
+			}
+		})
+	}
+}
+
+func TestHandleAddOptionConfirm(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		switch userID {
+		case "userID1":
+			return "@jhDoe", nil
+		case "userID2":
+			return "@jhDoe2", nil
+		case "userID3":
+			return "@jhDoe3", nil
+		case "userID4":
+			return "@jhDoe4", nil
+		default:
+			return "", &model.AppError{}
+		}
+	}
+
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: "userID1", TeamId: "teamID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/option/add", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+		result := w.Result()
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	userID := testutils.GetPollWithVotes().Creator
+	channelID := model.NewId()
+	postID := model.NewId()
+
+	poll1In := testutils.GetPollWithVotes()
+	poll1In.PostID = postID
+	poll1Out := poll1In.Copy()
+	err := poll1Out.AddAnswerOption("New Option")
+	require.Nil(t, err)
+	expectedPost1 := &model.Post{
+		ChannelId: channelID,
+	}
+	model.ParseMessageAttachment(expectedPost1, poll1Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	poll2In := testutils.GetPollWithoutPostID()
+	poll2Out := poll2In.Copy()
+	err = poll2Out.AddAnswerOption("New Option")
+	require.Nil(t, err)
+	expectedPost2 := &model.Post{}
+	model.ParseMessageAttachment(expectedPost2, poll2Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	poll3In := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 2})
+	poll3In.PostID = postID
+	poll3Out := poll3In.Copy()
+	err = poll3Out.AddAnswerOption("New Option")
+	require.Nil(t, err)
+	expectedPost3 := &model.Post{
+		ChannelId: channelID,
+	}
+	expectedPost3.AddProp("card", poll3Out.ToCard(testutils.GetBundle(), converter))
+	model.ParseMessageAttachment(expectedPost3, poll3Out.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPost1).Return(expectedPost1, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
+				store.PollStore.On("Update", poll1In, poll1Out).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully added the option.",
+		},
+		"Valid request, restricted poll, ineligible user": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", "userID2", channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				api.On("GetGroupsForUser", "userID2").Return([]*model.Group{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				restrictedPoll := poll1In.Copy()
+				restrictedPoll.Settings.PublicAddOption = true
+				restrictedPoll.Settings.Voters = &poll.Voters{Names: []string{"user1"}}
+				store.PollStore.On("Get", testutils.GetPollID()).Return(restrictedPoll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "You are not allowed to add options to this poll, as you are not allowed to vote in it.",
+		},
+		"Valid request, excluded bot": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", "userID2", channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Id: "userID2", IsBot: true, Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				excludingPoll := poll1In.Copy()
+				excludingPoll.Settings.PublicAddOption = true
+				excludingPoll.Settings.ExcludeBots = true
+				store.PollStore.On("Get", testutils.GetPollID()).Return(excludingPoll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Bots are not allowed to take part in this poll.",
+		},
+		"Valid request, with Progress settings": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost3, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe", Username: "jhDoe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "jhDoe2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "jhDoe3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "jhDoe4"}, nil)
+				api.On("UpdatePost", expectedPost3).Return(expectedPost3, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll3In.Copy(), nil)
+				store.PollStore.On("Update", poll3In, poll3Out).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption":      "New Option",
+					"settings-progress": true,
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully added the option.",
+		},
+		"Valid request, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				api.On("UpdatePost", expectedPost2).Return(expectedPost2, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll2In.Copy(), nil)
+				store.PollStore.On("Update", poll2In, poll2Out).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully added the option.",
+		},
+		"Valid request, GetUser fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = postID
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request with integer": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = postID
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": 1,
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, duplicate new answeroption": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = postID
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": poll1In.AnswerOptions[0].Answer,
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{
+					"answerOption": "Duplicate option: Answer 1",
+				},
+			},
+			ExpectedMsg: "",
+		},
+		"Valid request, UpdatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				api.On("UpdatePost", expectedPost1).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = postID
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, PollStore.Save fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetPost", postID).Return(&model.Post{}, nil)
+				api.On("UpdatePost", expectedPost1).Return(expectedPost1, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
+				store.PollStore.On("Update", poll1In, poll1Out).Return(errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = postID
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, post with invalid channelId": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := expectedPost1.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", postID).Return(p, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = postID
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request, without permission to read channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost1, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll1In.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"answerOption": "New Option",
+				},
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/option/add", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+			if test.ExpectedResponse != nil {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+			}
+		})
+	}
+}
+
+func TestHandleManageOptions(t *testing.T) {
+	userID := testutils.GetPollWithVotes().Creator
+	triggerID := model.NewId()
+
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: userID, PostId: "postID1", TriggerId: triggerID}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/option/manage/request", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	positions := []*model.PostActionOptions{
+		{Text: "1", Value: "1"},
+		{Text: "2", Value: "2"},
+		{Text: "3", Value: "3"},
+	}
+	elements := []model.DialogElement{}
+	for i, o := range testutils.GetPollWithVotes().AnswerOptions {
+		elements = append(elements, model.DialogElement{
+			DisplayName: fmt.Sprintf("Option %d", i+1),
+			Name:        fmt.Sprintf("option%d", i),
+			Type:        "text",
+			SubType:     "text",
+			Default:     o.Answer,
+			HelpText:    "Rename the option or clear it to delete the option and its votes.",
+			Optional:    true,
+		}, model.DialogElement{
+			DisplayName: fmt.Sprintf("Position of option %d", i+1),
+			Name:        fmt.Sprintf("position%d", i),
+			Type:        "select",
+			Options:     positions,
+			Default:     fmt.Sprintf("%d", i+1),
+		})
+	}
+	dialogRequest := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/manage", root.Manifest.Id, testutils.GetPollID()),
+		Dialog: model.Dialog{
+			Title:            "Manage Options",
+			IntroductionText: "Votes for renamed or moved options are kept. Voters of renamed and deleted options get notified.",
+			IconURL:          fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:       "postID1",
+			SubmitLabel:      "Save",
+			Elements:         elements,
+		},
+	}
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to manage its options.",
+		},
+		"Valid request, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/option/manage/request", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				assert.Equal(response, &model.PostActionIntegrationResponse{})
+			} else {
+				assert.Nil(response)
+			}
+		})
+	}
+}
+
+func TestHandleManageOptionsConfirm(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.SubmitDialogRequest{UserId: "userID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/option/manage", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+		result := w.Result()
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	userID := testutils.GetPollWithVotes().Creator
+	channelID := model.NewId()
+	postID := model.NewId()
+
+	// Rename "Answer 2", delete "Answer 3" and move "Answer 1" to the end
+	submission := map[string]interface{}{
+		"option0":   "Answer 1",
+		"position0": "3",
+		"option1":   "New Answer 2",
+		"position1": "1",
+		"option2":   nil,
+		"position2": "2",
+	}
+
+	pollIn := testutils.GetPollWithVotes()
+	pollIn.PostID = postID
+	pollOut := pollIn.Copy()
+	pollOut.AnswerOptions = []*poll.AnswerOption{
+		{Answer: "New Answer 2", Voter: []string{"userID4"}},
+		{Answer: "Answer 1", Voter: []string{"userID1", "userID2", "userID3"}},
+	}
+	expectedPost := &model.Post{
+		ChannelId: channelID,
+	}
+	model.ParseMessageAttachment(expectedPost, pollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UpdatePost", expectedPost).Return(expectedPost, nil)
+				api.On("SendEphemeralPost", "userID4", &model.Post{
+					ChannelId: channelID,
+					UserId:    testutils.GetBotUserID(),
+					Message:   `The option "Answer 2" of the poll **Question** was renamed to "New Answer 2". Your vote for it was kept.`,
+				}).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully updated the options.",
+		},
+		"Valid request, voters of deleted option get notified": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := pollIn.Copy()
+				p.AnswerOptions = p.AnswerOptions[:2]
+				post := &model.Post{ChannelId: channelID}
+				model.ParseMessageAttachment(post, p.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+				api.On("GetPost", postID).Return(post.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(post, nil)
+				api.On("SendEphemeralPost", "userID4", &model.Post{
+					ChannelId: channelID,
+					UserId:    testutils.GetBotUserID(),
+					Message:   `The option "Answer 2" of the poll **Question** was deleted. Your vote for it was removed.`,
+				}).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				p := pollIn.Copy()
+				p.AnswerOptions = p.AnswerOptions[:2]
+				store.PollStore.On("Get", testutils.GetPollID()).Return(p.Copy(), nil)
+				store.PollStore.On("Update", p, mock.AnythingOfType("*poll.Poll")).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"option0":   "Answer 1",
+					"position0": "1",
+					"option1":   "",
+					"position1": "2",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully updated the options.",
+		},
+		"Valid request, duplicate option": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"option0":   "Answer 1",
+					"position0": "1",
+					"option1":   "Answer 1",
+					"position1": "2",
+					"option2":   "Answer 3",
+					"position2": "3",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Error: "Duplicate option: Answer 1",
+			},
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID2", channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to manage its options.",
+		},
+		"Valid request, invalid submission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"option0": "Answer 1",
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, PollStore.Update fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(expectedPost.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/option/manage", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+			if test.ExpectedResponse != nil {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+			}
+		})
+	}
+}
+
+func TestHandleEditPoll(t *testing.T) {
+	userID := testutils.GetPollWithVotes().Creator
+	triggerID := model.NewId()
+
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: userID, PostId: "postID1", TriggerId: triggerID}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/edit/request", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	dialogRequest := func(elements []model.DialogElement) model.OpenDialogRequest {
+		return model.OpenDialogRequest{
+			TriggerId: triggerID,
+			URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/edit", root.Manifest.Id, testutils.GetPollID()),
+			Dialog: model.Dialog{
+				Title:       "Edit Poll",
+				IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+				CallbackId:  "postID1",
+				SubmitLabel: "Save",
+				Elements:    elements,
+			},
+		}
+	}
+	questionElement := model.DialogElement{
+		DisplayName: "Question",
+		Name:        "question",
+		Type:        "text",
+		SubType:     "text",
+		Default:     "Question",
+	}
+	progressElement := model.DialogElement{
+		DisplayName: "Progress",
+		Name:        "setting-progress",
+		Type:        "bool",
+		Placeholder: "During the poll, show how many votes each answer option got",
+		Default:     "true",
+		Optional:    true,
+	}
+	pollDialogRequest := dialogRequest([]model.DialogElement{questionElement, {
+		DisplayName: "Number of Votes",
+		Name:        "setting-multi",
+		Type:        "text",
+		SubType:     "number",
+		Default:     "2",
+		HelpText:    "The number of options that a user can vote on. It can't be lower than the number of votes a user already cast. 0 means that users can vote for all options.",
+	}, progressElement, {
+		DisplayName: "Results after vote",
+		Name:        "setting-results-after-vote",
+		Type:        "bool",
+		Placeholder: "Show the progress to users only after they voted, so early results don't bias later voters",
+		Default:     "false",
+		Optional:    true,
+	}, {
+		DisplayName: "Public Add Option",
+		Name:        "setting-public-add-option",
+		Type:        "bool",
+		Placeholder: "Allow all users to add additional options",
+		Default:     "false",
+		Optional:    true,
+	}})
+	surveyQuestionElement := questionElement
+	surveyQuestionElement.Default = "Survey"
+	surveyDialogRequest := dialogRequest([]model.DialogElement{surveyQuestionElement, progressElement})
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", pollDialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 2}), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, survey": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", surveyDialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				survey := testutils.GetSurveyWithAnswers()
+				survey.Settings.Progress = true
+				store.PollStore.On("Get", testutils.GetPollID()).Return(survey, nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to edit it.",
+		},
+		"Valid request, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", pollDialogRequest).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 2}), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/edit/request", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				assert.Equal(response, &model.PostActionIntegrationResponse{})
+			} else {
+				assert.Nil(response)
+			}
+		})
+	}
+}
+
+func TestHandleEditPollConfirm(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.SubmitDialogRequest{UserId: "userID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/edit", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+		result := w.Result()
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	userID := testutils.GetPollWithVotes().Creator
+	channelID := model.NewId()
+	postID := model.NewId()
+
+	submission := map[string]interface{}{
+		"question":                   "New Question",
+		"setting-multi":              float64(2),
+		"setting-progress":           false,
+		"setting-results-after-vote": false,
+		"setting-public-add-option":  true,
+	}
+
+	pollIn := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 1})
+	pollIn.PostID = postID
+	pollOut := pollIn.Copy()
+	pollOut.Question = "New Question"
+	pollOut.Settings.Progress = false
+	pollOut.Settings.PublicAddOption = true
+	pollOut.Settings.MaxVotes = 2
+	pollOut.EditedAt = testutils.GetMillis()
+
+	// The post of a poll with progress has a card, which is removed once the progress setting is turned off
+	postIn := &model.Post{
+		ChannelId: channelID,
+	}
+	postIn.AddProp("card", "card")
+	expectedPost := &model.Post{
+		ChannelId: channelID,
+	}
+	model.ParseMessageAttachment(expectedPost, pollOut.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(postIn.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPost).Return(expectedPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully edited the poll.",
+		},
+		"Valid request, max votes below votes of users": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(postIn.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				p := pollIn.Copy()
+				p.Settings.MaxVotes = 0
+				p.AnswerOptions[1].Voter = append(p.AnswerOptions[1].Voter, "userID1")
+				store.PollStore.On("Get", testutils.GetPollID()).Return(p, nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{
+					"setting-multi": float64(1),
+				},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Error: `Users already voted for up to 2 options, so the number of votes can't be lower than that. You specified "1".`,
+			},
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(postIn.Clone(), nil)
+				api.On("HasPermissionToChannel", "userID2", channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to edit it.",
+		},
+		"Valid request, PollStore.Update fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(postIn.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, UpdatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", postID).Return(postIn.Clone(), nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("UpdatePost", expectedPost).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollIn.Copy(), nil)
+				store.PollStore.On("Update", pollIn, pollOut).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: postID,
+				ChannelId:  channelID,
+				Submission: submission,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/edit", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+			if test.ExpectedResponse != nil {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+			}
+		})
+	}
+}
+
+func TestHandleViewHistory(t *testing.T) {
+	userID := testutils.GetPollWithVotes().Creator
+	berlinUser := &model.User{
+		Username: "user1",
+		Timezone: model.StringMap{"useAutomaticTimezone": "false", "manualTimezone": "Europe/Berlin"},
+	}
+	history := poll.VoteHistory{{Type: poll.VoteEventVote, UserID: "userID2", Answers: []string{"Answer 1"}, At: testutils.GetMillis()}}
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(berlinUser, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.VoteHistoryStore.On("Get", testutils.GetPollID()).Return(history, nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: userID, ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "#### Vote history of Question\n- Thu, 15 Jan 1970 07:56 CET: **@user2** voted for Answer 1",
+		},
+		"Valid request, ended poll without votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.VoteHistoryStore.On("Get", testutils.GetPollID()).Return(poll.VoteHistory{}, nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: userID, ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "#### Vote history of Question\nNobody has voted yet.",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to view its vote history.",
+		},
+		"Valid request, anonymous poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 1}), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: userID, ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Anonymous polls have no vote history.",
+		},
+		"Valid request, VoteHistoryStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.VoteHistoryStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: userID, ChannelId: "channelID1", PostId: "postID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: userID, PostId: "postID1"},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/history/request", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestHandleRerunPoll(t *testing.T) {
+	userID := testutils.GetPollWithVotes().Creator
+	triggerID := model.NewId()
+
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: userID, PostId: "postID1", TriggerId: triggerID}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/rerun/request", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	dialogRequest := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/rerun", root.Manifest.Id, testutils.GetPollID()),
+		Dialog: model.Dialog{
+			Title:            "Run Poll Again",
+			IconURL:          fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:       "postID1",
+			IntroductionText: fmt.Sprintf("A new poll with the same question, options and settings is posted. Votes aren't copied. You can also type `/poll rerun %s`.", testutils.GetPollID()),
+			SubmitLabel:      "Run",
+			Elements: []model.DialogElement{{
+				DisplayName: "Channel",
+				Name:        "channel",
+				Type:        "select",
+				DataSource:  "channels",
+				Default:     "channelID1",
+			}},
+		},
+	}
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, ended poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to run it again.",
+		},
+		"Valid request, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("OpenInteractiveDialog", dialogRequest).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    userID,
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/rerun/request", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				assert.Equal(response, &model.PostActionIntegrationResponse{})
+			} else {
+				assert.Nil(response)
+			}
+		})
+	}
+}
+
+func TestHandleRerunPollConfirm(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.SubmitDialogRequest{UserId: "userID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/rerun", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+		result := w.Result()
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	userID := testutils.GetPollWithVotes().Creator
+	channelID := model.NewId()
+	otherChannelID := model.NewId()
+	newPollID := model.NewId()
+
+	post := &model.Post{
+		ChannelId: channelID,
+	}
+
+	newPoll := testutils.GetPollWithoutPostID()
+	newPoll.ID = newPollID
+	expectedPost := &model.Post{
+		UserId:    testutils.GetBotUserID(),
+		ChannelId: otherChannelID,
+		Type:      MatterpollPostType,
+		Props: model.StringInterface{
+			"poll_id": newPollID,
+		},
+	}
+	model.ParseMessageAttachment(expectedPost, newPoll.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+	createdPost := expectedPost.Clone()
+	createdPost.Id = "postID2"
+	insertedPoll := newPoll.Copy()
+	insertedPoll.PostID = "postID2"
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", userID, otherChannelID, model.PermissionCreatePost).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("CreatePost", expectedPost).Return(createdPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Insert", insertedPoll).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: "postID1",
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{"channel": otherChannelID},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully ran the poll again.",
+		},
+		"Valid request, no permission to post": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", userID, otherChannelID, model.PermissionCreatePost).Return(false)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: "postID1",
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{"channel": otherChannelID},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse: &model.SubmitDialogResponse{
+				Errors: map[string]string{"channel": "You are not allowed to post in this channel."},
+			},
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", channelID, model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID2",
+				CallbackId: "postID1",
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{"channel": otherChannelID},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to run it again.",
+		},
+		"Valid request, CreatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+				api.On("HasPermissionToChannel", userID, otherChannelID, model.PermissionCreatePost).Return(true)
+				api.On("GetUser", userID).Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("CreatePost", expectedPost).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: "postID1",
+				ChannelId:  channelID,
+				Submission: map[string]interface{}{"channel": otherChannelID},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     userID,
+				CallbackId: "postID1",
+				ChannelId:  channelID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetNewID(func() string { return newPollID })
+			p.pf.SetMillis(testutils.GetMillis)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/rerun", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+			if test.ExpectedResponse != nil {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+			}
+		})
+	}
+}
+
+func TestHandleEndPoll(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "postID1"}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/end", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	triggerID := model.NewId()
+	dialog := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/end/confirm", root.Manifest.Id, testutils.GetPollID()),
+		Dialog: model.Dialog{
+			Title:       "Confirm Poll End",
+			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:  "postID1",
+			SubmitLabel: "End",
+		},
+	}
+
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request with no votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request with no votes, issuer is system admin": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{
+					Username: "user2",
+					Roles:    model.SystemAdminRoleId + " " + model.SystemUserRoleId,
+				}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, GetUser fails for issuer": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to end it.",
+		},
+		"Valid request, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, Store.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, post with invalid channelID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := post.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", "postID1").Return(p, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request, without permission to read channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/end", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				assert.Equal(response, &model.PostActionIntegrationResponse{})
+			} else {
+				assert.Nil(response)
+			}
+		})
+	}
+}
+
+func TestHandleEndPollConfirm(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.SubmitDialogRequest{}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/end/confirm", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	converter := func(userID string) (string, *model.AppError) {
+		switch userID {
+		case "userID1":
+			return "@user1", nil
+		case "userID2":
+			return "@user2", nil
+		case "userID3":
+			return "@user3", nil
+		case "userID4":
+			return "@user4", nil
+		default:
+			return "", &model.AppError{}
+		}
+	}
+	expectedPost, err := testutils.GetPollWithVotes().ToEndPollPost(testutils.GetBundle(), root.Manifest.Id, "John Doe", converter)
+	require.Nil(t, err)
+	expectedPost.Id = "postID1"
+	expectedPostWithoutChart := expectedPost.Clone()
+	expectedPost.FileIds = model.StringArray{"fileID1"}
+
+	expectedQuizPost, err := testutils.GetQuizPollWithVotes().ToEndPollPost(testutils.GetBundle(), root.Manifest.Id, "John Doe", converter)
+	require.Nil(t, err)
+	expectedQuizPost.Id = "postID1"
+	expectedQuizPost.FileIds = model.StringArray{"fileID1"}
+
+	tiedPoll := testutils.GetPollWithVotesAndSettings(poll.Settings{TieBreak: poll.TieBreakRunoff, MaxVotes: 1})
+	tiedPoll.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+	tiedPoll.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+	expectedTiedPost, err := tiedPoll.ToEndPollPost(testutils.GetBundle(), root.Manifest.Id, "John Doe", converter)
+	require.Nil(t, err)
+	expectedTiedPost.Id = "postID1"
+	expectedTiedPost.FileIds = model.StringArray{"fileID1"}
+
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request with votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedPost).Return(nil, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetPollWithVotes()).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Valid request with tie, runoff": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedTiedPost).Return(nil, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.Type == MatterpollPostType && post.RootId == ""
+				})).Return(&model.Post{Id: "runoffPostID"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.RootId == "postID1" && strings.Contains(post.Message, "A runoff poll between the tied options has been started. "+
+						"You can jump to it by pressing [here](https://example.org/_redirect/pl/runoffPostID).")
+				})).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(tiedPoll.Copy(), nil)
+				store.PollStore.On("End", tiedPoll).Return(nil)
+				store.PollStore.On("Insert", mock.MatchedBy(func(runoff *poll.Poll) bool {
+					return runoff.PostID == "runoffPostID" && len(runoff.AnswerOptions) == 2 && runoff.Settings.TieBreak == poll.TieBreakNone
+				})).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Valid request, UploadFile fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				api.On("UpdatePost", expectedPostWithoutChart).Return(nil, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetPollWithVotes()).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Valid request, quiz": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedQuizPost).Return(nil, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetQuizPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetQuizPollWithVotes()).Return(nil)
+				store.LeaderboardStore.On("AddQuizResults", "channelID1", testutils.GetQuizPollWithVotes()).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Valid request, quiz, adding the results to the leaderboard fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedQuizPost).Return(nil, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetQuizPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetQuizPollWithVotes()).Return(nil)
+				store.LeaderboardStore.On("AddQuizResults", "channelID1", testutils.GetQuizPollWithVotes()).Return(&model.AppError{})
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Valid request, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedPost).Return(nil, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithVotes().Copy()
+				poll.PostID = ""
+				store.PollStore.On("Get", testutils.GetPollID()).Return(poll, nil)
+				store.PollStore.On("End", poll).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Valid request, GetUser fails for poll creator": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("Save", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("DeleteEnded", testutils.GetPollWithVotes()).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, GetUser fails for voter": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("Save", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("DeleteEnded", testutils.GetPollWithVotes()).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, UpdatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedPost).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("Save", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("DeleteEnded", testutils.GetPollWithVotes()).Return(nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, PollStore.Delete fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("End", testutils.GetPollWithVotes()).Return(&model.AppError{})
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, poll ended in the meantime": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				return api
+			},
+			SetupStore: func(s *mockstore.Store) *mockstore.Store {
+				s.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				s.PollStore.On("End", testutils.GetPollWithVotes()).Return(store.ErrPollAlreadyEnded)
+				return s
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID2", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, post with invalid channelID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := post.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", "postID1").Return(p, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request, without permission to read channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request:            &model.SubmitDialogRequest{UserId: "userID1", ChannelId: "channelID1", CallbackId: "postID1", TeamId: "teamID1"},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/end/confirm", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+			if test.ExpectedResponse != nil {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+			}
+		})
+	}
+}
+
+func TestPostEndPollAnnouncement(t *testing.T) {
+	const endMessage = "The poll **Question** has ended and the original post has been updated. " +
+		"You can jump to it by pressing [here](https://example.org/_redirect/pl/postID1)."
+	getTiedPoll := func(tieBreak poll.TieBreak) *poll.Poll {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{TieBreak: tieBreak, MaxVotes: 1})
+		p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+		p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+		return p
+	}
+	tieBreakPost := &model.Post{
+		UserId:    testutils.GetBotUserID(),
+		ChannelId: "channelID1",
+		RootId:    "postID1",
+		Message:   endMessage + "\nIt's a tie between **Answer 1**, **Answer 2**.\nThe creator of the poll picks the winner via the buttons below.",
+		Type:      model.PostTypeDefault,
+	}
+	model.ParseMessageAttachment(tieBreakPost, []*model.MessageAttachment{{
+		Actions: []*model.PostAction{{
+			Id:   "breakTie0",
+			Name: "Answer 1",
+			Type: model.PostActionTypeButton,
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/tiebreak/0", root.Manifest.Id, testutils.GetPollID()),
+			},
+		}, {
+			Id:   "breakTie1",
+			Name: "Answer 2",
+			Type: model.PostActionTypeButton,
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/tiebreak/1", root.Manifest.Id, testutils.GetPollID()),
+			},
+		}},
+	}})
+
+	for name, test := range map[string]struct {
+		SetupAPI   func(*plugintest.API) *plugintest.API
+		Poll       *poll.Poll
+		RunoffPost *model.Post
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    "postID1",
+					Message:   endMessage,
+					Type:      model.PostTypeDefault,
+				}).Return(nil, nil)
+				return api
+			},
+			Poll: testutils.GetPoll(),
+		},
+		"Valid request, winner": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    "postID1",
+					Message:   endMessage + "\nThe winner is **Answer 1**.",
+					Type:      model.PostTypeDefault,
+				}).Return(nil, nil)
+				return api
+			},
+			Poll: testutils.GetPollWithVotes(),
+		},
+		"Valid request, tie": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    "postID1",
+					Message:   endMessage + "\nIt's a tie between **Answer 1**, **Answer 2**.",
+					Type:      model.PostTypeDefault,
+				}).Return(nil, nil)
+				return api
+			},
+			Poll: getTiedPoll(poll.TieBreakNone),
+		},
+		"Valid request, creator picks the winner": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", tieBreakPost).Return(nil, nil)
+				return api
+			},
+			Poll: getTiedPoll(poll.TieBreakCreator),
+		},
+		"Valid request, runoff": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    "postID1",
+					Message: endMessage + "\nIt's a tie between **Answer 1**, **Answer 2**." +
+						"\nA runoff poll between the tied options has been started. You can jump to it by pressing [here](https://example.org/_redirect/pl/runoffPostID).",
+					Type: model.PostTypeDefault,
+				}).Return(nil, nil)
+				return api
+			},
+			Poll:       getTiedPoll(poll.TieBreakRunoff),
+			RunoffPost: &model.Post{Id: "runoffPostID"},
+		},
+		"Valid request, CreatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			Poll: testutils.GetPoll(),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, &mockstore.Store{})
+			p.postEndPollAnnouncement(test.Poll, "channelID1", "postID1", test.RunoffPost)
+		})
+	}
+}
+
+func TestHandleBreakTie(t *testing.T) {
+	bundle := testutils.GetBundle()
+	getTiedPoll := func() *poll.Poll {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{TieBreak: poll.TieBreakCreator, MaxVotes: 1})
+		p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+		p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+		return p
+	}
+	brokenPoll := getTiedPoll()
+	brokenPoll.TieBreakWinner = "Answer 2"
+
+	usernames := map[string]string{"userID1": "user1", "userID2": "user2", "userID3": "user3", "userID4": "user4"}
+	converter := func(userID string) (string, *model.AppError) {
+		return "@" + usernames[userID], nil
+	}
+	expectedEndPost, appErr := brokenPoll.ToEndPollPost(bundle, root.Manifest.Id, "user1", converter)
+	require.Nil(t, appErr)
+	expectedEndPost.Id = "postID1"
+	expectedEndPost.FileIds = model.StringArray{"fileID1"}
+	expectedAnnouncement := &model.Post{
+		UserId:    testutils.GetBotUserID(),
+		ChannelId: "channelID1",
+		RootId:    "postID1",
+		Message: "The poll **Question** has ended and the original post has been updated. " +
+			"You can jump to it by pressing [here](https://example.org/_redirect/pl/postID1).\n" +
+			"The winner is **Answer 2**.\nThe creator of the poll broke the tie.",
+		Type: model.PostTypeDefault,
+	}
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		OptionIndex        int
+		ExpectedStatusCode int
+		ExpectedResponse   *model.PostActionIntegrationResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				for userID, username := range usernames {
+					api.On("GetUser", userID).Return(&model.User{Username: username}, nil)
+				}
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedEndPost).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(getTiedPoll(), nil)
+				store.PollStore.On("SaveEnded", brokenPoll).Return(nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "announcementID1"},
+			OptionIndex:        1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{Update: expectedAnnouncement},
+			ExpectedMsg:        "**Answer 2** is the winner of the poll.",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(getTiedPoll(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID2", ChannelId: "channelID1", PostId: "announcementID1"},
+			OptionIndex:        1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to break a tie.",
+		},
+		"Valid request, tie already broken": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(brokenPoll.Copy(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "announcementID1"},
+			OptionIndex:        0,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "There is no tie to break.",
+		},
+		"Valid request, option isn't tied": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(getTiedPoll(), nil)
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "announcementID1"},
+			OptionIndex:        2,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, PollStore.SaveEnded fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(getTiedPoll(), nil)
+				store.PollStore.On("SaveEnded", brokenPoll).Return(&model.AppError{})
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", ChannelId: "channelID1", PostId: "announcementID1"},
+			OptionIndex:        1,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   &model.PostActionIntegrationResponse{},
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.GetEnded fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request:            &model.PostActionIntegrationRequest{UserId: "userID1", PostId: "announcementID1"},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/tiebreak/%d", testutils.GetPollID(), test.OptionIndex)
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			r := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			if test.ExpectedResponse != nil {
+				var response *model.PostActionIntegrationResponse
+				err = json.NewDecoder(result.Body).Decode(&response)
+				require.Nil(t, err)
+				assert.Equal(test.ExpectedResponse, response)
+			}
+		})
+	}
+}
+
+func TestHandleDeletePoll(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.PostActionIntegrationRequest{}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/delete", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+
+		result := w.Result()
+		require.NotNil(t, result)
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	triggerID := model.NewId()
+	dialog := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/%s/delete/confirm", root.Manifest.Id, testutils.GetPollID()),
+		Dialog: model.Dialog{
+			Title:       "Confirm Poll Delete",
+			IconURL:     fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			CallbackId:  "postID1",
+			SubmitLabel: "Delete",
+		},
+	}
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.PostActionIntegrationRequest
+		ExpectedStatusCode int
+		ExpectedMsg        string
+	}{
+		"Valid request with no votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request with no votes, issuer is system admin": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{
+					Username: "user2",
+					Roles:    model.SystemAdminRoleId + " " + model.SystemUserRoleId,
+				}, nil)
 				api.On("OpenInteractiveDialog", dialog).Return(nil)
 				return api
 			},
@@ -2879,150 +8117,656 @@ func TestHandleDeletePoll(t *testing.T) {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "",
+		},
+		"Valid request, GetUser fails for issuer": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID2",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to delete it.",
+		},
+		"Valid request, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("OpenInteractiveDialog", dialog).Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, Store.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, post with invalid channelID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				p := post.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", "postID1").Return(p, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request, without permission to read channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.PostActionIntegrationRequest{
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				PostId:    "postID1",
+				TriggerId: triggerID,
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Invalid request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/delete", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.PostActionIntegrationResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			if result.StatusCode == http.StatusOK {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+				assert.Equal(response, &model.PostActionIntegrationResponse{})
+			} else {
+				assert.Nil(response)
+			}
+		})
+	}
+}
+
+func TestHandleDeletePollConfirm(t *testing.T) {
+	t.Run("not-authorized", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+		defer api.AssertExpectations(t)
+		p := setupTestPlugin(t, api, &mockstore.Store{})
+		request := &model.SubmitDialogRequest{}
+
+		w := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/polls/%s/delete/confirm", testutils.GetPollID())
+		b, err := json.Marshal(request)
+		require.Nil(t, err)
+		body := bytes.NewReader(b)
+		r := httptest.NewRequest(http.MethodPost, url, body)
+		p.ServeHTTP(nil, w, r)
+		result := w.Result()
+		defer closeBody(t, result.Body)
+
+		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	})
+
+	post := &model.Post{
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		Request            *model.SubmitDialogRequest
+		ExpectedStatusCode int
+		ExpectedResponse   *model.SubmitDialogResponse
+		ExpectedMsg        string
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("DeletePost", "postID1").Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Delete", testutils.GetPoll()).Return(nil)
+				store.VoteHistoryStore.On("Delete", testutils.GetPollID()).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully deleted the poll.",
+		},
+		"Valid request, poll without postID": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("DeletePost", "postID2").Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
+				store.PollStore.On("Delete", testutils.GetPollWithoutPostID()).Return(nil)
+				store.VoteHistoryStore.On("Delete", testutils.GetPollID()).Return(nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID2",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Successfully deleted the poll.",
+		},
+		"Valid request, DeletePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				api.On("DeletePost", "postID1").Return(&model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Valid request, PollStore.Delete fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("DeletePost", "postID1").Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Delete", testutils.GetPoll()).Return(&model.AppError{})
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "Something went wrong. Please try again later.",
+		},
+		"Invalid request, PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, errors.New(""))
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Invalid request, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				return store
+			},
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
 			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "",
+			ExpectedStatusCode: http.StatusInternalServerError,
 		},
-		"Valid request, GetUser fails for issuer": {
+		"Invalid request, post with invalid channelID": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				p := post.Clone()
+				p.ChannelId = "channelID2"
+				api.On("GetPost", "postID1").Return(p, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
 			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			ExpectedStatusCode: http.StatusUnauthorized,
 		},
-		"Valid request, Invalid permission": {
+		"Invalid request, without permission to read channel": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID2", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID2").Return(&model.User{Username: "user2", Roles: model.SystemUserRoleId}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID2",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			Request: &model.SubmitDialogRequest{
+				UserId:     "userID1",
+				CallbackId: "postID1",
+				ChannelId:  "channelID1",
+				Submission: map[string]interface{}{},
+			},
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		"Empty request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Request:            nil,
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   nil,
+			ExpectedMsg:        "",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			if test.ExpectedMsg != "" {
+				ephemeralPost := &model.Post{
+					ChannelId: test.Request.ChannelId,
+					UserId:    testutils.GetBotUserID(),
+					Message:   test.ExpectedMsg,
+				}
+				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
+			}
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/api/v1/polls/%s/delete/confirm", testutils.GetPollID())
+			b, err := json.Marshal(test.Request)
+			require.Nil(t, err)
+			body := bytes.NewReader(b)
+			r := httptest.NewRequest(http.MethodPost, url, body)
+			if test.Request != nil {
+				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
+			} else {
+				r.Header.Add("Mattermost-User-ID", model.NewId())
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+
+			var response *model.SubmitDialogResponse
+			// Don't check if the response typed error is nil in order to do additional assertions.
+			_ = json.NewDecoder(result.Body).Decode(&response)
+
+			assert.Equal(test.ExpectedResponse, response)
+			if test.ExpectedResponse != nil {
+				assert.Equal(http.Header{
+					"Content-Type": []string{"application/json"},
+				}, result.Header)
+			}
+		})
+	}
+}
+
+func TestHandlePollMetadata(t *testing.T) {
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		UserID             string
+		ShouldError        bool
+		ExpectedStatusCode int
+		ExpectedBody       *poll.Metadata
+	}{
+		"Valid request with votes": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
 			},
+			UserID:             "userID1",
+			ShouldError:        false,
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Only the creator of a poll and System Admins are allowed to delete it.",
+			ExpectedBody: (&poll.Metadata{
+				PollID:        testutils.GetPollID(),
+				UserID:        "userID1",
+				CanManagePoll: true,
+				VotedAnswers:  []string{"Answer 1"},
+			}),
 		},
-		"Valid request, OpenInteractiveDialog fails": {
+		"Valid request without votes": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("OpenInteractiveDialog", dialog).Return(&model.AppError{})
+				api.On("GetUser", "userID5").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			UserID:             "userID5",
+			ShouldError:        false,
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody: (&poll.Metadata{
+				PollID:        testutils.GetPollID(),
+				UserID:        "userID5",
+				CanManagePoll: false,
+				VotedAnswers:  []string{},
+			}),
+		},
+		"Valid request without votes, CanManagePoll fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID5").Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return().Maybe()
+				return api
 			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			UserID:             "userID5",
+			ShouldError:        false,
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			ExpectedBody: (&poll.Metadata{
+				PollID:        testutils.GetPollID(),
+				UserID:        "userID5",
+				CanManagePoll: false,
+				VotedAnswers:  []string{},
+			}),
 		},
-		"Invalid request, Store.Get fails": {
+		"Valid request, PollStore.Get fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			UserID:             "userID1",
+			ShouldError:        true,
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/polls/%s/metadata", testutils.GetPollID()), nil)
+			r.Header.Add("Mattermost-User-ID", test.UserID)
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			bodyBytes, err := io.ReadAll(result.Body)
+			require.Nil(t, err)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			if test.ShouldError {
+				assert.Equal([]byte{}, bodyBytes)
+				assert.Equal(http.Header{}, result.Header)
+			} else {
+				assert.Contains([]string{"application/json"}, result.Header.Get("Content-Type"))
+				b := new(bytes.Buffer)
+				err = json.NewEncoder(b).Encode(test.ExpectedBody)
+				assert.Nil(err)
+				assert.Equal(b.Bytes(), bodyBytes)
+			}
+		})
+	}
+}
+
+func TestHandleVoteHistory(t *testing.T) {
+	history := poll.VoteHistory{
+		{Type: poll.VoteEventVote, UserID: "userID2", Answers: []string{"Answer 1"}, At: testutils.GetMillis()},
+		{Type: poll.VoteEventReset, UserID: "userID2", At: testutils.GetMillis() + 1},
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		UserID             string
+		ExpectedStatusCode int
+		ExpectedBody       poll.VoteHistory
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.VoteHistoryStore.On("Get", testutils.GetPollID()).Return(history, nil)
+				return store
+			},
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       history,
 		},
-		"Invalid request, GetPost fails": {
+		"Valid request, system admin and ended poll": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				api.On("GetUser", "userID2").Return(&model.User{Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.VoteHistoryStore.On("Get", testutils.GetPollID()).Return(history, nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			UserID:             "userID2",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       history,
 		},
-		"Invalid request, post with invalid channelID": {
+		"Invalid permission": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := post.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", "postID1").Return(p, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Roles: model.SystemUserRoleId}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			UserID:             "userID2",
+			ExpectedStatusCode: http.StatusForbidden,
 		},
-		"Invalid request, without permission to read channel": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
-				return api
-			},
+		"Anonymous poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 1}), nil)
 				return store
 			},
-			Request: &model.PostActionIntegrationRequest{
-				UserId:    "userID1",
-				ChannelId: "channelID1",
-				PostId:    "postID1",
-				TriggerId: triggerID,
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusForbidden,
+		},
+		"VoteHistoryStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.VoteHistoryStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
 			},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusInternalServerError,
 		},
-		"Invalid request": {
-			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
-			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
-			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedMsg:        "",
+		"PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusInternalServerError,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -3031,32 +8775,14 @@ func TestHandleDeletePoll(t *testing.T) {
 			api := test.SetupAPI(&plugintest.API{})
 			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
 			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
-			if test.ExpectedMsg != "" {
-				ephemeralPost := &model.Post{
-					ChannelId: test.Request.ChannelId,
-					UserId:    testutils.GetBotUserID(),
-					Message:   test.ExpectedMsg,
-				}
-				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
-			}
 			defer api.AssertExpectations(t)
-
 			store := test.SetupStore(&mockstore.Store{})
 			defer store.AssertExpectations(t)
-
 			p := setupTestPlugin(t, api, store)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/delete", testutils.GetPollID())
-			b, err := json.Marshal(test.Request)
-			require.Nil(t, err)
-			body := bytes.NewReader(b)
-			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
-			}
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/polls/%s/history", testutils.GetPollID()), nil)
+			r.Header.Add("Mattermost-User-ID", test.UserID)
 			p.ServeHTTP(nil, w, r)
 
 			result := w.Result()
@@ -3064,221 +8790,299 @@ func TestHandleDeletePoll(t *testing.T) {
 			defer closeBody(t, result.Body)
 
 			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
-
-			var response *model.PostActionIntegrationResponse
-			// Don't check if the response typed error is nil in order to do additional assertions.
-			_ = json.NewDecoder(result.Body).Decode(&response)
-
-			if result.StatusCode == http.StatusOK {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
-				assert.Equal(response, &model.PostActionIntegrationResponse{})
-			} else {
-				assert.Nil(response)
+			if test.ExpectedStatusCode == http.StatusOK {
+				assert.Equal("application/json", result.Header.Get("Content-Type"))
+				var body poll.VoteHistory
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+				assert.Equal(test.ExpectedBody, body)
 			}
 		})
 	}
 }
 
-func TestHandleDeletePollConfirm(t *testing.T) {
-	t.Run("not-authorized", func(t *testing.T) {
-		api := &plugintest.API{}
-		api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
-		defer api.AssertExpectations(t)
-		p := setupTestPlugin(t, api, &mockstore.Store{})
-		request := &model.SubmitDialogRequest{}
-
-		w := httptest.NewRecorder()
-		url := fmt.Sprintf("/api/v1/polls/%s/delete/confirm", testutils.GetPollID())
-		b, err := json.Marshal(request)
-		require.Nil(t, err)
-		body := bytes.NewReader(b)
-		r := httptest.NewRequest(http.MethodPost, url, body)
-		p.ServeHTTP(nil, w, r)
-		result := w.Result()
-		defer closeBody(t, result.Body)
+func closeBody(t testing.TB, c io.Closer) {
+	t.Helper()
 
-		assert.Equal(t, http.StatusUnauthorized, result.StatusCode)
-	})
+	err := c.Close()
+	require.Nil(t, err)
+}
 
-	post := &model.Post{
-		ChannelId: "channelID1",
+func TestHandleExportPoll(t *testing.T) {
+	setupUsers := func(api *plugintest.API) {
+		api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+		api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+		api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+		api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
 	}
+	expectedCSV := "Option,Votes,Username,Display name,Voted at\n" +
+		"Answer 1,3,user1,John Doe,unknown\n" +
+		"Answer 1,3,user2,user2,unknown\n" +
+		"Answer 1,3,user3,user3,unknown\n" +
+		"Answer 2,1,user4,user4,unknown\n" +
+		"Answer 3,0,,,\n"
 
 	for name, test := range map[string]struct {
 		SetupAPI           func(*plugintest.API) *plugintest.API
 		SetupStore         func(*mockstore.Store) *mockstore.Store
-		Request            *model.SubmitDialogRequest
+		UserID             string
 		ExpectedStatusCode int
-		ExpectedResponse   *model.SubmitDialogResponse
-		ExpectedMsg        string
+		ExpectedBody       string
 	}{
 		"Valid request": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("DeletePost", "postID1").Return(nil)
+				setupUsers(api)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
-				store.PollStore.On("Delete", testutils.GetPoll()).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
-			},
+			UserID:             "userID1",
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Successfully deleted the poll.",
+			ExpectedBody:       expectedCSV,
 		},
-		"Valid request, poll without postID": {
+		"Valid request, system admin and ended poll": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("DeletePost", "postID2").Return(nil)
+				api.On("GetUser", "userID5").Return(&model.User{Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
+				setupUsers(api)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithoutPostID(), nil)
-				store.PollStore.On("Delete", testutils.GetPollWithoutPostID()).Return(nil)
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID2",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
+			UserID:             "userID5",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBody:       expectedCSV,
+		},
+		"Valid request, anonymous poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 1}), nil)
+				return store
 			},
+			UserID:             "userID1",
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Successfully deleted the poll.",
+			ExpectedBody:       "Option,Votes\nAnswer 1,3\nAnswer 2,1\nAnswer 3,0\n",
 		},
-		"Valid request, DeletePost fails": {
+		"Invalid permission": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
-				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
-				api.On("DeletePost", "postID1").Return(&model.AppError{})
+				api.On("GetUser", "userID2").Return(&model.User{Roles: model.SystemUserRoleId}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
+			UserID:             "userID2",
+			ExpectedStatusCode: http.StatusForbidden,
+		},
+		"Survey": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSurveyWithAnswers(), nil)
+				return store
 			},
-			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusForbidden,
 		},
-		"Valid request, PollStore.Delete fails": {
+		"GetUser fails for voter": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(true)
 				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
-				api.On("DeletePost", "postID1").Return(nil)
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
-				store.PollStore.On("Delete", testutils.GetPoll()).Return(&model.AppError{})
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"PollStore.Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"Unauthenticated request": {
+			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			UserID:             "",
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			api := test.SetupAPI(&plugintest.API{})
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return().Maybe()
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/polls/%s/export.csv", testutils.GetPollID()), nil)
+			if test.UserID != "" {
+				r.Header.Add("Mattermost-User-ID", test.UserID)
+			}
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer closeBody(t, result.Body)
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			if test.ExpectedStatusCode == http.StatusOK {
+				assert.Equal("text/csv; charset=utf-8", result.Header.Get("Content-Type"))
+				assert.Equal(fmt.Sprintf(`attachment; filename="poll-%s.csv"`, testutils.GetPollID()), result.Header.Get("Content-Disposition"))
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				assert.Equal(test.ExpectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestHandleExportPolls(t *testing.T) {
+	endedPoll := testutils.GetPollWithVotes()
+	endedPoll.ID = "pollID2"
+	endedPoll.PostID = "postID2"
+	withUsernames := func(a *poll.ArchivedPoll) *poll.ArchivedPoll {
+		require.Nil(t, a.ReplaceUsers(func(userID string) (string, *model.AppError) {
+			return strings.Replace(userID, "userID", "user", 1), nil
+		}))
+		return a
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI           func(*plugintest.API) *plugintest.API
+		SetupStore         func(*mockstore.Store) *mockstore.Store
+		UserID             string
+		ExpectedStatusCode int
+		ExpectedArchive    *poll.Archive
+	}{
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1", ChannelId: "channelID1"}, nil)
+				api.On("GetPost", "postID2").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("List").Return([]*poll.Poll{testutils.GetPoll()}, nil)
+				store.PollStore.On("ListEnded").Return([]*poll.Poll{endedPoll}, nil)
+				return store
 			},
+			UserID:             "userID1",
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "Something went wrong. Please try again later.",
+			ExpectedArchive: &poll.Archive{
+				Version:           poll.ArchiveVersion,
+				SecretBallotKeyID: poll.SecretBallotKeyID(nil),
+				Polls: []*poll.ArchivedPoll{
+					withUsernames(testutils.GetPoll().ToArchive("channelID1", false, nil)),
+					withUsernames(endedPoll.ToArchive("", true, nil)),
+				},
+			},
 		},
-		"Invalid request, PollStore.Get fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+		"Valid request, user of a poll doesn't exist anymore": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(nil, &model.AppError{})
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1", ChannelId: "channelID1"}, nil)
+				api.On("GetPost", "postID2").Return(nil, &model.AppError{})
+				return api
+			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, errors.New(""))
+				store.PollStore.On("List").Return([]*poll.Poll{testutils.GetPoll()}, nil)
+				store.PollStore.On("ListEnded").Return([]*poll.Poll{endedPoll}, nil)
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedArchive: &poll.Archive{
+				Version:           poll.ArchiveVersion,
+				SecretBallotKeyID: poll.SecretBallotKeyID(nil),
+				Polls: []*poll.ArchivedPoll{
+					withUsernames(testutils.GetPoll().ToArchive("channelID1", false, nil)),
+				},
 			},
-			ExpectedStatusCode: http.StatusInternalServerError,
 		},
-		"Invalid request, GetPost fails": {
+		"Valid request, no polls": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				api.On("GetUser", "userID1").Return(&model.User{Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("List").Return([]*poll.Poll{}, nil)
+				store.PollStore.On("ListEnded").Return([]*poll.Poll{}, nil)
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedArchive:    &poll.Archive{Version: poll.ArchiveVersion, SecretBallotKeyID: poll.SecretBallotKeyID(nil), Polls: []*poll.ArchivedPoll{}},
+		},
+		"Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Roles: model.SystemUserRoleId}, nil)
+				return api
 			},
-			ExpectedStatusCode: http.StatusInternalServerError,
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusForbidden,
 		},
-		"Invalid request, post with invalid channelID": {
+		"GetUser fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				p := post.Clone()
-				p.ChannelId = "channelID2"
-				api.On("GetPost", "postID1").Return(p, nil)
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusForbidden,
+		},
+		"PollStore.List fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("List").Return(nil, &model.AppError{})
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusInternalServerError,
 		},
-		"Invalid request, without permission to read channel": {
+		"PollStore.ListEnded fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetPost", "postID1").Return(post, nil)
-				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionReadChannel).Return(false)
+				api.On("GetUser", "userID1").Return(&model.User{Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
+				store.PollStore.On("List").Return([]*poll.Poll{}, nil)
+				store.PollStore.On("ListEnded").Return(nil, &model.AppError{})
 				return store
 			},
-			Request: &model.SubmitDialogRequest{
-				UserId:     "userID1",
-				CallbackId: "postID1",
-				ChannelId:  "channelID1",
-				Submission: map[string]interface{}{},
-			},
-			ExpectedStatusCode: http.StatusUnauthorized,
+			UserID:             "userID1",
+			ExpectedStatusCode: http.StatusInternalServerError,
 		},
-		"Empty request": {
+		"Unauthenticated request": {
 			SetupAPI:           func(api *plugintest.API) *plugintest.API { return api },
 			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
-			Request:            nil,
-			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedResponse:   nil,
-			ExpectedMsg:        "",
+			UserID:             "",
+			ExpectedStatusCode: http.StatusUnauthorized,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -3287,29 +9091,16 @@ func TestHandleDeletePollConfirm(t *testing.T) {
 			api := test.SetupAPI(&plugintest.API{})
 			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
 			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
-			if test.ExpectedMsg != "" {
-				ephemeralPost := &model.Post{
-					ChannelId: test.Request.ChannelId,
-					UserId:    testutils.GetBotUserID(),
-					Message:   test.ExpectedMsg,
-				}
-				api.On("SendEphemeralPost", test.Request.UserId, ephemeralPost).Return(nil)
-			}
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return().Maybe()
 			defer api.AssertExpectations(t)
 			store := test.SetupStore(&mockstore.Store{})
 			defer store.AssertExpectations(t)
 			p := setupTestPlugin(t, api, store)
 
 			w := httptest.NewRecorder()
-			url := fmt.Sprintf("/api/v1/polls/%s/delete/confirm", testutils.GetPollID())
-			b, err := json.Marshal(test.Request)
-			require.Nil(t, err)
-			body := bytes.NewReader(b)
-			r := httptest.NewRequest(http.MethodPost, url, body)
-			if test.Request != nil {
-				r.Header.Add("Mattermost-User-ID", test.Request.UserId)
-			} else {
-				r.Header.Add("Mattermost-User-ID", model.NewId())
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/polls/export", nil)
+			if test.UserID != "" {
+				r.Header.Add("Mattermost-User-ID", test.UserID)
 			}
 			p.ServeHTTP(nil, w, r)
 
@@ -3318,94 +9109,279 @@ func TestHandleDeletePollConfirm(t *testing.T) {
 			defer closeBody(t, result.Body)
 
 			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
-
-			var response *model.SubmitDialogResponse
-			// Don't check if the response typed error is nil in order to do additional assertions.
-			_ = json.NewDecoder(result.Body).Decode(&response)
-
-			assert.Equal(test.ExpectedResponse, response)
-			if test.ExpectedResponse != nil {
-				assert.Equal(http.Header{
-					"Content-Type": []string{"application/json"},
-				}, result.Header)
+			if test.ExpectedStatusCode == http.StatusOK {
+				assert.Equal("application/json", result.Header.Get("Content-Type"))
+				assert.Equal(`attachment; filename="matterpoll-export.json"`, result.Header.Get("Content-Disposition"))
+				expected, err := json.Marshal(test.ExpectedArchive)
+				require.NoError(t, err)
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				assert.JSONEq(string(expected), string(body))
 			}
 		})
 	}
 }
 
-func TestHandlePollMetadata(t *testing.T) {
+func TestHandleImportPolls(t *testing.T) {
+	endedPoll := testutils.GetPollWithVotes()
+	endedPoll.ID = "pollID2"
+
+	// Archives refer to users by their username
+	getArchiveWithKey := func(key []byte, polls ...*poll.ArchivedPoll) string {
+		for _, a := range polls {
+			require.Nil(t, a.ReplaceUsers(func(userID string) (string, *model.AppError) {
+				return strings.Replace(userID, "userID", "user", 1), nil
+			}))
+		}
+		b, err := json.Marshal(&poll.Archive{Version: poll.ArchiveVersion, SecretBallotKeyID: poll.SecretBallotKeyID(key), Polls: polls})
+		require.NoError(t, err)
+		return string(b)
+	}
+	getArchive := func(polls ...*poll.ArchivedPoll) string {
+		return getArchiveWithKey(nil, polls...)
+	}
+	secretBallot := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 1})
+	secretBallot.ConvertToSecretBallot([]byte("otherKey"))
+	endedSecretBallot := secretBallot.Copy()
+	endedSecretBallot.ID = "pollID2"
+	withPostID := func(postID string) interface{} {
+		return mock.MatchedBy(func(p *poll.Poll) bool { return p.PostID == postID })
+	}
+	setupAdmin := func(api *plugintest.API) {
+		api.On("GetUser", "userID5").Return(&model.User{Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId}, nil)
+	}
+	setupUsers := func(api *plugintest.API, usernames ...string) {
+		for _, username := range usernames {
+			api.On("GetUserByUsername", username).Return(&model.User{Id: strings.Replace(username, "user", "userID", 1)}, nil)
+		}
+	}
+	allUsers := []string{"user1", "user2", "user3", "user4"}
+	setupNewPolls := func(store *mockstore.Store, ids ...string) {
+		for _, id := range ids {
+			store.PollStore.On("Get", id).Return(nil, &model.AppError{})
+			store.PollStore.On("GetEnded", id).Return(nil, &model.AppError{})
+		}
+	}
+
 	for name, test := range map[string]struct {
 		SetupAPI           func(*plugintest.API) *plugintest.API
 		SetupStore         func(*mockstore.Store) *mockstore.Store
-		UserID             string
-		ShouldError        bool
+		Body               string
+		Query              string
 		ExpectedStatusCode int
-		ExpectedBody       *poll.Metadata
+		ExpectedResult     string
 	}{
-		"Valid request with votes": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+		"Valid request": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, allUsers...)
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				setupNewPolls(store, testutils.GetPollID(), "pollID2")
+				store.PollStore.On("Insert", testutils.GetPoll()).Return(nil)
+				store.PollStore.On("Insert", endedPoll).Return(nil)
+				store.PollStore.On("End", endedPoll).Return(nil)
 				return store
 			},
-			UserID:             "userID1",
-			ShouldError:        false,
+			Body:               getArchive(testutils.GetPoll().ToArchive("channelID1", false, nil), endedPoll.ToArchive("channelID1", true, nil)),
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedBody: (&poll.Metadata{
-				PollID:        testutils.GetPollID(),
-				UserID:        "userID1",
-				CanManagePoll: true,
-				VotedAnswers:  []string{"Answer 1"},
-			}),
+			ExpectedResult:     `{"imported": 2, "errors": []}`,
 		},
-		"Valid request without votes": {
+		"Valid request, create posts": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetUser", "userID5").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				setupAdmin(api)
+				setupUsers(api, allUsers...)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == "channelID1" && post.GetProp("poll_id") == testutils.GetPollID()
+				})).Return(&model.Post{Id: "postID2"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == "channelID1" && post.GetProp("poll_id") == "pollID2" &&
+						post.UserId == testutils.GetBotUserID() && post.Type == MatterpollPostType &&
+						len(post.FileIds) == 1 && post.FileIds[0] == "fileID1"
+				})).Return(&model.Post{Id: "postID3"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				setupNewPolls(store, testutils.GetPollID(), "pollID2")
+				store.PollStore.On("Insert", withPostID("postID2")).Return(nil)
+				store.PollStore.On("Insert", withPostID("postID3")).Return(nil)
+				store.PollStore.On("End", withPostID("postID3")).Return(nil)
 				return store
 			},
-			UserID:             "userID5",
-			ShouldError:        false,
+			Body:               getArchive(testutils.GetPoll().ToArchive("channelID1", false, nil), endedPoll.ToArchive("channelID1", true, nil)),
+			Query:              "?create_posts=true",
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedBody: (&poll.Metadata{
-				PollID:        testutils.GetPollID(),
-				UserID:        "userID5",
-				CanManagePoll: false,
-				VotedAnswers:  []string{},
-			}),
+			ExpectedResult:     `{"imported": 2, "errors": []}`,
 		},
-		"Valid request without votes, CanManagePoll fails": {
+		"Valid request, create posts without known channel": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("GetUser", "userID5").Return(nil, &model.AppError{})
-				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return().Maybe()
+				setupAdmin(api)
+				setupUsers(api, "user1")
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1"}, nil)
 				return api
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
-				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				setupNewPolls(store, testutils.GetPollID())
+				store.PollStore.On("Insert", testutils.GetPoll()).Return(nil)
 				return store
 			},
-			UserID:             "userID5",
-			ShouldError:        false,
+			Body:               getArchive(testutils.GetPoll().ToArchive("", false, nil)),
+			Query:              "?create_posts=true",
 			ExpectedStatusCode: http.StatusOK,
-			ExpectedBody: (&poll.Metadata{
-				PollID:        testutils.GetPollID(),
-				UserID:        "userID5",
-				CanManagePoll: false,
-				VotedAnswers:  []string{},
-			}),
+			ExpectedResult:     `{"imported": 1, "errors": []}`,
 		},
-		"Valid request, PollStore.Get fails": {
-			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+		"Invalid poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, allUsers...)
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				setupNewPolls(store, "pollID2")
+				store.PollStore.On("Insert", endedPoll).Return(nil)
+				store.PollStore.On("End", endedPoll).Return(nil)
+				return store
+			},
+			Body: getArchive(func() *poll.ArchivedPoll {
+				a := testutils.GetPoll().ToArchive("channelID1", false, nil)
+				a.Creator = ""
+				return a
+			}(), endedPoll.ToArchive("channelID1", true, nil)),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult:     `{"imported": 1, "errors": [{"id": "1234567890abcdefghij", "error": "The poll has no id or creator."}]}`,
+		},
+		"Running secret ballot from a server with another key": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, "user1")
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				setupNewPolls(store, "pollID2")
+				store.PollStore.On("Insert", endedSecretBallot).Return(nil)
+				store.PollStore.On("End", endedSecretBallot).Return(nil)
+				return store
+			},
+			Body: getArchiveWithKey([]byte("otherKey"),
+				secretBallot.ToArchive("channelID1", false, []byte("otherKey")),
+				endedSecretBallot.ToArchive("channelID1", true, []byte("otherKey")),
+			),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult: `{"imported": 1, "errors": [{"id": "1234567890abcdefghij", "error": "The poll is a running secret ballot from a server with another secret ballot key. ` +
+				`It can only be imported once it has ended."}]}`,
+		},
+		"Running anonymous poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, "user1")
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+			Body: getArchive(func() *poll.ArchivedPoll {
+				p := testutils.GetRankedPollWithBallots()
+				p.Settings.Anonymous = true
+				return p.ToArchive("channelID1", false, nil)
+			}()),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult: `{"imported": 0, "errors": [{"id": "1234567890abcdefghij", "error": "The poll is a running anonymous poll. ` +
+				`It can only be imported once it has ended, because the archive doesn't contain who voted."}]}`,
+		},
+		"Valid request, posts of another server": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, allUsers...)
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				setupNewPolls(store, testutils.GetPollID(), "pollID2")
+				store.PollStore.On("Insert", withPostID("")).Return(nil)
+				store.PollStore.On("End", withPostID("")).Return(nil)
+				return store
+			},
+			Body:               getArchive(testutils.GetPoll().ToArchive("channelID1", false, nil), endedPoll.ToArchive("channelID1", true, nil)),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult: `{"imported": 1, "errors": [{"id": "1234567890abcdefghij", ` +
+				`"error": "post of running poll doesn't exist on this server, import it with create_posts=true"}]}`,
+		},
+		"Unknown user": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, "user1", "user2", "user3")
+				api.On("GetUserByUsername", "user4").Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Body:               getArchive(endedPoll.ToArchive("channelID1", true, nil)),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult:     `{"imported": 0, "errors": [{"id": "pollID2", "error": "The user user4 of the poll doesn't exist."}]}`,
+		},
+		"Poll already exists": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, "user1")
+				return api
+			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPoll(), nil)
 				return store
 			},
-			UserID:             "userID1",
-			ShouldError:        true,
-			ExpectedStatusCode: http.StatusInternalServerError,
+			Body:               getArchive(testutils.GetPoll().ToArchive("channelID1", false, nil)),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult:     `{"imported": 0, "errors": [{"id": "1234567890abcdefghij", "error": "poll already exists"}]}`,
+		},
+		"PollStore.Insert fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				setupUsers(api, allUsers...)
+				api.On("GetPost", "postID1").Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				setupNewPolls(store, "pollID2")
+				store.PollStore.On("Insert", endedPoll).Return(errors.New("insert failed"))
+				return store
+			},
+			Body:               getArchive(endedPoll.ToArchive("channelID1", true, nil)),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResult:     `{"imported": 0, "errors": [{"id": "pollID2", "error": "failed to save poll: insert failed"}]}`,
+		},
+		"Unsupported version": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				return api
+			},
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Body:               `{"version": 2, "polls": []}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		"Invalid body": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				setupAdmin(api)
+				return api
+			},
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Body:               "{",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		"Invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID5").Return(&model.User{Roles: model.SystemUserRoleId}, nil)
+				return api
+			},
+			SetupStore:         func(store *mockstore.Store) *mockstore.Store { return store },
+			Body:               getArchive(testutils.GetPoll().ToArchive("channelID1", false, nil)),
+			ExpectedStatusCode: http.StatusForbidden,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -3414,41 +9390,27 @@ func TestHandlePollMetadata(t *testing.T) {
 			api := test.SetupAPI(&plugintest.API{})
 			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
 			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return().Maybe()
 			defer api.AssertExpectations(t)
 			store := test.SetupStore(&mockstore.Store{})
 			defer store.AssertExpectations(t)
 			p := setupTestPlugin(t, api, store)
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/polls/%s/metadata", testutils.GetPollID()), nil)
-			r.Header.Add("Mattermost-User-ID", test.UserID)
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/polls/import"+test.Query, strings.NewReader(test.Body))
+			r.Header.Add("Mattermost-User-ID", "userID5")
 			p.ServeHTTP(nil, w, r)
 
 			result := w.Result()
 			require.NotNil(t, result)
 			defer closeBody(t, result.Body)
 
-			bodyBytes, err := io.ReadAll(result.Body)
-			require.Nil(t, err)
-
 			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
-			if test.ShouldError {
-				assert.Equal([]byte{}, bodyBytes)
-				assert.Equal(http.Header{}, result.Header)
-			} else {
-				assert.Contains([]string{"application/json"}, result.Header.Get("Content-Type"))
-				b := new(bytes.Buffer)
-				err = json.NewEncoder(b).Encode(test.ExpectedBody)
-				assert.Nil(err)
-				assert.Equal(b.Bytes(), bodyBytes)
+			if test.ExpectedStatusCode == http.StatusOK {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				assert.JSONEq(test.ExpectedResult, string(body))
 			}
 		})
 	}
 }
-
-func closeBody(t testing.TB, c io.Closer) {
-	t.Helper()
-
-	err := c.Close()
-	require.Nil(t, err)
-}
@@ -3,6 +3,8 @@ package plugin
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
@@ -70,6 +72,104 @@ var (
 		ID:    "command.help.text.pollSetting.multi-vote",
 		Other: "Allow users to vote for X options. Default is 1. If X is 0, users have an unlimited amount of votes.",
 	}
+	commandHelpTextPollSettingRanked = &i18n.Message{
+		ID:    "command.help.text.pollSetting.ranked",
+		Other: "Let users rank the answer options. The winner is determined by instant-runoff voting.",
+	}
+	commandHelpTextPollSettingPoints = &i18n.Message{
+		ID:    "command.help.text.pollSetting.points",
+		Other: "Give users X points to spread across the options. They can put several points on one option.",
+	}
+	commandHelpTextPollSettingScheduling = &i18n.Message{
+		ID:    "command.help.text.pollSetting.scheduling",
+		Other: "Find a date: users answer yes, maybe or no for every option",
+	}
+	commandHelpTextPollSettingFreeText = &i18n.Message{
+		ID:    "command.help.text.pollSetting.freeText",
+		Other: "Let users type their own answer instead of choosing an option. Create it without any options.",
+	}
+	commandHelpTextPollSettingQuiz = &i18n.Message{
+		ID:    "command.help.text.pollSetting.quiz",
+		Other: "Create a quiz. Mark the correct answers with a leading `*`, e.g. `\"*Answer 1\"`. Voters can answer only once.",
+	}
+	commandHelpTextPollSettingResultsAfterVote = &i18n.Message{
+		ID:    "command.help.text.pollSetting.resultsAfterVote",
+		Other: "Show the progress to users only after they voted, so early results don't bias later voters",
+	}
+	commandHelpTextPollSettingFinal = &i18n.Message{
+		ID:    "command.help.text.pollSetting.final",
+		Other: "Make votes binding: users can't change or reset their votes once they are cast",
+	}
+	commandHelpTextPollSettingTieBreak = &i18n.Message{
+		ID:    "command.help.text.pollSetting.tieBreak",
+		Other: "Break a tie once the poll has ended: `creator` lets the creator pick the winner, `runoff` starts a new poll between the tied options",
+	}
+	commandHelpTextPollSettingCloseAt = &i18n.Message{
+		ID:    "command.help.text.pollSetting.closeAt",
+		Other: "End the poll automatically once X users have voted",
+	}
+	commandHelpTextPollSettingCloseWhenAllVoted = &i18n.Message{
+		ID:    "command.help.text.pollSetting.closeWhenAllVoted",
+		Other: "End the poll automatically once every member of the channel has voted. Bots and deactivated users are ignored.",
+	}
+	commandHelpTextPollSettingVoters = &i18n.Message{
+		ID:    "command.help.text.pollSetting.voters",
+		Other: "Only allow some users to vote: a comma-separated list of `@username`, `@group`, `~channel`, `team_admin` and `channel_admin`",
+	}
+	commandHelpTextPollSettingExcludeGuests = &i18n.Message{
+		ID:    "command.help.text.pollSetting.excludeGuests",
+		Other: "Don't allow guest accounts to vote and ignore them in the participation counts",
+	}
+	commandHelpTextPollSettingExcludeBots = &i18n.Message{
+		ID:    "command.help.text.pollSetting.excludeBots",
+		Other: "Don't allow bots and integrations to vote and ignore them in the participation counts",
+	}
+	commandHelpTextPollSettingCapacity = &i18n.Message{
+		ID:    "command.help.text.pollSetting.capacity",
+		Other: "Limit every option to X voters. Limit single options like `\"Slot A [5]\"`",
+	}
+	commandHelpTextPollSettingWaitlist = &i18n.Message{
+		ID:    "command.help.text.pollSetting.waitlist",
+		Other: "Put voters of full options on a waitlist. They get a spot automatically once someone leaves",
+	}
+	commandHelpTextPollSettingProxy = &i18n.Message{
+		ID:    "command.help.text.pollSetting.proxy",
+		Other: "Let voters delegate their vote and the poll creator vote on behalf of others. Proxy votes are marked in the results",
+	}
+	commandHelpTextPollSettingEnd = &i18n.Message{
+		ID:    "command.help.text.pollSetting.end",
+		Other: "End the poll automatically after a duration like `2h` or `3d`, or at a time like `2024-12-24T18:00` in your time zone",
+	}
+
+	commandHelpTextLeaderboard = &i18n.Message{
+		ID:    "command.help.text.leaderboard",
+		Other: "Type `/{{.Trigger}} leaderboard` to post the quiz leaderboard of the channel",
+	}
+
+	commandHelpTextSurvey = &i18n.Message{
+		ID:    "command.help.text.survey",
+		Other: "Type `/{{.Trigger}} survey` to create a survey with several questions",
+	}
+
+	commandHelpTextRerun = &i18n.Message{
+		ID:    "command.help.text.rerun",
+		Other: "Type `/{{.Trigger}} rerun <poll id> [~channel]` to run a poll again without its votes",
+	}
+
+	commandHelpTextExport = &i18n.Message{
+		ID:    "command.help.text.export",
+		Other: "Type `/{{.Trigger}} export <poll id>` to receive the results of a poll as CSV file in a direct message",
+	}
+
+	commandHelpTextSchedule = &i18n.Message{
+		ID:    "command.help.text.schedule",
+		Other: "Type `/{{.Trigger}} schedule add <weekday or daily> <HH:MM> [~channel] \"Question\" \"Answer 1\" \"Answer 2\"` to post a poll every week or every day. Add `--end-previous` to end the previous poll when the next one is posted. `/{{.Trigger}} schedule list` and `/{{.Trigger}} schedule remove <id>` show and remove the recurring polls of a channel.",
+	}
+
+	commandHelpTextTemplate = &i18n.Message{
+		ID:    "command.help.text.template",
+		Other: "Type `/{{.Trigger}} template save <name> \"Question\" \"Answer 1\" \"Answer 2\"` to save a poll as a template. Add `--team` to share it with the team. `/{{.Trigger}} template use <name>` creates a poll from a template, `/{{.Trigger}} template list` and `/{{.Trigger}} template delete <name>` show and delete your templates.",
+	}
 
 	commandErrorGeneric = &i18n.Message{
 		ID:    "command.error.generic",
@@ -105,12 +205,20 @@ func (p *MatterpollPlugin) executeCommand(args *model.CommandArgs) (string, *mod
 	defaultNo := p.bundle.LocalizeDefaultMessage(publicLocalizer, commandDefaultNo)
 
 	q, o, s := utils.ParseInput(args.Command, configuration.Trigger)
+	// Subcommands are only recognized if they aren't quoted. A question like "export the results?" creates a poll.
+	subcommand := isSubcommand(args.Command, configuration.Trigger)
 	if q == "" {
+		templates, err := p.Store.Template().List(creatorID, args.TeamId)
+		if err != nil {
+			// The dialog still works without templates.
+			p.API.LogWarn("failed to list templates", "error", err.Error())
+		}
+
 		siteURL := *p.ServerConfig.ServiceSettings.SiteURL
 		dialog := model.OpenDialogRequest{
 			TriggerId: args.TriggerId,
 			URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/create", root.Manifest.Id),
-			Dialog:    p.getCreatePollDialog(siteURL, args.RootId, userLocalizer, configuration),
+			Dialog:    p.getCreatePollDialog(siteURL, args.RootId, userLocalizer, configuration, templates),
 		}
 
 		if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
@@ -120,7 +228,7 @@ func (p *MatterpollPlugin) executeCommand(args *model.CommandArgs) (string, *mod
 		return "", nil
 	}
 
-	if q == "help" {
+	if subcommand && q == "help" {
 		msg := p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
 			DefaultMessage: commandHelpTextSimple,
 			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger, "Yes": defaultYes, "No": defaultNo},
@@ -136,12 +244,85 @@ func (p *MatterpollPlugin) executeCommand(args *model.CommandArgs) (string, *mod
 		msg += "- `--anonymous`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingAnonymous) + "\n"
 		msg += "- `--anonymous-creator`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingAnonymousCreator) + "\n"
 		msg += "- `--progress`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingProgress) + "\n"
+		msg += "- `--results-after-vote`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingResultsAfterVote) + "\n"
 		msg += "- `--public-add-option`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingPublicAddOption) + "\n"
-		msg += "- `--votes=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingMultiVote)
+		msg += "- `--votes=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingMultiVote) + "\n"
+		msg += "- `--ranked`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingRanked) + "\n"
+		msg += "- `--points=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingPoints) + "\n"
+		msg += "- `--scheduling`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingScheduling) + "\n"
+		msg += "- `--free-text`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingFreeText) + "\n"
+		msg += "- `--quiz`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingQuiz) + "\n"
+		msg += "- `--final`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingFinal) + "\n"
+		msg += "- `--tie-break=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingTieBreak) + "\n"
+		msg += "- `--close-at=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingCloseAt) + "\n"
+		msg += "- `--close-when-all-voted`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingCloseWhenAllVoted) + "\n"
+		msg += "- `--voters=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingVoters) + "\n"
+		msg += "- `--exclude-guests`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingExcludeGuests) + "\n"
+		msg += "- `--exclude-bots`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingExcludeBots) + "\n"
+		msg += "- `--capacity=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingCapacity) + "\n"
+		msg += "- `--waitlist`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingWaitlist) + "\n"
+		msg += "- `--proxy`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingProxy) + "\n"
+		msg += "- `--end=X`: " + p.bundle.LocalizeDefaultMessage(userLocalizer, commandHelpTextPollSettingEnd) + "\n"
+		msg += p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandHelpTextLeaderboard,
+			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger},
+		}) + "\n"
+		msg += p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandHelpTextSurvey,
+			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger},
+		}) + "\n"
+		msg += p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandHelpTextRerun,
+			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger},
+		}) + "\n"
+		msg += p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandHelpTextExport,
+			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger},
+		}) + "\n"
+		msg += p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandHelpTextSchedule,
+			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger},
+		}) + "\n"
+		msg += p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandHelpTextTemplate,
+			TemplateData:   map[string]interface{}{"Trigger": configuration.Trigger},
+		})
 
 		return msg, nil
 	}
 
+	if subcommand && q == "leaderboard" && len(o) == 0 {
+		return p.postLeaderboard(args, userLocalizer)
+	}
+
+	if subcommand && q == "survey" && len(o) == 0 {
+		siteURL := *p.ServerConfig.ServiceSettings.SiteURL
+		dialog := model.OpenDialogRequest{
+			TriggerId: args.TriggerId,
+			URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/survey/create", root.Manifest.Id),
+			Dialog:    p.getCreateSurveyDialog(siteURL, args.RootId, userLocalizer, configuration),
+		}
+
+		if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+			p.API.LogWarn("failed to open create survey dialog", "err", appErr.Error())
+			return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+		}
+		return "", nil
+	}
+
+	if fields := strings.Fields(q); subcommand && len(fields) > 1 {
+		switch {
+		case fields[0] == "rerun" && len(o) == 0 && len(fields) <= 3:
+			return p.executeRerunCommand(args, fields[1:], userLocalizer)
+		case fields[0] == "export" && len(o) == 0 && len(fields) == 2:
+			return p.executeExportCommand(args, fields[1], userLocalizer)
+		case fields[0] == "schedule" && slices.Contains([]string{"add", "list", "remove"}, fields[1]):
+			return p.executeScheduleCommand(args, configuration.Trigger, userLocalizer)
+		case fields[0] == "template" && slices.Contains([]string{"save", "use", "list", "delete"}, fields[1]):
+			return p.executeTemplateCommand(args, configuration.Trigger, userLocalizer)
+		}
+	}
+
 	if len(o) == 1 {
 		return "", &model.AppError{
 			Id:         p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorinvalidNumberOfOptions),
@@ -150,50 +331,67 @@ func (p *MatterpollPlugin) executeCommand(args *model.CommandArgs) (string, *mod
 		}
 	}
 
-	settings, errMsg := poll.NewSettingsFromStrings(s)
+	settings, errMsg := poll.NewSettingsFromStrings(s, p.pf.Millis(), p.getUserLocation(args.UserId))
 	if errMsg != nil {
-		appErr := &model.AppError{
-			Id: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
-				DefaultMessage: commandErrorInvalidInput,
-				TemplateData: map[string]interface{}{
-					"Error": p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
-				}}),
-			StatusCode: http.StatusBadRequest,
-			Where:      "ExecuteCommand",
-		}
-		return "", appErr
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
 	}
 
-	var newPoll *poll.Poll
-	if len(o) == 0 {
-		newPoll, errMsg = p.pf.NewPoll(creatorID, q, []string{defaultYes, defaultNo}, settings)
-	} else {
-		newPoll, errMsg = p.pf.NewPoll(creatorID, q, o, settings)
-	}
+	newPoll, errMsg := p.newPollFromCommand(creatorID, q, o, settings)
 	if errMsg != nil {
-		appErr := &model.AppError{
-			Id: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
-				DefaultMessage: commandErrorInvalidInput,
-				TemplateData: map[string]interface{}{
-					"Error": p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
-				}}),
-			StatusCode: http.StatusBadRequest,
-			Where:      "ExecuteCommand",
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+
+	rPost, err := p.createPollPost(newPoll, args.ChannelId, args.RootId)
+	if err != nil {
+		p.API.LogWarn("failed to create poll post", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+
+	rPostJSON, _ := rPost.ToJSON()
+	p.API.LogDebug("Created a new poll", "post", rPostJSON)
+
+	return "", nil
+}
+
+// newPollFromCommand creates a new poll from the parsed input of a command.
+// Polls without answer options get the options "Yes" and "No", unless they are free-text polls.
+func (p *MatterpollPlugin) newPollFromCommand(creatorID, question string, answerOptions []string, settings poll.Settings) (*poll.Poll, *utils.ErrorMessage) {
+	if len(answerOptions) == 0 && !settings.FreeText {
+		publicLocalizer := p.bundle.GetServerLocalizer()
+		answerOptions = []string{
+			p.bundle.LocalizeDefaultMessage(publicLocalizer, commandDefaultYes),
+			p.bundle.LocalizeDefaultMessage(publicLocalizer, commandDefaultNo),
 		}
-		return "", appErr
 	}
+	return p.pf.NewPoll(creatorID, question, answerOptions, settings)
+}
+
+// newInvalidInputError returns the error shown to a user who executed a command with an invalid input.
+func (p *MatterpollPlugin) newInvalidInputError(userLocalizer *i18n.Localizer, errMsg *utils.ErrorMessage) *model.AppError {
+	return &model.AppError{
+		Id: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandErrorInvalidInput,
+			TemplateData: map[string]interface{}{
+				"Error": p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			}}),
+		StatusCode: http.StatusBadRequest,
+		Where:      "ExecuteCommand",
+	}
+}
 
-	displayName, appErr := p.ConvertCreatorIDToDisplayName(creatorID)
+// createPollPost posts a new poll in a given channel, saves it and schedules its end.
+func (p *MatterpollPlugin) createPollPost(newPoll *poll.Poll, channelID, rootID string) (*model.Post, error) {
+	displayName, appErr := p.ConvertCreatorIDToDisplayName(newPoll.Creator)
 	if appErr != nil {
-		p.API.LogWarn("failed to ConvertCreatorIDToDisplayName", "error", appErr.Error())
-		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+		return nil, errors.Wrap(appErr, "failed to get display name for creator")
 	}
 
+	p.ignoreExcludedVoters(newPoll)
 	actions := newPoll.ToPostActions(p.bundle, root.Manifest.Id, displayName)
 	post := &model.Post{
 		UserId:    p.botUserID,
-		ChannelId: args.ChannelId,
-		RootId:    args.RootId,
+		ChannelId: channelID,
+		RootId:    rootID,
 		Type:      MatterpollPostType,
 		Props: map[string]interface{}{
 			"poll_id": newPoll.ID,
@@ -206,19 +404,580 @@ func (p *MatterpollPlugin) executeCommand(args *model.CommandArgs) (string, *mod
 
 	rPost, appErr := p.API.CreatePost(post)
 	if appErr != nil {
-		p.API.LogWarn("failed to post poll post", "error", appErr.Error())
-		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+		return nil, errors.Wrap(appErr, "failed to create poll post")
 	}
 
 	newPoll.PostID = rPost.Id
 
 	if err := p.Store.Poll().Insert(newPoll); err != nil {
-		p.API.LogWarn("failed to save poll", "error", err.Error())
+		return nil, errors.Wrap(err, "failed to save poll")
+	}
+
+	if err := p.scheduleEndPoll(newPoll); err != nil {
+		return nil, err
+	}
+
+	return rPost, nil
+}
+
+// executeRerunCommand runs the poll with the id given as first parameter again.
+// The poll is posted in the channel given as optional second parameter, or in the channel the command was executed in.
+func (p *MatterpollPlugin) executeRerunCommand(args *model.CommandArgs, params []string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	toRerun, err := p.getActiveOrEndedPoll(params[0])
+	if err != nil {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.rerun.notFound",
+				Other: "There is no poll with the id {{.ID}}.",
+			},
+			TemplateData: map[string]interface{}{"ID": params[0]},
+		}), nil
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(toRerun, args.UserId)
+	if appErr != nil {
+		p.API.LogWarn("failed to check permission", "error", appErr.Error())
 		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
 	}
+	if !canManagePoll {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, responseRerunPollInvalidPermission), nil
+	}
 
-	rPostJSON, _ := rPost.ToJSON()
-	p.API.LogDebug("Created a new poll", "post", rPostJSON)
+	channelID, msg := p.getTargetChannel(args, params[1:], userLocalizer)
+	if msg != "" {
+		return msg, nil
+	}
+
+	errMsg, err := p.rerunPoll(toRerun, args.UserId, channelID)
+	if err != nil {
+		p.API.LogWarn("failed to run poll again", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if errMsg != nil {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: commandErrorInvalidInput,
+			TemplateData: map[string]interface{}{
+				"Error": p.bundle.LocalizeErrorMessage(userLocalizer, errMsg),
+			}}), nil
+	}
+
+	return "", nil
+}
+
+// getTargetChannel returns the id of the channel a command posts a poll in.
+// It's the channel given as optional parameter like "~town-square", or the channel the command was executed in.
+// If the channel doesn't exist or the user isn't allowed to post in it, a message for the user is returned instead.
+func (p *MatterpollPlugin) getTargetChannel(args *model.CommandArgs, params []string, userLocalizer *i18n.Localizer) (string, string) {
+	channelID := args.ChannelId
+	if len(params) > 0 {
+		name := strings.TrimPrefix(params[0], "~")
+		channel, appErr := p.API.GetChannelByName(args.TeamId, name, false)
+		if appErr != nil {
+			return "", p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "command.rerun.channelNotFound",
+					Other: "There is no channel named ~{{.Channel}}.",
+				},
+				TemplateData: map[string]interface{}{"Channel": name},
+			})
+		}
+		channelID = channel.Id
+	}
+	if !p.API.HasPermissionToChannel(args.UserId, channelID, model.PermissionCreatePost) {
+		return "", p.bundle.LocalizeDefaultMessage(userLocalizer, responseRerunPollNoPostPermission)
+	}
+	return channelID, ""
+}
+
+// rerunPoll posts a copy of a poll without votes in a given channel. The user running it again becomes the creator of the new poll.
+func (p *MatterpollPlugin) rerunPoll(toRerun *poll.Poll, userID, channelID string) (*utils.ErrorMessage, error) {
+	newPoll, errMsg := p.pf.Rerun(userID, toRerun)
+	if errMsg != nil {
+		return errMsg, nil
+	}
+
+	if _, err := p.createPollPost(newPoll, channelID, ""); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// executeExportCommand sends the results of the poll with a given id as CSV file to the user in a direct message.
+func (p *MatterpollPlugin) executeExportCommand(args *model.CommandArgs, pollID string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	toExport, err := p.getActiveOrEndedPoll(pollID)
+	if err != nil {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.export.notFound",
+				Other: "There is no poll with the id {{.ID}}.",
+			},
+			TemplateData: map[string]interface{}{"ID": pollID},
+		}), nil
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(toExport, args.UserId)
+	if appErr != nil {
+		p.API.LogWarn("failed to check permission", "error", appErr.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if !canManagePoll {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, responseExportPollInvalidPermission), nil
+	}
+	if !toExport.CanExport() {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, responseExportPollNotSupported), nil
+	}
+
+	if err := p.sendExport(toExport, args.UserId); err != nil {
+		p.API.LogWarn("failed to export poll", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+
+	return p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+		ID:    "command.export.success",
+		Other: "The results have been sent to you in a direct message.",
+	}), nil
+}
+
+// sendExport sends the results of a poll as CSV file to a given user in a direct message from the bot.
+func (p *MatterpollPlugin) sendExport(toExport *poll.Poll, userID string) error {
+	data, err := toExport.ToCSV(p.bundle, p.ConvertUserIDToNames)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert poll to CSV")
+	}
+
+	channel, appErr := p.API.GetDirectChannel(userID, p.botUserID)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to get direct channel")
+	}
+
+	fileInfo, appErr := p.API.UploadFile(data, channel.Id, exportFilename(toExport))
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to upload CSV file")
+	}
+
+	userLocalizer := p.bundle.GetUserLocalizer(userID)
+	post := &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: channel.Id,
+		Message: p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.export.message",
+				Other: "Here are the results of the poll **{{.Question}}**.",
+			},
+			TemplateData: map[string]interface{}{"Question": toExport.Question},
+		}),
+		FileIds: model.StringArray{fileInfo.Id},
+	}
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return errors.Wrap(appErr, "failed to create post")
+	}
+	return nil
+}
+
+// exportFilename returns the name of the CSV file the results of a poll are exported to.
+func exportFilename(toExport *poll.Poll) string {
+	return fmt.Sprintf("poll-%s.csv", toExport.ID)
+}
+
+// isSubcommand returns true if the input of a command doesn't start with a quote, i.e. it isn't a poll.
+func isSubcommand(command, trigger string) bool {
+	input := strings.TrimSpace(strings.TrimPrefix(command, "/"+trigger))
+	return !strings.HasPrefix(input, `"`) && !strings.HasPrefix(input, "“")
+}
+
+// splitSubcommand splits a command like `/poll schedule add friday 10:00 "Question"` into the subcommand, e.g. "add",
+// and its parameters, e.g. `friday 10:00 "Question"`.
+func splitSubcommand(command, trigger, name string) (string, string) {
+	input := strings.TrimSpace(strings.TrimPrefix(command, "/"+trigger))
+	input = strings.TrimSpace(strings.TrimPrefix(input, name))
+	subcommand, params, _ := strings.Cut(input, " ")
+	return subcommand, strings.TrimSpace(params)
+}
+
+// splitPollInput splits the parameters of a subcommand into the words in front of the poll and the poll itself,
+// which starts with the quoted question.
+func splitPollInput(params string) ([]string, string) {
+	i := strings.IndexAny(params, `"“`)
+	if i < 0 {
+		return strings.Fields(params), ""
+	}
+	return strings.Fields(params[:i]), params[i:]
+}
+
+// executeScheduleCommand adds, lists or removes recurring polls.
+func (p *MatterpollPlugin) executeScheduleCommand(args *model.CommandArgs, trigger string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	subcommand, params := splitSubcommand(args.Command, trigger, "schedule")
+	switch subcommand {
+	case "add":
+		return p.addRecurringPoll(args, trigger, params, userLocalizer)
+	case "list":
+		return p.listRecurringPolls(args, userLocalizer)
+	default:
+		return p.removeRecurringPoll(args, trigger, params, userLocalizer)
+	}
+}
+
+// addRecurringPoll adds a recurring poll. params are the schedule, an optional channel and the poll in the syntax of the command,
+// e.g. `friday 10:00 ~town-square "Question" "Answer 1" "Answer 2" --progress --end-previous`.
+func (p *MatterpollPlugin) addRecurringPoll(args *model.CommandArgs, trigger, params string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	fields, pollInput := splitPollInput(params)
+	if len(fields) < 2 || len(fields) > 3 || pollInput == "" {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.schedule.add.usage",
+				Other: "Usage: `/{{.Trigger}} schedule add <weekday or daily> <HH:MM> [~channel] \"Question\" \"Answer 1\" \"Answer 2\" [--end-previous]`",
+			},
+			TemplateData: map[string]interface{}{"Trigger": trigger},
+		}), nil
+	}
+
+	channelID, msg := p.getTargetChannel(args, fields[2:], userLocalizer)
+	if msg != "" {
+		return msg, nil
+	}
+
+	q, o, s := utils.ParseInput(pollInput, "")
+	if len(o) == 1 {
+		return "", &model.AppError{
+			Id:         p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorinvalidNumberOfOptions),
+			StatusCode: http.StatusBadRequest,
+			Where:      "ExecuteCommand",
+		}
+	}
+	endPrevious := slices.Contains(s, "end-previous")
+	s = slices.DeleteFunc(s, func(setting string) bool { return setting == "end-previous" })
+
+	settings, errMsg := poll.NewSettingsFromStrings(s, p.pf.Millis(), p.getUserLocation(args.UserId))
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+	template, errMsg := p.newPollFromCommand(args.UserId, q, o, settings)
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+
+	user, appErr := p.API.GetUser(args.UserId)
+	if appErr != nil {
+		p.API.LogWarn("failed to get user", "error", appErr.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	r, errMsg := p.pf.NewRecurringPoll(template, channelID, fields[0], fields[1], user.GetPreferredTimezone(), endPrevious)
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+
+	if err := p.Store.RecurringPoll().Save(r); err != nil {
+		p.API.LogWarn("failed to save recurring poll", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if err := p.scheduleRecurringPoll(r); err != nil {
+		p.API.LogWarn("failed to schedule recurring poll", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+
+	return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "command.schedule.add.success",
+			Other: "Added the recurring poll {{.RecurringPoll}}. Remove it with `/{{.Trigger}} schedule remove {{.ID}}`.",
+		},
+		TemplateData: map[string]interface{}{
+			"RecurringPoll": r.ToMarkdown(p.bundle, userLocalizer),
+			"Trigger":       trigger,
+			"ID":            r.ID,
+		},
+	}), nil
+}
+
+// listRecurringPolls lists the recurring polls, that are posted in the channel the command was executed in.
+func (p *MatterpollPlugin) listRecurringPolls(args *model.CommandArgs, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	recurringPolls, err := p.Store.RecurringPoll().List(args.ChannelId)
+	if err != nil {
+		p.API.LogWarn("failed to list recurring polls", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if len(recurringPolls) == 0 {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "command.schedule.list.empty",
+			Other: "There are no recurring polls in this channel.",
+		}), nil
+	}
+
+	lines := []string{p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+		ID:    "command.schedule.list.heading",
+		Other: "Recurring polls in this channel:",
+	})}
+	for _, r := range recurringPolls {
+		lines = append(lines, "- "+r.ToMarkdown(p.bundle, userLocalizer))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// removeRecurringPoll removes the recurring poll with the id given as parameter. Polls, that were already posted, are kept.
+func (p *MatterpollPlugin) removeRecurringPoll(args *model.CommandArgs, trigger, id string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	if id == "" || strings.Contains(id, " ") {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.schedule.remove.usage",
+				Other: "Usage: `/{{.Trigger}} schedule remove <id>`. Type `/{{.Trigger}} schedule list` to get the ids of the recurring polls in this channel.",
+			},
+			TemplateData: map[string]interface{}{"Trigger": trigger},
+		}), nil
+	}
+
+	r, err := p.Store.RecurringPoll().Get(id)
+	if err != nil {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.schedule.remove.notFound",
+				Other: "There is no recurring poll with the id {{.ID}}.",
+			},
+			TemplateData: map[string]interface{}{"ID": id},
+		}), nil
+	}
+
+	canManagePoll, appErr := p.CanManagePoll(r.Poll, args.UserId)
+	if appErr != nil {
+		p.API.LogWarn("failed to check permission", "error", appErr.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if !canManagePoll {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "command.schedule.remove.invalidPermission",
+			Other: "Only the creator of a recurring poll and System Admins are allowed to remove it.",
+		}), nil
+	}
+
+	if err := p.Store.RecurringPoll().Delete(r); err != nil {
+		p.API.LogWarn("failed to delete recurring poll", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	p.cancelRecurringPoll(r)
+
+	return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "command.schedule.remove.success",
+			Other: "Removed the recurring poll **{{.Question}}**. Polls that were already posted are kept.",
+		},
+		TemplateData: map[string]interface{}{"Question": r.Poll.Question},
+	}), nil
+}
+
+// executeTemplateCommand saves, uses, lists or deletes poll templates.
+func (p *MatterpollPlugin) executeTemplateCommand(args *model.CommandArgs, trigger string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	subcommand, params := splitSubcommand(args.Command, trigger, "template")
+	if subcommand == "save" {
+		return p.saveTemplate(args, trigger, params, userLocalizer)
+	}
+
+	templates, err := p.Store.Template().List(args.UserId, args.TeamId)
+	if err != nil {
+		p.API.LogWarn("failed to list templates", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if subcommand == "list" {
+		return p.listTemplates(templates, trigger, userLocalizer), nil
+	}
+
+	template := poll.FindTemplate(templates, params)
+	if template == nil {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.template.notFound",
+				Other: "There is no template named `{{.Name}}`. Type `/{{.Trigger}} template list` to see your templates.",
+			},
+			TemplateData: map[string]interface{}{"Name": params, "Trigger": trigger},
+		}), nil
+	}
+	if subcommand == "use" {
+		return p.useTemplate(args, template, userLocalizer)
+	}
+	return p.deleteTemplate(args, template, userLocalizer)
+}
+
+// saveTemplate saves a poll as a template. params are the name of the template and the poll in the syntax of the command,
+// e.g. `standup "Question" "Answer 1" "Answer 2" --progress --team`.
+// An existing template with the same name is overwritten, if the user is allowed to manage it.
+func (p *MatterpollPlugin) saveTemplate(args *model.CommandArgs, trigger, params string, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	fields, pollInput := splitPollInput(params)
+	if len(fields) != 1 || pollInput == "" {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.template.save.usage",
+				Other: "Usage: `/{{.Trigger}} template save <name> \"Question\" \"Answer 1\" \"Answer 2\" [--team]`",
+			},
+			TemplateData: map[string]interface{}{"Trigger": trigger},
+		}), nil
+	}
+	name := fields[0]
+
+	q, o, s := utils.ParseInput(pollInput, "")
+	if len(o) == 1 {
+		return "", &model.AppError{
+			Id:         p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorinvalidNumberOfOptions),
+			StatusCode: http.StatusBadRequest,
+			Where:      "ExecuteCommand",
+		}
+	}
+	teamID := ""
+	if slices.Contains(s, "team") {
+		teamID = args.TeamId
+	}
+	s = slices.DeleteFunc(s, func(setting string) bool { return setting == "team" })
+
+	settings, errMsg := poll.NewSettingsFromStrings(s, p.pf.Millis(), p.getUserLocation(args.UserId))
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+	definition, errMsg := p.newPollFromCommand(args.UserId, q, o, settings)
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+	template, errMsg := p.pf.NewTemplate(name, teamID, definition)
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+
+	templates, err := p.Store.Template().List(args.UserId, args.TeamId)
+	if err != nil {
+		p.API.LogWarn("failed to list templates", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	for _, existing := range templates {
+		if existing.Name != name || existing.IsShared() != template.IsShared() {
+			continue
+		}
+		canManage, appErr := p.CanManagePoll(existing.Poll, args.UserId)
+		if appErr != nil {
+			p.API.LogWarn("failed to check permission", "error", appErr.Error())
+			return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+		}
+		if !canManage {
+			return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+				DefaultMessage: &i18n.Message{
+					ID:    "command.template.save.nameTaken",
+					Other: "A template named `{{.Name}}` is already shared with this team. Only its creator and System Admins are allowed to overwrite it.",
+				},
+				TemplateData: map[string]interface{}{"Name": name},
+			}), nil
+		}
+		template.ID = existing.ID
+	}
+
+	if err := p.Store.Template().Save(template); err != nil {
+		p.API.LogWarn("failed to save template", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+
+	return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "command.template.save.success",
+			Other: "Saved the template `{{.Name}}`. Create a poll from it with `/{{.Trigger}} template use {{.Name}}`.",
+		},
+		TemplateData: map[string]interface{}{"Name": name, "Trigger": trigger},
+	}), nil
+}
+
+// useTemplate creates a poll from a template in the channel the command was executed in.
+func (p *MatterpollPlugin) useTemplate(args *model.CommandArgs, template *poll.Template, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	newPoll, errMsg := p.pf.Rerun(args.UserId, template.Poll)
+	if errMsg != nil {
+		return "", p.newInvalidInputError(userLocalizer, errMsg)
+	}
+
+	if _, err := p.createPollPost(newPoll, args.ChannelId, args.RootId); err != nil {
+		p.API.LogWarn("failed to create poll from template", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	return "", nil
+}
+
+// listTemplates lists the personal templates of a user and the templates shared with the team.
+func (p *MatterpollPlugin) listTemplates(templates []*poll.Template, trigger string, userLocalizer *i18n.Localizer) string {
+	if len(templates) == 0 {
+		return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+			DefaultMessage: &i18n.Message{
+				ID:    "command.template.list.empty",
+				Other: "There are no templates yet. Save one with `/{{.Trigger}} template save <name> \"Question\" \"Answer 1\" \"Answer 2\"`.",
+			},
+			TemplateData: map[string]interface{}{"Trigger": trigger},
+		})
+	}
+
+	lines := []string{p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+		ID:    "command.template.list.heading",
+		Other: "Templates:",
+	})}
+	for _, t := range templates {
+		line := fmt.Sprintf("- `%s`: %s", t.Name, t.Poll.Question)
+		if t.IsShared() {
+			line += " " + p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+				ID:    "command.template.list.shared",
+				Other: "(shared with the team)",
+			})
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// deleteTemplate deletes a template, if the user is allowed to manage it.
+func (p *MatterpollPlugin) deleteTemplate(args *model.CommandArgs, template *poll.Template, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	canManage, appErr := p.CanManagePoll(template.Poll, args.UserId)
+	if appErr != nil {
+		p.API.LogWarn("failed to check permission", "error", appErr.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if !canManage {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "command.template.delete.invalidPermission",
+			Other: "Only the creator of a template and System Admins are allowed to delete it.",
+		}), nil
+	}
+
+	if err := p.Store.Template().Delete(template); err != nil {
+		p.API.LogWarn("failed to delete template", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+
+	return p.bundle.LocalizeWithConfig(userLocalizer, &i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "command.template.delete.success",
+			Other: "Deleted the template `{{.Name}}`.",
+		},
+		TemplateData: map[string]interface{}{"Name": template.Name},
+	}), nil
+}
+
+// postLeaderboard posts the quiz leaderboard of the channel a command was executed in.
+func (p *MatterpollPlugin) postLeaderboard(args *model.CommandArgs, userLocalizer *i18n.Localizer) (string, *model.AppError) {
+	leaderboard, err := p.Store.Leaderboard().Get(args.ChannelId)
+	if err != nil {
+		p.API.LogWarn("failed to get leaderboard", "error", err.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+	if len(leaderboard.Scores) == 0 {
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, &i18n.Message{
+			ID:    "command.leaderboard.empty",
+			Other: "No quiz has ended in this channel yet.",
+		}), nil
+	}
+
+	message, appErr := leaderboard.ToMarkdown(p.bundle, p.ConvertUserIDToDisplayName)
+	if appErr != nil {
+		p.API.LogWarn("failed to convert leaderboard to markdown", "error", appErr.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
+
+	post := &model.Post{
+		UserId:    p.botUserID,
+		ChannelId: args.ChannelId,
+		RootId:    args.RootId,
+		Message:   message,
+	}
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		p.API.LogWarn("failed to post leaderboard", "error", appErr.Error())
+		return p.bundle.LocalizeDefaultMessage(userLocalizer, commandErrorGeneric), nil
+	}
 
 	return "", nil
 }
@@ -240,16 +999,41 @@ func (p *MatterpollPlugin) getCommand(trigger string) (*model.Command, error) {
 	}, nil
 }
 
-func (p *MatterpollPlugin) getCreatePollDialog(siteURL, rootID string, l *i18n.Localizer, c *configuration) model.Dialog {
-	elements := []model.DialogElement{{
+// getCreatePollDialog returns the dialog to create a poll. If templates are given, the user can choose one of them instead of
+// entering a question and options.
+func (p *MatterpollPlugin) getCreatePollDialog(siteURL, rootID string, l *i18n.Localizer, c *configuration, templates []*poll.Template) model.Dialog {
+	elements := []model.DialogElement{}
+	if len(templates) > 0 {
+		options := make([]*model.PostActionOptions, 0, len(templates))
+		for _, t := range templates {
+			options = append(options, &model.PostActionOptions{Text: t.Name, Value: t.ID})
+		}
+		elements = append(elements, model.DialogElement{
+			DisplayName: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+				ID:    "dialog.createPoll.template",
+				Other: "Template",
+			}),
+			Name:    templateKey,
+			Type:    "select",
+			Options: options,
+			HelpText: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+				ID:    "dialog.createPoll.template.help",
+				Other: "Create the poll from a saved template. The other fields are ignored.",
+			}),
+			Optional: true,
+		})
+	}
+
+	elements = append(elements, model.DialogElement{
 		DisplayName: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
 			ID:    "dialog.createPoll.question",
 			Other: "Question",
 		}),
-		Name:    questionKey,
-		Type:    "text",
-		SubType: "text",
-	}}
+		Name:     questionKey,
+		Type:     "text",
+		SubType:  "text",
+		Optional: len(templates) > 0,
+	})
 	for i := 1; i < 4; i++ {
 		elements = append(elements, model.DialogElement{
 			DisplayName: p.bundle.LocalizeWithConfig(l, &i18n.LocalizeConfig{
@@ -263,7 +1047,7 @@ func (p *MatterpollPlugin) getCreatePollDialog(siteURL, rootID string, l *i18n.L
 			Name:     fmt.Sprintf("option%v", i),
 			Type:     "text",
 			SubType:  "text",
-			Optional: i > 2,
+			Optional: i > 2 || len(templates) > 0,
 		})
 	}
 
@@ -304,6 +1088,14 @@ func (p *MatterpollPlugin) getCreatePollDialog(siteURL, rootID string, l *i18n.L
 		Default:     fmt.Sprintf("%t", c.DefaultSettings["progress"]),
 		Optional:    true,
 	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Results after vote",
+		Name:        "setting-" + poll.SettingKeyResultsAfterVote,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingResultsAfterVote),
+		Default:     "false",
+		Optional:    true,
+	})
 	elements = append(elements, model.DialogElement{
 		DisplayName: "Public Add Option",
 		Name:        "setting-public-add-option",
@@ -312,6 +1104,143 @@ func (p *MatterpollPlugin) getCreatePollDialog(siteURL, rootID string, l *i18n.L
 		Default:     fmt.Sprintf("%t", c.DefaultSettings["publicAddOption"]),
 		Optional:    true,
 	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Ranked",
+		Name:        "setting-" + poll.SettingKeyRanked,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingRanked),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Points",
+		Name:        "setting-" + poll.SettingKeyPoints,
+		Type:        "text",
+		SubType:     "number",
+		Default:     "0",
+		HelpText:    p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingPoints),
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Scheduling",
+		Name:        "setting-" + poll.SettingKeyScheduling,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingScheduling),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Quiz",
+		Name:        "setting-" + poll.SettingKeyQuiz,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingQuiz),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Final",
+		Name:        "setting-" + poll.SettingKeyFinal,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingFinal),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Tie-break",
+		Name:        "setting-" + poll.SettingKeyTieBreak,
+		Type:        "select",
+		Options: []*model.PostActionOptions{{
+			Text: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+				ID:    "dialog.create.tieBreak.creator",
+				Other: "Creator picks the winner",
+			}),
+			Value: string(poll.TieBreakCreator),
+		}, {
+			Text: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+				ID:    "dialog.create.tieBreak.runoff",
+				Other: "Runoff poll",
+			}),
+			Value: string(poll.TieBreakRunoff),
+		}},
+		HelpText: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingTieBreak),
+		Optional: true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Close at",
+		Name:        "setting-" + poll.SettingKeyCloseAt,
+		Type:        "text",
+		SubType:     "number",
+		Default:     "0",
+		HelpText:    p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingCloseAt),
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Close when all voted",
+		Name:        "setting-" + poll.SettingKeyCloseWhenAllVoted,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingCloseWhenAllVoted),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Voters",
+		Name:        "setting-" + poll.SettingKeyVoters,
+		Type:        "text",
+		SubType:     "text",
+		Placeholder: "@user, ~channel, channel_admin",
+		HelpText:    p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingVoters),
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Exclude guests",
+		Name:        "setting-" + poll.SettingKeyExcludeGuests,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingExcludeGuests),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Exclude bots",
+		Name:        "setting-" + poll.SettingKeyExcludeBots,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingExcludeBots),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Capacity",
+		Name:        "setting-" + poll.SettingKeyCapacity,
+		Type:        "text",
+		SubType:     "number",
+		Default:     "0",
+		HelpText:    p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingCapacity),
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Waitlist",
+		Name:        "setting-" + poll.SettingKeyWaitlist,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingWaitlist),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Proxy votes",
+		Name:        "setting-" + poll.SettingKeyProxy,
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingProxy),
+		Default:     "false",
+		Optional:    true,
+	})
+	elements = append(elements, model.DialogElement{
+		DisplayName: "End",
+		Name:        "setting-" + poll.SettingKeyEnd,
+		Type:        "text",
+		SubType:     "text",
+		Placeholder: "2h",
+		HelpText:    p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingEnd),
+		Optional:    true,
+	})
 	dialog := model.Dialog{
 		CallbackId: rootID,
 		Title: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
@@ -328,3 +1257,66 @@ func (p *MatterpollPlugin) getCreatePollDialog(siteURL, rootID string, l *i18n.L
 
 	return dialog
 }
+
+// getCreateSurveyDialog returns the dialog to create a survey.
+// Every question is written in its own line, using the syntax of the slash command.
+func (p *MatterpollPlugin) getCreateSurveyDialog(siteURL, rootID string, l *i18n.Localizer, c *configuration) model.Dialog {
+	elements := []model.DialogElement{{
+		DisplayName: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+			ID:    "dialog.createSurvey.title",
+			Other: "Title",
+		}),
+		Name:    surveyTitleKey,
+		Type:    "text",
+		SubType: "text",
+	}, {
+		DisplayName: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+			ID:    "dialog.createSurvey.questions",
+			Other: "Questions",
+		}),
+		Name:        surveyQuestionsKey,
+		Type:        "textarea",
+		Placeholder: "\"Question 1\" \"Option 1\" \"Option 2\"\n\"Question 2\" \"Option 1\" \"Option 2\" \"Option 3\" --votes=2 --anonymous",
+		HelpText: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+			ID:    "dialog.createSurvey.questions.help",
+			Other: "Write every question in its own line, followed by its options in quotes. Add --anonymous or --votes=X to a line to set them for this question.",
+		}),
+		MaxLength: 3000,
+	}, {
+		DisplayName: "Anonymous creator",
+		Name:        "setting-anonymous-creator",
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingAnonymousCreator),
+		Default:     fmt.Sprintf("%t", c.DefaultSettings["anonymousCreator"]),
+		Optional:    true,
+	}, {
+		DisplayName: "Progress",
+		Name:        "setting-progress",
+		Type:        "bool",
+		Placeholder: p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingProgress),
+		Default:     fmt.Sprintf("%t", c.DefaultSettings["progress"]),
+		Optional:    true,
+	}, {
+		DisplayName: "End",
+		Name:        "setting-" + poll.SettingKeyEnd,
+		Type:        "text",
+		SubType:     "text",
+		Placeholder: "2h",
+		HelpText:    p.bundle.LocalizeDefaultMessage(l, commandHelpTextPollSettingEnd),
+		Optional:    true,
+	}}
+
+	return model.Dialog{
+		CallbackId: rootID,
+		Title: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+			ID:    "dialog.createSurvey.dialogTitle",
+			Other: "Create Survey",
+		}),
+		IconURL: fmt.Sprintf(responseIconURL, siteURL, root.Manifest.Id),
+		SubmitLabel: p.bundle.LocalizeDefaultMessage(l, &i18n.Message{
+			ID:    "dialog.create.submitLabel",
+			Other: "Create",
+		}),
+		Elements: elements,
+	}
+}
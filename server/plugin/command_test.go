@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
@@ -24,11 +27,47 @@ func TestPluginExecuteCommand(t *testing.T) {
 		"- `--anonymous`: Don't show who voted for what when the poll ends\n" +
 		"- `--anonymous-creator`: Don't show author of the poll\n" +
 		"- `--progress`: During the poll, show how many votes each answer option got\n" +
+		"- `--results-after-vote`: Show the progress to users only after they voted, so early results don't bias later voters\n" +
 		"- `--public-add-option`: Allow all users to add additional options\n" +
-		"- `--votes=X`: Allow users to vote for X options. Default is 1. If X is 0, users have an unlimited amount of votes."
+		"- `--votes=X`: Allow users to vote for X options. Default is 1. If X is 0, users have an unlimited amount of votes.\n" +
+		"- `--ranked`: Let users rank the answer options. The winner is determined by instant-runoff voting.\n" +
+		"- `--points=X`: Give users X points to spread across the options. They can put several points on one option.\n" +
+		"- `--scheduling`: Find a date: users answer yes, maybe or no for every option\n" +
+		"- `--free-text`: Let users type their own answer instead of choosing an option. Create it without any options.\n" +
+		"- `--quiz`: Create a quiz. Mark the correct answers with a leading `*`, e.g. `\"*Answer 1\"`. Voters can answer only once.\n" +
+		"- `--final`: Make votes binding: users can't change or reset their votes once they are cast\n" +
+		"- `--tie-break=X`: Break a tie once the poll has ended: `creator` lets the creator pick the winner, `runoff` starts a new poll between the tied options\n" +
+		"- `--close-at=X`: End the poll automatically once X users have voted\n" +
+		"- `--close-when-all-voted`: End the poll automatically once every member of the channel has voted. Bots and deactivated users are ignored.\n" +
+		"- `--voters=X`: Only allow some users to vote: a comma-separated list of `@username`, `@group`, `~channel`, `team_admin` and `channel_admin`\n" +
+		"- `--exclude-guests`: Don't allow guest accounts to vote and ignore them in the participation counts\n" +
+		"- `--exclude-bots`: Don't allow bots and integrations to vote and ignore them in the participation counts\n" +
+		"- `--capacity=X`: Limit every option to X voters. Limit single options like `\"Slot A [5]\"`\n" +
+		"- `--waitlist`: Put voters of full options on a waitlist. They get a spot automatically once someone leaves\n" +
+		"- `--proxy`: Let voters delegate their vote and the poll creator vote on behalf of others. Proxy votes are marked in the results\n" +
+		"- `--end=X`: End the poll automatically after a duration like `2h` or `3d`, or at a time like `2024-12-24T18:00` in your time zone\n" +
+		"Type `/poll leaderboard` to post the quiz leaderboard of the channel\n" +
+		"Type `/poll survey` to create a survey with several questions\n" +
+		"Type `/poll rerun <poll id> [~channel]` to run a poll again without its votes\n" +
+		"Type `/poll export <poll id>` to receive the results of a poll as CSV file in a direct message\n" +
+		"Type `/poll schedule add <weekday or daily> <HH:MM> [~channel] \"Question\" \"Answer 1\" \"Answer 2\"` to post a poll every week or every day. Add `--end-previous` to end the previous poll when the next one is posted. `/poll schedule list` and `/poll schedule remove <id>` show and remove the recurring polls of a channel.\n" +
+		"Type `/poll template save <name> \"Question\" \"Answer 1\" \"Answer 2\"` to save a poll as a template. Add `--team` to share it with the team. `/poll template use <name>` creates a poll from a template, `/poll template list` and `/poll template delete <name>` show and delete your templates."
 	triggerID := model.NewId()
 	rootID := model.NewId()
 
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	recurringPoll := &poll.RecurringPoll{
+		ID:          testutils.GetPollID(),
+		ChannelID:   "channelID1",
+		Poll:        testutils.GetPollWithoutPostID(),
+		Weekday:     time.Friday,
+		Hour:        10,
+		Timezone:    "Europe/Berlin",
+		EndPrevious: true,
+		NextRunAt:   time.Date(1970, 1, 16, 10, 0, 0, 0, berlin).UnixMilli(),
+	}
+
 	createPollDialog := model.OpenDialogRequest{
 		TriggerId: triggerID,
 		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/create", root.Manifest.Id),
@@ -86,6 +125,13 @@ func TestPluginExecuteCommand(t *testing.T) {
 				Placeholder: "During the poll, show how many votes each answer option got",
 				Default:     "false",
 				Optional:    true,
+			}, {
+				DisplayName: "Results after vote",
+				Name:        "setting-results-after-vote",
+				Type:        "bool",
+				Placeholder: "Show the progress to users only after they voted, so early results don't bias later voters",
+				Default:     "false",
+				Optional:    true,
 			}, {
 				DisplayName: "Public Add Option",
 				Name:        "setting-public-add-option",
@@ -93,51 +139,809 @@ func TestPluginExecuteCommand(t *testing.T) {
 				Placeholder: "Allow all users to add additional options",
 				Default:     "true",
 				Optional:    true,
+			}, {
+				DisplayName: "Ranked",
+				Name:        "setting-ranked",
+				Type:        "bool",
+				Placeholder: "Let users rank the answer options. The winner is determined by instant-runoff voting.",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Points",
+				Name:        "setting-points",
+				Type:        "text",
+				SubType:     "number",
+				Default:     "0",
+				HelpText:    "Give users X points to spread across the options. They can put several points on one option.",
+				Optional:    true,
+			}, {
+				DisplayName: "Scheduling",
+				Name:        "setting-scheduling",
+				Type:        "bool",
+				Placeholder: "Find a date: users answer yes, maybe or no for every option",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Quiz",
+				Name:        "setting-quiz",
+				Type:        "bool",
+				Placeholder: "Create a quiz. Mark the correct answers with a leading `*`, e.g. `\"*Answer 1\"`. Voters can answer only once.",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Final",
+				Name:        "setting-final",
+				Type:        "bool",
+				Placeholder: "Make votes binding: users can't change or reset their votes once they are cast",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Tie-break",
+				Name:        "setting-tie-break",
+				Type:        "select",
+				Options: []*model.PostActionOptions{{
+					Text:  "Creator picks the winner",
+					Value: "creator",
+				}, {
+					Text:  "Runoff poll",
+					Value: "runoff",
+				}},
+				HelpText: "Break a tie once the poll has ended: `creator` lets the creator pick the winner, `runoff` starts a new poll between the tied options",
+				Optional: true,
+			}, {
+				DisplayName: "Close at",
+				Name:        "setting-close-at",
+				Type:        "text",
+				SubType:     "number",
+				Default:     "0",
+				HelpText:    "End the poll automatically once X users have voted",
+				Optional:    true,
+			}, {
+				DisplayName: "Close when all voted",
+				Name:        "setting-close-when-all-voted",
+				Type:        "bool",
+				Placeholder: "End the poll automatically once every member of the channel has voted. Bots and deactivated users are ignored.",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Voters",
+				Name:        "setting-voters",
+				Type:        "text",
+				SubType:     "text",
+				Placeholder: "@user, ~channel, channel_admin",
+				HelpText:    "Only allow some users to vote: a comma-separated list of `@username`, `@group`, `~channel`, `team_admin` and `channel_admin`",
+				Optional:    true,
+			}, {
+				DisplayName: "Exclude guests",
+				Name:        "setting-exclude-guests",
+				Type:        "bool",
+				Placeholder: "Don't allow guest accounts to vote and ignore them in the participation counts",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Exclude bots",
+				Name:        "setting-exclude-bots",
+				Type:        "bool",
+				Placeholder: "Don't allow bots and integrations to vote and ignore them in the participation counts",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Capacity",
+				Name:        "setting-capacity",
+				Type:        "text",
+				SubType:     "number",
+				Default:     "0",
+				HelpText:    "Limit every option to X voters. Limit single options like `\"Slot A [5]\"`",
+				Optional:    true,
+			}, {
+				DisplayName: "Waitlist",
+				Name:        "setting-waitlist",
+				Type:        "bool",
+				Placeholder: "Put voters of full options on a waitlist. They get a spot automatically once someone leaves",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Proxy votes",
+				Name:        "setting-proxy",
+				Type:        "bool",
+				Placeholder: "Let voters delegate their vote and the poll creator vote on behalf of others. Proxy votes are marked in the results",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "End",
+				Name:        "setting-end",
+				Type:        "text",
+				SubType:     "text",
+				Placeholder: "2h",
+				HelpText:    "End the poll automatically after a duration like `2h` or `3d`, or at a time like `2024-12-24T18:00` in your time zone",
+				Optional:    true,
 			}},
 			SubmitLabel: "Create",
 		},
-	}
-
-	converter := func(userID string) (string, *model.AppError) {
-		switch userID {
-		case "userID1":
-			return "@jhDoe", nil
-		default:
-			return "", &model.AppError{}
-		}
-	}
-
-	for name, test := range map[string]struct {
-		SetupAPI     func(*plugintest.API) *plugintest.API
-		SetupStore   func(*mockstore.Store) *mockstore.Store
-		Command      string
-		ExpectedText string
-		ShouldError  bool
-	}{
-		"No argument": {
+	}
+
+	template := &poll.Template{ID: "templateID1", Name: "standup", Poll: testutils.GetPollWithoutPostID()}
+	sharedTemplate := &poll.Template{ID: "templateID2", Name: "retro", TeamID: "teamID1", Poll: testutils.GetPollWithoutPostID()}
+	sharedTemplate.Poll.Creator = "userID2"
+
+	createPollDialogWithTemplates := createPollDialog
+	createPollDialogWithTemplates.Dialog.Elements = append([]model.DialogElement{{
+		DisplayName: "Template",
+		Name:        "template",
+		Type:        "select",
+		Options: []*model.PostActionOptions{
+			{Text: "standup", Value: "templateID1"},
+			{Text: "retro", Value: "templateID2"},
+		},
+		HelpText: "Create the poll from a saved template. The other fields are ignored.",
+		Optional: true,
+	}}, createPollDialog.Dialog.Elements...)
+	for i := 1; i <= 3; i++ {
+		createPollDialogWithTemplates.Dialog.Elements[i].Optional = true
+	}
+
+	createSurveyDialog := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/v1/polls/survey/create", root.Manifest.Id),
+		Dialog: model.Dialog{
+			CallbackId: rootID,
+			Title:      "Create Survey",
+			IconURL:    fmt.Sprintf(responseIconURL, testutils.GetSiteURL(), root.Manifest.Id),
+			Elements: []model.DialogElement{{
+				DisplayName: "Title",
+				Name:        "title",
+				Type:        "text",
+				SubType:     "text",
+			}, {
+				DisplayName: "Questions",
+				Name:        "questions",
+				Type:        "textarea",
+				Placeholder: "\"Question 1\" \"Option 1\" \"Option 2\"\n\"Question 2\" \"Option 1\" \"Option 2\" \"Option 3\" --votes=2 --anonymous",
+				HelpText:    "Write every question in its own line, followed by its options in quotes. Add --anonymous or --votes=X to a line to set them for this question.",
+				MaxLength:   3000,
+			}, {
+				DisplayName: "Anonymous creator",
+				Name:        "setting-anonymous-creator",
+				Type:        "bool",
+				Placeholder: "Don't show author of the poll",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "Progress",
+				Name:        "setting-progress",
+				Type:        "bool",
+				Placeholder: "During the poll, show how many votes each answer option got",
+				Default:     "false",
+				Optional:    true,
+			}, {
+				DisplayName: "End",
+				Name:        "setting-end",
+				Type:        "text",
+				SubType:     "text",
+				Placeholder: "2h",
+				HelpText:    "End the poll automatically after a duration like `2h` or `3d`, or at a time like `2024-12-24T18:00` in your time zone",
+				Optional:    true,
+			}},
+			SubmitLabel: "Create",
+		},
+	}
+
+	converter := func(userID string) (string, *model.AppError) {
+		switch userID {
+		case "userID1":
+			return "@jhDoe", nil
+		default:
+			return "", &model.AppError{}
+		}
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI     func(*plugintest.API) *plugintest.API
+		SetupStore   func(*mockstore.Store) *mockstore.Store
+		Command      string
+		ExpectedText string
+		ShouldError  bool
+	}{
+		"No argument": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("OpenInteractiveDialog", createPollDialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s", trigger),
+			ExpectedText: "",
+		},
+		"No argument, with templates": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("OpenInteractiveDialog", createPollDialogWithTemplates).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{template, sharedTemplate}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s", trigger),
+			ExpectedText: "",
+		},
+		"No argument, listing templates fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				api.On("OpenInteractiveDialog", createPollDialog).Return(nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s", trigger),
+			ExpectedText: "",
+		},
+		"No argument, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("OpenInteractiveDialog", createPollDialog).Return(&model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Survey": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("OpenInteractiveDialog", createSurveyDialog).Return(nil)
+				return api
+			},
+			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:      fmt.Sprintf("/%s survey", trigger),
+			ExpectedText: "",
+		},
+		"Survey, OpenInteractiveDialog fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("OpenInteractiveDialog", createSurveyDialog).Return(&model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:      fmt.Sprintf("/%s survey", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Help text": {
+			SetupAPI:     func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:      fmt.Sprintf("/%s help", trigger),
+			ExpectedText: helpText,
+		},
+		"Leaderboard": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    rootID,
+					Message: "#### Quiz Leaderboard\n" +
+						"| Rank | User | Correct answers | Answered quizzes |\n" +
+						"|---:|:---|---:|---:|\n" +
+						"| 1 | @user2 | 2 | 2 |\n" +
+						"| 2 | @user1 | 1 | 2 |",
+				}).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.LeaderboardStore.On("Get", "channelID1").Return(&poll.Leaderboard{Scores: map[string]*poll.Score{
+					"userID1": {Correct: 1, Answered: 2},
+					"userID2": {Correct: 2, Answered: 2},
+				}}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s leaderboard", trigger),
+			ExpectedText: "",
+		},
+		"Leaderboard, no quiz yet": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.LeaderboardStore.On("Get", "channelID1").Return(poll.NewLeaderboard(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s leaderboard", trigger),
+			ExpectedText: "No quiz has ended in this channel yet.",
+		},
+		"Leaderboard, Get fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.LeaderboardStore.On("Get", "channelID1").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s leaderboard", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Leaderboard, CreatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.LeaderboardStore.On("Get", "channelID1").Return(&poll.Leaderboard{Scores: map[string]*poll.Score{
+					"userID1": {Correct: 1, Answered: 2},
+				}}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s leaderboard", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Rerun": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(true)
+
+				post := &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					Type:      MatterpollPostType,
+					Props: model.StringInterface{
+						"poll_id": testutils.GetPollID(),
+					},
+				}
+				model.ParseMessageAttachment(post, testutils.GetPollWithoutPostID().ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+				rPost := post.Clone()
+				rPost.Id = "postID1"
+
+				api.On("CreatePost", post).Return(rPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Insert", testutils.GetPoll()).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s rerun %s", trigger, testutils.GetPollID()),
+		},
+		"Rerun ended poll in other channel": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetChannelByName", "teamID1", "town-square", false).Return(&model.Channel{Id: "channelID2"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID2", model.PermissionCreatePost).Return(true)
+
+				post := &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID2",
+					Type:      MatterpollPostType,
+					Props: model.StringInterface{
+						"poll_id": testutils.GetPollID(),
+					},
+				}
+				model.ParseMessageAttachment(post, testutils.GetPollWithoutPostID().ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+				rPost := post.Clone()
+				rPost.Id = "postID1"
+
+				api.On("CreatePost", post).Return(rPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Insert", testutils.GetPoll()).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s rerun %s ~town-square", trigger, testutils.GetPollID()),
+		},
+		"Rerun, poll not found": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", "unknownPollID").Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", "unknownPollID").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s rerun unknownPollID", trigger),
+			ExpectedText: "There is no poll with the id unknownPollID.",
+		},
+		"Rerun, invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				p := testutils.GetPollWithVotes()
+				p.Creator = "userID2"
+				store.PollStore.On("Get", testutils.GetPollID()).Return(p, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s rerun %s", trigger, testutils.GetPollID()),
+			ExpectedText: responseRerunPollInvalidPermission.Other,
+		},
+		"Rerun, channel not found": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetChannelByName", "teamID1", "unknown", false).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s rerun %s ~unknown", trigger, testutils.GetPollID()),
+			ExpectedText: "There is no channel named ~unknown.",
+		},
+		"Rerun, no permission to post": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(false)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s rerun %s", trigger, testutils.GetPollID()),
+			ExpectedText: responseRerunPollNoPostPermission.Other,
+		},
+		"Rerun, CreatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(true)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s rerun %s", trigger, testutils.GetPollID()),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Export": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("GetDirectChannel", "userID1", testutils.GetBotUserID()).Return(&model.Channel{Id: "dmChannelID1"}, nil)
+				expectedCSV := "Option,Votes,Username,Display name,Voted at\n" +
+					"Answer 1,3,user1,user1,unknown\n" +
+					"Answer 1,3,user2,user2,unknown\n" +
+					"Answer 1,3,user3,user3,unknown\n" +
+					"Answer 2,1,user4,user4,unknown\n" +
+					"Answer 3,0,,,\n"
+				api.On("UploadFile", []byte(expectedCSV), "dmChannelID1", "poll-"+testutils.GetPollID()+".csv").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("CreatePost", &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "dmChannelID1",
+					Message:   "Here are the results of the poll **Question**.",
+					FileIds:   model.StringArray{"fileID1"},
+				}).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s export %s", trigger, testutils.GetPollID()),
+			ExpectedText: "The results have been sent to you in a direct message.",
+		},
+		"Export, poll not found": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", "unknownPollID").Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", "unknownPollID").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s export unknownPollID", trigger),
+			ExpectedText: "There is no poll with the id unknownPollID.",
+		},
+		"Export, invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				p := testutils.GetPollWithVotes()
+				p.Creator = "userID2"
+				store.PollStore.On("Get", testutils.GetPollID()).Return(p, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s export %s", trigger, testutils.GetPollID()),
+			ExpectedText: responseExportPollInvalidPermission.Other,
+		},
+		"Export, survey": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetSurveyWithAnswers(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s export %s", trigger, testutils.GetPollID()),
+			ExpectedText: responseExportPollNotSupported.Other,
+		},
+		"Export, UploadFile fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("GetDirectChannel", "userID1", testutils.GetBotUserID()).Return(&model.Channel{Id: "dmChannelID1"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "dmChannelID1", "poll-"+testutils.GetPollID()+".csv").Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s export %s", trigger, testutils.GetPollID()),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Schedule add": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Timezone: model.StringMap{
+					"useAutomaticTimezone": "false",
+					"manualTimezone":       "Europe/Berlin",
+				}}, nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(true)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("Save", recurringPoll).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s schedule add friday 10:00 \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --end-previous", trigger),
+			ExpectedText: fmt.Sprintf("Added the recurring poll `%s`: **Question** every Friday at 10:00 (Europe/Berlin), next on Fri, 16 Jan 1970 10:00 CET. "+
+				"Remove it with `/poll schedule remove %s`.", testutils.GetPollID(), testutils.GetPollID()),
+		},
+		"Schedule add, invalid schedule": {
+			SetupAPI:     func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:      fmt.Sprintf("/%s schedule add friday \"Question\"", trigger),
+			ExpectedText: "Usage: `/poll schedule add <weekday or daily> <HH:MM> [~channel] \"Question\" \"Answer 1\" \"Answer 2\" [--end-previous]`",
+		},
+		"Schedule add, invalid day": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("OpenInteractiveDialog", createPollDialog).Return(nil)
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(true)
+				return api
+			},
+			SetupStore:  func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:     fmt.Sprintf("/%s schedule add someday 10:00 \"Question\"", trigger),
+			ShouldError: true,
+		},
+		"Schedule add, invalid setting": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(true)
+				return api
+			},
+			SetupStore:  func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:     fmt.Sprintf("/%s schedule add daily 10:00 \"Question\" --unknown", trigger),
+			ShouldError: true,
+		},
+		"Schedule add, no permission to post": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(false)
 				return api
 			},
 			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
-			Command:      fmt.Sprintf("/%s", trigger),
-			ExpectedText: "",
+			Command:      fmt.Sprintf("/%s schedule add daily 10:00 \"Question\"", trigger),
+			ExpectedText: responseRerunPollNoPostPermission.Other,
 		},
-		"No argument, OpenInteractiveDialog fails": {
+		"Schedule add, Save fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
-				api.On("OpenInteractiveDialog", createPollDialog).Return(&model.AppError{})
+				api.On("HasPermissionToChannel", "userID1", "channelID1", model.PermissionCreatePost).Return(true)
 				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
 				return api
 			},
-			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
-			Command:      fmt.Sprintf("/%s", trigger),
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("Save", mock.AnythingOfType("*poll.RecurringPoll")).Return(&model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s schedule add daily 10:00 \"Question\"", trigger),
 			ExpectedText: commandErrorGeneric.Other,
 		},
-		"Help text": {
+		"Schedule list": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("List", "channelID1").Return([]*poll.RecurringPoll{recurringPoll}, nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s schedule list", trigger),
+			ExpectedText: "Recurring polls in this channel:\n" +
+				fmt.Sprintf("- `%s`: **Question** every Friday at 10:00 (Europe/Berlin), next on Fri, 16 Jan 1970 10:00 CET", testutils.GetPollID()),
+		},
+		"Schedule list, no recurring polls": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("List", "channelID1").Return([]*poll.RecurringPoll{}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s schedule list", trigger),
+			ExpectedText: "There are no recurring polls in this channel.",
+		},
+		"Schedule list, List fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("List", "channelID1").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s schedule list", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Schedule remove": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("Get", testutils.GetPollID()).Return(recurringPoll, nil)
+				store.RecurringPollStore.On("Delete", recurringPoll).Return(nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s schedule remove %s", trigger, testutils.GetPollID()),
+			ExpectedText: "Removed the recurring poll **Question**. Polls that were already posted are kept.",
+		},
+		"Schedule remove, missing id": {
 			SetupAPI:     func(api *plugintest.API) *plugintest.API { return api },
 			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
-			Command:      fmt.Sprintf("/%s help", trigger),
-			ExpectedText: helpText,
+			Command:      fmt.Sprintf("/%s schedule remove", trigger),
+			ExpectedText: "Usage: `/poll schedule remove <id>`. Type `/poll schedule list` to get the ids of the recurring polls in this channel.",
+		},
+		"Schedule remove, not found": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("Get", "unknownID").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s schedule remove unknownID", trigger),
+			ExpectedText: "There is no recurring poll with the id unknownID.",
+		},
+		"Schedule remove, invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				r := *recurringPoll
+				r.Poll = testutils.GetPollWithoutPostID()
+				r.Poll.Creator = "userID2"
+				store.RecurringPollStore.On("Get", testutils.GetPollID()).Return(&r, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s schedule remove %s", trigger, testutils.GetPollID()),
+			ExpectedText: "Only the creator of a recurring poll and System Admins are allowed to remove it.",
+		},
+		"Template save": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{}, nil)
+				store.TemplateStore.On("Save", &poll.Template{ID: testutils.GetPollID(), Name: "standup", Poll: testutils.GetPollWithoutPostID()}).Return(nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template save standup \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\"", trigger),
+			ExpectedText: "Saved the template `standup`. Create a poll from it with `/poll template use standup`.",
+		},
+		"Template save, shared with the team, overwrites own template": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				existing := &poll.Template{ID: "templateID3", Name: "standup", TeamID: "teamID1", Poll: testutils.GetPollWithoutPostID()}
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{template, existing}, nil)
+				store.TemplateStore.On("Save", &poll.Template{ID: "templateID3", Name: "standup", TeamID: "teamID1", Poll: testutils.GetPollWithoutPostID()}).Return(nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template save standup \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --team", trigger),
+			ExpectedText: "Saved the template `standup`. Create a poll from it with `/poll template use standup`.",
+		},
+		"Template save, name taken by other user": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{sharedTemplate}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template save retro \"Question\" --team", trigger),
+			ExpectedText: "A template named `retro` is already shared with this team. Only its creator and System Admins are allowed to overwrite it.",
+		},
+		"Template save, missing name": {
+			SetupAPI:     func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:   func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:      fmt.Sprintf("/%s template save \"Question\"", trigger),
+			ExpectedText: "Usage: `/poll template save <name> \"Question\" \"Answer 1\" \"Answer 2\" [--team]`",
+		},
+		"Template save, invalid setting": {
+			SetupAPI:    func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:  func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:     fmt.Sprintf("/%s template save standup \"Question\" --unknown", trigger),
+			ShouldError: true,
+		},
+		"Template save, Save fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{}, nil)
+				store.TemplateStore.On("Save", mock.AnythingOfType("*poll.Template")).Return(&model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template save standup \"Question\"", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Template use": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+
+				post := &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    rootID,
+					Type:      MatterpollPostType,
+					Props: model.StringInterface{
+						"poll_id": testutils.GetPollID(),
+					},
+				}
+				model.ParseMessageAttachment(post, testutils.GetPollWithoutPostID().ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe"))
+
+				rPost := post.Clone()
+				rPost.Id = "postID1"
+
+				api.On("CreatePost", post).Return(rPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{sharedTemplate, template}, nil)
+				store.PollStore.On("Insert", testutils.GetPoll()).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s template use standup", trigger),
+		},
+		"Template use, not found": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{template}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template use unknown", trigger),
+			ExpectedText: "There is no template named `unknown`. Type `/poll template list` to see your templates.",
+		},
+		"Template use, List fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return(nil, &model.AppError{})
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template use standup", trigger),
+			ExpectedText: commandErrorGeneric.Other,
+		},
+		"Template list": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{sharedTemplate, template}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template list", trigger),
+			ExpectedText: "Templates:\n- `retro`: Question (shared with the team)\n- `standup`: Question",
+		},
+		"Template list, no templates": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template list", trigger),
+			ExpectedText: "There are no templates yet. Save one with `/poll template save <name> \"Question\" \"Answer 1\" \"Answer 2\"`.",
+		},
+		"Template delete": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{template}, nil)
+				store.TemplateStore.On("Delete", template).Return(nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template delete standup", trigger),
+			ExpectedText: "Deleted the template `standup`.",
+		},
+		"Template delete, invalid permission": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.TemplateStore.On("List", "userID1", "teamID1").Return([]*poll.Template{sharedTemplate}, nil)
+				return store
+			},
+			Command:      fmt.Sprintf("/%s template delete retro", trigger),
+			ExpectedText: "Only the creator of a template and System Admins are allowed to delete it.",
 		},
 		"Two arguments": {
 			SetupAPI:    func(api *plugintest.API) *plugintest.API { return api },
@@ -174,6 +978,21 @@ func TestPluginExecuteCommand(t *testing.T) {
 			},
 			Command: fmt.Sprintf("/%s \"Question\"", trigger),
 		},
+		"Question starting with a subcommand": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				p := testutils.GetPollTwoOptions()
+				p.Question = "export results"
+				store.PollStore.On("Insert", p).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s \"export results\"", trigger),
+		},
 		"Just question and setting anonymous creator": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
@@ -205,6 +1024,45 @@ func TestPluginExecuteCommand(t *testing.T) {
 			},
 			Command: fmt.Sprintf("/%s \"Question\" --anonymous-creator", trigger),
 		},
+		"Just question and setting free-text": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+
+				post := &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    rootID,
+					Type:      MatterpollPostType,
+					Props: model.StringInterface{
+						"poll_id": testutils.GetPollID(),
+					},
+				}
+				poll := testutils.GetFreeTextPollWithResponses()
+				poll.Responses = nil
+				actions := poll.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe")
+				model.ParseMessageAttachment(post, actions)
+
+				rPost := post.Clone()
+				rPost.Id = "postID1"
+
+				api.On("CreatePost", post).Return(rPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetFreeTextPollWithResponses()
+				poll.Responses = nil
+				store.PollStore.On("Insert", poll).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s \"Question\" --free-text", trigger),
+		},
+		"Free-text setting with options": {
+			SetupAPI:    func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:  func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:     fmt.Sprintf("/%s \"Question\" \"Answer 1\" \"Answer 2\" --free-text", trigger),
+			ShouldError: true,
+		},
 		"Just question, CreatePost fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
@@ -366,6 +1224,7 @@ func TestPluginExecuteCommand(t *testing.T) {
 					},
 				}
 				poll := testutils.GetPollWithSettings(poll.Settings{Progress: true, Anonymous: true, MaxVotes: 1})
+				poll.SecretBallot = true
 				actions := poll.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe")
 				model.ParseMessageAttachment(post, actions)
 				post.AddProp("card", poll.ToCard(testutils.GetBundle(), converter))
@@ -378,11 +1237,43 @@ func TestPluginExecuteCommand(t *testing.T) {
 			},
 			SetupStore: func(store *mockstore.Store) *mockstore.Store {
 				poll := testutils.GetPollWithSettings(poll.Settings{Progress: true, Anonymous: true, MaxVotes: 1})
+				poll.SecretBallot = true
 				store.PollStore.On("Insert", poll).Return(nil)
 				return store
 			},
 			Command: fmt.Sprintf("/%s \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --anonymous --progress", trigger),
 		},
+		"With 4 arguments and end setting": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+
+				post := &model.Post{
+					UserId:    testutils.GetBotUserID(),
+					ChannelId: "channelID1",
+					RootId:    rootID,
+					Type:      MatterpollPostType,
+					Props: model.StringInterface{
+						"poll_id": testutils.GetPollID(),
+					},
+				}
+				poll := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: testutils.GetMillis() + 2*60*60*1000})
+				actions := poll.ToPostActions(testutils.GetBundle(), root.Manifest.Id, "John Doe")
+				model.ParseMessageAttachment(post, actions)
+
+				rPost := post.Clone()
+				rPost.Id = "postID1"
+
+				api.On("CreatePost", post).Return(rPost, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				poll := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: testutils.GetMillis() + 2*60*60*1000})
+				store.PollStore.On("Insert", poll).Return(nil)
+				return store
+			},
+			Command: fmt.Sprintf("/%s \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --end=2h", trigger),
+		},
 		"Store.Save fails": {
 			SetupAPI: func(api *plugintest.API) *plugintest.API {
 				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
@@ -450,6 +1341,12 @@ func TestPluginExecuteCommand(t *testing.T) {
 			Command:     fmt.Sprintf("/%s \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --votes=4", trigger),
 			ShouldError: true,
 		},
+		"Invalid end setting": {
+			SetupAPI:    func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:  func(store *mockstore.Store) *mockstore.Store { return store },
+			Command:     fmt.Sprintf("/%s \"Question\" \"Answer 1\" \"Answer 2\" \"Answer 3\" --end=someday", trigger),
+			ShouldError: true,
+		},
 		"Invalid multi setting, not number": {
 			SetupAPI:    func(api *plugintest.API) *plugintest.API { return api },
 			SetupStore:  func(store *mockstore.Store) *mockstore.Store { return store },
@@ -485,6 +1382,7 @@ func TestPluginExecuteCommand(t *testing.T) {
 				Command:   test.Command,
 				UserId:    "userID1",
 				ChannelId: "channelID1",
+				TeamId:    "teamID1",
 				RootId:    rootID,
 				TriggerId: triggerID,
 			})
@@ -498,3 +1396,46 @@ func TestPluginExecuteCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestPluginExecuteCommandQuotedKeyword(t *testing.T) {
+	const trigger = "poll"
+
+	for name, question := range map[string]string{
+		"help":        "help",
+		"leaderboard": "leaderboard",
+		"survey":      "survey",
+		"rerun":       "rerun " + testutils.GetPollID(),
+		"export":      "export " + testutils.GetPollID(),
+		"schedule":    "schedule add",
+		"template":    "template list",
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := &plugintest.API{}
+			api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+			api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: "postID1"}, nil)
+			defer api.AssertExpectations(t)
+			store := &mockstore.Store{}
+			expectedPoll := testutils.GetPollTwoOptions()
+			expectedPoll.Question = question
+			store.PollStore.On("Insert", expectedPoll).Return(nil)
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.configuration.Trigger = trigger
+			p.configuration.DefaultSettings = map[string]bool{"anonymous": true, "publicAddOption": true}
+
+			p.pf.SetNewID(testutils.GetPollID)
+			p.pf.SetMillis(testutils.GetMillis)
+
+			r, err := p.ExecuteCommand(nil, &model.CommandArgs{
+				Command:   fmt.Sprintf("/%s \"%s\"", trigger, question),
+				UserId:    "userID1",
+				ChannelId: "channelID1",
+				TeamId:    "teamID1",
+			})
+
+			assert.Equal(t, &model.CommandResponse{}, r)
+			assert.Nil(t, err)
+		})
+	}
+}
@@ -13,6 +13,11 @@ type configuration struct {
 	Trigger         string          `json:"trigger"`
 	ExperimentalUI  bool            `json:"experimentalui"`
 	DefaultSettings map[string]bool `json:"default_settings"`
+	// ExcludeGuests and ExcludeBots block guest accounts and bots from voting in all polls.
+	ExcludeGuests bool `json:"excludeguests"`
+	ExcludeBots   bool `json:"excludebots"`
+	// EndedPollRetentionDays is the number of days after which ended polls are deleted. 0 keeps them forever.
+	EndedPollRetentionDays int `json:"endedpollretentiondays"`
 }
 
 // OnConfigurationChange loads the plugin configuration, validates it and saves it.
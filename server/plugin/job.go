@@ -0,0 +1,204 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+)
+
+const (
+	// endPollJobPrefix is the key prefix of jobs that end a poll automatically.
+	endPollJobPrefix = "endpoll_"
+	// recurringPollJobPrefix is the key prefix of jobs that post a recurring poll.
+	recurringPollJobPrefix = "recurringpoll_"
+	// cleanupJobKey is the key of the job that deletes ended polls after their retention period.
+	cleanupJobKey = "cleanup_ended_polls"
+)
+
+// jobScheduler schedules jobs which run once at a given time.
+// Jobs are persisted in the KV Store, hence they survive plugin restarts,
+// and each job only runs on one server of a cluster.
+type jobScheduler interface {
+	ScheduleOnce(key string, runAt time.Time, props any) (*cluster.JobOnce, error)
+	Cancel(key string)
+}
+
+// initScheduler starts the job scheduler and picks up all jobs scheduled before the plugin was (re)started.
+func (p *MatterpollPlugin) initScheduler() error {
+	scheduler := cluster.GetJobOnceScheduler(p.API)
+	if err := scheduler.SetCallback(p.runScheduledJob); err != nil {
+		return errors.Wrap(err, "failed to set callback of job scheduler")
+	}
+	if err := scheduler.Start(); err != nil {
+		return errors.Wrap(err, "failed to start job scheduler")
+	}
+
+	p.scheduler = scheduler
+	return nil
+}
+
+// initCleanupJob starts the job that deletes ended polls after their retention period.
+// It runs once a day on one server of a cluster.
+func (p *MatterpollPlugin) initCleanupJob() error {
+	job, err := cluster.Schedule(p.API, cleanupJobKey, cluster.MakeWaitForRoundedInterval(24*time.Hour), p.deleteExpiredEndedPolls)
+	if err != nil {
+		return errors.Wrap(err, "failed to schedule cleanup job")
+	}
+
+	p.cleanupJob = job
+	return nil
+}
+
+// runScheduledJob is called by the job scheduler when a job is due.
+func (p *MatterpollPlugin) runScheduledJob(key string, _ any) {
+	switch {
+	case strings.HasPrefix(key, endPollJobPrefix):
+		pollID := strings.TrimPrefix(key, endPollJobPrefix)
+		if err := p.endPollAutomatically(pollID); err != nil {
+			p.API.LogWarn("Failed to end poll automatically", "pollID", pollID, "error", err.Error())
+		}
+	case strings.HasPrefix(key, recurringPollJobPrefix):
+		recurringPollID, _, _ := strings.Cut(strings.TrimPrefix(key, recurringPollJobPrefix), "_")
+		if err := p.postRecurringPoll(recurringPollID); err != nil {
+			p.API.LogWarn("Failed to post recurring poll", "recurringPollID", recurringPollID, "error", err.Error())
+		}
+	default:
+		p.API.LogWarn("Unknown scheduled job", "key", key)
+	}
+}
+
+// scheduleEndPoll schedules a job that ends a given poll at its end time.
+// It's a no-op for polls without an end time.
+func (p *MatterpollPlugin) scheduleEndPoll(poll *poll.Poll) error {
+	if !poll.HasEndTime() {
+		return nil
+	}
+
+	if _, err := p.scheduler.ScheduleOnce(endPollJobPrefix+poll.ID, time.UnixMilli(poll.Settings.EndAt), nil); err != nil {
+		return errors.Wrap(err, "failed to schedule end of poll")
+	}
+	return nil
+}
+
+// cancelEndPoll cancels the job that ends a given poll automatically.
+// It must not be called from within a running job, as canceling waits for the job to finish.
+func (p *MatterpollPlugin) cancelEndPoll(poll *poll.Poll) {
+	if !poll.HasEndTime() {
+		return
+	}
+
+	p.scheduler.Cancel(endPollJobPrefix + poll.ID)
+}
+
+// endPollAutomatically ends the poll with a given id once its end time has come.
+func (p *MatterpollPlugin) endPollAutomatically(pollID string) error {
+	poll, err := p.Store.Poll().Get(pollID)
+	if err != nil {
+		// The poll was already ended or deleted manually.
+		return errors.Wrap(err, "failed to get poll")
+	}
+
+	post, appErr := p.API.GetPost(poll.PostID)
+	if appErr != nil {
+		return errors.Wrap(appErr, "failed to get post")
+	}
+
+	return p.endPoll(poll, post.Id, post.ChannelId)
+}
+
+// recurringPollJobKey returns the key of the job that posts a given recurring poll at its next run.
+// Every run gets its own key, as a job can't schedule another job with the same key while it's running.
+func recurringPollJobKey(r *poll.RecurringPoll) string {
+	return fmt.Sprintf("%s%s_%d", recurringPollJobPrefix, r.ID, r.NextRunAt)
+}
+
+// scheduleRecurringPoll schedules a job that posts a given recurring poll at its next run.
+func (p *MatterpollPlugin) scheduleRecurringPoll(r *poll.RecurringPoll) error {
+	if _, err := p.scheduler.ScheduleOnce(recurringPollJobKey(r), time.UnixMilli(r.NextRunAt), nil); err != nil {
+		return errors.Wrap(err, "failed to schedule recurring poll")
+	}
+	return nil
+}
+
+// cancelRecurringPoll cancels the job that posts a given recurring poll at its next run.
+func (p *MatterpollPlugin) cancelRecurringPoll(r *poll.RecurringPoll) {
+	p.scheduler.Cancel(recurringPollJobKey(r))
+}
+
+// postRecurringPoll posts the recurring poll with a given id and schedules its next run.
+// If the recurring poll should end the previous poll, it's ended first.
+func (p *MatterpollPlugin) postRecurringPoll(recurringPollID string) error {
+	r, err := p.Store.RecurringPoll().Get(recurringPollID)
+	if err != nil {
+		// The recurring poll was removed.
+		return errors.Wrap(err, "failed to get recurring poll")
+	}
+
+	if r.EndPrevious && r.LastPollID != "" {
+		if err := p.endPollAutomatically(r.LastPollID); err != nil {
+			// The previous poll was already ended or deleted manually.
+			p.API.LogDebug("Didn't end previous poll of recurring poll", "recurringPollID", r.ID, "error", err.Error())
+		}
+	}
+
+	// A failed post doesn't stop the recurring poll, it's posted again at the next run.
+	var postErr error
+	newPoll, errMsg := p.pf.Rerun(r.Poll.Creator, r.Poll)
+	if errMsg != nil {
+		postErr = errors.Errorf("invalid poll: %s", errMsg.Message.Other)
+	} else if _, err := p.createPollPost(newPoll, r.ChannelID, ""); err != nil {
+		postErr = err
+	} else {
+		r.LastPollID = newPoll.ID
+	}
+
+	r.NextRunAt = r.NextRun(max(p.pf.Millis(), r.NextRunAt))
+	if err := p.Store.RecurringPoll().Save(r); err != nil {
+		return errors.Wrap(err, "failed to save recurring poll")
+	}
+	if err := p.scheduleRecurringPoll(r); err != nil {
+		return err
+	}
+
+	return postErr
+}
+
+// deleteExpiredEndedPolls deletes all ended polls, whose retention period is over, together with their vote histories.
+func (p *MatterpollPlugin) deleteExpiredEndedPolls() {
+	retentionDays := p.getConfiguration().EndedPollRetentionDays
+	if retentionDays <= 0 {
+		return
+	}
+
+	endedPolls, err := p.Store.Poll().ListEnded()
+	if err != nil {
+		p.API.LogWarn("Failed to list ended polls", "error", err.Error())
+		return
+	}
+
+	deleteBefore := p.pf.Millis() - int64(retentionDays)*(24*time.Hour).Milliseconds()
+	for _, endedPoll := range endedPolls {
+		endedAt := endedPoll.EndedAt
+		if endedAt == 0 {
+			// The poll ended before the end time was recorded
+			endedAt = endedPoll.CreatedAt
+		}
+		if endedAt >= deleteBefore {
+			continue
+		}
+
+		if err := p.Store.Poll().DeleteEnded(endedPoll); err != nil {
+			p.API.LogWarn("Failed to delete ended poll", "pollID", endedPoll.ID, "error", err.Error())
+			continue
+		}
+		if err := p.Store.VoteHistory().Delete(endedPoll.ID); err != nil {
+			p.API.LogWarn("Failed to delete the vote history", "pollID", endedPoll.ID, "error", err.Error())
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+
+	root "github.com/matterpoll/matterpoll"
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/store"
+	"github.com/matterpoll/matterpoll/server/store/mockstore"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestScheduleEndPoll(t *testing.T) {
+	endAt := testutils.GetMillis() + 60*60*1000
+
+	t.Run("poll without end time", func(t *testing.T) {
+		p := setupTestPlugin(t, &plugintest.API{}, &mockstore.Store{})
+
+		err := p.scheduleEndPoll(testutils.GetPoll())
+		assert.Nil(t, err)
+		assert.Empty(t, p.scheduler.(*testScheduler).jobs)
+	})
+
+	t.Run("poll with end time", func(t *testing.T) {
+		p := setupTestPlugin(t, &plugintest.API{}, &mockstore.Store{})
+
+		err := p.scheduleEndPoll(testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: endAt}))
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]time.Time{
+			endPollJobPrefix + testutils.GetPollID(): time.UnixMilli(endAt),
+		}, p.scheduler.(*testScheduler).jobs)
+	})
+
+	t.Run("job already scheduled", func(t *testing.T) {
+		p := setupTestPlugin(t, &plugintest.API{}, &mockstore.Store{})
+		poll := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: endAt})
+
+		require.Nil(t, p.scheduleEndPoll(poll))
+		assert.NotNil(t, p.scheduleEndPoll(poll))
+	})
+}
+
+func TestCancelEndPoll(t *testing.T) {
+	endAt := testutils.GetMillis() + 60*60*1000
+	p := setupTestPlugin(t, &plugintest.API{}, &mockstore.Store{})
+	poll := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: endAt})
+
+	require.Nil(t, p.scheduleEndPoll(poll))
+	p.cancelEndPoll(poll)
+	assert.Empty(t, p.scheduler.(*testScheduler).jobs)
+}
+
+func TestRunScheduledJob(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		switch userID {
+		case "userID1":
+			return "@user1", nil
+		case "userID2":
+			return "@user2", nil
+		case "userID3":
+			return "@user3", nil
+		case "userID4":
+			return "@user4", nil
+		default:
+			return "", &model.AppError{}
+		}
+	}
+	pollWithEndTime := testutils.GetPollWithVotes()
+	pollWithEndTime.Settings.EndAt = testutils.GetMillis() + 60*60*1000
+
+	expectedPost, err := pollWithEndTime.ToEndPollPost(testutils.GetBundle(), root.Manifest.Id, "John Doe", converter)
+	require.Nil(t, err)
+	expectedPost.Id = "postID1"
+	expectedPost.FileIds = model.StringArray{"fileID1"}
+
+	post := &model.Post{
+		Id:        "postID1",
+		ChannelId: "channelID1",
+	}
+
+	for name, test := range map[string]struct {
+		SetupAPI   func(*plugintest.API) *plugintest.API
+		SetupStore func(*mockstore.Store) *mockstore.Store
+		Key        string
+	}{
+		"end poll": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1", FirstName: "John", LastName: "Doe"}, nil)
+				api.On("GetUser", "userID2").Return(&model.User{Username: "user2"}, nil)
+				api.On("GetUser", "userID3").Return(&model.User{Username: "user3"}, nil)
+				api.On("GetUser", "userID4").Return(&model.User{Username: "user4"}, nil)
+				api.On("UploadFile", mock.AnythingOfType("[]uint8"), "channelID1", "results.png").Return(&model.FileInfo{Id: "fileID1"}, nil)
+				api.On("UpdatePost", expectedPost).Return(nil, nil)
+				api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+					return p.ChannelId == "channelID1" && p.RootId == "postID1"
+				})).Return(nil, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollWithEndTime.Copy(), nil)
+				store.PollStore.On("End", pollWithEndTime).Return(nil)
+				return store
+			},
+			Key: endPollJobPrefix + testutils.GetPollID(),
+		},
+		"end poll, poll was already ended": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				return store
+			},
+			Key: endPollJobPrefix + testutils.GetPollID(),
+		},
+		"end poll, poll was ended manually at the same time": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(post, nil)
+				return api
+			},
+			SetupStore: func(s *mockstore.Store) *mockstore.Store {
+				s.PollStore.On("Get", testutils.GetPollID()).Return(pollWithEndTime.Copy(), nil)
+				s.PollStore.On("End", pollWithEndTime).Return(store.ErrPollAlreadyEnded)
+				return s
+			},
+			Key: endPollJobPrefix + testutils.GetPollID(),
+		},
+		"end poll, GetPost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetPost", "postID1").Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(pollWithEndTime.Copy(), nil)
+				return store
+			},
+			Key: endPollJobPrefix + testutils.GetPollID(),
+		},
+		"post recurring poll, recurring poll was removed": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.RecurringPollStore.On("Get", "recurringPollID").Return(nil, &model.AppError{})
+				return store
+			},
+			Key: recurringPollJobPrefix + "recurringPollID_1735293600000",
+		},
+		"unknown job": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+			Key:        "unknown_key",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			p.runScheduledJob(test.Key, nil)
+		})
+	}
+}
+
+func TestPostRecurringPoll(t *testing.T) {
+	// Friday, 27 Dec 2024 10:00 UTC
+	runAt := time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC)
+	nextRunAt := runAt.AddDate(0, 0, 7).UnixMilli()
+	getRecurringPoll := func() *poll.RecurringPoll {
+		return &poll.RecurringPoll{
+			ID:        "recurringPollID",
+			ChannelID: "channelID1",
+			Poll:      testutils.GetPollWithoutPostID(),
+			Weekday:   time.Friday,
+			Hour:      10,
+			Timezone:  "UTC",
+			NextRunAt: runAt.UnixMilli(),
+		}
+	}
+	newPoll := testutils.GetPoll()
+	newPoll.ID = "newPollID"
+	newPoll.CreatedAt = runAt.UnixMilli()
+
+	for name, test := range map[string]struct {
+		SetupAPI      func(*plugintest.API) *plugintest.API
+		SetupStore    func(*mockstore.Store) *mockstore.Store
+		RecurringPoll *poll.RecurringPoll
+		ShouldError   bool
+		ExpectedJobs  map[string]time.Time
+	}{
+		"all fine": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				r := getRecurringPoll()
+				r.LastPollID = "newPollID"
+				r.NextRunAt = nextRunAt
+				store.PollStore.On("Insert", newPoll).Return(nil)
+				store.RecurringPollStore.On("Save", r).Return(nil)
+				return store
+			},
+			RecurringPoll: getRecurringPoll(),
+			ExpectedJobs: map[string]time.Time{
+				recurringPollJobPrefix + "recurringPollID_1735898400000": time.UnixMilli(nextRunAt),
+			},
+		},
+		"previous poll was already ended": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				r := getRecurringPoll()
+				r.EndPrevious = true
+				r.LastPollID = "newPollID"
+				r.NextRunAt = nextRunAt
+				store.PollStore.On("Get", "previousPollID").Return(nil, &model.AppError{})
+				store.PollStore.On("Insert", newPoll).Return(nil)
+				store.RecurringPollStore.On("Save", r).Return(nil)
+				return store
+			},
+			RecurringPoll: func() *poll.RecurringPoll {
+				r := getRecurringPoll()
+				r.EndPrevious = true
+				r.LastPollID = "previousPollID"
+				return r
+			}(),
+			ExpectedJobs: map[string]time.Time{
+				recurringPollJobPrefix + "recurringPollID_1735898400000": time.UnixMilli(nextRunAt),
+			},
+		},
+		"CreatePost fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, &model.AppError{})
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				r := getRecurringPoll()
+				r.NextRunAt = nextRunAt
+				store.RecurringPollStore.On("Save", r).Return(nil)
+				return store
+			},
+			RecurringPoll: getRecurringPoll(),
+			ShouldError:   true,
+			ExpectedJobs: map[string]time.Time{
+				recurringPollJobPrefix + "recurringPollID_1735898400000": time.UnixMilli(nextRunAt),
+			},
+		},
+		"Save fails": {
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{FirstName: "John", LastName: "Doe"}, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: "postID1"}, nil)
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Insert", newPoll).Return(nil)
+				store.RecurringPollStore.On("Save", mock.AnythingOfType("*poll.RecurringPoll")).Return(&model.AppError{})
+				return store
+			},
+			RecurringPoll: getRecurringPoll(),
+			ShouldError:   true,
+			ExpectedJobs:  map[string]time.Time{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			store.RecurringPollStore.On("Get", "recurringPollID").Return(test.RecurringPoll, nil)
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.pf.SetNewID(func() string { return "newPollID" })
+			p.pf.SetMillis(func() int64 { return runAt.UnixMilli() })
+
+			err := p.postRecurringPoll("recurringPollID")
+			if test.ShouldError {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, test.ExpectedJobs, p.scheduler.(*testScheduler).jobs)
+		})
+	}
+}
+
+func TestDeleteExpiredEndedPolls(t *testing.T) {
+	day := (24 * time.Hour).Milliseconds()
+	expiredPoll := testutils.GetPollWithVotes()
+	expiredPoll.ID = "pollID1"
+	expiredPoll.EndedAt = testutils.GetMillis() - 31*day
+	recentPoll := testutils.GetPollWithVotes()
+	recentPoll.ID = "pollID2"
+	recentPoll.EndedAt = testutils.GetMillis() - 29*day
+	legacyPoll := testutils.GetPollWithVotes()
+	legacyPoll.ID = "pollID3"
+	legacyPoll.CreatedAt = testutils.GetMillis() - 31*day
+
+	for name, test := range map[string]struct {
+		RetentionDays int
+		SetupAPI      func(*plugintest.API) *plugintest.API
+		SetupStore    func(*mockstore.Store) *mockstore.Store
+	}{
+		"Expired polls are deleted": {
+			RetentionDays: 30,
+			SetupAPI:      func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("ListEnded").Return([]*poll.Poll{expiredPoll, recentPoll, legacyPoll}, nil)
+				store.PollStore.On("DeleteEnded", expiredPoll).Return(nil)
+				store.PollStore.On("DeleteEnded", legacyPoll).Return(nil)
+				store.VoteHistoryStore.On("Delete", "pollID1").Return(nil)
+				store.VoteHistoryStore.On("Delete", "pollID3").Return(nil)
+				return store
+			},
+		},
+		"Retention disabled": {
+			RetentionDays: 0,
+			SetupAPI:      func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore:    func(store *mockstore.Store) *mockstore.Store { return store },
+		},
+		"ListEnded fails": {
+			RetentionDays: 30,
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("ListEnded").Return(nil, &model.AppError{})
+				return store
+			},
+		},
+		"DeleteEnded fails": {
+			RetentionDays: 30,
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("ListEnded").Return([]*poll.Poll{expiredPoll}, nil)
+				store.PollStore.On("DeleteEnded", expiredPoll).Return(&model.AppError{})
+				return store
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+			p.setConfiguration(&configuration{Trigger: "poll", EndedPollRetentionDays: test.RetentionDays})
+			p.pf.SetMillis(testutils.GetMillis)
+
+			p.deleteExpiredEndedPolls()
+		})
+	}
+}
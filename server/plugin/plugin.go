@@ -1,8 +1,11 @@
 package plugin
 
 import (
+	"net/http"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
@@ -11,6 +14,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/mattermost/mattermost/server/public/pluginapi/experimental/command"
 
 	root "github.com/matterpoll/matterpoll"
@@ -43,6 +47,14 @@ type MatterpollPlugin struct {
 	getIconData func() (string, error)
 
 	pf poll.Factory
+
+	// secretBallotKey is used to derive the voter tokens of secret ballots. It's derived from the at rest encryption key of the server and never stored.
+	secretBallotKey []byte
+
+	// scheduler runs jobs like ending polls at their end time.
+	scheduler jobScheduler
+	// cleanupJob deletes ended polls after their retention period.
+	cleanupJob *cluster.Job
 }
 
 var botDescription = &i18n.Message{
@@ -81,6 +93,11 @@ func (p *MatterpollPlugin) OnActivate() error {
 		return errors.Wrap(err, "failed to create store")
 	}
 
+	p.secretBallotKey, err = p.Store.System().GetSecretBallotKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to get secret ballot key")
+	}
+
 	p.bundle, err = utils.InitBundle(p.API, filepath.Join("assets", "i18n"))
 	if err != nil {
 		return errors.Wrap(err, "failed to init localisation bundle")
@@ -110,6 +127,14 @@ func (p *MatterpollPlugin) OnActivate() error {
 		return errors.Wrap(err, "failed to register command")
 	}
 
+	if err := p.initScheduler(); err != nil {
+		return errors.Wrap(err, "failed to init job scheduler")
+	}
+
+	if err := p.initCleanupJob(); err != nil {
+		return errors.Wrap(err, "failed to init cleanup job")
+	}
+
 	p.router = p.InitAPI()
 
 	p.setActivated(true)
@@ -117,13 +142,51 @@ func (p *MatterpollPlugin) OnActivate() error {
 	return nil
 }
 
-// OnDeactivate marks the plugin as deactivated
+// getSecretBallotKey returns the key used to derive the voter tokens of secret ballots.
+func (p *MatterpollPlugin) getSecretBallotKey() []byte {
+	return p.secretBallotKey
+}
+
+// OnDeactivate marks the plugin as deactivated and stops the cleanup job
 func (p *MatterpollPlugin) OnDeactivate() error {
 	p.setActivated(false)
 
+	if p.cleanupJob != nil {
+		if err := p.cleanupJob.Close(); err != nil {
+			return errors.Wrap(err, "failed to close cleanup job")
+		}
+	}
+
 	return nil
 }
 
+// MessageHasBeenDeleted deletes the poll of a deleted poll post, no matter if it's active or ended, together with its vote history.
+func (p *MatterpollPlugin) MessageHasBeenDeleted(_ *plugin.Context, post *model.Post) {
+	if post.UserId != p.botUserID {
+		return
+	}
+	pollID, _ := post.GetProp("poll_id").(string)
+	if pollID == "" {
+		return
+	}
+
+	// The poll is already deleted, if the post was deleted with the delete button of the poll
+	if activePoll, err := p.Store.Poll().Get(pollID); err == nil {
+		p.cancelEndPoll(activePoll)
+		if err := p.Store.Poll().Delete(activePoll); err != nil {
+			p.API.LogWarn("Failed to delete poll", "pollID", pollID, "error", err.Error())
+		}
+	}
+	if endedPoll, err := p.Store.Poll().GetEnded(pollID); err == nil {
+		if err := p.Store.Poll().DeleteEnded(endedPoll); err != nil {
+			p.API.LogWarn("Failed to delete ended poll", "pollID", pollID, "error", err.Error())
+		}
+	}
+	if err := p.Store.VoteHistory().Delete(pollID); err != nil {
+		p.API.LogWarn("Failed to delete the vote history", "pollID", pollID, "error", err.Error())
+	}
+}
+
 func (p *MatterpollPlugin) setActivated(activated bool) {
 	p.activated = activated
 }
@@ -165,13 +228,63 @@ func (p *MatterpollPlugin) ConvertCreatorIDToDisplayName(creatorID string) (stri
 	if err != nil {
 		return "", err
 	}
+	return p.getDisplayName(user), nil
+}
+
+// ConvertUserIDToNames returns the username and the display name to a given user ID
+func (p *MatterpollPlugin) ConvertUserIDToNames(userID string) (string, string, *model.AppError) {
+	user, err := p.API.GetUser(userID)
+	if err != nil {
+		return "", "", err
+	}
+	return user.Username, p.getDisplayName(user), nil
+}
+
+// ConvertUserIDToUsername returns the username to a given user ID
+func (p *MatterpollPlugin) ConvertUserIDToUsername(userID string) (string, *model.AppError) {
+	user, err := p.API.GetUser(userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+// ConvertUsernameToUserID returns the user ID to a given username
+func (p *MatterpollPlugin) ConvertUsernameToUserID(username string) (string, *model.AppError) {
+	user, err := p.API.GetUserByUsername(username)
+	if err != nil {
+		return "", err
+	}
+	return user.Id, nil
+}
+
+// getUserLocation returns the time zone of a given user. It falls back to UTC if the user or their time zone can't be found.
+func (p *MatterpollPlugin) getUserLocation(userID string) *time.Location {
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		return time.UTC
+	}
+	return getLocation(user)
+}
+
+// getLocation returns the preferred time zone of a given user, or UTC if it's unknown.
+func getLocation(user *model.User) *time.Location {
+	loc, err := time.LoadLocation(user.GetPreferredTimezone())
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// getDisplayName returns the full name of a given user, if the server is configured to show it, and the username otherwise.
+func (p *MatterpollPlugin) getDisplayName(user *model.User) string {
 	setting := p.ServerConfig.PrivacySettings.ShowFullName
 	// Need to check if settings value is nil pointer, because PrivacySettings.ShowFullName
 	// can be nil pointer when ShowFullName setting is false.
 	if setting == nil || !*setting {
-		return user.GetDisplayName(model.ShowUsername), nil
+		return user.GetDisplayName(model.ShowUsername)
 	}
-	return user.GetDisplayName(model.ShowNicknameFullName), nil
+	return user.GetDisplayName(model.ShowNicknameFullName)
 }
 
 // CanManagePoll checks if a given user has the permission to manage i.e. end or delete a given poll
@@ -180,16 +293,154 @@ func (p *MatterpollPlugin) CanManagePoll(poll *poll.Poll, issuerID string) (bool
 		return true, nil
 	}
 
-	user, appErr := p.API.GetUser(issuerID)
+	return p.IsSystemAdmin(issuerID)
+}
+
+// IsSystemAdmin checks if a given user is a system admin
+func (p *MatterpollPlugin) IsSystemAdmin(userID string) (bool, *model.AppError) {
+	user, appErr := p.API.GetUser(userID)
 	if appErr != nil {
 		return false, appErr
 	}
-	if user.IsInRole(model.SystemAdminRoleId) {
+	return user.IsInRole(model.SystemAdminRoleId), nil
+}
+
+// IsEligible checks if a given user is allowed to vote in a given poll, which is posted in a given channel.
+// Everyone who can read the channel is allowed to vote, unless the poll restricts the voters.
+func (p *MatterpollPlugin) IsEligible(poll *poll.Poll, userID, channelID string) (bool, error) {
+	if !poll.IsRestricted() {
 		return true, nil
 	}
+	voters := poll.Settings.Voters
+
+	if len(voters.Names) > 0 {
+		user, appErr := p.API.GetUser(userID)
+		if appErr != nil {
+			return false, errors.Wrap(appErr, "failed to get user")
+		}
+		if slices.Contains(voters.Names, user.Username) {
+			return true, nil
+		}
+
+		groups, appErr := p.API.GetGroupsForUser(userID)
+		if appErr != nil {
+			return false, errors.Wrap(appErr, "failed to get groups of user")
+		}
+		for _, group := range groups {
+			if group.Name != nil && slices.Contains(voters.Names, *group.Name) {
+				return true, nil
+			}
+		}
+	}
+
+	if voters.HasRole(model.ChannelAdminRoleId) {
+		member, appErr := p.API.GetChannelMember(channelID, userID)
+		if appErr != nil {
+			return false, errors.Wrap(appErr, "failed to get channel member")
+		}
+		if member.SchemeAdmin || slices.Contains(member.GetRoles(), model.ChannelAdminRoleId) {
+			return true, nil
+		}
+	}
+
+	if !voters.HasRole(model.TeamAdminRoleId) && len(voters.Channels) == 0 {
+		return false, nil
+	}
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		return false, errors.Wrap(appErr, "failed to get channel")
+	}
+
+	if voters.HasRole(model.TeamAdminRoleId) {
+		member, appErr := p.API.GetTeamMember(channel.TeamId, userID)
+		if appErr != nil {
+			return false, errors.Wrap(appErr, "failed to get team member")
+		}
+		if member.SchemeAdmin || slices.Contains(member.GetRoles(), model.TeamAdminRoleId) {
+			return true, nil
+		}
+	}
+
+	for _, name := range voters.Channels {
+		votersChannel, appErr := p.API.GetChannelByName(channel.TeamId, name, false)
+		if appErr != nil {
+			// The channel might have been renamed or deleted since the poll was created
+			p.API.LogWarn("Failed to get channel of eligible voters", "channel", name, "error", appErr.Error())
+			continue
+		}
+		if _, appErr := p.API.GetChannelMember(votersChannel.Id, userID); appErr == nil {
+			return true, nil
+		} else if appErr.StatusCode != http.StatusNotFound {
+			return false, errors.Wrap(appErr, "failed to get channel member")
+		}
+	}
 	return false, nil
 }
 
+// isExcluded returns true if a given user isn't allowed to vote in a given poll,
+// because guests or bots are excluded by the plugin configuration or the poll settings.
+func (p *MatterpollPlugin) isExcluded(poll *poll.Poll, user *model.User) bool {
+	configuration := p.getConfiguration()
+	if user.IsGuest() && (configuration.ExcludeGuests || poll.Settings.ExcludeGuests) {
+		return true
+	}
+	if user.IsBot && (configuration.ExcludeBots || poll.Settings.ExcludeBots) {
+		return true
+	}
+	return false
+}
+
+// excludesAccounts returns true if guests or bots are excluded from voting in a given poll.
+func (p *MatterpollPlugin) excludesAccounts(poll *poll.Poll) bool {
+	configuration := p.getConfiguration()
+	return configuration.ExcludeGuests || configuration.ExcludeBots || poll.Settings.ExcludeGuests || poll.Settings.ExcludeBots
+}
+
+// getExclusionMessage returns a message for a given user, if they aren't allowed to take part in a given poll
+// because guests or bots are excluded. It returns nil if they are allowed.
+func (p *MatterpollPlugin) getExclusionMessage(poll *poll.Poll, userID string) (*i18n.Message, error) {
+	if !p.excludesAccounts(poll) {
+		return nil, nil
+	}
+
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get user")
+	}
+	if !p.isExcluded(poll, user) {
+		return nil, nil
+	}
+	if user.IsBot {
+		return responseExcludedBot, nil
+	}
+	return responseExcludedGuest, nil
+}
+
+// ignoreExcludedVoters leaves the votes of excluded guests and bots out of the participation counts of a poll post.
+// Such votes only exist if they were cast before the accounts got excluded.
+func (p *MatterpollPlugin) ignoreExcludedVoters(poll *poll.Poll) {
+	if !p.excludesAccounts(poll) || poll.IsSecretBallot() {
+		return
+	}
+	voters := poll.GetVoters()
+	if len(voters) == 0 {
+		return
+	}
+
+	users, appErr := p.API.GetUsersByIds(voters)
+	if appErr != nil {
+		p.API.LogWarn("Failed to get voters to ignore excluded accounts", "pollID", poll.ID, "error", appErr.Error())
+		return
+	}
+	ignored := []string{}
+	for _, user := range users {
+		if p.isExcluded(poll, user) {
+			ignored = append(ignored, user.Id)
+		}
+	}
+	poll.IgnoreVoters(ignored)
+}
+
 // SendEphemeralPost sends an ephemeral post to a user as the bot account
 func (p *MatterpollPlugin) SendEphemeralPost(channelID, userID, rootID, message string) {
 	ephemeralPost := &model.Post{
@@ -1,13 +1,19 @@
 package plugin
 
 import (
+	"errors"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 
+	"github.com/matterpoll/matterpoll/server/poll"
 	"github.com/matterpoll/matterpoll/server/store/mockstore"
 	"github.com/matterpoll/matterpoll/server/utils"
 	"github.com/matterpoll/matterpoll/server/utils/testutils"
@@ -29,6 +35,7 @@ func setupTestPlugin(_ *testing.T, api *plugintest.API, store *mockstore.Store)
 	api.On("GetBundlePath").Return(".", nil)
 	p.bundle, _ = utils.InitBundle(api, ".")
 	p.Store = store
+	p.scheduler = &testScheduler{jobs: map[string]time.Time{}}
 	p.router = p.InitAPI()
 	p.setActivated(true)
 
@@ -39,6 +46,23 @@ func getIconDataMock() (string, error) {
 	return "someIconData", nil
 }
 
+// testScheduler is a jobScheduler that only records scheduled jobs instead of running them.
+type testScheduler struct {
+	jobs map[string]time.Time
+}
+
+func (s *testScheduler) ScheduleOnce(key string, runAt time.Time, _ any) (*cluster.JobOnce, error) {
+	if _, ok := s.jobs[key]; ok {
+		return nil, errors.New("job already scheduled")
+	}
+	s.jobs[key] = runAt
+	return nil, nil
+}
+
+func (s *testScheduler) Cancel(key string) {
+	delete(s.jobs, key)
+}
+
 func TestPluginOnActivate(t *testing.T) {
 	t.Run("SiteURL not set", func(t *testing.T) {
 		api := &plugintest.API{}
@@ -68,6 +92,76 @@ func TestPluginOnDeactivate(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestPluginMessageHasBeenDeleted(t *testing.T) {
+	pollPost := &model.Post{Id: "postID1", UserId: testutils.GetBotUserID()}
+	pollPost.AddProp("poll_id", testutils.GetPollID())
+	userPost := pollPost.Clone()
+	userPost.UserId = "userID1"
+
+	for name, test := range map[string]struct {
+		Post       *model.Post
+		SetupAPI   func(*plugintest.API) *plugintest.API
+		SetupStore func(*mockstore.Store) *mockstore.Store
+	}{
+		"Active poll": {
+			Post:     pollPost,
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("Delete", testutils.GetPollWithVotes()).Return(nil)
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.VoteHistoryStore.On("Delete", testutils.GetPollID()).Return(nil)
+				return store
+			},
+		},
+		"Ended poll": {
+			Post:     pollPost,
+			SetupAPI: func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("DeleteEnded", testutils.GetPollWithVotes()).Return(nil)
+				store.VoteHistoryStore.On("Delete", testutils.GetPollID()).Return(nil)
+				return store
+			},
+		},
+		"DeleteEnded fails": {
+			Post: pollPost,
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			SetupStore: func(store *mockstore.Store) *mockstore.Store {
+				store.PollStore.On("Get", testutils.GetPollID()).Return(nil, &model.AppError{})
+				store.PollStore.On("GetEnded", testutils.GetPollID()).Return(testutils.GetPollWithVotes(), nil)
+				store.PollStore.On("DeleteEnded", testutils.GetPollWithVotes()).Return(&model.AppError{})
+				store.VoteHistoryStore.On("Delete", testutils.GetPollID()).Return(nil)
+				return store
+			},
+		},
+		"Post not by the bot": {
+			Post:       userPost,
+			SetupAPI:   func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+		},
+		"Post without poll": {
+			Post:       &model.Post{Id: "postID1", UserId: testutils.GetBotUserID()},
+			SetupAPI:   func(api *plugintest.API) *plugintest.API { return api },
+			SetupStore: func(store *mockstore.Store) *mockstore.Store { return store },
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			store := test.SetupStore(&mockstore.Store{})
+			defer store.AssertExpectations(t)
+			p := setupTestPlugin(t, api, store)
+
+			p.MessageHasBeenDeleted(nil, test.Post)
+		})
+	}
+}
+
 func TestConvertCreatorIDToDisplayName(t *testing.T) {
 	user := &model.User{
 		Id:        "userID1",
@@ -131,3 +225,328 @@ func TestConvertCreatorIDToDisplayName(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertUserIDToNames(t *testing.T) {
+	user := &model.User{
+		Id:        "userID1",
+		Username:  "user1",
+		FirstName: "John",
+		LastName:  "Doe",
+	}
+	for name, test := range map[string]struct {
+		SettingShowFullName bool
+		SetupAPI            func(*plugintest.API) *plugintest.API
+		ShouldError         bool
+		ExpectedDisplayName string
+	}{
+		"all fine, ShowFullName is true": {
+			SettingShowFullName: true,
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", user.Id).Return(user, nil)
+				return api
+			},
+			ShouldError:         false,
+			ExpectedDisplayName: user.GetFullName(),
+		},
+		"all fine, ShowFullName is false": {
+			SettingShowFullName: false,
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", user.Id).Return(user, nil)
+				return api
+			},
+			ShouldError:         false,
+			ExpectedDisplayName: user.Username,
+		},
+		"GetUser fails": {
+			SettingShowFullName: true,
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", user.Id).Return(nil, &model.AppError{})
+				return api
+			},
+			ShouldError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+
+			p := setupTestPlugin(t, api, &mockstore.Store{})
+			fn := test.SettingShowFullName
+			p.ServerConfig.PrivacySettings.ShowFullName = &fn
+
+			username, displayName, err := p.ConvertUserIDToNames(user.Id)
+
+			if test.ShouldError {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, user.Username, username)
+				assert.Equal(t, test.ExpectedDisplayName, displayName)
+			}
+		})
+	}
+}
+
+func TestIsEligible(t *testing.T) {
+	for name, test := range map[string]struct {
+		Voters           *poll.Voters
+		SetupAPI         func(*plugintest.API) *plugintest.API
+		ShouldError      bool
+		ExpectedEligible bool
+	}{
+		"not restricted": {
+			Voters:           nil,
+			SetupAPI:         func(api *plugintest.API) *plugintest.API { return api },
+			ExpectedEligible: true,
+		},
+		"eligible by username": {
+			Voters: &poll.Voters{Names: []string{"user1"}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				return api
+			},
+			ExpectedEligible: true,
+		},
+		"eligible by group": {
+			Voters: &poll.Voters{Names: []string{"developers"}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("GetGroupsForUser", "userID1").Return([]*model.Group{{Name: nil}, {Name: model.NewPointer("developers")}}, nil)
+				return api
+			},
+			ExpectedEligible: true,
+		},
+		"eligible as channel admin": {
+			Voters: &poll.Voters{Roles: []string{model.ChannelAdminRoleId}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetChannelMember", "channelID1", "userID1").Return(&model.ChannelMember{SchemeAdmin: true}, nil)
+				return api
+			},
+			ExpectedEligible: true,
+		},
+		"eligible as team admin": {
+			Voters: &poll.Voters{Roles: []string{model.TeamAdminRoleId}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetChannel", "channelID1").Return(&model.Channel{Id: "channelID1", TeamId: "teamID1"}, nil)
+				api.On("GetTeamMember", "teamID1", "userID1").Return(&model.TeamMember{Roles: model.TeamUserRoleId + " " + model.TeamAdminRoleId}, nil)
+				return api
+			},
+			ExpectedEligible: true,
+		},
+		"eligible as member of channel": {
+			Voters: &poll.Voters{Channels: []string{"deleted", "developers"}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetChannel", "channelID1").Return(&model.Channel{Id: "channelID1", TeamId: "teamID1"}, nil)
+				api.On("GetChannelByName", "teamID1", "deleted", false).Return(nil, &model.AppError{StatusCode: http.StatusNotFound})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				api.On("GetChannelByName", "teamID1", "developers", false).Return(&model.Channel{Id: "channelID2"}, nil)
+				api.On("GetChannelMember", "channelID2", "userID1").Return(&model.ChannelMember{}, nil)
+				return api
+			},
+			ExpectedEligible: true,
+		},
+		"not eligible": {
+			Voters: &poll.Voters{
+				Names:    []string{"user2"},
+				Roles:    []string{model.ChannelAdminRoleId, model.TeamAdminRoleId},
+				Channels: []string{"developers"},
+			},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(&model.User{Username: "user1"}, nil)
+				api.On("GetGroupsForUser", "userID1").Return([]*model.Group{}, nil)
+				api.On("GetChannelMember", "channelID1", "userID1").Return(&model.ChannelMember{Roles: model.ChannelUserRoleId}, nil)
+				api.On("GetChannel", "channelID1").Return(&model.Channel{Id: "channelID1", TeamId: "teamID1"}, nil)
+				api.On("GetTeamMember", "teamID1", "userID1").Return(&model.TeamMember{Roles: model.TeamUserRoleId}, nil)
+				api.On("GetChannelByName", "teamID1", "developers", false).Return(&model.Channel{Id: "channelID2"}, nil)
+				api.On("GetChannelMember", "channelID2", "userID1").Return(nil, &model.AppError{StatusCode: http.StatusNotFound})
+				return api
+			},
+			ExpectedEligible: false,
+		},
+		"GetUser fails": {
+			Voters: &poll.Voters{Names: []string{"user1"}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(nil, &model.AppError{})
+				return api
+			},
+			ShouldError: true,
+		},
+		"GetChannelMember fails": {
+			Voters: &poll.Voters{Channels: []string{"developers"}},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetChannel", "channelID1").Return(&model.Channel{Id: "channelID1", TeamId: "teamID1"}, nil)
+				api.On("GetChannelByName", "teamID1", "developers", false).Return(&model.Channel{Id: "channelID2"}, nil)
+				api.On("GetChannelMember", "channelID2", "userID1").Return(nil, &model.AppError{StatusCode: http.StatusInternalServerError})
+				return api
+			},
+			ShouldError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			p := setupTestPlugin(t, api, &mockstore.Store{})
+
+			eligible, err := p.IsEligible(testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Voters: test.Voters}), "userID1", "channelID1")
+			if test.ShouldError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.ExpectedEligible, eligible)
+		})
+	}
+}
+
+func TestGetExclusionMessage(t *testing.T) {
+	guest := &model.User{Id: "userID2", Roles: model.SystemGuestRoleId}
+	bot := &model.User{Id: "userID2", IsBot: true, Roles: model.SystemUserRoleId}
+	user := &model.User{Id: "userID2", Roles: model.SystemUserRoleId}
+
+	for name, test := range map[string]struct {
+		Settings      poll.Settings
+		ExcludeGuests bool
+		ExcludeBots   bool
+		UserID        string
+		SetupAPI      func(*plugintest.API) *plugintest.API
+		ShouldError   bool
+		ExpectedMsg   *i18n.Message
+	}{
+		"nothing excluded": {
+			Settings:    poll.Settings{MaxVotes: 1},
+			UserID:      "userID2",
+			SetupAPI:    func(api *plugintest.API) *plugintest.API { return api },
+			ExpectedMsg: nil,
+		},
+		"guest excluded by poll settings": {
+			Settings: poll.Settings{MaxVotes: 1, ExcludeGuests: true},
+			UserID:   "userID2",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(guest, nil)
+				return api
+			},
+			ExpectedMsg: responseExcludedGuest,
+		},
+		"guest excluded by configuration": {
+			Settings:      poll.Settings{MaxVotes: 1},
+			ExcludeGuests: true,
+			UserID:        "userID2",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(guest, nil)
+				return api
+			},
+			ExpectedMsg: responseExcludedGuest,
+		},
+		"bot excluded by configuration": {
+			Settings:    poll.Settings{MaxVotes: 1},
+			ExcludeBots: true,
+			UserID:      "userID2",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(bot, nil)
+				return api
+			},
+			ExpectedMsg: responseExcludedBot,
+		},
+		"bot allowed, if only guests are excluded": {
+			Settings: poll.Settings{MaxVotes: 1, ExcludeGuests: true},
+			UserID:   "userID2",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(bot, nil)
+				return api
+			},
+			ExpectedMsg: nil,
+		},
+		"regular user": {
+			Settings:      poll.Settings{MaxVotes: 1, ExcludeBots: true},
+			ExcludeGuests: true,
+			UserID:        "userID2",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(user, nil)
+				return api
+			},
+			ExpectedMsg: nil,
+		},
+		"creator is excluded like everyone else": {
+			Settings: poll.Settings{MaxVotes: 1, ExcludeGuests: true},
+			UserID:   "userID1",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID1").Return(guest, nil)
+				return api
+			},
+			ExpectedMsg: responseExcludedGuest,
+		},
+		"GetUser fails": {
+			Settings: poll.Settings{MaxVotes: 1, ExcludeGuests: true},
+			UserID:   "userID2",
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUser", "userID2").Return(nil, &model.AppError{})
+				return api
+			},
+			ShouldError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			p := setupTestPlugin(t, api, &mockstore.Store{})
+			p.configuration.ExcludeGuests = test.ExcludeGuests
+			p.configuration.ExcludeBots = test.ExcludeBots
+
+			msg, err := p.getExclusionMessage(testutils.GetPollWithSettings(test.Settings), test.UserID)
+			if test.ShouldError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.ExpectedMsg, msg)
+		})
+	}
+}
+
+func TestIgnoreExcludedVoters(t *testing.T) {
+	for name, test := range map[string]struct {
+		Settings     poll.Settings
+		SetupAPI     func(*plugintest.API) *plugintest.API
+		ExpectedText string
+	}{
+		"nothing excluded": {
+			Settings:     poll.Settings{MaxVotes: 0},
+			SetupAPI:     func(api *plugintest.API) *plugintest.API { return api },
+			ExpectedText: "---\n**Poll Settings**: votes=unlimited\n**Total votes**: 4 (4 voters)",
+		},
+		"guests excluded": {
+			Settings: poll.Settings{MaxVotes: 0, ExcludeGuests: true},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUsersByIds", []string{"userID1", "userID2", "userID3", "userID4"}).Return([]*model.User{
+					{Id: "userID1", Roles: model.SystemUserRoleId},
+					{Id: "userID2", Roles: model.SystemGuestRoleId},
+					{Id: "userID3", Roles: model.SystemUserRoleId},
+					{Id: "userID4", Roles: model.SystemGuestRoleId},
+				}, nil)
+				return api
+			},
+			ExpectedText: "---\n**Poll Settings**: exclude-guests, votes=unlimited\n**Total votes**: 2 (2 voters)",
+		},
+		"GetUsersByIds fails": {
+			Settings: poll.Settings{MaxVotes: 0, ExcludeGuests: true},
+			SetupAPI: func(api *plugintest.API) *plugintest.API {
+				api.On("GetUsersByIds", []string{"userID1", "userID2", "userID3", "userID4"}).Return(nil, &model.AppError{})
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				return api
+			},
+			ExpectedText: "---\n**Poll Settings**: exclude-guests, votes=unlimited\n**Total votes**: 4 (4 voters)",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := test.SetupAPI(&plugintest.API{})
+			defer api.AssertExpectations(t)
+			p := setupTestPlugin(t, api, &mockstore.Store{})
+
+			poll := testutils.GetPollWithVotesAndSettings(test.Settings)
+			p.ignoreExcludedVoters(poll)
+
+			attachments := poll.ToPostActions(p.bundle, "com.github.matterpoll.matterpoll", "John Doe")
+			assert.Equal(t, test.ExpectedText, attachments[0].Text)
+		})
+	}
+}
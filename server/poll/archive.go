@@ -0,0 +1,462 @@
+package poll
+
+import (
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// UserConverter converts a reference to a user into another one, e.g. the user id into the username.
+type UserConverter func(user string) (string, *model.AppError)
+
+// ArchiveVersion is the version of the archive format, in which polls are exported and imported.
+// The format is independent of the schema polls are stored with in the KV Store.
+// It must only be increased for changes, that older versions of Matterpoll can't import.
+const ArchiveVersion = 1
+
+// Archive is a set of polls exported from a Mattermost instance, e.g. to import them into another one.
+// See the README for a description of the format.
+// SecretBallotKeyID identifies the key, from which the voter tokens in the archive are derived, see SecretBallotKeyID.
+type Archive struct {
+	Version           int             `json:"version"`
+	SecretBallotKeyID string          `json:"secret_ballot_key_id,omitempty"`
+	Polls             []*ArchivedPoll `json:"polls"`
+}
+
+// ArchivedPoll is a poll in an archive. Ended is true if the poll has already ended.
+// ChannelID is the channel the poll was posted in. It's empty if the post of the poll doesn't exist anymore.
+// Users are referred to by their username, see ReplaceUsers.
+// Pseudonymized is true if the voters were replaced by voter tokens, because the poll is anonymous.
+type ArchivedPoll struct {
+	ID             string                  `json:"id"`
+	PostID         string                  `json:"post_id,omitempty"`
+	ChannelID      string                  `json:"channel_id,omitempty"`
+	Ended          bool                    `json:"ended,omitempty"`
+	CreatedAt      int64                   `json:"created_at"`
+	Creator        string                  `json:"creator"`
+	Question       string                  `json:"question"`
+	AnswerOptions  []*ArchivedAnswerOption `json:"answer_options,omitempty"`
+	Responses      []*ArchivedResponse     `json:"responses,omitempty"`
+	Questions      []*ArchivedQuestion     `json:"questions,omitempty"`
+	Settings       ArchivedSettings        `json:"settings"`
+	SecretBallot   bool                    `json:"secret_ballot,omitempty"`
+	Ballots        map[string][]int        `json:"ballots,omitempty"`
+	EditedAt       int64                   `json:"edited_at,omitempty"`
+	EndedAt        int64                   `json:"ended_at,omitempty"`
+	TieBreakWinner string                  `json:"tie_break_winner,omitempty"`
+	Delegates      map[string]string       `json:"delegates,omitempty"`
+	Pseudonymized  bool                    `json:"pseudonymized,omitempty"`
+}
+
+// ArchivedAnswerOption is an answer option of an archived poll. The maps are keyed by the voters.
+type ArchivedAnswerOption struct {
+	Answer       string                  `json:"answer"`
+	Voters       []string                `json:"voters,omitempty"`
+	Ranks        map[string]int          `json:"ranks,omitempty"`
+	Points       map[string]int          `json:"points,omitempty"`
+	Availability map[string]Availability `json:"availability,omitempty"`
+	Correct      bool                    `json:"correct,omitempty"`
+	Votes        int                     `json:"votes,omitempty"`
+	VotedAt      map[string]int64        `json:"voted_at,omitempty"`
+	Capacity     int                     `json:"capacity,omitempty"`
+	Waitlist     []string                `json:"waitlist,omitempty"`
+	Proxies      map[string]string       `json:"proxies,omitempty"`
+}
+
+// ArchivedResponse is the answer of a user to an archived free-text poll.
+type ArchivedResponse struct {
+	UserID string `json:"user_id"`
+	Text   string `json:"text"`
+}
+
+// ArchivedQuestion is a question of an archived survey.
+type ArchivedQuestion struct {
+	Question      string                  `json:"question"`
+	AnswerOptions []*ArchivedAnswerOption `json:"answer_options"`
+	Settings      ArchivedSettings        `json:"settings"`
+}
+
+// ArchivedSettings are the settings of an archived poll.
+type ArchivedSettings struct {
+	Anonymous         bool     `json:"anonymous,omitempty"`
+	AnonymousCreator  bool     `json:"anonymous_creator,omitempty"`
+	Progress          bool     `json:"progress,omitempty"`
+	PublicAddOption   bool     `json:"public_add_option,omitempty"`
+	MaxVotes          int      `json:"max_votes"`
+	EndAt             int64    `json:"end_at,omitempty"`
+	Ranked            bool     `json:"ranked,omitempty"`
+	Points            int      `json:"points,omitempty"`
+	Scheduling        bool     `json:"scheduling,omitempty"`
+	Quiz              bool     `json:"quiz,omitempty"`
+	FreeText          bool     `json:"free_text,omitempty"`
+	CloseAt           int      `json:"close_at,omitempty"`
+	CloseWhenAllVoted bool     `json:"close_when_all_voted,omitempty"`
+	ResultsAfterVote  bool     `json:"results_after_vote,omitempty"`
+	Final             bool     `json:"final,omitempty"`
+	TieBreak          TieBreak `json:"tie_break,omitempty"`
+	Voters            *Voters  `json:"voters,omitempty"`
+	ExcludeGuests     bool     `json:"exclude_guests,omitempty"`
+	ExcludeBots       bool     `json:"exclude_bots,omitempty"`
+	Capacity          int      `json:"capacity,omitempty"`
+	Waitlist          bool     `json:"waitlist,omitempty"`
+	Proxy             bool     `json:"proxy,omitempty"`
+}
+
+// ToArchive converts the poll into its archive format.
+// The archive doesn't reveal who voted for what in anonymous polls: like for secret ballots, the ids of the voters are replaced
+// by voter tokens, which are derived with the given secret ballot key.
+func (p *Poll) ToArchive(channelID string, ended bool, key []byte) *ArchivedPoll {
+	a := &ArchivedPoll{
+		ID:             p.ID,
+		PostID:         p.PostID,
+		ChannelID:      channelID,
+		Ended:          ended,
+		CreatedAt:      p.CreatedAt,
+		Creator:        p.Creator,
+		Question:       p.Question,
+		AnswerOptions:  archiveAnswerOptions(p.AnswerOptions),
+		Settings:       archiveSettings(p.Settings),
+		SecretBallot:   p.SecretBallot,
+		Ballots:        p.Copy().Ballots,
+		EditedAt:       p.EditedAt,
+		EndedAt:        p.EndedAt,
+		TieBreakWinner: p.TieBreakWinner,
+		Delegates:      p.Copy().Delegates,
+	}
+	for _, r := range p.Responses {
+		a.Responses = append(a.Responses, &ArchivedResponse{UserID: r.UserID, Text: r.Text})
+	}
+	for _, q := range p.Questions {
+		archived := &ArchivedQuestion{
+			Question:      q.Question,
+			AnswerOptions: archiveAnswerOptions(q.AnswerOptions),
+			Settings:      archiveSettings(q.Settings),
+		}
+		if q.Settings.Anonymous {
+			replaceAnswerOptionUsers(archived.AnswerOptions, p.tokenizer(key))
+			a.Pseudonymized = true
+		}
+		a.Questions = append(a.Questions, archived)
+	}
+
+	if a.isPseudonymized() {
+		token := p.tokenizer(key)
+		replaceAnswerOptionUsers(a.AnswerOptions, token)
+		for _, r := range a.Responses {
+			r.UserID = token(r.UserID)
+		}
+		a.Delegates = replaceDelegates(a.Delegates, token)
+		a.Pseudonymized = true
+	}
+	return a
+}
+
+// isPseudonymized returns true if the voters of the answer options, the responses and the delegates of the archived poll
+// are voter tokens. This is the case for anonymous polls, that aren't secret ballots.
+func (a *ArchivedPoll) isPseudonymized() bool {
+	return a.Settings.Anonymous && !a.SecretBallot
+}
+
+// ReplaceUsers replaces every user the archived poll refers to with the result of convert, e.g. the user ids with the usernames on export,
+// so that the poll can be imported into another server, and the usernames with the user ids of that server on import.
+// Voter tokens aren't replaced. An error message is returned for the first user, that can't be converted.
+func (a *ArchivedPoll) ReplaceUsers(convert UserConverter) *utils.ErrorMessage {
+	converted := map[string]string{}
+	unknownUser := ""
+	replace := func(user string) string {
+		if user == "" {
+			return user
+		}
+		if c, ok := converted[user]; ok {
+			return c
+		}
+		c, appErr := convert(user)
+		if appErr != nil {
+			if unknownUser == "" {
+				unknownUser = user
+			}
+			return user
+		}
+		converted[user] = c
+		return c
+	}
+
+	a.Creator = replace(a.Creator)
+	if !a.isPseudonymized() {
+		replaceAnswerOptionUsers(a.AnswerOptions, replace)
+		for _, r := range a.Responses {
+			r.UserID = replace(r.UserID)
+		}
+		a.Delegates = replaceDelegates(a.Delegates, replace)
+	}
+	for _, q := range a.Questions {
+		if !q.Settings.Anonymous {
+			replaceAnswerOptionUsers(q.AnswerOptions, replace)
+		}
+	}
+
+	if unknownUser != "" {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.archive.unknownUser",
+				Other: "The user {{.User}} of the poll doesn't exist.",
+			},
+			Data: map[string]interface{}{
+				"User": unknownUser,
+			},
+		}
+	}
+	return nil
+}
+
+// tokenizer returns a function, which converts the ids of the voters of the poll into voter tokens.
+func (p *Poll) tokenizer(key []byte) func(userID string) string {
+	return func(userID string) string {
+		return voterToken(key, p.ID, userID)
+	}
+}
+
+// replaceAnswerOptionUsers replaces the voters of archived answer options with the result of replace, e.g. with voter tokens.
+func replaceAnswerOptionUsers(options []*ArchivedAnswerOption, replace func(string) string) {
+	for _, o := range options {
+		for i, user := range o.Voters {
+			o.Voters[i] = replace(user)
+		}
+		for i, user := range o.Waitlist {
+			o.Waitlist[i] = replace(user)
+		}
+		o.Ranks = replaceUserKeys(o.Ranks, replace)
+		o.Points = replaceUserKeys(o.Points, replace)
+		o.Availability = replaceUserKeys(o.Availability, replace)
+		o.VotedAt = replaceUserKeys(o.VotedAt, replace)
+		o.Proxies = replaceDelegates(o.Proxies, replace)
+	}
+}
+
+// replaceDelegates returns a copy of a map from users to users, e.g. from voters to their proxies, in which both are replaced with the result of replace.
+func replaceDelegates(m map[string]string, replace func(string) string) map[string]string {
+	m = replaceUserKeys(m, replace)
+	for user, delegate := range m {
+		m[user] = replace(delegate)
+	}
+	return m
+}
+
+// replaceUserKeys returns a copy of a map keyed by users, which is keyed by the result of replace instead.
+func replaceUserKeys[V any](m map[string]V, replace func(string) string) map[string]V {
+	if m == nil {
+		return nil
+	}
+	replaced := make(map[string]V, len(m))
+	for user, v := range m {
+		replaced[replace(user)] = v
+	}
+	return replaced
+}
+
+// ToPoll converts an archived poll back into a poll. An error message is returned if the archived poll isn't valid.
+// sameKey must be true if the voter tokens in the archive were derived with the secret ballot key of this server.
+// Otherwise running secret ballots can't be imported, because their voters wouldn't be recognized anymore.
+// Running anonymous polls, that aren't secret ballots, can't be imported at all, because the archive doesn't contain their voters.
+func (a *ArchivedPoll) ToPoll(sameKey bool) (*Poll, *utils.ErrorMessage) {
+	if a.ID == "" || a.Creator == "" {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.archive.missingField",
+				Other: "The poll has no id or creator.",
+			},
+		}
+	}
+	if !a.Ended && a.SecretBallot && !sameKey {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.archive.secretBallotKey",
+				Other: "The poll is a running secret ballot from a server with another secret ballot key. It can only be imported once it has ended.",
+			},
+		}
+	}
+	if !a.Ended && a.Pseudonymized {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.archive.pseudonymized",
+				Other: "The poll is a running anonymous poll. It can only be imported once it has ended, because the archive doesn't contain who voted.",
+			},
+		}
+	}
+
+	p := &Poll{
+		ID:             a.ID,
+		PostID:         a.PostID,
+		CreatedAt:      a.CreatedAt,
+		Creator:        a.Creator,
+		Question:       a.Question,
+		Settings:       a.Settings.toSettings(),
+		SecretBallot:   a.SecretBallot,
+		Ballots:        a.Ballots,
+		EditedAt:       a.EditedAt,
+		EndedAt:        a.EndedAt,
+		TieBreakWinner: a.TieBreakWinner,
+		Delegates:      a.Delegates,
+	}
+	var errMsg *utils.ErrorMessage
+	if p.AnswerOptions, errMsg = unarchiveAnswerOptions(a.AnswerOptions); errMsg != nil {
+		return nil, errMsg
+	}
+	for _, r := range a.Responses {
+		p.Responses = append(p.Responses, &Response{UserID: r.UserID, Text: r.Text})
+	}
+	for _, q := range a.Questions {
+		options, errMsg := unarchiveAnswerOptions(q.AnswerOptions)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+		p.Questions = append(p.Questions, &SurveyQuestion{
+			Question:      q.Question,
+			AnswerOptions: options,
+			Settings:      q.Settings.toSettings(),
+		})
+	}
+
+	for _, indexes := range p.Ballots {
+		for _, i := range indexes {
+			if i < 0 || i >= len(p.AnswerOptions) {
+				return nil, &utils.ErrorMessage{
+					Message: &i18n.Message{
+						ID:    "poll.archive.invalidBallot",
+						Other: "A ballot of the poll contains an invalid answer option.",
+					},
+				}
+			}
+		}
+	}
+
+	if p.IsSurvey() {
+		if errMsg := p.validateSurvey(); errMsg != nil {
+			return nil, errMsg
+		}
+		return p, nil
+	}
+	if p.IsFreeText() != (len(p.AnswerOptions) == 0) {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.archive.answerOptions",
+				Other: "Only free-text polls and surveys can have no answer options.",
+			},
+		}
+	}
+	if errMsg := p.validate(); errMsg != nil {
+		return nil, errMsg
+	}
+	return p, nil
+}
+
+func archiveAnswerOptions(options []*AnswerOption) []*ArchivedAnswerOption {
+	if options == nil {
+		return nil
+	}
+	archived := make([]*ArchivedAnswerOption, len(options))
+	for i, o := range copyAnswerOptions(options) {
+		archived[i] = &ArchivedAnswerOption{
+			Answer:       o.Answer,
+			Voters:       o.Voter,
+			Ranks:        o.Ranks,
+			Points:       o.Points,
+			Availability: o.Availability,
+			Correct:      o.Correct,
+			Votes:        o.Votes,
+			VotedAt:      o.VotedAt,
+			Capacity:     o.Capacity,
+			Waitlist:     o.Waitlist,
+			Proxies:      o.Proxies,
+		}
+	}
+	return archived
+}
+
+// unarchiveAnswerOptions converts archived answer options back. Like for new polls, the answers must not be empty or duplicated.
+func unarchiveAnswerOptions(archived []*ArchivedAnswerOption) ([]*AnswerOption, *utils.ErrorMessage) {
+	if archived == nil {
+		return nil, nil
+	}
+	scratch := &Poll{}
+	options := make([]*AnswerOption, len(archived))
+	for i, o := range archived {
+		if errMsg := scratch.AddAnswerOption(o.Answer); errMsg != nil {
+			return nil, errMsg
+		}
+		voters := o.Voters
+		if voters == nil {
+			voters = []string{}
+		}
+		options[i] = &AnswerOption{
+			Answer:       o.Answer,
+			Voter:        voters,
+			Ranks:        o.Ranks,
+			Points:       o.Points,
+			Availability: o.Availability,
+			Correct:      o.Correct,
+			Votes:        o.Votes,
+			VotedAt:      o.VotedAt,
+			Capacity:     o.Capacity,
+			Waitlist:     o.Waitlist,
+			Proxies:      o.Proxies,
+		}
+	}
+	return options, nil
+}
+
+func archiveSettings(s Settings) ArchivedSettings {
+	return ArchivedSettings{
+		Anonymous:         s.Anonymous,
+		AnonymousCreator:  s.AnonymousCreator,
+		Progress:          s.Progress,
+		PublicAddOption:   s.PublicAddOption,
+		MaxVotes:          s.MaxVotes,
+		EndAt:             s.EndAt,
+		Ranked:            s.Ranked,
+		Points:            s.Points,
+		Scheduling:        s.Scheduling,
+		Quiz:              s.Quiz,
+		FreeText:          s.FreeText,
+		CloseAt:           s.CloseAt,
+		CloseWhenAllVoted: s.CloseWhenAllVoted,
+		ResultsAfterVote:  s.ResultsAfterVote,
+		Final:             s.Final,
+		TieBreak:          s.TieBreak,
+		Voters:            s.Voters,
+		ExcludeGuests:     s.ExcludeGuests,
+		ExcludeBots:       s.ExcludeBots,
+		Capacity:          s.Capacity,
+		Waitlist:          s.Waitlist,
+		Proxy:             s.Proxy,
+	}
+}
+
+func (s ArchivedSettings) toSettings() Settings {
+	return Settings{
+		Anonymous:         s.Anonymous,
+		AnonymousCreator:  s.AnonymousCreator,
+		Progress:          s.Progress,
+		PublicAddOption:   s.PublicAddOption,
+		MaxVotes:          s.MaxVotes,
+		EndAt:             s.EndAt,
+		Ranked:            s.Ranked,
+		Points:            s.Points,
+		Scheduling:        s.Scheduling,
+		Quiz:              s.Quiz,
+		FreeText:          s.FreeText,
+		CloseAt:           s.CloseAt,
+		CloseWhenAllVoted: s.CloseWhenAllVoted,
+		ResultsAfterVote:  s.ResultsAfterVote,
+		Final:             s.Final,
+		TieBreak:          s.TieBreak,
+		Voters:            s.Voters,
+		ExcludeGuests:     s.ExcludeGuests,
+		ExcludeBots:       s.ExcludeBots,
+		Capacity:          s.Capacity,
+		Waitlist:          s.Waitlist,
+		Proxy:             s.Proxy,
+	}
+}
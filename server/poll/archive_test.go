@@ -0,0 +1,339 @@
+package poll_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestPollArchiveRoundTrip(t *testing.T) {
+	for name, p := range map[string]*poll.Poll{
+		"Normal poll": func() *poll.Poll {
+			p := testutils.GetPollWithVotesAndSettings(poll.Settings{
+				Progress: true, MaxVotes: 2, EndAt: 1234567890000, TieBreak: poll.TieBreakRunoff,
+				Voters:        &poll.Voters{Names: []string{"user1"}, Roles: []string{"channel_admin"}, Channels: []string{"town-square"}},
+				ExcludeGuests: true,
+			})
+			p.AnswerOptions[0].VotedAt = map[string]int64{"userID1": 0, "userID2": 1234567890000, "userID3": 1234567890000}
+			p.EditedAt = testutils.GetMillis()
+			p.EndedAt = testutils.GetMillis()
+			return p
+		}(),
+		"Poll without votes": testutils.GetPoll(),
+		"Sign-up sheet": func() *poll.Poll {
+			p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Capacity: 3, Waitlist: true})
+			p.AnswerOptions[0].Capacity = 3
+			p.AnswerOptions[0].Waitlist = []string{"userID5"}
+			return p
+		}(),
+		"Poll with proxy votes": func() *poll.Poll {
+			p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+			p.AnswerOptions[0].Proxies = map[string]string{"userID2": "userID1"}
+			p.Delegates = map[string]string{"userID3": "userID1"}
+			return p
+		}(),
+		"Secret ballot":   getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1}),
+		"Ranked poll":     testutils.GetRankedPollWithBallots(),
+		"Points poll":     testutils.GetPointsPollWithVotes(),
+		"Scheduling poll": testutils.GetSchedulingPollWithVotes(),
+		"Quiz":            testutils.GetQuizPollWithVotes(),
+		"Free-text poll":  testutils.GetFreeTextPollWithResponses(),
+		"Survey": func() *poll.Poll {
+			p := testutils.GetSurveyWithAnswers()
+			p.Questions[1].Settings.Anonymous = false
+			return p
+		}(),
+		"Tie broken by creator": func() *poll.Poll {
+			p := getTiedPoll(poll.TieBreakCreator)
+			p.TieBreakWinner = "Answer 2"
+			return p
+		}(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			archived := p.ToArchive("channelID1", true, secretBallotKey)
+			assert.Equal(t, p.ID, archived.ID)
+			assert.Equal(t, "channelID1", archived.ChannelID)
+			assert.True(t, archived.Ended)
+
+			b, err := json.Marshal(&poll.Archive{Version: poll.ArchiveVersion, Polls: []*poll.ArchivedPoll{archived}})
+			require.NoError(t, err)
+			var archive poll.Archive
+			require.NoError(t, json.Unmarshal(b, &archive))
+			require.Len(t, archive.Polls, 1)
+
+			p2, errMsg := archive.Polls[0].ToPoll(true)
+			require.Nil(t, errMsg)
+			assert.Equal(t, p, p2)
+		})
+	}
+}
+
+func TestPollArchiveFormat(t *testing.T) {
+	p := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 1})
+	p.AnswerOptions[1].VotedAt = map[string]int64{"userID4": 1234567890000}
+
+	b, err := json.Marshal(p.ToArchive("channelID1", false, secretBallotKey))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "1234567890abcdefghij",
+		"post_id": "postID1",
+		"channel_id": "channelID1",
+		"created_at": 1234567890,
+		"creator": "userID1",
+		"question": "Question",
+		"answer_options": [
+			{"answer": "Answer 1", "voters": ["userID1", "userID2", "userID3"]},
+			{"answer": "Answer 2", "voters": ["userID4"], "voted_at": {"userID4": 1234567890000}},
+			{"answer": "Answer 3"}
+		],
+		"settings": {"progress": true, "max_votes": 1}
+	}`, string(b))
+}
+
+func TestPollArchivePseudonymized(t *testing.T) {
+	t.Run("anonymous poll", func(t *testing.T) {
+		p := testutils.GetRankedPollWithBallots()
+		p.Settings.Anonymous = true
+		p.AnswerOptions[0].VotedAt = map[string]int64{"userID1": 1234567890000}
+
+		archived := p.ToArchive("channelID1", true, secretBallotKey)
+		assert.True(t, archived.Pseudonymized)
+		b, err := json.Marshal(archived.AnswerOptions)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "userID")
+
+		// The ballots of every voter stay intact
+		token := archived.AnswerOptions[0].Voters[0]
+		assert.Equal(t, 1, archived.AnswerOptions[0].Ranks[token])
+		assert.Equal(t, 2, archived.AnswerOptions[1].Ranks[token])
+		assert.Equal(t, int64(1234567890000), archived.AnswerOptions[0].VotedAt[token])
+
+		p2, errMsg := archived.ToPoll(true)
+		require.Nil(t, errMsg)
+		assert.Equal(t, p.GetWinners(), p2.GetWinners())
+	})
+	t.Run("anonymous free-text poll", func(t *testing.T) {
+		p := testutils.GetFreeTextPollWithResponses()
+		p.Settings.Anonymous = true
+
+		archived := p.ToArchive("channelID1", true, secretBallotKey)
+		assert.True(t, archived.Pseudonymized)
+		for _, r := range archived.Responses {
+			assert.NotContains(t, r.UserID, "userID")
+		}
+	})
+	t.Run("anonymous survey question", func(t *testing.T) {
+		p := testutils.GetSurveyWithAnswers()
+
+		archived := p.ToArchive("channelID1", true, secretBallotKey)
+		assert.True(t, archived.Pseudonymized)
+		assert.Equal(t, p.Questions[0].AnswerOptions[0].Voter, archived.Questions[0].AnswerOptions[0].Voters)
+		b, err := json.Marshal(archived.Questions[1])
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "userID")
+	})
+	t.Run("secret ballot", func(t *testing.T) {
+		p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1})
+
+		archived := p.ToArchive("channelID1", false, secretBallotKey)
+		assert.False(t, archived.Pseudonymized)
+		assert.Equal(t, p.Ballots, archived.Ballots)
+	})
+	t.Run("poll that isn't anonymous", func(t *testing.T) {
+		archived := testutils.GetPollWithVotes().ToArchive("channelID1", false, secretBallotKey)
+		assert.False(t, archived.Pseudonymized)
+		assert.Equal(t, []string{"userID4"}, archived.AnswerOptions[1].Voters)
+	})
+}
+
+func TestArchivedPollToPoll(t *testing.T) {
+	for name, test := range map[string]struct {
+		Archived        func() *poll.ArchivedPoll
+		ExpectedMessage string
+	}{
+		"missing id": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.ID = ""
+				return a
+			},
+			ExpectedMessage: "poll.archive.missingField",
+		},
+		"missing creator": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.Creator = ""
+				return a
+			},
+			ExpectedMessage: "poll.archive.missingField",
+		},
+		"empty answer option": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.AnswerOptions[1].Answer = " "
+				return a
+			},
+			ExpectedMessage: "poll.addAnswerOption.empty",
+		},
+		"duplicate answer option": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.AnswerOptions[1].Answer = "Answer 1"
+				return a
+			},
+			ExpectedMessage: "poll.addAnswerOption.duplicate",
+		},
+		"duplicate answer option in survey question": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetSurveyWithAnswers().ToArchive("", true, secretBallotKey)
+				a.Questions[0].AnswerOptions[1].Answer = "Answer 1"
+				return a
+			},
+			ExpectedMessage: "poll.addAnswerOption.duplicate",
+		},
+		"no answer options": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.AnswerOptions = nil
+				return a
+			},
+			ExpectedMessage: "poll.archive.answerOptions",
+		},
+		"free-text poll with answer options": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.Settings.FreeText = true
+				return a
+			},
+			ExpectedMessage: "poll.archive.answerOptions",
+		},
+		"invalid ballot": {
+			Archived: func() *poll.ArchivedPoll {
+				a := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1}).ToArchive("", false, secretBallotKey)
+				for token := range a.Ballots {
+					a.Ballots[token] = []int{3}
+				}
+				return a
+			},
+			ExpectedMessage: "poll.archive.invalidBallot",
+		},
+		"invalid settings": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetPollWithVotes().ToArchive("", false, secretBallotKey)
+				a.Settings.Progress = true
+				a.Settings.ResultsAfterVote = true
+				return a
+			},
+			ExpectedMessage: "poll.newPoll.resultsAfterVoteSetting.combined",
+		},
+		"invalid survey settings": {
+			Archived: func() *poll.ArchivedPoll {
+				a := testutils.GetSurveyWithAnswers().ToArchive("", true, secretBallotKey)
+				a.Settings.Anonymous = true
+				return a
+			},
+			ExpectedMessage: "poll.newSurvey.unsupportedSetting",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, errMsg := test.Archived().ToPoll(true)
+			assert.Nil(t, p)
+			require.NotNil(t, errMsg)
+			assert.Equal(t, test.ExpectedMessage, errMsg.Message.ID)
+		})
+	}
+
+	t.Run("running secret ballot from a server with another key", func(t *testing.T) {
+		a := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1}).ToArchive("", false, secretBallotKey)
+		p, errMsg := a.ToPoll(false)
+		assert.Nil(t, p)
+		require.NotNil(t, errMsg)
+		assert.Equal(t, "poll.archive.secretBallotKey", errMsg.Message.ID)
+
+		a.Ended = true
+		p, errMsg = a.ToPoll(false)
+		assert.Nil(t, errMsg)
+		assert.NotNil(t, p)
+	})
+	t.Run("running anonymous poll", func(t *testing.T) {
+		p := testutils.GetRankedPollWithBallots()
+		p.Settings.Anonymous = true
+		p2, errMsg := p.ToArchive("", false, secretBallotKey).ToPoll(true)
+		assert.Nil(t, p2)
+		require.NotNil(t, errMsg)
+		assert.Equal(t, "poll.archive.pseudonymized", errMsg.Message.ID)
+	})
+}
+
+func TestArchivedPollReplaceUsers(t *testing.T) {
+	toUsername := func(userID string) (string, *model.AppError) {
+		return strings.Replace(userID, "userID", "user", 1), nil
+	}
+
+	t.Run("poll that isn't anonymous", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+		p.AnswerOptions[0].Proxies = map[string]string{"userID2": "userID1"}
+		p.AnswerOptions[0].VotedAt = map[string]int64{"userID1": 1234567890000}
+		p.AnswerOptions[1].Waitlist = []string{"userID5"}
+		p.Delegates = map[string]string{"userID3": "userID1"}
+
+		archived := p.ToArchive("channelID1", true, secretBallotKey)
+		require.Nil(t, archived.ReplaceUsers(toUsername))
+		assert.Equal(t, "user1", archived.Creator)
+		assert.Equal(t, []string{"user1", "user2", "user3"}, archived.AnswerOptions[0].Voters)
+		assert.Equal(t, map[string]string{"user2": "user1"}, archived.AnswerOptions[0].Proxies)
+		assert.Equal(t, map[string]int64{"user1": 1234567890000}, archived.AnswerOptions[0].VotedAt)
+		assert.Equal(t, []string{"user5"}, archived.AnswerOptions[1].Waitlist)
+		assert.Equal(t, map[string]string{"user3": "user1"}, archived.Delegates)
+		b, err := json.Marshal(archived)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "userID")
+
+		// The poll itself is unchanged
+		assert.Equal(t, []string{"userID1", "userID2", "userID3"}, p.AnswerOptions[0].Voter)
+	})
+	t.Run("free-text poll", func(t *testing.T) {
+		archived := testutils.GetFreeTextPollWithResponses().ToArchive("channelID1", true, secretBallotKey)
+		require.Nil(t, archived.ReplaceUsers(toUsername))
+		for _, r := range archived.Responses {
+			assert.NotContains(t, r.UserID, "userID")
+		}
+	})
+	t.Run("anonymous poll keeps voter tokens", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 1})
+		archived := p.ToArchive("channelID1", true, secretBallotKey)
+		tokens := slices.Clone(archived.AnswerOptions[0].Voters)
+
+		require.Nil(t, archived.ReplaceUsers(toUsername))
+		assert.Equal(t, "user1", archived.Creator)
+		assert.Equal(t, tokens, archived.AnswerOptions[0].Voters)
+	})
+	t.Run("anonymous survey question keeps voter tokens", func(t *testing.T) {
+		archived := testutils.GetSurveyWithAnswers().ToArchive("channelID1", true, secretBallotKey)
+		tokens := slices.Clone(archived.Questions[1].AnswerOptions[0].Voters)
+
+		require.Nil(t, archived.ReplaceUsers(toUsername))
+		assert.Equal(t, []string{"user1", "user2"}, archived.Questions[0].AnswerOptions[0].Voters)
+		assert.Equal(t, tokens, archived.Questions[1].AnswerOptions[0].Voters)
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		archived := testutils.GetPollWithVotes().ToArchive("channelID1", true, secretBallotKey)
+		errMsg := archived.ReplaceUsers(func(userID string) (string, *model.AppError) {
+			if userID == "userID4" {
+				return "", &model.AppError{}
+			}
+			return toUsername(userID)
+		})
+		require.NotNil(t, errMsg)
+		assert.Equal(t, "poll.archive.unknownUser", errMsg.Message.ID)
+		assert.Equal(t, "userID4", errMsg.Data["User"])
+	})
+}
@@ -0,0 +1,222 @@
+package poll
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// answerCapacityPattern matches answer options with a capacity like "Slot A [5]".
+var answerCapacityPattern = regexp.MustCompile(`^(.*\S)\s*\[([1-9]\d*)\]$`)
+
+var pollMessageOptionFull = &i18n.Message{
+	ID:    "poll.updateVote.full",
+	Other: "This option is full. Please choose another one.",
+}
+
+// Promotion is a user, who got a spot in an answer option from its waitlist.
+type Promotion struct {
+	UserID string
+	Answer string
+}
+
+// parseCapacity strips the capacity like "Slot A [5]" from an answer option.
+// It returns 0 if the answer option has no capacity.
+func parseCapacity(answerOption string) (string, int) {
+	e := answerCapacityPattern.FindStringSubmatch(answerOption)
+	if len(e) != 3 {
+		return answerOption, 0
+	}
+	capacity, err := strconv.Atoi(e[2])
+	if err != nil {
+		return answerOption, 0
+	}
+	return e[1], capacity
+}
+
+// supportsCapacity returns true if the answer options of the poll can have a capacity.
+// Only polls, in which every vote takes a spot of a known user, support it.
+func (p *Poll) supportsCapacity() bool {
+	return !p.IsRanked() && !p.HasPointBudget() && !p.IsScheduling() && !p.IsQuiz() && !p.IsFreeText() && !p.IsSurvey() && !p.IsSecretBallot()
+}
+
+// hasCapacity returns true if any answer option of the poll has a capacity.
+func (p *Poll) hasCapacity() bool {
+	if p.Settings.Capacity > 0 {
+		return true
+	}
+	for _, o := range p.AnswerOptions {
+		if o.Capacity > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Poll) validateCapacity() *utils.ErrorMessage {
+	if p.Settings.Capacity < 0 {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.capacitySetting.invalid",
+				Other: `The capacity of the options must be a positive number. You specified "{{.Capacity}}".`,
+			},
+			Data: map[string]interface{}{
+				"Capacity": p.Settings.Capacity,
+			},
+		}
+	}
+	if (p.Settings.Capacity > 0 || p.Settings.Waitlist) && !p.supportsCapacity() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.capacitySetting.combined",
+				Other: "The capacity and waitlist settings can't be combined with the ranked, points, scheduling, free-text or quiz setting.",
+			},
+		}
+	}
+	if (p.Settings.Capacity > 0 || p.Settings.Waitlist) && p.Settings.Anonymous {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.capacitySetting.anonymous",
+				Other: "The capacity and waitlist settings can't be combined with the anonymous setting, as the votes of anonymous polls are stored as secret ballots.",
+			},
+		}
+	}
+	if p.Settings.Waitlist && !p.hasCapacity() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.waitlistSetting.noCapacity",
+				Other: `The waitlist setting needs options with a capacity. Set it for all options with "--capacity=X" or for single options like "Slot A [5]".`,
+			},
+		}
+	}
+	if p.Settings.Waitlist && p.IsFinal() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.waitlistSetting.final",
+				Other: "The waitlist setting can't be combined with the final setting, as getting a spot from the waitlist changes the vote of a user.",
+			},
+		}
+	}
+	return nil
+}
+
+// IsFull returns true if the answer option with a given index has no spot left for a given user.
+// Users, who already voted for the answer option, keep their spot.
+func (p *Poll) IsFull(index int, userID string) bool {
+	if len(p.AnswerOptions) <= index || index < 0 {
+		return false
+	}
+	o := p.AnswerOptions[index]
+	return o.Capacity > 0 && len(o.Voter) >= o.Capacity && !slices.Contains(o.Voter, userID)
+}
+
+// JoinWaitlist adds a given user to the waitlist of the full answer option with a given index.
+// It returns the position of the user on the waitlist, starting at 1.
+func (p *Poll) JoinWaitlist(userID string, index int) (int, error) {
+	if len(p.AnswerOptions) <= index || index < 0 {
+		return 0, fmt.Errorf("invalid index")
+	}
+	if userID == "" {
+		return 0, fmt.Errorf("invalid userID")
+	}
+	if !p.Settings.Waitlist || !p.IsFull(index, userID) {
+		return 0, fmt.Errorf("option has no waitlist")
+	}
+
+	o := p.AnswerOptions[index]
+	if i := slices.Index(o.Waitlist, userID); i >= 0 {
+		return i + 1, nil
+	}
+	o.Waitlist = append(o.Waitlist, userID)
+	return len(o.Waitlist), nil
+}
+
+// IsWaiting returns true if a given user is on the waitlist of any answer option.
+func (p *Poll) IsWaiting(userID string) bool {
+	for _, o := range p.AnswerOptions {
+		if slices.Contains(o.Waitlist, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// leaveWaitlists removes a given user from the waitlists of all answer options.
+func (p *Poll) leaveWaitlists(userID string) {
+	for _, o := range p.AnswerOptions {
+		o.Waitlist = slices.DeleteFunc(o.Waitlist, func(v string) bool { return v == userID })
+		if len(o.Waitlist) == 0 {
+			o.Waitlist = nil
+		}
+	}
+}
+
+// promoteFromWaitlist gives the free spots of the answer option with a given index to the users on its waitlist.
+// In single vote mode, promoted users lose their previous vote, which might free a spot for the waitlist of another answer option.
+// In multi vote mode, users without votes left are skipped.
+func (p *Poll) promoteFromWaitlist(index int) {
+	o := p.AnswerOptions[index]
+	for len(o.Waitlist) > 0 && (o.Capacity == 0 || len(o.Voter) < o.Capacity) {
+		userID := o.Waitlist[0]
+		o.Waitlist = o.Waitlist[1:]
+
+		if p.IsMultiVote() {
+			if p.Settings.MaxVotes != 0 && len(p.GetVotedAnswers(userID)) >= p.Settings.MaxVotes {
+				continue
+			}
+			o.Voter = append(o.Voter, userID)
+			continue
+		}
+
+		p.leaveWaitlists(userID)
+		left := p.removeVotes(userID)
+		o.Voter = append(o.Voter, userID)
+		for _, i := range left {
+			p.promoteFromWaitlist(i)
+		}
+	}
+	if len(o.Waitlist) == 0 {
+		o.Waitlist = nil
+	}
+}
+
+// removeVotes removes the votes of a given user from all answer options. It returns the indexes of the answer options the user left.
+func (p *Poll) removeVotes(userID string) []int {
+	left := []int{}
+	for i, o := range p.AnswerOptions {
+		if slices.Contains(o.Voter, userID) {
+			o.Voter = slices.DeleteFunc(o.Voter, func(v string) bool { return v == userID })
+			o.removeProxy(userID)
+			left = append(left, i)
+		}
+	}
+	return left
+}
+
+// GetPromotions returns the users, who got a spot from a waitlist since a given previous state of the poll.
+func (p *Poll) GetPromotions(prev *Poll) []*Promotion {
+	promotions := []*Promotion{}
+	for i, o := range p.AnswerOptions {
+		if len(prev.AnswerOptions) <= i {
+			break
+		}
+		prevOption := prev.AnswerOptions[i]
+		for _, userID := range o.Voter {
+			if slices.Contains(prevOption.Waitlist, userID) && !slices.Contains(prevOption.Voter, userID) {
+				promotions = append(promotions, &Promotion{UserID: userID, Answer: o.Answer})
+			}
+		}
+	}
+	return promotions
+}
+
+// makeCapacityLabel returns the label of the button of an answer option with a capacity, e.g. "Slot A (3/5)" if 3 of 5 spots are left.
+func (o *AnswerOption) makeCapacityLabel() string {
+	remaining := max(o.Capacity-len(o.Voter), 0)
+	return fmt.Sprintf("%s (%d/%d)", o.Answer, remaining, o.Capacity)
+}
@@ -0,0 +1,229 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func newSignUpSheet(t *testing.T, settings poll.Settings) *poll.Poll {
+	var pf poll.Factory
+	pf.SetMillis(testutils.GetMillis)
+	pf.SetNewID(testutils.GetPollID)
+
+	p, errMsg := pf.NewPoll("userID1", "Question", []string{"Slot A [2]", "Slot B [1]", "Slot C"}, settings)
+	require.Nil(t, errMsg)
+	return p
+}
+
+func TestNewPollWithCapacity(t *testing.T) {
+	var pf poll.Factory
+	pf.SetMillis(testutils.GetMillis)
+	pf.SetNewID(testutils.GetPollID)
+
+	t.Run("capacity of single options", func(t *testing.T) {
+		p, errMsg := pf.NewPoll("userID1", "Question", []string{"Slot A [5]", "Slot B[1]", "Slot C", "[3]"}, poll.Settings{MaxVotes: 1, Waitlist: true})
+		require.Nil(t, errMsg)
+		assert.Equal(t, []*poll.AnswerOption{
+			{Answer: "Slot A", Voter: []string{}, Capacity: 5},
+			{Answer: "Slot B", Voter: []string{}, Capacity: 1},
+			{Answer: "Slot C", Voter: []string{}},
+			{Answer: "[3]", Voter: []string{}},
+		}, p.AnswerOptions)
+	})
+	t.Run("capacity setting is the default", func(t *testing.T) {
+		p, errMsg := pf.NewPoll("userID1", "Question", []string{"Slot A [5]", "Slot B"}, poll.Settings{MaxVotes: 1, Capacity: 2})
+		require.Nil(t, errMsg)
+		assert.Equal(t, 5, p.AnswerOptions[0].Capacity)
+		assert.Equal(t, 2, p.AnswerOptions[1].Capacity)
+
+		require.Nil(t, p.AddAnswerOption("Slot C"))
+		assert.Equal(t, 2, p.AnswerOptions[2].Capacity)
+	})
+	t.Run("brackets are kept for ranked polls", func(t *testing.T) {
+		p, errMsg := pf.NewPoll("userID1", "Question", []string{"Slot A [5]", "Slot B"}, poll.Settings{MaxVotes: 1, Ranked: true})
+		require.Nil(t, errMsg)
+		assert.Equal(t, "Slot A [5]", p.AnswerOptions[0].Answer)
+		assert.Equal(t, 0, p.AnswerOptions[0].Capacity)
+	})
+	t.Run("brackets are kept without capacity or waitlist setting", func(t *testing.T) {
+		p, errMsg := pf.NewPoll("userID1", "Question", []string{"Version [2]", "Version [3]"}, poll.Settings{MaxVotes: 1, Anonymous: true})
+		require.Nil(t, errMsg)
+		assert.Equal(t, "Version [2]", p.AnswerOptions[0].Answer)
+		assert.Equal(t, 0, p.AnswerOptions[0].Capacity)
+		assert.True(t, p.IsSecretBallot())
+	})
+
+	for name, settings := range map[string]poll.Settings{
+		"negative capacity":          {MaxVotes: 1, Capacity: -1},
+		"capacity with ranked":       {MaxVotes: 1, Capacity: 2, Ranked: true},
+		"waitlist with points":       {MaxVotes: 1, Waitlist: true, Points: 3},
+		"waitlist without capacity":  {MaxVotes: 1, Waitlist: true},
+		"waitlist with final":        {MaxVotes: 1, Capacity: 2, Waitlist: true, Final: true},
+		"capacity with a free-text ": {MaxVotes: 1, Capacity: 2, FreeText: true},
+		"capacity with anonymous":    {MaxVotes: 1, Capacity: 2, Anonymous: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			answerOptions := []string{"Slot A", "Slot B"}
+			if settings.FreeText {
+				answerOptions = nil
+			}
+			p, errMsg := pf.NewPoll("userID1", "Question", answerOptions, settings)
+			assert.NotNil(t, errMsg)
+			assert.Nil(t, p)
+		})
+	}
+}
+
+func TestUpdateVoteWithCapacity(t *testing.T) {
+	p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Capacity: 3})
+
+	msg, err := p.UpdateVote("userID2", 1)
+	require.NoError(t, err)
+	require.Nil(t, msg)
+	assert.True(t, p.IsFull(1, "userID3"))
+	assert.False(t, p.IsFull(1, "userID2"))
+
+	msg, err = p.UpdateVote("userID3", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "poll.updateVote.full", msg.ID)
+	assert.Equal(t, []string{"userID2"}, p.AnswerOptions[1].Voter)
+
+	// Voting again for the same option keeps the spot
+	msg, err = p.UpdateVote("userID2", 1)
+	require.NoError(t, err)
+	require.Nil(t, msg)
+	assert.Equal(t, []string{"userID2"}, p.AnswerOptions[1].Voter)
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Waitlist: true})
+	_, err := p.UpdateVote("userID2", 1)
+	require.NoError(t, err)
+
+	_, err = p.JoinWaitlist("userID3", 0)
+	assert.Error(t, err, "option isn't full")
+	_, err = p.JoinWaitlist("userID3", 3)
+	assert.Error(t, err)
+
+	position, err := p.JoinWaitlist("userID3", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, position)
+	position, err = p.JoinWaitlist("userID4", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, position)
+	position, err = p.JoinWaitlist("userID3", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, position)
+	assert.Equal(t, []string{"userID3", "userID4"}, p.AnswerOptions[1].Waitlist)
+
+	t.Run("without waitlist setting", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Capacity: 3})
+		_, err := p.UpdateVote("userID2", 1)
+		require.NoError(t, err)
+
+		_, err = p.JoinWaitlist("userID3", 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestPromoteFromWaitlist(t *testing.T) {
+	t.Run("reset votes promotes the next user", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Waitlist: true})
+		_, err := p.UpdateVote("userID2", 1)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID3", 1)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID4", 1)
+		require.NoError(t, err)
+
+		prev := p.Copy()
+		p.ResetVotes("userID2")
+		assert.Equal(t, []string{"userID3"}, p.AnswerOptions[1].Voter)
+		assert.Equal(t, []string{"userID4"}, p.AnswerOptions[1].Waitlist)
+		assert.Equal(t, []*poll.Promotion{{UserID: "userID3", Answer: "Slot B"}}, p.GetPromotions(prev))
+	})
+	t.Run("reset votes leaves the waitlists", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Waitlist: true})
+		_, err := p.UpdateVote("userID2", 1)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID3", 1)
+		require.NoError(t, err)
+
+		assert.True(t, p.IsWaiting("userID3"))
+		p.ResetVotes("userID3")
+		assert.Nil(t, p.AnswerOptions[1].Waitlist)
+		assert.False(t, p.IsWaiting("userID3"))
+	})
+	t.Run("changing the vote promotes the next user, who loses their previous vote", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Waitlist: true})
+		for _, userID := range []string{"userID2", "userID3"} {
+			_, err := p.UpdateVote(userID, 0)
+			require.NoError(t, err)
+		}
+		_, err := p.UpdateVote("userID4", 1)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID5", 0)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID4", 0)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID6", 1)
+		require.NoError(t, err)
+
+		prev := p.Copy()
+		// userID2 leaves Slot A for Slot C. userID5 takes the spot. userID4 stays on the waitlist of Slot A.
+		_, err = p.UpdateVote("userID2", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"userID3", "userID5"}, p.AnswerOptions[0].Voter)
+		assert.Equal(t, []string{"userID4"}, p.AnswerOptions[0].Waitlist)
+		assert.Equal(t, []*poll.Promotion{{UserID: "userID5", Answer: "Slot A"}}, p.GetPromotions(prev))
+
+		// userID3 leaves. userID4 takes the spot and frees Slot B for userID6.
+		prev = p.Copy()
+		p.ResetVotes("userID3")
+		assert.Equal(t, []string{"userID5", "userID4"}, p.AnswerOptions[0].Voter)
+		assert.Nil(t, p.AnswerOptions[0].Waitlist)
+		assert.Equal(t, []string{"userID6"}, p.AnswerOptions[1].Voter)
+		assert.Nil(t, p.AnswerOptions[1].Waitlist)
+		assert.Equal(t, []*poll.Promotion{
+			{UserID: "userID4", Answer: "Slot A"},
+			{UserID: "userID6", Answer: "Slot B"},
+		}, p.GetPromotions(prev))
+	})
+	t.Run("multi vote skips users without votes left", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 2, Waitlist: true})
+		_, err := p.UpdateVote("userID2", 1)
+		require.NoError(t, err)
+		for _, index := range []int{0, 2} {
+			_, err = p.UpdateVote("userID3", index)
+			require.NoError(t, err)
+		}
+		_, err = p.JoinWaitlist("userID3", 1)
+		require.NoError(t, err)
+		_, err = p.JoinWaitlist("userID4", 1)
+		require.NoError(t, err)
+
+		p.ResetVotes("userID2")
+		assert.Equal(t, []string{"userID4"}, p.AnswerOptions[1].Voter)
+		assert.Nil(t, p.AnswerOptions[1].Waitlist)
+		assert.Equal(t, []string{"Slot A", "Slot C"}, p.GetVotedAnswers("userID3"))
+	})
+}
+
+func TestPollToPostActionsWithCapacity(t *testing.T) {
+	p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Progress: true, Capacity: 3})
+	_, err := p.UpdateVote("userID2", 0)
+	require.NoError(t, err)
+	_, err = p.UpdateVote("userID3", 2)
+	require.NoError(t, err)
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "Slot A (1/2)", attachments[0].Actions[0].Name)
+	assert.Equal(t, "Slot B (1/1)", attachments[0].Actions[1].Name)
+	assert.Equal(t, "Slot C (2/3)", attachments[0].Actions[2].Name)
+}
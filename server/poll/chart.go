@@ -0,0 +1,311 @@
+package poll
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+
+	"github.com/hajimehoshi/bitmapfont/v3"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+const (
+	chartWidth       = 800
+	chartPadding     = 24
+	chartLabelWidth  = 240
+	chartCountWidth  = 110
+	chartRowHeight   = 40
+	chartVotersExtra = 18
+	chartBarHeight   = 22
+)
+
+var (
+	chartBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	chartText       = color.RGBA{0x3d, 0x3c, 0x40, 0xff}
+	chartMutedText  = color.RGBA{0x8a, 0x8a, 0x8c, 0xff}
+	chartBar        = color.RGBA{0x16, 0x6d, 0xe0, 0xff}
+	chartWinnerBar  = color.RGBA{0x3d, 0xb8, 0x87, 0xff}
+	chartBarTrack   = color.RGBA{0xed, 0xed, 0xee, 0xff}
+
+	chartSubtitleVotes = &i18n.Message{
+		ID:    "poll.chart.subtitle.votes",
+		One:   "{{.Total}} vote",
+		Few:   "{{.Total}} votes",
+		Many:  "{{.Total}} votes",
+		Other: "{{.Total}} votes",
+	}
+	chartSubtitlePoints = &i18n.Message{
+		ID:    "poll.chart.subtitle.points",
+		One:   "{{.Total}} point",
+		Few:   "{{.Total}} points",
+		Many:  "{{.Total}} points",
+		Other: "{{.Total}} points",
+	}
+	chartSubtitleRanked = &i18n.Message{
+		ID:    "poll.chart.subtitle.ranked",
+		One:   "First choices of {{.Total}} ballot",
+		Few:   "First choices of {{.Total}} ballots",
+		Many:  "First choices of {{.Total}} ballots",
+		Other: "First choices of {{.Total}} ballots",
+	}
+	chartSubtitleScheduling = &i18n.Message{
+		ID:    "poll.chart.subtitle.scheduling",
+		One:   "Yes answers of {{.Total}} voter",
+		Few:   "Yes answers of {{.Total}} voters",
+		Many:  "Yes answers of {{.Total}} voters",
+		Other: "Yes answers of {{.Total}} voters",
+	}
+)
+
+// chartFonts holds the fonts used to label charts. They are parsed once on first use.
+// The Go fonts only cover latin, greek and cyrillic scripts. All other characters, e.g. of japanese or korean texts,
+// are drawn with a bitmap font.
+var chartFonts struct {
+	once          sync.Once
+	regular, bold *sfnt.Font
+	err           error
+}
+
+// newChartFaces returns the faces for the title, the labels and the voters of a chart.
+// Font faces aren't safe for concurrent use, so every chart gets its own faces, which need to be closed afterwards.
+func newChartFaces() (font.Face, font.Face, font.Face, error) {
+	chartFonts.once.Do(func() {
+		if chartFonts.regular, chartFonts.err = opentype.Parse(goregular.TTF); chartFonts.err != nil {
+			chartFonts.err = fmt.Errorf("failed to parse regular font: %w", chartFonts.err)
+			return
+		}
+		if chartFonts.bold, chartFonts.err = opentype.Parse(gobold.TTF); chartFonts.err != nil {
+			chartFonts.err = fmt.Errorf("failed to parse bold font: %w", chartFonts.err)
+		}
+	})
+	if chartFonts.err != nil {
+		return nil, nil, nil, chartFonts.err
+	}
+
+	title, err := newChartFace(chartFonts.bold, 20)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	label, err := newChartFace(chartFonts.regular, 14)
+	if err != nil {
+		title.Close()
+		return nil, nil, nil, err
+	}
+	small, err := newChartFace(chartFonts.regular, 11)
+	if err != nil {
+		title.Close()
+		label.Close()
+		return nil, nil, nil, err
+	}
+	return title, label, small, nil
+}
+
+func newChartFace(f *sfnt.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create font face: %w", err)
+	}
+	return &fallbackFace{primary: face, fallback: bitmapfont.Face}, nil
+}
+
+// fallbackFace draws every rune with the primary face, unless the primary face has no glyph for it.
+// The metrics are always those of the primary face.
+type fallbackFace struct {
+	primary  font.Face
+	fallback font.Face
+}
+
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	if _, ok := f.primary.GlyphAdvance(r); ok {
+		return f.primary
+	}
+	if _, ok := f.fallback.GlyphAdvance(r); ok {
+		return f.fallback
+	}
+	return f.primary
+}
+
+func (f *fallbackFace) Close() error {
+	return f.primary.Close()
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if face := f.faceFor(r0); face == f.faceFor(r1) {
+		return face.Kern(r0, r1)
+	}
+	return 0
+}
+
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.primary.Metrics()
+}
+
+// HasChart returns true if the results of the poll can be rendered as chart. Free-text polls and surveys can't.
+func (p *Poll) HasChart() bool {
+	return !p.IsFreeText() && !p.IsSurvey()
+}
+
+// ToChart renders the results of the poll as PNG bar chart, labelled with the question and the answer options.
+// Winning options are highlighted. Unless the poll is anonymous, the voters are listed below every bar.
+func (p *Poll) ToChart(bundle *utils.Bundle, convert IDToNameConverter) ([]byte, error) {
+	if !p.HasChart() {
+		return nil, fmt.Errorf("poll has no chart")
+	}
+	titleFace, labelFace, smallFace, err := newChartFaces()
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+	defer labelFace.Close()
+	defer smallFace.Close()
+
+	counts := p.countProgress()
+	total, subtitle := p.chartTotal(bundle, counts)
+	highest := 0
+	for _, count := range counts {
+		highest = max(highest, count)
+	}
+
+	voters := make([]string, len(p.AnswerOptions))
+	if !p.Settings.Anonymous {
+		for i, o := range p.AnswerOptions {
+			names := make([]string, 0, len(o.Voter))
+			for _, userID := range o.Voter {
+				if p.IsScheduling() && o.Availability[userID] != AvailabilityYes {
+					continue
+				}
+				name, appErr := convert(userID)
+				if appErr != nil {
+					return nil, appErr
+				}
+				names = append(names, name)
+			}
+			voters[i] = strings.Join(names, ", ")
+		}
+	}
+
+	rowHeight := chartRowHeight
+	if !p.Settings.Anonymous {
+		rowHeight += chartVotersExtra
+	}
+	height := chartPadding*2 + 56 + rowHeight*len(p.AnswerOptions)
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(chartBackground), image.Point{}, draw.Src)
+
+	drawChartText(img, titleFace, chartText, chartPadding, chartPadding+20, p.Question, chartWidth-2*chartPadding)
+	drawChartText(img, labelFace, chartMutedText, chartPadding, chartPadding+44, subtitle, chartWidth-2*chartPadding)
+
+	winners := map[int]bool{}
+	for _, i := range p.GetWinners() {
+		winners[i] = true
+	}
+	barX := chartPadding + chartLabelWidth + 16
+	barWidth := chartWidth - barX - chartCountWidth - chartPadding
+	for i, o := range p.AnswerOptions {
+		top := chartPadding + 56 + i*rowHeight + (chartRowHeight-chartBarHeight)/2
+		drawChartText(img, labelFace, chartText, chartPadding, top+16, o.Answer, chartLabelWidth)
+
+		draw.Draw(img, image.Rect(barX, top, barX+barWidth, top+chartBarHeight), image.NewUniform(chartBarTrack), image.Point{}, draw.Src)
+		if highest > 0 && counts[i] > 0 {
+			bar := chartBar
+			if winners[i] {
+				bar = chartWinnerBar
+			}
+			width := max(barWidth*counts[i]/highest, 2)
+			draw.Draw(img, image.Rect(barX, top, barX+width, top+chartBarHeight), image.NewUniform(bar), image.Point{}, draw.Src)
+		}
+
+		count := fmt.Sprintf("%d · %d%%", counts[i], percentage(counts[i], total))
+		drawChartText(img, labelFace, chartText, barX+barWidth+12, top+16, count, chartCountWidth-12)
+
+		if voters[i] != "" {
+			drawChartText(img, smallFace, chartMutedText, barX, top+chartBarHeight+14, voters[i], barWidth+chartCountWidth)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// chartTotal returns the total the bars of a chart are compared to and the subtitle describing it.
+func (p *Poll) chartTotal(bundle *utils.Bundle, counts []int) (int, string) {
+	var total int
+	var subtitle *i18n.Message
+	switch {
+	case p.IsRanked():
+		total = len(p.GetBallotVoters())
+		subtitle = chartSubtitleRanked
+	case p.IsScheduling():
+		total = len(p.GetVoters())
+		subtitle = chartSubtitleScheduling
+	default:
+		for _, count := range counts {
+			total += count
+		}
+		subtitle = chartSubtitleVotes
+		if p.HasPointBudget() {
+			subtitle = chartSubtitlePoints
+		}
+	}
+
+	return total, bundle.LocalizeWithConfig(bundle.GetServerLocalizer(), &i18n.LocalizeConfig{
+		DefaultMessage: subtitle,
+		TemplateData:   map[string]interface{}{"Total": total},
+		PluralCount:    total,
+	})
+}
+
+// drawChartText draws a line of text with its baseline at y. Text wider than maxWidth is shortened with an ellipsis.
+func drawChartText(img draw.Image, face font.Face, c color.Color, x, y int, text string, maxWidth int) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(truncateChartText(face, text, maxWidth))
+}
+
+// truncateChartText shortens a text with an ellipsis until it fits into maxWidth.
+func truncateChartText(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		shortened := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, shortened).Ceil() <= maxWidth {
+			return shortened
+		}
+	}
+	return ""
+}
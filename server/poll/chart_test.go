@@ -0,0 +1,135 @@
+package poll_test
+
+import (
+	"bytes"
+	"image/png"
+	"sync"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestPollToChart(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		return "@" + userID, nil
+	}
+
+	for name, test := range map[string]struct {
+		Poll           *poll.Poll
+		ExpectedHeight int
+	}{
+		"Normal poll": {
+			Poll:           testutils.GetPollWithVotes(),
+			ExpectedHeight: 278,
+		},
+		"Anonymous poll": {
+			Poll:           testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true}),
+			ExpectedHeight: 224,
+		},
+		"Ranked poll": {
+			Poll:           testutils.GetRankedPollWithBallots(),
+			ExpectedHeight: 278,
+		},
+		"Points poll": {
+			Poll:           testutils.GetPointsPollWithVotes(),
+			ExpectedHeight: 278,
+		},
+		"Scheduling poll": {
+			Poll:           testutils.GetSchedulingPollWithVotes(),
+			ExpectedHeight: 278,
+		},
+		"Long question and answers": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotes()
+				p.Question = "What is the one thing you would change about the way we plan, run and review our sprints?"
+				p.AnswerOptions[0].Answer = "A very long answer option that doesn't fit into the space left of the bar"
+				return p
+			}(),
+			ExpectedHeight: 278,
+		},
+		"No votes": {
+			Poll:           testutils.GetPoll(),
+			ExpectedHeight: 278,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := test.Poll.ToChart(testutils.GetBundle(), converter)
+			require.NoError(t, err)
+
+			img, err := png.Decode(bytes.NewReader(b))
+			require.NoError(t, err)
+			assert.Equal(t, 800, img.Bounds().Dx())
+			assert.Equal(t, test.ExpectedHeight, img.Bounds().Dy())
+		})
+	}
+
+	t.Run("anonymous poll doesn't resolve voters", func(t *testing.T) {
+		converter := func(_ string) (string, *model.AppError) {
+			require.Fail(t, "converter must not be called")
+			return "", nil
+		}
+		b, err := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true}).ToChart(testutils.GetBundle(), converter)
+		require.NoError(t, err)
+		assert.NotEmpty(t, b)
+	})
+	t.Run("converter fails", func(t *testing.T) {
+		converter := func(_ string) (string, *model.AppError) {
+			return "", &model.AppError{}
+		}
+		b, err := testutils.GetPollWithVotes().ToChart(testutils.GetBundle(), converter)
+		assert.Error(t, err)
+		assert.Nil(t, b)
+	})
+	t.Run("japanese question", func(t *testing.T) {
+		render := func(question string) []byte {
+			p := testutils.GetPollWithVotes()
+			p.Question = question
+			b, err := p.ToChart(testutils.GetBundle(), converter)
+			require.NoError(t, err)
+			return b
+		}
+
+		// Both questions would be rendered as identical placeholder glyphs, if the characters weren't supported
+		assert.NotEqual(t, render("昼食はどこで食べますか"), render("夕食は何時に始めますか"))
+	})
+	t.Run("concurrent charts", func(t *testing.T) {
+		expected, err := testutils.GetPollWithVotes().ToChart(testutils.GetBundle(), converter)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		charts := make([][]byte, 10)
+		for i := range charts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				charts[i], _ = testutils.GetPollWithVotes().ToChart(testutils.GetBundle(), converter)
+			}()
+		}
+		wg.Wait()
+
+		for _, b := range charts {
+			assert.Equal(t, expected, b)
+		}
+	})
+	t.Run("free-text poll", func(t *testing.T) {
+		p := testutils.GetFreeTextPollWithResponses()
+		assert.False(t, p.HasChart())
+
+		b, err := p.ToChart(testutils.GetBundle(), converter)
+		assert.Error(t, err)
+		assert.Nil(t, b)
+	})
+	t.Run("survey", func(t *testing.T) {
+		p := testutils.GetSurveyWithAnswers()
+		assert.False(t, p.HasChart())
+
+		b, err := p.ToChart(testutils.GetBundle(), converter)
+		assert.Error(t, err)
+		assert.Nil(t, b)
+	})
+}
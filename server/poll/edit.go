@@ -0,0 +1,121 @@
+package poll
+
+import (
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// Edit contains the question and the settings of a poll, that can be changed after the poll was posted.
+// Other settings, e.g. the voting mode or anonymous, would change the meaning of votes, that were already cast.
+type Edit struct {
+	Question         string
+	Progress         bool
+	ResultsAfterVote bool
+	PublicAddOption  bool
+	MaxVotes         int
+}
+
+// NewEdit returns the current values of all editable fields of a poll.
+func (p *Poll) NewEdit() Edit {
+	return Edit{
+		Question:         p.Question,
+		Progress:         p.Settings.Progress,
+		ResultsAfterVote: p.Settings.ResultsAfterVote,
+		PublicAddOption:  p.Settings.PublicAddOption,
+		MaxVotes:         p.Settings.MaxVotes,
+	}
+}
+
+// IsEdited returns true if the poll was edited after it was posted
+func (p *Poll) IsEdited() bool {
+	return p.EditedAt > 0
+}
+
+// CanEditMaxVotes returns true if the votes setting of the poll can be edited.
+// Ranked-choice, point-budget, scheduling, quiz, free-text polls and surveys always use a single vote.
+func (p *Poll) CanEditMaxVotes() bool {
+	return !p.IsRanked() && !p.HasPointBudget() && !p.IsScheduling() && !p.IsQuiz() && !p.IsFreeText() && !p.IsSurvey()
+}
+
+// ApplyEdit changes the question and the settings of a poll and marks it as edited at a given time in milliseconds.
+// The votes setting can't be lowered below the number of votes a user already cast, so existing votes are always kept.
+// If the edit is invalid, a message is returned and the poll is left unchanged.
+func (p *Poll) ApplyEdit(e Edit, now int64) *utils.ErrorMessage {
+	e.Question = strings.TrimSpace(e.Question)
+	if e == p.NewEdit() {
+		return nil
+	}
+	if e.Question == "" {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.applyEdit.question.empty",
+				Other: "The question can't be empty.",
+			},
+		}
+	}
+	if e.MaxVotes != p.Settings.MaxVotes && !p.CanEditMaxVotes() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.applyEdit.maxVotes.unsupported",
+				Other: "The number of votes of this poll can't be changed.",
+			},
+		}
+	}
+	if votes := p.maxVotesOfVoter(); e.MaxVotes != p.Settings.MaxVotes && e.MaxVotes != 0 && e.MaxVotes < votes {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.applyEdit.maxVotes.belowVotes",
+				Other: `Users already voted for up to {{.Votes}} options, so the number of votes can't be lower than that. You specified "{{.MaxVotes}}".`,
+			},
+			Data: map[string]interface{}{
+				"Votes":    votes,
+				"MaxVotes": e.MaxVotes,
+			},
+		}
+	}
+
+	prev := p.Copy()
+	p.Question = e.Question
+	p.Settings.Progress = e.Progress
+	p.Settings.ResultsAfterVote = e.ResultsAfterVote
+	p.Settings.PublicAddOption = e.PublicAddOption
+	p.Settings.MaxVotes = e.MaxVotes
+
+	var errMsg *utils.ErrorMessage
+	if p.IsSurvey() {
+		errMsg = p.validateSurvey()
+	} else {
+		errMsg = p.validate()
+	}
+	if errMsg != nil {
+		*p = *prev
+		return errMsg
+	}
+
+	p.EditedAt = now
+	return nil
+}
+
+// maxVotesOfVoter returns the highest number of answer options a single user voted for.
+func (p *Poll) maxVotesOfVoter() int {
+	votes := map[string]int{}
+	for _, o := range p.AnswerOptions {
+		for _, userID := range o.Voter {
+			votes[userID]++
+		}
+	}
+	for token, indexes := range p.Ballots {
+		votes[token] = len(indexes)
+	}
+
+	highest := 0
+	for _, n := range votes {
+		if n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
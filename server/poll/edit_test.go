@@ -0,0 +1,184 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestNewEdit(t *testing.T) {
+	p := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, PublicAddOption: true, MaxVotes: 2})
+	assert.Equal(t, poll.Edit{Question: "Question", Progress: true, PublicAddOption: true, MaxVotes: 2}, p.NewEdit())
+}
+
+func TestApplyEdit(t *testing.T) {
+	now := testutils.GetMillis() + 1000
+
+	for name, test := range map[string]struct {
+		Poll             *poll.Poll
+		Edit             func(poll.Edit) poll.Edit
+		ExpectedPoll     func(*poll.Poll)
+		ExpectedErrorMsg string
+	}{
+		"change question and settings": {
+			Poll: testutils.GetPollWithVotes(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.Question = " New Question "
+				e.Progress = true
+				e.PublicAddOption = true
+				e.MaxVotes = 2
+				return e
+			},
+			ExpectedPoll: func(p *poll.Poll) {
+				p.Question = "New Question"
+				p.Settings.Progress = true
+				p.Settings.PublicAddOption = true
+				p.Settings.MaxVotes = 2
+				p.EditedAt = now
+			},
+		},
+		"no changes": {
+			Poll:         testutils.GetPollWithVotes(),
+			Edit:         func(e poll.Edit) poll.Edit { return e },
+			ExpectedPoll: func(p *poll.Poll) {},
+		},
+		"raise max votes to unlimited": {
+			Poll: testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 2}),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.MaxVotes = 0
+				return e
+			},
+			ExpectedPoll: func(p *poll.Poll) {
+				p.Settings.MaxVotes = 0
+				p.EditedAt = now
+			},
+		},
+		"lower max votes to the votes of users": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 0})
+				p.AnswerOptions[1].Voter = append(p.AnswerOptions[1].Voter, "userID1")
+				return p
+			}(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.MaxVotes = 2
+				return e
+			},
+			ExpectedPoll: func(p *poll.Poll) {
+				p.Settings.MaxVotes = 2
+				p.EditedAt = now
+			},
+		},
+		"lower max votes below the votes of users": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 0})
+				p.AnswerOptions[1].Voter = append(p.AnswerOptions[1].Voter, "userID1")
+				return p
+			}(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.MaxVotes = 1
+				return e
+			},
+			ExpectedErrorMsg: "poll.applyEdit.maxVotes.belowVotes",
+		},
+		"lower max votes below the votes of a secret ballot": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 0})
+				p.AnswerOptions[1].Voter = append(p.AnswerOptions[1].Voter, "userID1")
+				require.True(t, p.ConvertToSecretBallot(secretBallotKey))
+				return p
+			}(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.MaxVotes = 1
+				return e
+			},
+			ExpectedErrorMsg: "poll.applyEdit.maxVotes.belowVotes",
+		},
+		"empty question": {
+			Poll: testutils.GetPollWithVotes(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.Question = "  "
+				return e
+			},
+			ExpectedErrorMsg: "poll.applyEdit.question.empty",
+		},
+		"max votes of ranked poll": {
+			Poll: testutils.GetPollWithVotesAndSettings(poll.Settings{Ranked: true, MaxVotes: 1}),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.MaxVotes = 2
+				return e
+			},
+			ExpectedErrorMsg: "poll.applyEdit.maxVotes.unsupported",
+		},
+		"max votes above number of options": {
+			Poll: testutils.GetPollWithVotes(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.MaxVotes = 4
+				return e
+			},
+			ExpectedErrorMsg: "poll.newPoll.votesettings.invalidSetting",
+		},
+		"progress combined with results after vote": {
+			Poll: testutils.GetPollWithVotesAndSettings(poll.Settings{ResultsAfterVote: true, MaxVotes: 1}),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.Progress = true
+				return e
+			},
+			ExpectedErrorMsg: "poll.newPoll.resultsAfterVoteSetting.combined",
+		},
+		"survey title and progress": {
+			Poll: testutils.GetSurveyWithAnswers(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.Question = "New Survey"
+				e.Progress = true
+				return e
+			},
+			ExpectedPoll: func(p *poll.Poll) {
+				p.Question = "New Survey"
+				p.Settings.Progress = true
+				p.EditedAt = now
+			},
+		},
+		"survey with public add option": {
+			Poll: testutils.GetSurveyWithAnswers(),
+			Edit: func(e poll.Edit) poll.Edit {
+				e.PublicAddOption = true
+				return e
+			},
+			ExpectedErrorMsg: "poll.newSurvey.unsupportedSetting",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := test.Poll
+			expected := p.Copy()
+
+			errMsg := p.ApplyEdit(test.Edit(p.NewEdit()), now)
+
+			if test.ExpectedErrorMsg != "" {
+				require.NotNil(t, errMsg)
+				assert.Equal(t, test.ExpectedErrorMsg, errMsg.Message.ID)
+			} else {
+				require.Nil(t, errMsg)
+				test.ExpectedPoll(expected)
+			}
+			assert.Equal(t, expected, p)
+		})
+	}
+}
+
+func TestIsEdited(t *testing.T) {
+	p := testutils.GetPoll()
+	assert.False(t, p.IsEdited())
+	p.EditedAt = testutils.GetMillis()
+	assert.True(t, p.IsEdited())
+}
+
+func TestCanEditMaxVotes(t *testing.T) {
+	assert.True(t, testutils.GetPollWithVotes().CanEditMaxVotes())
+	assert.False(t, testutils.GetQuizPollWithVotes().CanEditMaxVotes())
+	assert.False(t, testutils.GetFreeTextPollWithResponses().CanEditMaxVotes())
+	assert.False(t, testutils.GetSurveyWithAnswers().CanEditMaxVotes())
+}
@@ -0,0 +1,173 @@
+package poll
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// IDToNamesConverter converts a given userID to the username and the display name of the user.
+type IDToNamesConverter func(userID string) (string, string, *model.AppError)
+
+var (
+	exportHeaderOption       = &i18n.Message{ID: "poll.export.header.option", Other: "Option"}
+	exportHeaderResponse     = &i18n.Message{ID: "poll.export.header.response", Other: "Response"}
+	exportHeaderVotes        = &i18n.Message{ID: "poll.export.header.votes", Other: "Votes"}
+	exportHeaderPoints       = &i18n.Message{ID: "poll.export.header.points", Other: "Points"}
+	exportHeaderFirstChoices = &i18n.Message{ID: "poll.export.header.firstChoices", Other: "First choices"}
+	exportHeaderYesAnswers   = &i18n.Message{ID: "poll.export.header.yesAnswers", Other: "Yes answers"}
+	exportHeaderUsername     = &i18n.Message{ID: "poll.export.header.username", Other: "Username"}
+	exportHeaderDisplayName  = &i18n.Message{ID: "poll.export.header.displayName", Other: "Display name"}
+	exportHeaderRank         = &i18n.Message{ID: "poll.export.header.rank", Other: "Rank"}
+	exportHeaderAvailability = &i18n.Message{ID: "poll.export.header.availability", Other: "Availability"}
+	exportHeaderVotedAt      = &i18n.Message{ID: "poll.export.header.votedAt", Other: "Voted at"}
+	exportHeaderProxy        = &i18n.Message{ID: "poll.export.header.proxy", Other: "Proxy"}
+
+	exportVotedAtUnknown = &i18n.Message{ID: "poll.export.votedAt.unknown", Other: "unknown"}
+)
+
+// CanExport returns true if the results of the poll can be exported as CSV. Surveys can't.
+func (p *Poll) CanExport() bool {
+	return !p.IsSurvey()
+}
+
+// ToCSV returns the results of the poll as CSV with a row per answer option and the number of votes it got.
+// Unless the poll is anonymous, there is a row per voter instead, which also contains the username and the display name of the voter.
+// Free-text polls have a row per response.
+func (p *Poll) ToCSV(bundle *utils.Bundle, convert IDToNamesConverter) ([]byte, error) {
+	if !p.CanExport() {
+		return nil, fmt.Errorf("poll can't be exported")
+	}
+
+	localizer := bundle.GetServerLocalizer()
+	header := func(messages ...*i18n.Message) []string {
+		record := make([]string, len(messages))
+		for i, m := range messages {
+			record[i] = bundle.LocalizeDefaultMessage(localizer, m)
+		}
+		return record
+	}
+
+	var records [][]string
+	if p.IsFreeText() {
+		if p.Settings.Anonymous {
+			records = append(records, header(exportHeaderResponse))
+		} else {
+			records = append(records, header(exportHeaderResponse, exportHeaderUsername, exportHeaderDisplayName))
+		}
+		for _, r := range p.Responses {
+			record := []string{r.Text}
+			if !p.Settings.Anonymous {
+				username, displayName, appErr := convert(r.UserID)
+				if appErr != nil {
+					return nil, appErr
+				}
+				record = append(record, username, displayName)
+			}
+			records = append(records, record)
+		}
+		return encodeCSV(records)
+	}
+
+	countHeader := exportHeaderVotes
+	var detailHeader *i18n.Message
+	switch {
+	case p.IsRanked():
+		countHeader, detailHeader = exportHeaderFirstChoices, exportHeaderRank
+	case p.HasPointBudget():
+		countHeader, detailHeader = exportHeaderPoints, exportHeaderPoints
+	case p.IsScheduling():
+		countHeader, detailHeader = exportHeaderYesAnswers, exportHeaderAvailability
+	}
+
+	if p.Settings.Anonymous {
+		records = append(records, header(exportHeaderOption, countHeader))
+	} else {
+		messages := []*i18n.Message{exportHeaderOption, countHeader, exportHeaderUsername, exportHeaderDisplayName}
+		if detailHeader != nil {
+			messages = append(messages, detailHeader)
+		}
+		messages = append(messages, exportHeaderVotedAt)
+		if p.Settings.Proxy {
+			messages = append(messages, exportHeaderProxy)
+		}
+		records = append(records, header(messages...))
+	}
+
+	unknownVotedAt := bundle.LocalizeDefaultMessage(localizer, exportVotedAtUnknown)
+	counts := p.countProgress()
+	for i, o := range p.AnswerOptions {
+		count := strconv.Itoa(counts[i])
+		if p.Settings.Anonymous || len(o.Voter) == 0 {
+			// Options without voters leave the voter columns empty
+			record := make([]string, len(records[0]))
+			record[0], record[1] = o.Answer, count
+			records = append(records, record)
+			continue
+		}
+		for _, userID := range o.Voter {
+			username, displayName, appErr := convert(userID)
+			if appErr != nil {
+				return nil, appErr
+			}
+			record := []string{o.Answer, count, username, displayName}
+			switch {
+			case p.IsRanked():
+				record = append(record, strconv.Itoa(o.Ranks[userID]))
+			case p.HasPointBudget():
+				record = append(record, strconv.Itoa(o.Points[userID]))
+			case p.IsScheduling():
+				record = append(record, string(o.Availability[userID]))
+			}
+			record = append(record, formatVotedAt(o.VotedAt[userID], unknownVotedAt))
+			if p.Settings.Proxy {
+				// The username of the user, who cast the vote on behalf of the voter
+				var proxyName string
+				if proxyID := o.Proxies[userID]; proxyID != "" {
+					if proxyName, _, appErr = convert(proxyID); appErr != nil {
+						return nil, appErr
+					}
+				}
+				record = append(record, proxyName)
+			}
+			records = append(records, record)
+		}
+	}
+	return encodeCSV(records)
+}
+
+// formatVotedAt returns a vote time in milliseconds as RFC 3339 timestamp.
+// Votes cast before vote times were recorded get unknown instead.
+func formatVotedAt(millis int64, unknown string) string {
+	if millis == 0 {
+		return unknown
+	}
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+// encodeCSV writes records as CSV.
+// Cells that spreadsheet applications would evaluate as formula, e.g. an answer option like "=HYPERLINK(...)", are prefixed with a '.
+func encodeCSV(records [][]string) ([]byte, error) {
+	for _, record := range records {
+		for i, cell := range record {
+			if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+				record[i] = "'" + cell
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,170 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestPollToCSV(t *testing.T) {
+	converter := func(userID string) (string, string, *model.AppError) {
+		return "user" + userID[len(userID)-1:], "User " + userID[len(userID)-1:], nil
+	}
+
+	for name, test := range map[string]struct {
+		Poll        *poll.Poll
+		ExpectedCSV string
+	}{
+		"Normal poll": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotes()
+				p.AnswerOptions[0].VotedAt = map[string]int64{"userID1": 0, "userID2": 1234567890000, "userID3": 1234567890000}
+				return p
+			}(),
+			ExpectedCSV: "Option,Votes,Username,Display name,Voted at\n" +
+				"Answer 1,3,user1,User 1,unknown\n" +
+				"Answer 1,3,user2,User 2,2009-02-13T23:31:30Z\n" +
+				"Answer 1,3,user3,User 3,2009-02-13T23:31:30Z\n" +
+				"Answer 2,1,user4,User 4,unknown\n" +
+				"Answer 3,0,,,\n",
+		},
+		"Poll with proxy votes": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+				p.AnswerOptions[0].Proxies = map[string]string{"userID2": "userID1"}
+				return p
+			}(),
+			ExpectedCSV: "Option,Votes,Username,Display name,Voted at,Proxy\n" +
+				"Answer 1,3,user1,User 1,unknown,\n" +
+				"Answer 1,3,user2,User 2,unknown,user1\n" +
+				"Answer 1,3,user3,User 3,unknown,\n" +
+				"Answer 2,1,user4,User 4,unknown,\n" +
+				"Answer 3,0,,,,\n",
+		},
+		"Anonymous poll": {
+			Poll: testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true}),
+			ExpectedCSV: "Option,Votes\n" +
+				"Answer 1,3\n" +
+				"Answer 2,1\n" +
+				"Answer 3,0\n",
+		},
+		"Ranked poll": {
+			Poll: testutils.GetRankedPollWithBallots(),
+			ExpectedCSV: "Option,First choices,Username,Display name,Rank,Voted at\n" +
+				"Answer 1,2,user1,User 1,1,unknown\n" +
+				"Answer 1,2,user2,User 2,2,unknown\n" +
+				"Answer 1,2,user4,User 4,1,unknown\n" +
+				"Answer 2,1,user1,User 1,2,unknown\n" +
+				"Answer 2,1,user2,User 2,1,unknown\n" +
+				"Answer 2,1,user3,User 3,2,unknown\n" +
+				"Answer 3,1,user3,User 3,1,unknown\n" +
+				"Answer 3,1,user4,User 4,2,unknown\n",
+		},
+		"Points poll": {
+			Poll: testutils.GetPointsPollWithVotes(),
+			ExpectedCSV: "Option,Points,Username,Display name,Points,Voted at\n" +
+				"Answer 1,4,user1,User 1,3,unknown\n" +
+				"Answer 1,4,user2,User 2,1,unknown\n" +
+				"Answer 2,2,user1,User 1,2,unknown\n" +
+				"Answer 3,0,,,,\n",
+		},
+		"Scheduling poll": {
+			Poll: testutils.GetSchedulingPollWithVotes(),
+			ExpectedCSV: "Option,Yes answers,Username,Display name,Availability,Voted at\n" +
+				"Answer 1,1,user1,User 1,yes,unknown\n" +
+				"Answer 1,1,user2,User 2,no,unknown\n" +
+				"Answer 2,2,user1,User 1,yes,unknown\n" +
+				"Answer 2,2,user2,User 2,yes,unknown\n" +
+				"Answer 3,0,user1,User 1,maybe,unknown\n" +
+				"Answer 3,0,user2,User 2,maybe,unknown\n",
+		},
+		"Free-text poll": {
+			Poll: testutils.GetFreeTextPollWithResponses(),
+			ExpectedCSV: "Response,Username,Display name\n" +
+				"First response,user1,User 1\n" +
+				"Second response,user2,User 2\n",
+		},
+		"Anonymous free-text poll": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetFreeTextPollWithResponses()
+				p.Settings.Anonymous = true
+				return p
+			}(),
+			ExpectedCSV: "Response\n" +
+				"First response\n" +
+				"Second response\n",
+		},
+		"Answers with commas and quotes": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true})
+				p.AnswerOptions[0].Answer = `Yes, "definitely"`
+				return p
+			}(),
+			ExpectedCSV: "Option,Votes\n" +
+				`"Yes, ""definitely""",3` + "\n" +
+				"Answer 2,1\n" +
+				"Answer 3,0\n",
+		},
+		"Answers that look like formulas": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true})
+				p.AnswerOptions[0].Answer = `=HYPERLINK("https://example.com")`
+				p.AnswerOptions[1].Answer = "+1"
+				p.AnswerOptions[2].Answer = "@here"
+				return p
+			}(),
+			ExpectedCSV: "Option,Votes\n" +
+				`"'=HYPERLINK(""https://example.com"")",3` + "\n" +
+				"'+1,1\n" +
+				"'@here,0\n",
+		},
+		"Free-text response that looks like a formula": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetFreeTextPollWithResponses()
+				p.Settings.Anonymous = true
+				p.Responses[0].Text = "-2+3"
+				return p
+			}(),
+			ExpectedCSV: "Response\n" +
+				"'-2+3\n" +
+				"Second response\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := test.Poll.ToCSV(testutils.GetBundle(), converter)
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedCSV, string(b))
+		})
+	}
+
+	t.Run("anonymous poll doesn't resolve voters", func(t *testing.T) {
+		converter := func(_ string) (string, string, *model.AppError) {
+			require.Fail(t, "converter must not be called")
+			return "", "", nil
+		}
+		b, err := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true}).ToCSV(testutils.GetBundle(), converter)
+		require.NoError(t, err)
+		assert.NotEmpty(t, b)
+	})
+	t.Run("converter fails", func(t *testing.T) {
+		converter := func(_ string) (string, string, *model.AppError) {
+			return "", "", &model.AppError{}
+		}
+		b, err := testutils.GetPollWithVotes().ToCSV(testutils.GetBundle(), converter)
+		assert.Error(t, err)
+		assert.Nil(t, b)
+	})
+	t.Run("survey", func(t *testing.T) {
+		p := testutils.GetSurveyWithAnswers()
+		assert.False(t, p.CanExport())
+
+		b, err := p.ToCSV(testutils.GetBundle(), converter)
+		assert.Error(t, err)
+		assert.Nil(t, b)
+	})
+}
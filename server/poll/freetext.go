@@ -0,0 +1,67 @@
+package poll
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// Response is the answer of a user to a free-text poll.
+type Response struct {
+	UserID string `json:"user_id"`
+	Text   string `json:"text"`
+}
+
+// IsFreeText returns true if voters type their answer instead of choosing an answer option
+func (p *Poll) IsFreeText() bool {
+	return p.Settings.FreeText
+}
+
+// GetResponse returns the response of a given user to a free-text poll. It returns an empty string if the user didn't respond yet.
+func (p *Poll) GetResponse(userID string) string {
+	for _, r := range p.Responses {
+		if r.UserID == userID {
+			return r.Text
+		}
+	}
+	return ""
+}
+
+// SetResponse stores the response of a given user to a free-text poll.
+// An existing response of the user is replaced, but keeps its position.
+func (p *Poll) SetResponse(userID, text string) (*i18n.Message, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid userID")
+	}
+	if !p.IsFreeText() {
+		return nil, fmt.Errorf("poll isn't a free-text poll")
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return &i18n.Message{
+			ID:    "poll.setResponse.empty",
+			Other: "Please enter an answer.",
+		}, nil
+	}
+
+	for _, r := range p.Responses {
+		if r.UserID == userID {
+			r.Text = text
+			return nil, nil
+		}
+	}
+	p.Responses = append(p.Responses, &Response{UserID: userID, Text: text})
+	return nil, nil
+}
+
+// removeResponse removes the response of a given user from a free-text poll.
+func (p *Poll) removeResponse(userID string) {
+	for i, r := range p.Responses {
+		if r.UserID == userID {
+			p.Responses = append(p.Responses[:i], p.Responses[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestSetResponse(t *testing.T) {
+	for name, test := range map[string]struct {
+		Poll              *poll.Poll
+		UserID            string
+		Text              string
+		ExpectedResponses []*poll.Response
+		Error             bool
+		ReturnMessage     bool
+	}{
+		"first response": {
+			Poll:   testutils.GetFreeTextPollWithResponses(),
+			UserID: "userID3",
+			Text:   " Third response ",
+			ExpectedResponses: []*poll.Response{
+				{UserID: "userID1", Text: "First response"},
+				{UserID: "userID2", Text: "Second response"},
+				{UserID: "userID3", Text: "Third response"},
+			},
+		},
+		"replace response": {
+			Poll:   testutils.GetFreeTextPollWithResponses(),
+			UserID: "userID1",
+			Text:   "Changed response",
+			ExpectedResponses: []*poll.Response{
+				{UserID: "userID1", Text: "Changed response"},
+				{UserID: "userID2", Text: "Second response"},
+			},
+		},
+		"empty response": {
+			Poll:              testutils.GetFreeTextPollWithResponses(),
+			UserID:            "userID1",
+			Text:              "  ",
+			ExpectedResponses: testutils.GetFreeTextPollWithResponses().Responses,
+			ReturnMessage:     true,
+		},
+		"invalid user id": {
+			Poll:              testutils.GetFreeTextPollWithResponses(),
+			UserID:            "",
+			Text:              "Response",
+			ExpectedResponses: testutils.GetFreeTextPollWithResponses().Responses,
+			Error:             true,
+		},
+		"no free-text poll": {
+			Poll:   testutils.GetPoll(),
+			UserID: "userID1",
+			Text:   "Response",
+			Error:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			msg, err := test.Poll.SetResponse(test.UserID, test.Text)
+
+			if test.Error {
+				assert.NotNil(err)
+			} else {
+				assert.Nil(err)
+			}
+
+			if test.ReturnMessage {
+				assert.NotNil(msg)
+			} else {
+				assert.Nil(msg)
+			}
+			assert.Equal(test.ExpectedResponses, test.Poll.Responses)
+		})
+	}
+}
+
+func TestGetResponse(t *testing.T) {
+	p := testutils.GetFreeTextPollWithResponses()
+
+	assert.Equal(t, "First response", p.GetResponse("userID1"))
+	assert.Equal(t, "Second response", p.GetResponse("userID2"))
+	assert.Equal(t, "", p.GetResponse("userID3"))
+	assert.Equal(t, []string{"Second response"}, p.GetVotedAnswers("userID2"))
+	assert.Equal(t, []string{}, p.GetVotedAnswers("userID3"))
+	assert.True(t, p.HasVoted("userID1"))
+	assert.False(t, p.HasVoted("userID3"))
+}
+
+func TestResetResponse(t *testing.T) {
+	p := testutils.GetFreeTextPollWithResponses()
+
+	p.ResetVotes("userID1")
+	assert.Equal(t, []*poll.Response{{UserID: "userID2", Text: "Second response"}}, p.Responses)
+}
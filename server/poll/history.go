@@ -0,0 +1,143 @@
+package poll
+
+import (
+	"fmt"
+	"slices"
+)
+
+// VoteEventType is the kind of change a user made to their votes.
+type VoteEventType string
+
+const (
+	// VoteEventVote is recorded when a user votes for the first time or after they reset their votes.
+	VoteEventVote VoteEventType = "vote"
+	// VoteEventChange is recorded when a user changes their votes.
+	VoteEventChange VoteEventType = "change"
+	// VoteEventReset is recorded when a user resets their votes.
+	VoteEventReset VoteEventType = "reset"
+)
+
+// VoteEvent is an entry of the vote history of a poll.
+type VoteEvent struct {
+	Type   VoteEventType `json:"type"`
+	UserID string        `json:"user_id"`
+	// Answers are the answers of the user after the event. It's empty for resets.
+	Answers []string `json:"answers,omitempty"`
+	// At is the time of the event in milliseconds.
+	At int64 `json:"at"`
+	// ProxyID is the user, who changed the answers on behalf of the user. It's empty if the user changed them by themselves.
+	ProxyID string `json:"proxy_id,omitempty"`
+}
+
+// VoteHistory is the list of vote events of a poll in the order they happened.
+type VoteHistory []*VoteEvent
+
+// HasVoteHistory returns true if the vote history of the poll is recorded.
+// Anonymous polls don't record it, because it would tell who voted for what.
+// For the same reason, the history of a survey leaves out the anonymous questions.
+func (p *Poll) HasVoteHistory() bool {
+	return !p.Settings.Anonymous
+}
+
+// NewVoteEvent returns the event, that changed the answers of a user from before to after.
+// before and after are the answers as returned by GetVoteSummary. Returns nil if the answers didn't change.
+func NewVoteEvent(userID string, before, after []string, at int64) *VoteEvent {
+	if slices.Equal(before, after) {
+		return nil
+	}
+
+	event := &VoteEvent{
+		Type:    VoteEventChange,
+		UserID:  userID,
+		Answers: after,
+		At:      at,
+	}
+	switch {
+	case len(after) == 0:
+		event.Type = VoteEventReset
+		event.Answers = nil
+	case len(before) == 0:
+		event.Type = VoteEventVote
+	}
+	return event
+}
+
+// GetVoteSummary returns the answers of a given user in a form, that shows every change of their votes.
+// In contrast to GetVotedAnswers it includes the response to free-text polls, the points of point-budget polls,
+// every answer of scheduling polls and the answers to every question of a survey, that isn't anonymous.
+func (p *Poll) GetVoteSummary(userID string) []string {
+	summary := []string{}
+	switch {
+	case p.IsSurvey():
+		answers := p.GetSurveyAnswers(userID)
+		for i, q := range p.Questions {
+			if q.Settings.Anonymous {
+				continue
+			}
+			for _, j := range answers[i] {
+				summary = append(summary, fmt.Sprintf("%s: %s", q.Question, q.AnswerOptions[j].Answer))
+			}
+		}
+	case p.IsFreeText():
+		if r := p.GetResponse(userID); r != "" {
+			summary = append(summary, r)
+		}
+	case p.HasPointBudget():
+		for _, o := range p.AnswerOptions {
+			if points := o.Points[userID]; points > 0 {
+				summary = append(summary, fmt.Sprintf("%s (%d)", o.Answer, points))
+			}
+		}
+	case p.IsScheduling():
+		for _, o := range p.AnswerOptions {
+			if a, ok := o.Availability[userID]; ok {
+				summary = append(summary, fmt.Sprintf("%s (%s)", o.Answer, a))
+			}
+		}
+	default:
+		summary = p.GetVotedAnswers(userID)
+	}
+	return summary
+}
+
+// UpdateVoteTimes records the given time for the votes, that a user cast since prev,
+// and forgets the times of the votes they removed. Votes of polls without a vote history have no time.
+// Votes cast before vote times were recorded keep having no time.
+func (p *Poll) UpdateVoteTimes(prev *Poll, userID string, now int64) {
+	prevOptions := prev.timedAnswerOptions()
+	for i, o := range p.timedAnswerOptions() {
+		votedBefore := i < len(prevOptions) && prevOptions[i].Answer == o.Answer && slices.Contains(prevOptions[i].Voter, userID)
+		o.updateVoteTime(userID, now, votedBefore)
+	}
+}
+
+// timedAnswerOptions returns the answer options, whose votes have a time.
+func (p *Poll) timedAnswerOptions() []*AnswerOption {
+	if !p.HasVoteHistory() {
+		return nil
+	}
+	options := slices.Clone(p.AnswerOptions)
+	for _, q := range p.Questions {
+		if !q.Settings.Anonymous {
+			options = append(options, q.AnswerOptions...)
+		}
+	}
+	return options
+}
+
+func (o *AnswerOption) updateVoteTime(userID string, now int64, votedBefore bool) {
+	if !slices.Contains(o.Voter, userID) {
+		delete(o.VotedAt, userID)
+		if len(o.VotedAt) == 0 {
+			o.VotedAt = nil
+		}
+		return
+	}
+	if _, ok := o.VotedAt[userID]; ok || votedBefore {
+		return
+	}
+	if o.VotedAt == nil {
+		o.VotedAt = map[string]int64{}
+	}
+	o.VotedAt[userID] = now
+}
@@ -0,0 +1,151 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestHasVoteHistory(t *testing.T) {
+	assert.True(t, testutils.GetPoll().HasVoteHistory())
+	assert.False(t, testutils.GetPollWithSettings(poll.Settings{Anonymous: true}).HasVoteHistory())
+}
+
+func TestNewVoteEvent(t *testing.T) {
+	at := testutils.GetMillis()
+	for name, test := range map[string]struct {
+		Before   []string
+		After    []string
+		Expected *poll.VoteEvent
+	}{
+		"vote": {
+			Before:   []string{},
+			After:    []string{"Answer 1"},
+			Expected: &poll.VoteEvent{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1"}, At: at},
+		},
+		"change": {
+			Before:   []string{"Answer 1"},
+			After:    []string{"Answer 1", "Answer 2"},
+			Expected: &poll.VoteEvent{Type: poll.VoteEventChange, UserID: "userID1", Answers: []string{"Answer 1", "Answer 2"}, At: at},
+		},
+		"reset": {
+			Before:   []string{"Answer 1"},
+			After:    []string{},
+			Expected: &poll.VoteEvent{Type: poll.VoteEventReset, UserID: "userID1", At: at},
+		},
+		"no change": {
+			Before:   []string{"Answer 1"},
+			After:    []string{"Answer 1"},
+			Expected: nil,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, poll.NewVoteEvent("userID1", test.Before, test.After, at))
+		})
+	}
+}
+
+func TestGetVoteSummary(t *testing.T) {
+	for name, test := range map[string]struct {
+		Poll     *poll.Poll
+		Expected []string
+	}{
+		"poll": {
+			Poll:     testutils.GetPollWithVotes(),
+			Expected: []string{"Answer 1"},
+		},
+		"free-text poll": {
+			Poll:     testutils.GetFreeTextPollWithResponses(),
+			Expected: []string{"First response"},
+		},
+		"point-budget poll": {
+			Poll:     testutils.GetPointsPollWithVotes(),
+			Expected: []string{"Answer 1 (3)", "Answer 2 (2)"},
+		},
+		"scheduling poll": {
+			Poll:     testutils.GetSchedulingPollWithVotes(),
+			Expected: []string{"Answer 1 (yes)", "Answer 2 (yes)", "Answer 3 (maybe)"},
+		},
+		"survey leaves out anonymous questions": {
+			Poll:     testutils.GetSurveyWithAnswers(),
+			Expected: []string{"Question 1: Answer 1"},
+		},
+		"no votes": {
+			Poll:     testutils.GetPoll(),
+			Expected: []string{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, test.Poll.GetVoteSummary("userID1"))
+		})
+	}
+}
+
+func TestUpdateVoteTimes(t *testing.T) {
+	t.Run("new vote", func(t *testing.T) {
+		p := testutils.GetPoll()
+		prev := p.Copy()
+		msg, err := p.UpdateVote("userID1", 1)
+		require.Nil(t, msg)
+		require.NoError(t, err)
+
+		p.UpdateVoteTimes(prev, "userID1", 100)
+		assert.Nil(t, p.AnswerOptions[0].VotedAt)
+		assert.Equal(t, map[string]int64{"userID1": 100}, p.AnswerOptions[1].VotedAt)
+	})
+	t.Run("changed vote keeps the time of unchanged votes", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 2})
+		prev := p.Copy()
+		_, _ = p.UpdateVote("userID1", 0)
+		p.UpdateVoteTimes(prev, "userID1", 100)
+		prev = p.Copy()
+		_, _ = p.UpdateVote("userID1", 1)
+		p.UpdateVoteTimes(prev, "userID1", 200)
+
+		assert.Equal(t, map[string]int64{"userID1": 100}, p.AnswerOptions[0].VotedAt)
+		assert.Equal(t, map[string]int64{"userID1": 200}, p.AnswerOptions[1].VotedAt)
+	})
+	t.Run("votes cast before vote times were recorded", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 2})
+		prev := p.Copy()
+		_, _ = p.UpdateVote("userID1", 1)
+		p.UpdateVoteTimes(prev, "userID1", 100)
+
+		assert.Nil(t, p.AnswerOptions[0].VotedAt)
+		assert.Equal(t, map[string]int64{"userID1": 100}, p.AnswerOptions[1].VotedAt)
+	})
+	t.Run("reset", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		p.UpdateVoteTimes(testutils.GetPoll(), "userID1", 100)
+		p.UpdateVoteTimes(testutils.GetPoll(), "userID2", 100)
+		prev := p.Copy()
+		p.ResetVotes("userID1")
+
+		p.UpdateVoteTimes(prev, "userID1", 200)
+		assert.Equal(t, map[string]int64{"userID2": 100}, p.AnswerOptions[0].VotedAt)
+	})
+	t.Run("survey", func(t *testing.T) {
+		p := testutils.GetSurveyWithAnswers()
+		prev := p.Copy()
+		for _, q := range prev.Questions {
+			for _, o := range q.AnswerOptions {
+				o.Voter = nil
+			}
+		}
+		p.UpdateVoteTimes(prev, "userID1", 100)
+
+		assert.Equal(t, map[string]int64{"userID1": 100}, p.Questions[0].AnswerOptions[0].VotedAt)
+		assert.Nil(t, p.Questions[0].AnswerOptions[1].VotedAt)
+		assert.Nil(t, p.Questions[1].AnswerOptions[0].VotedAt)
+	})
+	t.Run("anonymous poll", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{Anonymous: true, MaxVotes: 1})
+		p.UpdateVoteTimes(testutils.GetPollWithSettings(poll.Settings{Anonymous: true, MaxVotes: 1}), "userID1", 100)
+
+		assert.Nil(t, p.AnswerOptions[0].VotedAt)
+	})
+}
@@ -18,12 +18,14 @@ func TestToMap(t *testing.T) {
 	}
 
 	expectedMap := map[string]interface{}{
-		"voted_answers":             []string(nil),
-		"poll_id":                   "pollID",
-		"user_id":                   "userID",
-		"can_manage_poll":           true,
-		"setting_progress":          true,
-		"setting_public_add_option": true,
+		"voted_answers":              []string(nil),
+		"poll_id":                    "pollID",
+		"user_id":                    "userID",
+		"can_manage_poll":            true,
+		"setting_progress":           true,
+		"setting_public_add_option":  true,
+		"setting_results_after_vote": false,
+		"results":                    []int(nil),
 	}
 	assert.Equal(t, expectedMap, m.ToMap())
 }
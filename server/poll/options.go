@@ -0,0 +1,114 @@
+package poll
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// OptionChange describes how an existing answer option is changed by ManageAnswerOptions.
+type OptionChange struct {
+	// Answer is the new text of the answer option. An empty answer deletes the option together with its votes.
+	Answer string
+	// Position is the new position of the answer option, starting at 1.
+	// Options with the same position keep their previous order.
+	Position int
+}
+
+// ChangedAnswerOption describes an answer option, that was renamed or deleted by ManageAnswerOptions.
+type ChangedAnswerOption struct {
+	Answer string
+	// NewAnswer is the new text of a renamed answer option. It's empty if the option was deleted.
+	NewAnswer string
+	// Voters are the users, who voted for the answer option. It's empty for secret ballots.
+	Voters []string
+}
+
+// ManageAnswerOptions renames, deletes and reorders the answer options of a poll.
+// changes must contain a change for every answer option, in the current order of the options.
+// Votes for renamed or moved options are kept, votes for deleted options are removed.
+// It returns the renamed and deleted options, so that their voters can be notified.
+// If the resulting poll is invalid, a message is returned and the poll is left unchanged.
+func (p *Poll) ManageAnswerOptions(changes []OptionChange) ([]*ChangedAnswerOption, *utils.ErrorMessage, error) {
+	if len(changes) != len(p.AnswerOptions) {
+		return nil, nil, fmt.Errorf("invalid number of changes")
+	}
+
+	kept := []int{}
+	seen := map[string]bool{}
+	for i, c := range changes {
+		if c.Position < 1 || c.Position > len(p.AnswerOptions) {
+			return nil, nil, fmt.Errorf("invalid position")
+		}
+		answer := strings.TrimSpace(c.Answer)
+		if answer == "" {
+			continue
+		}
+		if seen[answer] {
+			return nil, newDuplicateAnswerOptionError(answer), nil
+		}
+		seen[answer] = true
+		kept = append(kept, i)
+	}
+	if len(kept) == 0 {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.manageAnswerOptions.noOptions",
+				Other: "A poll needs at least one option.",
+			},
+		}, nil
+	}
+	sort.SliceStable(kept, func(a, b int) bool {
+		return changes[kept[a]].Position < changes[kept[b]].Position
+	})
+
+	prev := p.Copy()
+	changed := []*ChangedAnswerOption{}
+	newIndexes := map[int]int{}
+	options := make([]*AnswerOption, 0, len(kept))
+	for newIndex, oldIndex := range kept {
+		o := p.AnswerOptions[oldIndex]
+		answer := strings.TrimSpace(changes[oldIndex].Answer)
+		if answer != o.Answer {
+			changed = append(changed, &ChangedAnswerOption{Answer: o.Answer, NewAnswer: answer, Voters: o.Voter})
+			o.Answer = answer
+		}
+		newIndexes[oldIndex] = newIndex
+		options = append(options, o)
+	}
+	for i, o := range p.AnswerOptions {
+		if _, ok := newIndexes[i]; !ok {
+			changed = append(changed, &ChangedAnswerOption{Answer: o.Answer, Voters: o.Voter})
+		}
+	}
+	p.AnswerOptions = options
+	p.remapBallots(newIndexes)
+
+	if errMsg := p.validate(); errMsg != nil {
+		*p = *prev
+		return nil, errMsg, nil
+	}
+	return changed, nil, nil
+}
+
+// remapBallots moves the votes of a secret ballot to the new indexes of the answer options.
+// Votes for options without a new index are removed.
+func (p *Poll) remapBallots(newIndexes map[int]int) {
+	for token, indexes := range p.Ballots {
+		ballot := []int{}
+		for _, index := range indexes {
+			if newIndex, ok := newIndexes[index]; ok {
+				ballot = append(ballot, newIndex)
+			}
+		}
+		if len(ballot) == 0 {
+			delete(p.Ballots, token)
+			continue
+		}
+		p.Ballots[token] = ballot
+	}
+}
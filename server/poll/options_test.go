@@ -0,0 +1,148 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestManageAnswerOptions(t *testing.T) {
+	t.Run("rename", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "Answer 1", Position: 1},
+			{Answer: " New Answer 2 ", Position: 2},
+			{Answer: "Answer 3", Position: 3},
+		})
+		require.Nil(t, err)
+		require.Nil(t, errMsg)
+
+		assert.Equal([]*poll.ChangedAnswerOption{
+			{Answer: "Answer 2", NewAnswer: "New Answer 2", Voters: []string{"userID4"}},
+		}, changed)
+		assert.Equal("New Answer 2", p.AnswerOptions[1].Answer)
+		assert.Equal([]string{"userID4"}, p.AnswerOptions[1].Voter)
+	})
+	t.Run("delete", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "", Position: 1},
+			{Answer: "Answer 2", Position: 2},
+			{Answer: "Answer 3", Position: 3},
+		})
+		require.Nil(t, err)
+		require.Nil(t, errMsg)
+
+		assert.Equal([]*poll.ChangedAnswerOption{
+			{Answer: "Answer 1", Voters: []string{"userID1", "userID2", "userID3"}},
+		}, changed)
+		require.Len(t, p.AnswerOptions, 2)
+		assert.Equal("Answer 2", p.AnswerOptions[0].Answer)
+		assert.Equal("Answer 3", p.AnswerOptions[1].Answer)
+		assert.False(p.HasVoted("userID2"))
+		assert.True(p.HasVoted("userID4"))
+	})
+	t.Run("reorder", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "Answer 1", Position: 3},
+			{Answer: "Answer 2", Position: 1},
+			{Answer: "Answer 3", Position: 1},
+		})
+		require.Nil(t, err)
+		require.Nil(t, errMsg)
+
+		assert.Equal([]*poll.ChangedAnswerOption{}, changed)
+		require.Len(t, p.AnswerOptions, 3)
+		assert.Equal("Answer 2", p.AnswerOptions[0].Answer)
+		assert.Equal("Answer 3", p.AnswerOptions[1].Answer)
+		assert.Equal("Answer 1", p.AnswerOptions[2].Answer)
+		assert.Equal([]string{"Answer 1"}, p.GetVotedAnswers("userID1"))
+	})
+	t.Run("secret ballot", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 0})
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "", Position: 1},
+			{Answer: "Answer 2", Position: 2},
+			{Answer: "Answer 3", Position: 1},
+		})
+		require.Nil(t, err)
+		require.Nil(t, errMsg)
+
+		assert.Equal([]*poll.ChangedAnswerOption{{Answer: "Answer 1", Voters: []string{}}}, changed)
+		assert.Equal("Answer 3", p.AnswerOptions[0].Answer)
+		assert.Equal([]string{"Answer 2"}, p.GetVotedAnswers(p.VoterID(secretBallotKey, "userID4")))
+		assert.False(p.HasVoted(p.VoterID(secretBallotKey, "userID1")))
+		assert.Len(p.GetVoters(), 1)
+	})
+	t.Run("duplicate answer", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "Answer 1", Position: 1},
+			{Answer: "Answer 1", Position: 2},
+			{Answer: "Answer 3", Position: 3},
+		})
+		require.Nil(t, err)
+		require.NotNil(t, errMsg)
+		assert.Equal(t, "poll.addAnswerOption.duplicate", errMsg.Message.ID)
+		assert.Nil(t, changed)
+		assert.Equal(t, testutils.GetPollWithVotes(), p)
+	})
+	t.Run("no options left", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "", Position: 1},
+			{Answer: " ", Position: 2},
+			{Answer: "", Position: 3},
+		})
+		require.Nil(t, err)
+		require.NotNil(t, errMsg)
+		assert.Equal(t, "poll.manageAnswerOptions.noOptions", errMsg.Message.ID)
+		assert.Nil(t, changed)
+		assert.Equal(t, testutils.GetPollWithVotes(), p)
+	})
+	t.Run("invalid poll afterwards", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 3})
+		expected := p.Copy()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "Answer 1", Position: 1},
+			{Answer: "", Position: 2},
+			{Answer: "New Answer 3", Position: 3},
+		})
+		require.Nil(t, err)
+		require.NotNil(t, errMsg)
+		assert.Nil(t, changed)
+		assert.Equal(t, expected, p)
+	})
+	t.Run("invalid number of changes", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{{Answer: "Answer 1", Position: 1}})
+		assert.NotNil(t, err)
+		assert.Nil(t, errMsg)
+		assert.Nil(t, changed)
+	})
+	t.Run("invalid position", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		changed, errMsg, err := p.ManageAnswerOptions([]poll.OptionChange{
+			{Answer: "Answer 1", Position: 0},
+			{Answer: "Answer 2", Position: 2},
+			{Answer: "Answer 3", Position: 4},
+		})
+		assert.NotNil(t, err)
+		assert.Nil(t, errMsg)
+		assert.Nil(t, changed)
+		assert.Equal(t, testutils.GetPollWithVotes(), p)
+	})
+}
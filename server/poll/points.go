@@ -0,0 +1,119 @@
+package poll
+
+import (
+	"fmt"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// HasPointBudget returns true if voters spread a budget of points across the answer options
+func (p *Poll) HasPointBudget() bool {
+	return p.Settings.Points > 0
+}
+
+// TotalPoints returns the sum of all points spent on an answer option
+func (o *AnswerOption) TotalPoints() int {
+	total := 0
+	for _, points := range o.Points {
+		total += points
+	}
+	return total
+}
+
+// GetSpentPoints returns the number of points a given user has spent across all answer options
+func (p *Poll) GetSpentPoints(userID string) int {
+	spent := 0
+	for _, o := range p.AnswerOptions {
+		spent += o.Points[userID]
+	}
+	return spent
+}
+
+// GetRemainingPoints returns the number of points a given user has left
+func (p *Poll) GetRemainingPoints(userID string) int {
+	return p.Settings.Points - p.GetSpentPoints(userID)
+}
+
+// addPoint spends one point of a given user on the answer option with the given index.
+func (p *Poll) addPoint(userID string, index int) *i18n.Message {
+	if p.GetRemainingPoints(userID) <= 0 {
+		return &i18n.Message{
+			ID:    "poll.updateVote.noPointsLeft",
+			Other: "You don't have any points left. Use the Edit Your Points button to move points between options.",
+		}
+	}
+
+	p.setPoints(userID, index, p.AnswerOptions[index].Points[userID]+1)
+	return nil
+}
+
+// SetPoints replaces the points a given user spent with a new distribution.
+// points contains the number of points per answer option and must have an entry for every answer option.
+func (p *Poll) SetPoints(userID string, points []int) (*utils.ErrorMessage, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid userID")
+	}
+	if len(points) != len(p.AnswerOptions) {
+		return nil, fmt.Errorf("invalid number of points")
+	}
+
+	total := 0
+	for _, n := range points {
+		if n < 0 {
+			return &utils.ErrorMessage{
+				Message: &i18n.Message{
+					ID:    "poll.setPoints.negative",
+					Other: "The number of points can't be negative.",
+				},
+			}, nil
+		}
+		total += n
+	}
+	if total > p.Settings.Points {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.setPoints.exceedsBudget",
+				Other: "You spent {{.Spent}} points, but only have {{.Points}}.",
+			},
+			Data: map[string]interface{}{
+				"Spent":  total,
+				"Points": p.Settings.Points,
+			},
+		}, nil
+	}
+
+	for i, n := range points {
+		p.setPoints(userID, i, n)
+	}
+	return nil, nil
+}
+
+// setPoints sets the points a given user spent on the answer option with the given index
+// and keeps the voters of the answer option in sync.
+func (p *Poll) setPoints(userID string, index, points int) {
+	o := p.AnswerOptions[index]
+	_, voted := o.Points[userID]
+
+	if points == 0 {
+		if voted {
+			delete(o.Points, userID)
+			for i := 0; i < len(o.Voter); i++ {
+				if o.Voter[i] == userID {
+					o.Voter = append(o.Voter[:i], o.Voter[i+1:]...)
+					break
+				}
+			}
+		}
+		return
+	}
+
+	if !voted {
+		o.Voter = append(o.Voter, userID)
+	}
+	if o.Points == nil {
+		o.Points = map[string]int{}
+	}
+	o.Points[userID] = points
+}
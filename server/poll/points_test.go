@@ -0,0 +1,150 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestUpdateVotePoints(t *testing.T) {
+	for name, test := range map[string]struct {
+		UserID        string
+		Index         int
+		ExpectedPoll  func() *poll.Poll
+		ReturnMessage bool
+	}{
+		"first point": {
+			UserID: "userID3",
+			Index:  2,
+			ExpectedPoll: func() *poll.Poll {
+				p := testutils.GetPointsPollWithVotes()
+				p.AnswerOptions[2].Voter = []string{"userID3"}
+				p.AnswerOptions[2].Points = map[string]int{"userID3": 1}
+				return p
+			},
+		},
+		"second point on the same option": {
+			UserID: "userID2",
+			Index:  0,
+			ExpectedPoll: func() *poll.Poll {
+				p := testutils.GetPointsPollWithVotes()
+				p.AnswerOptions[0].Points["userID2"] = 2
+				return p
+			},
+		},
+		"no points left": {
+			UserID:        "userID1",
+			Index:         2,
+			ExpectedPoll:  testutils.GetPointsPollWithVotes,
+			ReturnMessage: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			p := testutils.GetPointsPollWithVotes()
+
+			msg, err := p.UpdateVote(test.UserID, test.Index)
+
+			assert.Nil(err)
+			if test.ReturnMessage {
+				assert.NotNil(msg)
+			} else {
+				assert.Nil(msg)
+			}
+			assert.Equal(test.ExpectedPoll(), p)
+		})
+	}
+}
+
+func TestSetPoints(t *testing.T) {
+	for name, test := range map[string]struct {
+		UserID        string
+		Points        []int
+		ExpectedPoll  func() *poll.Poll
+		Error         bool
+		ReturnMessage bool
+	}{
+		"move points": {
+			UserID: "userID1",
+			Points: []int{0, 1, 4},
+			ExpectedPoll: func() *poll.Poll {
+				p := testutils.GetPointsPollWithVotes()
+				p.AnswerOptions[0].Voter = []string{"userID2"}
+				p.AnswerOptions[0].Points = map[string]int{"userID2": 1}
+				p.AnswerOptions[1].Points = map[string]int{"userID1": 1}
+				p.AnswerOptions[2].Voter = []string{"userID1"}
+				p.AnswerOptions[2].Points = map[string]int{"userID1": 4}
+				return p
+			},
+		},
+		"remove all points": {
+			UserID: "userID2",
+			Points: []int{0, 0, 0},
+			ExpectedPoll: func() *poll.Poll {
+				p := testutils.GetPointsPollWithVotes()
+				p.AnswerOptions[0].Voter = []string{"userID1"}
+				p.AnswerOptions[0].Points = map[string]int{"userID1": 3}
+				return p
+			},
+		},
+		"exceeds budget": {
+			UserID:        "userID2",
+			Points:        []int{3, 3, 0},
+			ExpectedPoll:  testutils.GetPointsPollWithVotes,
+			ReturnMessage: true,
+		},
+		"negative points": {
+			UserID:        "userID2",
+			Points:        []int{3, -1, 0},
+			ExpectedPoll:  testutils.GetPointsPollWithVotes,
+			ReturnMessage: true,
+		},
+		"missing option": {
+			UserID:       "userID2",
+			Points:       []int{1, 1},
+			ExpectedPoll: testutils.GetPointsPollWithVotes,
+			Error:        true,
+		},
+		"invalid user id": {
+			UserID:       "",
+			Points:       []int{1, 1, 1},
+			ExpectedPoll: testutils.GetPointsPollWithVotes,
+			Error:        true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			p := testutils.GetPointsPollWithVotes()
+
+			errMsg, err := p.SetPoints(test.UserID, test.Points)
+
+			if test.Error {
+				assert.NotNil(err)
+			} else {
+				assert.Nil(err)
+			}
+			if test.ReturnMessage {
+				assert.NotNil(errMsg)
+			} else {
+				assert.Nil(errMsg)
+			}
+			assert.Equal(test.ExpectedPoll(), p)
+		})
+	}
+}
+
+func TestPoints(t *testing.T) {
+	p := testutils.GetPointsPollWithVotes()
+
+	assert.True(t, p.HasPointBudget())
+	assert.False(t, testutils.GetPoll().HasPointBudget())
+	assert.Equal(t, 4, p.AnswerOptions[0].TotalPoints())
+	assert.Equal(t, 0, p.AnswerOptions[2].TotalPoints())
+	assert.Equal(t, 5, p.GetSpentPoints("userID1"))
+	assert.Equal(t, 0, p.GetRemainingPoints("userID1"))
+	assert.Equal(t, 4, p.GetRemainingPoints("userID2"))
+	assert.Equal(t, 5, p.GetRemainingPoints("userID3"))
+}
@@ -3,9 +3,12 @@ package poll
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 
@@ -14,13 +17,42 @@ import (
 	"github.com/matterpoll/matterpoll/server/utils"
 )
 
-var votesSettingPattern = regexp.MustCompile(`^votes=(\d+)$`)
+var (
+	votesSettingPattern  = regexp.MustCompile(`^votes=(\d+)$`)
+	endSettingPattern    = regexp.MustCompile(`^end=(.+)$`)
+	pointsSettingPattern = regexp.MustCompile(`^points=(\d+)$`)
+	closeAtPattern       = regexp.MustCompile(`^close-at=(\d+)$`)
+	tieBreakPattern      = regexp.MustCompile(`^tie-break=(.+)$`)
+	votersPattern        = regexp.MustCompile(`^voters=(.+)$`)
+	capacityPattern      = regexp.MustCompile(`^capacity=(\d+)$`)
+	daysPattern          = regexp.MustCompile(`^(\d+)d$`)
+)
+
+// localTimeLayouts are the layouts of end times without offset, e.g. "2024-12-24 18:00" or "2024-12-24T18:00".
+var localTimeLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02T15:04:05"}
 
 const (
-	SettingKeyAnonymous        = "anonymous"
-	SettingKeyAnonymousCreator = "anonymous-creator"
-	SettingKeyProgress         = "progress"
-	SettingKeyPublicAddOption  = "public-add-option"
+	SettingKeyAnonymous         = "anonymous"
+	SettingKeyAnonymousCreator  = "anonymous-creator"
+	SettingKeyProgress          = "progress"
+	SettingKeyPublicAddOption   = "public-add-option"
+	SettingKeyEnd               = "end"
+	SettingKeyRanked            = "ranked"
+	SettingKeyPoints            = "points"
+	SettingKeyScheduling        = "scheduling"
+	SettingKeyQuiz              = "quiz"
+	SettingKeyFreeText          = "free-text"
+	SettingKeyCloseAt           = "close-at"
+	SettingKeyCloseWhenAllVoted = "close-when-all-voted"
+	SettingKeyResultsAfterVote  = "results-after-vote"
+	SettingKeyFinal             = "final"
+	SettingKeyTieBreak          = "tie-break"
+	SettingKeyVoters            = "voters"
+	SettingKeyExcludeGuests     = "exclude-guests"
+	SettingKeyExcludeBots       = "exclude-bots"
+	SettingKeyCapacity          = "capacity"
+	SettingKeyWaitlist          = "waitlist"
+	SettingKeyProxy             = "proxy"
 )
 
 // Poll stores all needed information for a poll
@@ -33,13 +65,54 @@ type Poll struct {
 	Creator       string
 	Question      string
 	AnswerOptions []*AnswerOption
-	Settings      Settings
+	// Responses are the answers typed by the voters of a free-text poll, in the order they were submitted.
+	Responses []*Response `json:"responses,omitempty"`
+	// Questions are the questions of a survey. Question is used as the title of the survey then.
+	Questions []*SurveyQuestion `json:"questions,omitempty"`
+	Settings  Settings
+	// SecretBallot is true if the votes are stored without the ids of the voters, see secret.go.
+	SecretBallot bool `json:"secret_ballot,omitempty"`
+	// Ballots maps the voter tokens of a secret ballot to the indexes of the answer options they voted for.
+	Ballots map[string][]int `json:"ballots,omitempty"`
+	// EditedAt is the time in milliseconds at which the question or settings were last edited. 0 means the poll was never edited.
+	EditedAt int64 `json:"edited_at,omitempty"`
+	// EndedAt is the time in milliseconds at which the poll ended. 0 means the poll is still running or ended before the time was recorded.
+	EndedAt int64 `json:"ended_at,omitempty"`
+	// TieBreakWinner is the answer option the creator picked to break a tie, see result.go.
+	TieBreakWinner string `json:"tie_break_winner,omitempty"`
+	// Delegates maps the users, who delegated their vote, to the user allowed to vote for them, see proxy.go.
+	Delegates map[string]string `json:"delegates,omitempty"`
+
+	// ignoredVoters are the users, whose votes aren't part of the participation counts, see IgnoreVoters.
+	ignoredVoters map[string]bool
 }
 
 // AnswerOption stores a possible answer and a list of user who voted for this
 type AnswerOption struct {
 	Answer string
 	Voter  []string
+	// Ranks maps the users in Voter to the rank they gave this answer, starting at 1.
+	// It's only used by ranked-choice polls.
+	Ranks map[string]int `json:"ranks,omitempty"`
+	// Points maps the users in Voter to the number of points they spent on this answer.
+	// It's only used by point-budget polls.
+	Points map[string]int `json:"points,omitempty"`
+	// Availability maps the users in Voter to their answer for this option.
+	// It's only used by scheduling polls.
+	Availability map[string]Availability `json:"availability,omitempty"`
+	// Correct is true for the correct answers of a quiz.
+	Correct bool `json:"correct,omitempty"`
+	// Votes is the number of votes for this answer. It's only used by secret ballots, which keep Voter empty.
+	Votes int `json:"votes,omitempty"`
+	// VotedAt maps the users in Voter to the time in milliseconds at which they voted for this answer.
+	// 0 means that the vote was cast before vote times were recorded.
+	VotedAt map[string]int64 `json:"voted_at,omitempty"`
+	// Capacity is the maximum number of voters of this answer, e.g. the seats of a workshop. 0 means unlimited.
+	Capacity int `json:"capacity,omitempty"`
+	// Waitlist are the users waiting for a spot, once this answer is full, in the order they joined.
+	Waitlist []string `json:"waitlist,omitempty"`
+	// Proxies maps the users in Voter, whose vote was cast by someone else, to the user who cast it.
+	Proxies map[string]string `json:"proxies,omitempty"`
 }
 
 // Settings stores possible settings for a poll.
@@ -51,6 +124,44 @@ type Settings struct {
 	Progress         bool
 	PublicAddOption  bool
 	MaxVotes         int `json:"max_votes"`
+	// EndAt is the time in milliseconds at which the poll gets ended automatically.
+	// 0 means that the poll only ends when someone ends it manually.
+	EndAt int64 `json:"end_at,omitempty"`
+	// Ranked polls let voters rank the answer options and are tallied via instant-runoff.
+	Ranked bool `json:"ranked,omitempty"`
+	// Points is the number of points every voter can spread across the answer options.
+	// 0 means that the poll isn't a point-budget poll.
+	Points int `json:"points,omitempty"`
+	// Scheduling polls let voters answer yes, maybe or no for every option, e.g. to find a date.
+	Scheduling bool `json:"scheduling,omitempty"`
+	// Quiz polls have correct answers. Voters can answer only once and score on the leaderboard of the channel.
+	Quiz bool `json:"quiz,omitempty"`
+	// FreeText polls have no answer options. Voters type their answer instead.
+	FreeText bool `json:"free_text,omitempty"`
+	// CloseAt is the number of voters after which the poll gets ended automatically.
+	// 0 means that the number of voters doesn't end the poll.
+	CloseAt int `json:"close_at,omitempty"`
+	// CloseWhenAllVoted ends the poll automatically once every member of the channel has voted. Bots and deactivated users are ignored.
+	CloseWhenAllVoted bool `json:"close_when_all_voted,omitempty"`
+	// ResultsAfterVote hides the progress from the poll post. Every user gets to see it after they voted instead.
+	ResultsAfterVote bool `json:"results_after_vote,omitempty"`
+	// Final votes can't be changed or reset once they are cast.
+	Final bool `json:"final,omitempty"`
+	// TieBreak is the rule applied if several answer options tie for the win once the poll has ended.
+	TieBreak TieBreak `json:"tie_break,omitempty"`
+	// Voters restricts who is allowed to vote. nil means that everyone who can read the channel is allowed to vote.
+	Voters *Voters `json:"voters,omitempty"`
+	// ExcludeGuests blocks guest accounts from voting and ignores them in the participation counts.
+	ExcludeGuests bool `json:"exclude_guests,omitempty"`
+	// ExcludeBots blocks bots from voting and ignores them in the participation counts.
+	ExcludeBots bool `json:"exclude_bots,omitempty"`
+	// Capacity is the maximum number of voters of every answer option without an own capacity like "Slot A [5]".
+	// 0 means that the answer options are unlimited.
+	Capacity int `json:"capacity,omitempty"`
+	// Waitlist lets users queue up for full answer options. They get a spot automatically once someone leaves.
+	Waitlist bool `json:"waitlist,omitempty"`
+	// Proxy lets users delegate their vote and poll managers vote on behalf of others.
+	Proxy bool `json:"proxy,omitempty"`
 }
 
 // Factory is used to create a new [Poll].
@@ -97,10 +208,16 @@ func (fac *Factory) NewPoll(creator, question string, answerOptions []string, se
 		Settings:  settings,
 	}
 	for _, answerOption := range answerOptions {
+		correct := false
+		if p.IsQuiz() {
+			answerOption, correct = parseQuizAnswer(answerOption)
+		}
 		if errMsg := p.AddAnswerOption(answerOption); errMsg != nil {
 			return nil, errMsg
 		}
+		p.AnswerOptions[len(p.AnswerOptions)-1].Correct = correct
 	}
+	p.SecretBallot = p.supportsSecretBallot()
 
 	if errMsg := p.validate(); errMsg != nil {
 		return nil, errMsg
@@ -110,7 +227,8 @@ func (fac *Factory) NewPoll(creator, question string, answerOptions []string, se
 }
 
 // NewSettingsFromStrings creates a new settings with the given parameter.
-func NewSettingsFromStrings(strs []string) (Settings, *utils.ErrorMessage) {
+// now is the current time in milliseconds and is used to resolve relative settings like "--end=2h".
+func NewSettingsFromStrings(strs []string, now int64, loc *time.Location) (Settings, *utils.ErrorMessage) {
 	settings := Settings{MaxVotes: 1}
 	for _, str := range strs {
 		switch {
@@ -122,12 +240,90 @@ func NewSettingsFromStrings(strs []string) (Settings, *utils.ErrorMessage) {
 			settings.Progress = true
 		case str == SettingKeyPublicAddOption:
 			settings.PublicAddOption = true
+		case str == SettingKeyRanked:
+			settings.Ranked = true
+		case str == SettingKeyScheduling:
+			settings.Scheduling = true
+		case str == SettingKeyQuiz:
+			settings.Quiz = true
+		case str == SettingKeyFreeText:
+			settings.FreeText = true
+		case str == SettingKeyCloseWhenAllVoted:
+			settings.CloseWhenAllVoted = true
+		case str == SettingKeyResultsAfterVote:
+			settings.ResultsAfterVote = true
+		case str == SettingKeyFinal:
+			settings.Final = true
+		case str == SettingKeyExcludeGuests:
+			settings.ExcludeGuests = true
+		case str == SettingKeyExcludeBots:
+			settings.ExcludeBots = true
+		case str == SettingKeyWaitlist:
+			settings.Waitlist = true
+		case str == SettingKeyProxy:
+			settings.Proxy = true
 		case votesSettingPattern.MatchString(str):
 			i, errMsg := parseVotesSettings(str)
 			if errMsg != nil {
 				return settings, errMsg
 			}
 			settings.MaxVotes = i
+		case pointsSettingPattern.MatchString(str):
+			i, err := strconv.Atoi(pointsSettingPattern.FindStringSubmatch(str)[1])
+			if err != nil {
+				return settings, &utils.ErrorMessage{
+					Message: &i18n.Message{
+						ID:    "poll.newPoll.pointsSetting.unexpectedError",
+						Other: "Unexpected error happens when parsing {{.Setting}}",
+					},
+					Data: map[string]interface{}{
+						"Setting": str,
+					},
+				}
+			}
+			settings.Points = i
+		case closeAtPattern.MatchString(str):
+			i, err := strconv.Atoi(closeAtPattern.FindStringSubmatch(str)[1])
+			if err != nil {
+				return settings, &utils.ErrorMessage{
+					Message: &i18n.Message{
+						ID:    "poll.newPoll.closeAtSetting.unexpectedError",
+						Other: "Unexpected error happens when parsing {{.Setting}}",
+					},
+					Data: map[string]interface{}{
+						"Setting": str,
+					},
+				}
+			}
+			settings.CloseAt = i
+		case capacityPattern.MatchString(str):
+			i, err := strconv.Atoi(capacityPattern.FindStringSubmatch(str)[1])
+			if err != nil {
+				return settings, &utils.ErrorMessage{
+					Message: &i18n.Message{
+						ID:    "poll.newPoll.capacitySetting.unexpectedError",
+						Other: "Unexpected error happens when parsing {{.Setting}}",
+					},
+					Data: map[string]interface{}{
+						"Setting": str,
+					},
+				}
+			}
+			settings.Capacity = i
+		case tieBreakPattern.MatchString(str):
+			settings.TieBreak = TieBreak(tieBreakPattern.FindStringSubmatch(str)[1])
+		case votersPattern.MatchString(str):
+			voters, errMsg := parseVoters(votersPattern.FindStringSubmatch(str)[1])
+			if errMsg != nil {
+				return settings, errMsg
+			}
+			settings.Voters = voters
+		case endSettingPattern.MatchString(str):
+			endAt, errMsg := parseEndSetting(endSettingPattern.FindStringSubmatch(str)[1], now, loc)
+			if errMsg != nil {
+				return settings, errMsg
+			}
+			settings.EndAt = endAt
 		default:
 			return settings, &utils.ErrorMessage{
 				Message: &i18n.Message{
@@ -144,7 +340,8 @@ func NewSettingsFromStrings(strs []string) (Settings, *utils.ErrorMessage) {
 }
 
 // NewSettingsFromSubmission creates a new settings with the given parameter.
-func NewSettingsFromSubmission(submission map[string]interface{}) Settings {
+// now is the current time in milliseconds and is used to resolve a relative end time.
+func NewSettingsFromSubmission(submission map[string]interface{}, now int64, loc *time.Location) (Settings, *utils.ErrorMessage) {
 	settings := Settings{MaxVotes: 1}
 	for k, v := range submission {
 		if k == "setting-multi" {
@@ -152,6 +349,44 @@ func NewSettingsFromSubmission(submission map[string]interface{}) Settings {
 			if ok {
 				settings.MaxVotes = int(f)
 			}
+		} else if k == "setting-"+SettingKeyPoints {
+			f, ok := v.(float64)
+			if ok {
+				settings.Points = int(f)
+			}
+		} else if k == "setting-"+SettingKeyCloseAt {
+			f, ok := v.(float64)
+			if ok {
+				settings.CloseAt = int(f)
+			}
+		} else if k == "setting-"+SettingKeyCapacity {
+			f, ok := v.(float64)
+			if ok {
+				settings.Capacity = int(f)
+			}
+		} else if k == "setting-"+SettingKeyTieBreak {
+			str, ok := v.(string)
+			if ok {
+				settings.TieBreak = TieBreak(str)
+			}
+		} else if k == "setting-"+SettingKeyVoters {
+			str, ok := v.(string)
+			if ok && strings.TrimSpace(str) != "" {
+				voters, errMsg := parseVoters(str)
+				if errMsg != nil {
+					return settings, errMsg
+				}
+				settings.Voters = voters
+			}
+		} else if k == "setting-"+SettingKeyEnd {
+			str, ok := v.(string)
+			if ok && strings.TrimSpace(str) != "" {
+				endAt, errMsg := parseEndSetting(strings.TrimSpace(str), now, loc)
+				if errMsg != nil {
+					return settings, errMsg
+				}
+				settings.EndAt = endAt
+			}
 		} else if strings.HasPrefix(k, "setting-") {
 			b, ok := v.(bool)
 			if b && ok {
@@ -165,11 +400,33 @@ func NewSettingsFromSubmission(submission map[string]interface{}) Settings {
 					settings.Progress = true
 				case SettingKeyPublicAddOption:
 					settings.PublicAddOption = true
+				case SettingKeyRanked:
+					settings.Ranked = true
+				case SettingKeyScheduling:
+					settings.Scheduling = true
+				case SettingKeyQuiz:
+					settings.Quiz = true
+				case SettingKeyFreeText:
+					settings.FreeText = true
+				case SettingKeyCloseWhenAllVoted:
+					settings.CloseWhenAllVoted = true
+				case SettingKeyResultsAfterVote:
+					settings.ResultsAfterVote = true
+				case SettingKeyFinal:
+					settings.Final = true
+				case SettingKeyExcludeGuests:
+					settings.ExcludeGuests = true
+				case SettingKeyExcludeBots:
+					settings.ExcludeBots = true
+				case SettingKeyWaitlist:
+					settings.Waitlist = true
+				case SettingKeyProxy:
+					settings.Proxy = true
 				}
 			}
 		}
 	}
-	return settings
+	return settings, nil
 }
 
 // parseVotesSettings parses setting for votes ("--votes=X")
@@ -201,8 +458,144 @@ func parseVotesSettings(s string) (int, *utils.ErrorMessage) {
 	return i, nil
 }
 
+// parseEndSetting parses the value of the end setting ("--end=X") and returns the end time in milliseconds.
+// X is either a duration relative to now, e.g. "90m", "2h" or "3d", a RFC 3339 timestamp
+// or a time without offset like "2024-12-24 18:00", which is read in the given time zone of the creator.
+func parseEndSetting(s string, now int64, loc *time.Location) (int64, *utils.ErrorMessage) {
+	var endAt int64
+	if d, err := time.ParseDuration(s); err == nil {
+		endAt = now + d.Milliseconds()
+	} else if e := daysPattern.FindStringSubmatch(s); len(e) == 2 {
+		days, err := strconv.Atoi(e[1])
+		if err != nil {
+			return 0, newInvalidEndSettingError(s)
+		}
+		endAt = now + (time.Duration(days) * 24 * time.Hour).Milliseconds()
+	} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+		endAt = t.UnixMilli()
+	} else if t, ok := parseLocalTime(s, loc); ok {
+		endAt = t.UnixMilli()
+	} else {
+		return 0, newInvalidEndSettingError(s)
+	}
+
+	if endAt <= now {
+		return 0, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.endSetting.inPast",
+				Other: `The end time "{{.End}}" must be in the future.`,
+			},
+			Data: map[string]interface{}{
+				"End": s,
+			},
+		}
+	}
+	return endAt, nil
+}
+
+// parseLocalTime parses a time without offset like "2024-12-24 18:00" in a given time zone.
+func parseLocalTime(s string, loc *time.Location) (time.Time, bool) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func newInvalidEndSettingError(s string) *utils.ErrorMessage {
+	return &utils.ErrorMessage{
+		Message: &i18n.Message{
+			ID:    "poll.newPoll.endSetting.invalid",
+			Other: `Invalid end time "{{.End}}". Use a duration like "90m", "2h" or "3d", or a time like "2024-12-24T18:00" in your time zone.`,
+		},
+		Data: map[string]interface{}{
+			"End": s,
+		},
+	}
+}
+
 // validate checks if poll is valid
 func (p *Poll) validate() *utils.ErrorMessage {
+	if p.Settings.CloseAt < 0 {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.closeAtSetting.invalid",
+				Other: `The number of voters after which the poll closes must be a positive number. You specified "{{.CloseAt}}".`,
+			},
+			Data: map[string]interface{}{
+				"CloseAt": p.Settings.CloseAt,
+			},
+		}
+	}
+	if p.Settings.ResultsAfterVote && p.Settings.Progress {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.resultsAfterVoteSetting.combined",
+				Other: "The results-after-vote setting can't be combined with the progress setting, as it already shows the progress to everyone who voted.",
+			},
+		}
+	}
+	if !p.Settings.TieBreak.isValid() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.tieBreakSetting.invalid",
+				Other: `Invalid tie-break rule "{{.TieBreak}}". Use "creator" or "runoff".`,
+			},
+			Data: map[string]interface{}{
+				"TieBreak": p.Settings.TieBreak,
+			},
+		}
+	}
+	if p.Settings.TieBreak != TieBreakNone && !p.HasWinners() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.tieBreakSetting.combined",
+				Other: "The tie-break setting can't be combined with the free-text, scheduling or quiz setting, as these polls have no winner.",
+			},
+		}
+	}
+	if p.IsRestricted() && p.Settings.CloseWhenAllVoted {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.votersSetting.combined",
+				Other: "The voters setting can't be combined with the close-when-all-voted setting, as not every member of the channel is allowed to vote.",
+			},
+		}
+	}
+	if errMsg := p.validateCapacity(); errMsg != nil {
+		return errMsg
+	}
+	if p.Settings.Proxy && !p.supportsProxyVotes() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.proxySetting.combined",
+				Other: "The proxy setting can't be combined with the anonymous, ranked, points, scheduling, free-text or quiz setting, as proxy votes must be visible to everyone.",
+			},
+		}
+	}
+	if p.IsFinal() && (p.IsFreeText() || p.Settings.Ranked || p.HasPointBudget() || p.IsScheduling()) {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.finalSetting.combined",
+				Other: "The final setting can't be combined with the free-text, ranked, points or scheduling setting.",
+			},
+		}
+	}
+	if p.IsFreeText() {
+		if len(p.AnswerOptions) > 0 || p.Settings.MaxVotes != 1 || p.Settings.Ranked || p.HasPointBudget() || p.IsScheduling() || p.IsQuiz() || p.Settings.PublicAddOption {
+			return &utils.ErrorMessage{
+				Message: &i18n.Message{
+					ID:    "poll.newPoll.freeTextSetting.combined",
+					Other: "A free-text poll can't have answer options and can't be combined with the votes, ranked, points, scheduling, quiz or public-add-option setting.",
+				},
+			}
+		}
+		return nil
+	}
 	if p.Settings.MaxVotes < 0 || p.Settings.MaxVotes > len(p.AnswerOptions) {
 		return &utils.ErrorMessage{
 			Message: &i18n.Message{
@@ -215,9 +608,75 @@ func (p *Poll) validate() *utils.ErrorMessage {
 			},
 		}
 	}
+	if p.Settings.Points < 0 {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.pointsSetting.invalid",
+				Other: `The number of points must be a positive number. You specified "{{.Points}}".`,
+			},
+			Data: map[string]interface{}{
+				"Points": p.Settings.Points,
+			},
+		}
+	}
+	if p.HasPointBudget() && (p.Settings.MaxVotes != 1 || p.Settings.Ranked) {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.pointsSetting.combined",
+				Other: "The points setting can't be combined with the votes or ranked setting.",
+			},
+		}
+	}
+	if p.IsScheduling() && (p.Settings.MaxVotes != 1 || p.Settings.Ranked || p.HasPointBudget()) {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.schedulingSetting.combined",
+				Other: "The scheduling setting can't be combined with the votes, ranked or points setting.",
+			},
+		}
+	}
+	if p.IsQuiz() && (p.Settings.MaxVotes != 1 || p.Settings.Ranked || p.HasPointBudget() || p.IsScheduling()) {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.quizSetting.combined",
+				Other: "The quiz setting can't be combined with the votes, ranked, points or scheduling setting.",
+			},
+		}
+	}
+	if p.IsQuiz() && len(p.GetCorrectAnswers()) == 0 {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.quizSetting.noCorrectAnswer",
+				Other: `A quiz needs at least one correct answer. Mark correct answers with a leading "*", e.g. "*Answer 1".`,
+			},
+		}
+	}
+	if p.Settings.Ranked && p.Settings.MaxVotes != 1 {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newPoll.ranked.votesSetting",
+				Other: "The votes setting can't be combined with ranked-choice voting, as voters rank all options anyway.",
+			},
+		}
+	}
 	return nil
 }
 
+// ClosesAutomatically returns true if the poll is ended automatically once enough users voted
+func (p *Poll) ClosesAutomatically() bool {
+	return p.Settings.CloseAt > 0 || p.Settings.CloseWhenAllVoted
+}
+
+// IsFinal returns true if votes can't be changed or reset once they are cast
+func (p *Poll) IsFinal() bool {
+	return p.Settings.Final
+}
+
+// HasEndTime returns true if the poll is ended automatically at a given time
+func (p *Poll) HasEndTime() bool {
+	return p.Settings.EndAt > 0
+}
+
 // IsMultiVote return true if poll is set to multi vote
 func (p *Poll) IsMultiVote() bool {
 	return p.Settings.MaxVotes == 0 || p.Settings.MaxVotes > 1
@@ -226,6 +685,14 @@ func (p *Poll) IsMultiVote() bool {
 // AddAnswerOption adds a new AnswerOption to a poll
 func (p *Poll) AddAnswerOption(newAnswerOption string) *utils.ErrorMessage {
 	newAnswerOption = strings.TrimSpace(newAnswerOption)
+	capacity := 0
+	// Brackets are only read as capacity, if the poll is a sign-up sheet. Otherwise an answer like "Version [2]" would get a capacity.
+	if p.supportsCapacity() && (p.Settings.Capacity > 0 || p.Settings.Waitlist) {
+		newAnswerOption, capacity = parseCapacity(newAnswerOption)
+		if capacity == 0 {
+			capacity = p.Settings.Capacity
+		}
+	}
 	if newAnswerOption == "" {
 		return &utils.ErrorMessage{
 			Message: &i18n.Message{
@@ -236,25 +703,31 @@ func (p *Poll) AddAnswerOption(newAnswerOption string) *utils.ErrorMessage {
 	}
 	for _, answerOption := range p.AnswerOptions {
 		if answerOption.Answer == newAnswerOption {
-			return &utils.ErrorMessage{
-				Message: &i18n.Message{
-					ID:    "poll.addAnswerOption.duplicate",
-					Other: "Duplicate option: {{.Option}}",
-				},
-				Data: map[string]interface{}{
-					"Option": newAnswerOption,
-				},
-			}
+			return newDuplicateAnswerOptionError(newAnswerOption)
 		}
 	}
 	ao := &AnswerOption{
-		Answer: newAnswerOption,
-		Voter:  []string{},
+		Answer:   newAnswerOption,
+		Voter:    []string{},
+		Capacity: capacity,
 	}
 	p.AnswerOptions = append(p.AnswerOptions, ao)
 	return nil
 }
 
+// newDuplicateAnswerOptionError returns the error for an answer option, that already exists
+func newDuplicateAnswerOptionError(answerOption string) *utils.ErrorMessage {
+	return &utils.ErrorMessage{
+		Message: &i18n.Message{
+			ID:    "poll.addAnswerOption.duplicate",
+			Other: "Duplicate option: {{.Option}}",
+		},
+		Data: map[string]interface{}{
+			"Option": answerOption,
+		},
+	}
+}
+
 // UpdateVote performs a vote for a given user
 func (p *Poll) UpdateVote(userID string, index int) (*i18n.Message, error) {
 	if len(p.AnswerOptions) <= index || index < 0 {
@@ -264,6 +737,35 @@ func (p *Poll) UpdateVote(userID string, index int) (*i18n.Message, error) {
 		return nil, fmt.Errorf("invalid userID")
 	}
 
+	if p.IsRanked() {
+		return &i18n.Message{
+			ID:    "poll.updateVote.ranked",
+			Other: "This is a ranked-choice poll. Please submit your ranking via the dialog.",
+		}, nil
+	}
+	if p.IsScheduling() {
+		return &i18n.Message{
+			ID:    "poll.updateVote.scheduling",
+			Other: "This is a scheduling poll. Please submit your availability via the dialog.",
+		}, nil
+	}
+
+	if p.HasPointBudget() {
+		return p.addPoint(userID, index), nil
+	}
+
+	if p.IsQuiz() && p.HasVoted(userID) {
+		return &i18n.Message{
+			ID:    "poll.updateVote.quiz.alreadyAnswered",
+			Other: "You've already answered this quiz.",
+		}, nil
+	}
+
+	if p.IsFull(index, userID) {
+		return pollMessageOptionFull, nil
+	}
+
+	var left []int
 	if p.IsMultiVote() {
 		// Multi Answer Mode
 		votedAnswers := p.GetVotedAnswers(userID)
@@ -281,35 +783,87 @@ func (p *Poll) UpdateVote(userID string, index int) (*i18n.Message, error) {
 				Other: "You could't vote for this option, because you don't have any votes left. Use the reset button to reset your votes.",
 			}, nil
 		}
+	} else if p.IsFinal() && p.HasVoted(userID) {
+		return &i18n.Message{
+			ID:    "poll.updateVote.final",
+			Other: "Your vote is final and can't be changed.",
+		}, nil
+	} else if p.IsSecretBallot() {
+		// Single Answer Mode
+		p.resetSecretVotes(userID)
 	} else {
 		// Single Answer Mode
-		for _, o := range p.AnswerOptions {
-			for i := 0; i < len(o.Voter); i++ {
-				if userID == o.Voter[i] {
-					o.Voter = append(o.Voter[:i], o.Voter[i+1:]...)
-				}
-			}
-		}
+		left = p.removeVotes(userID)
 	}
 
+	if p.IsSecretBallot() {
+		p.addSecretVote(userID, index)
+		return nil, nil
+	}
 	p.AnswerOptions[index].Voter = append(p.AnswerOptions[index].Voter, userID)
+	p.AnswerOptions[index].removeProxy(userID)
+	for _, i := range left {
+		if i != index {
+			p.promoteFromWaitlist(i)
+		}
+	}
 	return nil, nil
 }
 
 // ResetVotes remove votes by a given user
 func (p *Poll) ResetVotes(userID string) {
+	if p.IsSecretBallot() {
+		p.resetSecretVotes(userID)
+		return
+	}
+	left := p.removeVotes(userID)
 	for _, o := range p.AnswerOptions {
-		for i := 0; i < len(o.Voter); i++ {
-			if userID == o.Voter[i] {
-				o.Voter = append(o.Voter[:i], o.Voter[i+1:]...)
-			}
-		}
+		delete(o.Ranks, userID)
+		delete(o.Points, userID)
+		delete(o.Availability, userID)
+	}
+	for _, q := range p.Questions {
+		q.resetVotes(userID)
+	}
+	p.removeResponse(userID)
+
+	p.leaveWaitlists(userID)
+	for _, i := range left {
+		p.promoteFromWaitlist(i)
 	}
 }
 
 // GetVotedAnswers collect voted answers by a user and returns it as string array.
+// For ranked-choice polls the answers are ordered by the rank the user gave them.
+// For scheduling polls only the answers the user is available for are returned.
+// For free-text polls the response of the user is returned.
 func (p *Poll) GetVotedAnswers(userID string) []string {
 	votedAnswer := []string{}
+	if p.IsRanked() {
+		for _, i := range p.GetBallot(userID) {
+			votedAnswer = append(votedAnswer, p.AnswerOptions[i].Answer)
+		}
+		return votedAnswer
+	}
+	if p.IsFreeText() {
+		if response := p.GetResponse(userID); response != "" {
+			votedAnswer = append(votedAnswer, response)
+		}
+		return votedAnswer
+	}
+	if p.IsScheduling() {
+		// Only options the user is available for count as voted
+		for _, o := range p.AnswerOptions {
+			if o.Availability[userID] == AvailabilityYes {
+				votedAnswer = append(votedAnswer, o.Answer)
+			}
+		}
+		return votedAnswer
+	}
+	if p.IsSecretBallot() {
+		return p.getSecretVotedAnswers(userID)
+	}
+
 	for _, o := range p.AnswerOptions {
 		for _, v := range o.Voter {
 			if userID == v {
@@ -323,18 +877,34 @@ func (p *Poll) GetVotedAnswers(userID string) []string {
 
 // GetMetadata returns personalized metadata of a poll.
 func (p *Poll) GetMetadata(userID string, permission bool) *Metadata {
-	return &Metadata{
-		PollID:                 p.ID,
-		UserID:                 userID,
-		CanManagePoll:          permission,
-		VotedAnswers:           p.GetVotedAnswers(userID),
-		SettingProgress:        p.Settings.Progress,
-		SettingPublicAddOption: p.Settings.PublicAddOption,
+	metadata := &Metadata{
+		PollID:                  p.ID,
+		UserID:                  userID,
+		CanManagePoll:           permission,
+		VotedAnswers:            p.GetVotedAnswers(userID),
+		SettingProgress:         p.Settings.Progress,
+		SettingPublicAddOption:  p.Settings.PublicAddOption,
+		SettingResultsAfterVote: p.Settings.ResultsAfterVote,
+	}
+	if p.Settings.ResultsAfterVote && p.HasVoted(userID) {
+		metadata.Results = p.countProgress()
 	}
+	return metadata
 }
 
 // HasVoted return true if a given user has voted in this poll
 func (p *Poll) HasVoted(userID string) bool {
+	if _, ok := p.Ballots[userID]; ok {
+		return true
+	}
+	if p.GetResponse(userID) != "" {
+		return true
+	}
+	for _, q := range p.Questions {
+		if q.hasVoted(userID) {
+			return true
+		}
+	}
 	for _, o := range p.AnswerOptions {
 		for i := 0; i < len(o.Voter); i++ {
 			if userID == o.Voter[i] {
@@ -345,6 +915,33 @@ func (p *Poll) HasVoted(userID string) bool {
 	return false
 }
 
+// GetVoters returns the ids of all users who voted in this poll, sorted in the order they first appear in the poll.
+func (p *Poll) GetVoters() []string {
+	voters := []string{}
+	seen := map[string]bool{}
+	add := func(userID string) {
+		if !seen[userID] {
+			seen[userID] = true
+			voters = append(voters, userID)
+		}
+	}
+	for _, o := range p.AnswerOptions {
+		for _, v := range o.Voter {
+			add(v)
+		}
+	}
+	for _, r := range p.Responses {
+		add(r.UserID)
+	}
+	for _, v := range p.GetSurveyRespondents() {
+		add(v)
+	}
+	for _, v := range p.getSecretVoters() {
+		add(v)
+	}
+	return voters
+}
+
 // EncodeToByte returns a poll as a byte array
 func (p *Poll) EncodeToByte() []byte {
 	b, _ := json.Marshal(p)
@@ -365,19 +962,64 @@ func DecodePollFromByte(b []byte) *Poll {
 func (p *Poll) Copy() *Poll {
 	p2 := new(Poll)
 	*p2 = *p
-	p2.AnswerOptions = make([]*AnswerOption, len(p.AnswerOptions))
-	for i, o := range p.AnswerOptions {
-		p2.AnswerOptions[i] = new(AnswerOption)
-		p2.AnswerOptions[i].Answer = o.Answer
+	p2.AnswerOptions = copyAnswerOptions(p.AnswerOptions)
+	p2.Settings.Voters = p.Settings.Voters.copy()
+	p2.Delegates = maps.Clone(p.Delegates)
+	p2.ignoredVoters = maps.Clone(p.ignoredVoters)
+	if p.Ballots != nil {
+		p2.Ballots = make(map[string][]int, len(p.Ballots))
+		for token, indexes := range p.Ballots {
+			p2.Ballots[token] = slices.Clone(indexes)
+		}
+	}
+	if p.Responses != nil {
+		p2.Responses = make([]*Response, len(p.Responses))
+		for i, r := range p.Responses {
+			p2.Responses[i] = &Response{UserID: r.UserID, Text: r.Text}
+		}
+	}
+	if p.Questions != nil {
+		p2.Questions = make([]*SurveyQuestion, len(p.Questions))
+		for i, q := range p.Questions {
+			p2.Questions[i] = &SurveyQuestion{
+				Question:      q.Question,
+				AnswerOptions: copyAnswerOptions(q.AnswerOptions),
+				Settings:      q.Settings,
+			}
+			p2.Questions[i].Settings.Voters = q.Settings.Voters.copy()
+		}
+	}
+	return p2
+}
+
+// copyAnswerOptions deep copies a list of answer options
+func copyAnswerOptions(options []*AnswerOption) []*AnswerOption {
+	// Free-text polls and surveys have no answer options. Keep them nil to ensure the copy is exact.
+	if options == nil {
+		return nil
+	}
+	options2 := make([]*AnswerOption, len(options))
+	for i, o := range options {
+		options2[i] = new(AnswerOption)
+		options2[i].Answer = o.Answer
 		// Only copy Voter if they are nil to ensure the new poll is an exact copy.
 		// Please note that polls fetched from the DB might have a nil value,
 		// hence we have to still think about this case in the future.
 		if o.Voter != nil {
-			p2.AnswerOptions[i].Voter = make([]string, len(o.Voter))
-			copy(p2.AnswerOptions[i].Voter, o.Voter)
+			options2[i].Voter = make([]string, len(o.Voter))
+			copy(options2[i].Voter, o.Voter)
 		}
+		options2[i].Ranks = maps.Clone(o.Ranks)
+		options2[i].Points = maps.Clone(o.Points)
+		options2[i].Availability = maps.Clone(o.Availability)
+		options2[i].Correct = o.Correct
+		options2[i].Votes = o.Votes
+		options2[i].Capacity = o.Capacity
+		options2[i].Waitlist = slices.Clone(o.Waitlist)
+		options2[i].Proxies = maps.Clone(o.Proxies)
+		options2[i].VotedAt = maps.Clone(o.VotedAt)
 	}
-	return p2
+	return options2
 }
 
 func (s Settings) String() string {
@@ -394,6 +1036,51 @@ func (s Settings) String() string {
 	if s.PublicAddOption {
 		settingsText = append(settingsText, "public-add-option")
 	}
+	if s.Ranked {
+		settingsText = append(settingsText, "ranked")
+	}
+	if s.Points > 0 {
+		settingsText = append(settingsText, fmt.Sprintf("points=%d", s.Points))
+	}
+	if s.Scheduling {
+		settingsText = append(settingsText, "scheduling")
+	}
+	if s.Quiz {
+		settingsText = append(settingsText, "quiz")
+	}
+	if s.FreeText {
+		settingsText = append(settingsText, "free-text")
+	}
+	if s.CloseAt > 0 {
+		settingsText = append(settingsText, fmt.Sprintf("close-at=%d", s.CloseAt))
+	}
+	if s.CloseWhenAllVoted {
+		settingsText = append(settingsText, "close-when-all-voted")
+	}
+	if s.ResultsAfterVote {
+		settingsText = append(settingsText, "results-after-vote")
+	}
+	if s.Final {
+		settingsText = append(settingsText, "final")
+	}
+	if s.Capacity > 0 {
+		settingsText = append(settingsText, fmt.Sprintf("capacity=%d", s.Capacity))
+	}
+	if s.Waitlist {
+		settingsText = append(settingsText, "waitlist")
+	}
+	if s.Proxy {
+		settingsText = append(settingsText, "proxy")
+	}
+	if s.ExcludeGuests {
+		settingsText = append(settingsText, "exclude-guests")
+	}
+	if s.ExcludeBots {
+		settingsText = append(settingsText, "exclude-bots")
+	}
+	if s.TieBreak != TieBreakNone {
+		settingsText = append(settingsText, fmt.Sprintf("tie-break=%s", s.TieBreak))
+	}
 	if s.MaxVotes != 1 {
 		switch s.MaxVotes {
 		case 0:
@@ -3,6 +3,7 @@ package poll_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -55,6 +56,141 @@ func TestNewPoll(t *testing.T) {
 			Settings:    poll.Settings{Anonymous: true, Progress: true, PublicAddOption: true, MaxVotes: 1},
 			ShouldError: true,
 		},
+		"fine, ranked": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Ranked: true, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, ranked with votes setting": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Ranked: true, MaxVotes: 2},
+			ShouldError: true,
+		},
+		"fine, points": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Points: 10, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, points with votes setting": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Points: 10, MaxVotes: 2},
+			ShouldError: true,
+		},
+		"invalid, points with ranked setting": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Points: 10, Ranked: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, negative points": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Points: -1, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"fine, scheduling": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Scheduling: true, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, scheduling with votes setting": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Scheduling: true, MaxVotes: 2},
+			ShouldError: true,
+		},
+		"invalid, scheduling with ranked setting": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Scheduling: true, Ranked: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, scheduling with points setting": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Scheduling: true, Points: 10, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, quiz without correct answer": {
+			Options:     []string{option1, option2, option3},
+			Settings:    poll.Settings{Quiz: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, quiz with votes setting": {
+			Options:     []string{"*" + option1, option2, option3},
+			Settings:    poll.Settings{Quiz: true, MaxVotes: 2},
+			ShouldError: true,
+		},
+		"invalid, quiz with ranked setting": {
+			Options:     []string{"*" + option1, option2, option3},
+			Settings:    poll.Settings{Quiz: true, Ranked: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"valid, free-text without options": {
+			Options:     []string{},
+			Settings:    poll.Settings{FreeText: true, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, free-text with options": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{FreeText: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, free-text with public-add-option setting": {
+			Options:     []string{},
+			Settings:    poll.Settings{FreeText: true, PublicAddOption: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"valid, close-at setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{CloseAt: 10, CloseWhenAllVoted: true, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, results-after-vote with progress setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{ResultsAfterVote: true, Progress: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"valid, final setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{Final: true, MaxVotes: 2},
+			ShouldError: false,
+		},
+		"invalid, final with ranked setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{Final: true, Ranked: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, final with points setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{Final: true, Points: 3, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"valid, tie-break setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{TieBreak: poll.TieBreakRunoff, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, unknown tie-break setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{TieBreak: "coin", MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, tie-break with scheduling setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{TieBreak: poll.TieBreakCreator, Scheduling: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"valid, voters setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{Voters: &poll.Voters{Names: []string{"user1"}}, MaxVotes: 1},
+			ShouldError: false,
+		},
+		"invalid, voters with close-when-all-voted setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{Voters: &poll.Voters{Names: []string{"user1"}}, CloseWhenAllVoted: true, MaxVotes: 1},
+			ShouldError: true,
+		},
+		"invalid, negative close-at setting": {
+			Options:     []string{option1, option2},
+			Settings:    poll.Settings{CloseAt: -1, MaxVotes: 1},
+			ShouldError: true,
+		},
 		"invalid, votes setting exceeds": {
 			Options:     []string{option1, option2, option3}, // options1 is duplicated
 			Settings:    poll.Settings{Anonymous: true, Progress: true, PublicAddOption: true, MaxVotes: 4},
@@ -137,6 +273,198 @@ func TestNewSettingsFromStrings(t *testing.T) {
 				MaxVotes:         1,
 			},
 		},
+		"ranked setting": {
+			Strs:        []string{"ranked"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Ranked:   true,
+			},
+		},
+		"points setting": {
+			Strs:        []string{"points=10"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Points:   10,
+			},
+		},
+		"scheduling setting": {
+			Strs:        []string{"scheduling"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes:   1,
+				Scheduling: true,
+			},
+		},
+		"quiz setting": {
+			Strs:        []string{"quiz"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Quiz:     true,
+			},
+		},
+		"free-text setting": {
+			Strs:        []string{"free-text"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				FreeText: true,
+			},
+		},
+		"close-at setting": {
+			Strs:        []string{"close-at=20"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				CloseAt:  20,
+			},
+		},
+		"invalid close-at setting": {
+			Strs:        []string{"close-at=9223372036854775808"}, // Exceed math.MaxInt64
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"results-after-vote setting": {
+			Strs:        []string{"results-after-vote"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes:         1,
+				ResultsAfterVote: true,
+			},
+		},
+		"final setting": {
+			Strs:        []string{"final"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Final:    true,
+			},
+		},
+		"capacity and waitlist settings": {
+			Strs:        []string{"capacity=5", "waitlist"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Capacity: 5,
+				Waitlist: true,
+			},
+		},
+		"proxy setting": {
+			Strs:        []string{"proxy"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Proxy:    true,
+			},
+		},
+		"exclude-guests and exclude-bots settings": {
+			Strs:        []string{"exclude-guests", "exclude-bots"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes:      1,
+				ExcludeGuests: true,
+				ExcludeBots:   true,
+			},
+		},
+		"tie-break setting": {
+			Strs:        []string{"tie-break=creator"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				TieBreak: poll.TieBreakCreator,
+			},
+		},
+		"voters setting": {
+			Strs:        []string{"voters=@User1,@developers,channel_admin,team_admin,~Town-Square"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Voters: &poll.Voters{
+					Names:    []string{"user1", "developers"},
+					Roles:    []string{"channel_admin", "team_admin"},
+					Channels: []string{"town-square"},
+				},
+			},
+		},
+		"voters setting, invalid voter": {
+			Strs:        []string{"voters=@user1,user2"},
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"voters setting, unknown role": {
+			Strs:        []string{"voters=system_admin"},
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"close-when-all-voted setting": {
+			Strs:        []string{"close-when-all-voted"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes:          1,
+				CloseWhenAllVoted: true,
+			},
+		},
+		"invalid points setting": {
+			Strs:        []string{"points=9223372036854775808"}, // Exceed math.MaxInt64
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"end setting, duration": {
+			Strs:        []string{"end=2h"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				EndAt:    testutils.GetMillis() + 2*60*60*1000,
+			},
+		},
+		"end setting, days": {
+			Strs:        []string{"end=3d"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				EndAt:    testutils.GetMillis() + 3*24*60*60*1000,
+			},
+		},
+		"end setting, timestamp": {
+			Strs:        []string{"end=2030-01-02T15:04:05Z"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				EndAt:    1893596645000,
+			},
+		},
+		"end setting, time in the time zone of the creator": {
+			Strs:        []string{"end=2030-01-02T15:04"},
+			ShouldError: false,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				EndAt:    1893564240000,
+			},
+		},
+		"end setting, timestamp in the past": {
+			Strs:        []string{"end=1970-01-01T00:00:00Z"},
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"end setting, invalid value": {
+			Strs:        []string{"end=tomorrow"},
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
 		"invalid setting": {
 			Strs:        []string{"anonymous", "progress", "public-add-option", "invalid"},
 			ShouldError: true,
@@ -152,7 +480,7 @@ func TestNewSettingsFromStrings(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			settings, errMsg := poll.NewSettingsFromStrings(test.Strs)
+			settings, errMsg := poll.NewSettingsFromStrings(test.Strs, testutils.GetMillis(), time.FixedZone("JST", 9*60*60))
 			if test.ShouldError {
 				assert.NotNil(errMsg)
 			} else {
@@ -166,6 +494,7 @@ func TestNewSettingsFromStrings(t *testing.T) {
 func TestNewSettingsFromSubmission(t *testing.T) {
 	for name, test := range map[string]struct {
 		Submission       map[string]interface{}
+		ShouldError      bool
 		ExpectedSettings poll.Settings
 	}{
 		"no settings": {
@@ -194,25 +523,205 @@ func TestNewSettingsFromSubmission(t *testing.T) {
 				MaxVotes:         4,
 			},
 		},
-		"without votes settings": {
+		"without votes settings": {
+			Submission: map[string]interface{}{
+				"setting-anonymous":         false,
+				"setting-progress":          false,
+				"setting-public-add-option": false,
+			},
+			ExpectedSettings: poll.Settings{
+				Anonymous:        false,
+				AnonymousCreator: false,
+				Progress:         false,
+				PublicAddOption:  false,
+				MaxVotes:         1,
+			},
+		},
+		"with ranked setting": {
+			Submission: map[string]interface{}{
+				"setting-ranked": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Ranked:   true,
+			},
+		},
+		"with points setting": {
+			Submission: map[string]interface{}{
+				"setting-points": float64(10),
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Points:   10,
+			},
+		},
+		"with scheduling setting": {
+			Submission: map[string]interface{}{
+				"setting-scheduling": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes:   1,
+				Scheduling: true,
+			},
+		},
+		"with quiz setting": {
+			Submission: map[string]interface{}{
+				"setting-quiz": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Quiz:     true,
+			},
+		},
+		"with free-text setting": {
+			Submission: map[string]interface{}{
+				"setting-free-text": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				FreeText: true,
+			},
+		},
+		"with results-after-vote setting": {
+			Submission: map[string]interface{}{
+				"setting-results-after-vote": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes:         1,
+				ResultsAfterVote: true,
+			},
+		},
+		"with final setting": {
+			Submission: map[string]interface{}{
+				"setting-final": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Final:    true,
+			},
+		},
+		"with capacity and waitlist settings": {
+			Submission: map[string]interface{}{
+				"setting-capacity": float64(5),
+				"setting-waitlist": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Capacity: 5,
+				Waitlist: true,
+			},
+		},
+		"with proxy setting": {
+			Submission: map[string]interface{}{
+				"setting-proxy": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Proxy:    true,
+			},
+		},
+		"with exclude-guests and exclude-bots settings": {
+			Submission: map[string]interface{}{
+				"setting-exclude-guests": true,
+				"setting-exclude-bots":   true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes:      1,
+				ExcludeGuests: true,
+				ExcludeBots:   true,
+			},
+		},
+		"with tie-break setting": {
+			Submission: map[string]interface{}{
+				"setting-tie-break": "runoff",
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				TieBreak: poll.TieBreakRunoff,
+			},
+		},
+		"with voters setting": {
+			Submission: map[string]interface{}{
+				"setting-voters": "@user1, ~town-square",
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				Voters:   &poll.Voters{Names: []string{"user1"}, Channels: []string{"town-square"}},
+			},
+		},
+		"with empty voters setting": {
+			Submission: map[string]interface{}{
+				"setting-voters": " ",
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"with close settings": {
+			Submission: map[string]interface{}{
+				"setting-close-at":             float64(20),
+				"setting-close-when-all-voted": true,
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes:          1,
+				CloseAt:           20,
+				CloseWhenAllVoted: true,
+			},
+		},
+		"with end setting": {
+			Submission: map[string]interface{}{
+				"setting-end": "90m",
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				EndAt:    testutils.GetMillis() + 90*60*1000,
+			},
+		},
+		"with end setting in the time zone of the creator": {
+			Submission: map[string]interface{}{
+				"setting-end": "2030-01-02 15:04",
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+				EndAt:    1893564240000,
+			},
+		},
+		"with empty end setting": {
+			Submission: map[string]interface{}{
+				"setting-end": " ",
+			},
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"with invalid voters setting": {
+			Submission: map[string]interface{}{
+				"setting-voters": "user1",
+			},
+			ShouldError: true,
+			ExpectedSettings: poll.Settings{
+				MaxVotes: 1,
+			},
+		},
+		"with invalid end setting": {
 			Submission: map[string]interface{}{
-				"setting-anonymous":         false,
-				"setting-progress":          false,
-				"setting-public-add-option": false,
+				"setting-end": "someday",
 			},
+			ShouldError: true,
 			ExpectedSettings: poll.Settings{
-				Anonymous:        false,
-				AnonymousCreator: false,
-				Progress:         false,
-				PublicAddOption:  false,
-				MaxVotes:         1,
+				MaxVotes: 1,
 			},
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			settings := poll.NewSettingsFromSubmission(test.Submission)
+			settings, errMsg := poll.NewSettingsFromSubmission(test.Submission, testutils.GetMillis(), time.FixedZone("JST", 9*60*60))
+			if test.ShouldError {
+				assert.NotNil(errMsg)
+			} else {
+				assert.Nil(errMsg)
+			}
 			assert.Equal(test.ExpectedSettings, settings)
 		})
 	}
@@ -573,6 +1082,160 @@ func TestUpdateVote(t *testing.T) {
 			Error:         true,
 			ReturnMessage: false,
 		},
+		"Ranked setting": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1"},
+					{Answer: "Answer 2"},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+			UserID: "a",
+			Index:  0,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1"},
+					{Answer: "Answer 2"},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+			Error:         false,
+			ReturnMessage: true,
+		},
+		"Scheduling setting": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1"},
+					{Answer: "Answer 2"},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+			},
+			UserID: "a",
+			Index:  0,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1"},
+					{Answer: "Answer 2"},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+			},
+			Error:         false,
+			ReturnMessage: true,
+		},
+		"Quiz setting, first answer": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{}, Correct: true},
+					{Answer: "Answer 2", Voter: []string{}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Quiz: true},
+			},
+			UserID: "a",
+			Index:  1,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{}, Correct: true},
+					{Answer: "Answer 2", Voter: []string{"a"}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Quiz: true},
+			},
+			Error:         false,
+			ReturnMessage: false,
+		},
+		"Quiz setting, already answered": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{}, Correct: true},
+					{Answer: "Answer 2", Voter: []string{"a"}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Quiz: true},
+			},
+			UserID: "a",
+			Index:  0,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{}, Correct: true},
+					{Answer: "Answer 2", Voter: []string{"a"}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Quiz: true},
+			},
+			Error:         false,
+			ReturnMessage: true,
+		},
+		"Final setting, first vote": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{}},
+				},
+				Settings: poll.Settings{Final: true, MaxVotes: 1},
+			},
+			UserID: "b",
+			Index:  1,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{"b"}},
+				},
+				Settings: poll.Settings{Final: true, MaxVotes: 1},
+			},
+			Error:         false,
+			ReturnMessage: false,
+		},
+		"Final setting, changed vote": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{}},
+				},
+				Settings: poll.Settings{Final: true, MaxVotes: 1},
+			},
+			UserID: "a",
+			Index:  1,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{}},
+				},
+				Settings: poll.Settings{Final: true, MaxVotes: 1},
+			},
+			Error:         false,
+			ReturnMessage: true,
+		},
+		"Final and multi votes setting, second vote": {
+			Poll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{}},
+				},
+				Settings: poll.Settings{Final: true, MaxVotes: 2},
+			},
+			UserID: "a",
+			Index:  1,
+			ExpectedPoll: poll.Poll{
+				Question: "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{"a"}},
+				},
+				Settings: poll.Settings{Final: true, MaxVotes: 2},
+			},
+			Error:         false,
+			ReturnMessage: false,
+		},
 		"Multi votes setting (--votes=0), third vote": {
 			Poll: poll.Poll{
 				Question: "Question",
@@ -707,6 +1370,63 @@ func TestResetVotes(t *testing.T) {
 				Settings: poll.Settings{MaxVotes: 3},
 			},
 		},
+		"Reset success, points": {
+			Poll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a", "b"}, Points: map[string]int{"a": 2, "b": 1}},
+					{Answer: "Answer 2", Voter: []string{"a"}, Points: map[string]int{"a": 1}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Points: 3},
+			},
+			UserID: "a",
+			ExpectedPoll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"b"}, Points: map[string]int{"b": 1}},
+					{Answer: "Answer 2", Voter: []string{}, Points: map[string]int{}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Points: 3},
+			},
+		},
+		"Reset success, scheduling": {
+			Poll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a", "b"}, Availability: map[string]poll.Availability{"a": poll.AvailabilityNo, "b": poll.AvailabilityYes}},
+					{Answer: "Answer 2", Voter: []string{"a"}, Availability: map[string]poll.Availability{"a": poll.AvailabilityMaybe}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+			},
+			UserID: "a",
+			ExpectedPoll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"b"}, Availability: map[string]poll.Availability{"b": poll.AvailabilityYes}},
+					{Answer: "Answer 2", Voter: []string{}, Availability: map[string]poll.Availability{}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+			},
+		},
+		"Reset success, ranked": {
+			Poll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a", "b"}, Ranks: map[string]int{"a": 2, "b": 1}},
+					{Answer: "Answer 2", Voter: []string{"a"}, Ranks: map[string]int{"a": 1}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+			UserID: "a",
+			ExpectedPoll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"b"}, Ranks: map[string]int{"b": 1}},
+					{Answer: "Answer 2", Voter: []string{}, Ranks: map[string]int{}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -802,6 +1522,45 @@ func TestGetMetadata(t *testing.T) {
 				SettingProgress:        true,
 				SettingPublicAddOption: true,
 			}},
+		"Results after vote, voted": {
+			Poll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{"b"}},
+					{Answer: "Answer 3", Voter: []string{"b", "c"}},
+				},
+				Settings: poll.Settings{ResultsAfterVote: true, MaxVotes: 0},
+			},
+			UserID:     "a",
+			Permission: false,
+			ExpectedResponse: &poll.Metadata{
+				PollID:                  testutils.GetPollID(),
+				UserID:                  "a",
+				CanManagePoll:           false,
+				VotedAnswers:            []string{"Answer 1"},
+				SettingResultsAfterVote: true,
+				Results:                 []int{1, 1, 2},
+			}},
+		"Results after vote, not voted": {
+			Poll: poll.Poll{
+				ID: testutils.GetPollID(),
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}},
+					{Answer: "Answer 2", Voter: []string{"b"}},
+					{Answer: "Answer 3", Voter: []string{"b", "c"}},
+				},
+				Settings: poll.Settings{ResultsAfterVote: true, MaxVotes: 0},
+			},
+			UserID:     "d",
+			Permission: false,
+			ExpectedResponse: &poll.Metadata{
+				PollID:                  testutils.GetPollID(),
+				UserID:                  "d",
+				CanManagePoll:           false,
+				VotedAnswers:            []string{},
+				SettingResultsAfterVote: true,
+			}},
 		"Invalid userID": {
 			Poll: poll.Poll{
 				ID: testutils.GetPollID(),
@@ -840,6 +1599,13 @@ func TestHasVoted(t *testing.T) {
 	assert.False(t, p1.HasVoted("b"))
 }
 
+func TestGetVoters(t *testing.T) {
+	assert.Equal(t, []string{"userID1", "userID2", "userID3", "userID4"}, testutils.GetPollWithVotes().GetVoters())
+	assert.Equal(t, []string{}, testutils.GetPoll().GetVoters())
+	assert.Equal(t, []string{"userID1", "userID2"}, testutils.GetFreeTextPollWithResponses().GetVoters())
+	assert.Equal(t, []string{"userID1", "userID2", "userID3"}, testutils.GetSurveyWithAnswers().GetVoters())
+}
+
 func TestPollCopy(t *testing.T) {
 	t.Run("no change", func(t *testing.T) {
 		p := testutils.GetPoll()
@@ -875,6 +1641,69 @@ func TestPollCopy(t *testing.T) {
 		assert.NotEqual(t, p, p2)
 		assert.Equal(t, testutils.GetPollWithVotes(), p2)
 	})
+	t.Run("change Ranks", func(t *testing.T) {
+		p := testutils.GetRankedPollWithBallots()
+		p2 := p.Copy()
+
+		msg, err := p.SubmitBallot("userID1", []int{2, 1, 0})
+		require.Nil(t, msg)
+		require.NoError(t, err)
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, testutils.GetRankedPollWithBallots(), p2)
+	})
+	t.Run("change Points", func(t *testing.T) {
+		p := testutils.GetPointsPollWithVotes()
+		p2 := p.Copy()
+
+		errMsg, err := p.SetPoints("userID1", []int{1, 1, 1})
+		require.Nil(t, errMsg)
+		require.NoError(t, err)
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, testutils.GetPointsPollWithVotes(), p2)
+	})
+	t.Run("change Availability", func(t *testing.T) {
+		p := testutils.GetSchedulingPollWithVotes()
+		p2 := p.Copy()
+
+		err := p.SetAvailability("userID1", []poll.Availability{poll.AvailabilityNo, poll.AvailabilityNo, poll.AvailabilityNo})
+		require.NoError(t, err)
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, testutils.GetSchedulingPollWithVotes(), p2)
+	})
+	t.Run("change VotedAt", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		p.UpdateVoteTimes(testutils.GetPoll(), "userID1", testutils.GetMillis())
+		p2 := p.Copy()
+
+		p.UpdateVoteTimes(testutils.GetPoll(), "userID2", testutils.GetMillis())
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, map[string]int64{"userID1": testutils.GetMillis()}, p2.AnswerOptions[0].VotedAt)
+	})
+	t.Run("change Correct", func(t *testing.T) {
+		p := testutils.GetQuizPollWithVotes()
+		p2 := p.Copy()
+
+		p.AnswerOptions[0].Correct = false
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, testutils.GetQuizPollWithVotes(), p2)
+	})
+	t.Run("change Responses", func(t *testing.T) {
+		p := testutils.GetFreeTextPollWithResponses()
+		p2 := p.Copy()
+
+		p.Responses[0].Text = "Changed response"
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, testutils.GetFreeTextPollWithResponses(), p2)
+	})
+	t.Run("change Questions", func(t *testing.T) {
+		p := testutils.GetSurveyWithAnswers()
+		p2 := p.Copy()
+
+		p.Questions[1].AnswerOptions[2].Voter = append(p.Questions[1].AnswerOptions[2].Voter, "userID2")
+		p.Questions[0].Settings.Anonymous = true
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, testutils.GetSurveyWithAnswers(), p2)
+	})
 	t.Run("change Settings", func(t *testing.T) {
 		p := testutils.GetPoll()
 		p2 := p.Copy()
@@ -884,6 +1713,38 @@ func TestPollCopy(t *testing.T) {
 		assert.NotEqual(t, p, p2)
 		assert.Equal(t, testutils.GetPoll(), p2)
 	})
+	t.Run("change Voters", func(t *testing.T) {
+		restrictedPoll := func() *poll.Poll {
+			return testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Voters: &poll.Voters{
+				Names:    []string{"user1"},
+				Roles:    []string{"team_admin"},
+				Channels: []string{"town-square"},
+			}})
+		}
+		p := restrictedPoll()
+		p2 := p.Copy()
+
+		p.Settings.Voters.Names[0] = "user2"
+		p.Settings.Voters.Roles = append(p.Settings.Voters.Roles, "channel_admin")
+		p.Settings.Voters.Channels[0] = "off-topic"
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, restrictedPoll(), p2)
+	})
+	t.Run("change Capacity and Waitlist", func(t *testing.T) {
+		fullPoll := func() *poll.Poll {
+			p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Capacity: 2, Waitlist: true})
+			p.AnswerOptions[0].Capacity = 3
+			p.AnswerOptions[0].Waitlist = []string{"userID5", "userID6"}
+			return p
+		}
+		p := fullPoll()
+		p2 := p.Copy()
+
+		p.AnswerOptions[0].Capacity = 4
+		p.AnswerOptions[0].Waitlist[0] = "userID7"
+		assert.NotEqual(t, p, p2)
+		assert.Equal(t, fullPoll(), p2)
+	})
 }
 
 func TestSettingsString(t *testing.T) {
@@ -907,6 +1768,58 @@ func TestSettingsString(t *testing.T) {
 			Settings: poll.Settings{PublicAddOption: true, MaxVotes: 1},
 			Expected: "public-add-option",
 		},
+		"ranked": {
+			Settings: poll.Settings{Ranked: true, MaxVotes: 1},
+			Expected: "ranked",
+		},
+		"points": {
+			Settings: poll.Settings{Points: 10, MaxVotes: 1},
+			Expected: "points=10",
+		},
+		"scheduling": {
+			Settings: poll.Settings{Scheduling: true, MaxVotes: 1},
+			Expected: "scheduling",
+		},
+		"quiz": {
+			Settings: poll.Settings{Quiz: true, MaxVotes: 1},
+			Expected: "quiz",
+		},
+		"free-text": {
+			Settings: poll.Settings{FreeText: true, MaxVotes: 1},
+			Expected: "free-text",
+		},
+		"close-at": {
+			Settings: poll.Settings{CloseAt: 20, MaxVotes: 1},
+			Expected: "close-at=20",
+		},
+		"close-when-all-voted": {
+			Settings: poll.Settings{CloseWhenAllVoted: true, MaxVotes: 1},
+			Expected: "close-when-all-voted",
+		},
+		"results-after-vote": {
+			Settings: poll.Settings{ResultsAfterVote: true, MaxVotes: 1},
+			Expected: "results-after-vote",
+		},
+		"final": {
+			Settings: poll.Settings{Final: true, MaxVotes: 1},
+			Expected: "final",
+		},
+		"capacity and waitlist": {
+			Settings: poll.Settings{Capacity: 5, Waitlist: true, MaxVotes: 1},
+			Expected: "capacity=5, waitlist",
+		},
+		"proxy": {
+			Settings: poll.Settings{Proxy: true, MaxVotes: 1},
+			Expected: "proxy",
+		},
+		"exclude-guests and exclude-bots": {
+			Settings: poll.Settings{ExcludeGuests: true, ExcludeBots: true, MaxVotes: 1},
+			Expected: "exclude-guests, exclude-bots",
+		},
+		"tie-break": {
+			Settings: poll.Settings{TieBreak: poll.TieBreakRunoff, MaxVotes: 1},
+			Expected: "tie-break=runoff",
+		},
 		"default votes": {
 			Settings: poll.Settings{MaxVotes: 1},
 			Expected: "",
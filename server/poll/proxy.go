@@ -0,0 +1,133 @@
+package poll
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// supportsProxyVotes returns true if votes of the poll can be cast on behalf of others.
+// Proxy votes must be visible to everyone, which rules out anonymous polls.
+// Polls, in which users vote via a dialog or score on a leaderboard, aren't supported either.
+func (p *Poll) supportsProxyVotes() bool {
+	return !p.Settings.Anonymous && !p.IsRanked() && !p.HasPointBudget() && !p.IsScheduling() && !p.IsQuiz() && !p.IsFreeText() && !p.IsSurvey()
+}
+
+// AllowsProxyVotes returns true if users can delegate their vote and poll managers can vote on behalf of others.
+func (p *Poll) AllowsProxyVotes() bool {
+	return p.Settings.Proxy && p.supportsProxyVotes()
+}
+
+// Delegate lets the user with a given delegateID vote on behalf of a given user.
+// An empty delegateID takes back the delegation.
+func (p *Poll) Delegate(userID, delegateID string) (*utils.ErrorMessage, error) {
+	if !p.AllowsProxyVotes() {
+		return nil, fmt.Errorf("poll doesn't allow proxy votes")
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("invalid userID")
+	}
+	if delegateID == userID {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.delegate.self",
+				Other: "You can't delegate your vote to yourself.",
+			},
+		}, nil
+	}
+
+	if delegateID == "" {
+		delete(p.Delegates, userID)
+		if len(p.Delegates) == 0 {
+			p.Delegates = nil
+		}
+		return nil, nil
+	}
+	if p.Delegates == nil {
+		p.Delegates = map[string]string{}
+	}
+	p.Delegates[userID] = delegateID
+	return nil, nil
+}
+
+// GetDelegate returns the user, to whom a given user delegated their vote. It's empty if they didn't delegate it.
+func (p *Poll) GetDelegate(userID string) string {
+	return p.Delegates[userID]
+}
+
+// GetDelegators returns the users, who delegated their vote to a given user, sorted by their ids.
+func (p *Poll) GetDelegators(delegateID string) []string {
+	delegators := []string{}
+	for userID, d := range p.Delegates {
+		if d == delegateID {
+			delegators = append(delegators, userID)
+		}
+	}
+	slices.Sort(delegators)
+	return delegators
+}
+
+// HasVotedByThemselves returns true if a given user cast any of their votes without a proxy.
+func (p *Poll) HasVotedByThemselves(userID string) bool {
+	for _, o := range p.AnswerOptions {
+		if slices.Contains(o.Voter, userID) && o.Proxies[userID] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateVoteByProxy performs a vote of a given user, that the user with a given proxyID cast on their behalf.
+// The vote is marked with the proxyID until the user votes again by themselves or resets their votes.
+// A proxy can only replace earlier proxy votes, but never the votes, that the user cast by themselves.
+func (p *Poll) UpdateVoteByProxy(proxyID, userID string, index int) (*i18n.Message, error) {
+	if !p.AllowsProxyVotes() {
+		return nil, fmt.Errorf("poll doesn't allow proxy votes")
+	}
+	if proxyID == "" {
+		return nil, fmt.Errorf("invalid proxyID")
+	}
+	if proxyID != userID && p.HasVotedByThemselves(userID) {
+		return &i18n.Message{
+			ID:    "poll.updateVoteByProxy.votedByThemselves",
+			Other: "This user has already voted by themselves.",
+		}, nil
+	}
+
+	msg, err := p.UpdateVote(userID, index)
+	if msg != nil || err != nil {
+		return msg, err
+	}
+	if proxyID != userID {
+		o := p.AnswerOptions[index]
+		if o.Proxies == nil {
+			o.Proxies = map[string]string{}
+		}
+		o.Proxies[userID] = proxyID
+	}
+	return nil, nil
+}
+
+// GetProxy returns the user, who cast the vote of a given user for the answer option with a given index.
+// It's empty if the user voted by themselves.
+func (p *Poll) GetProxy(userID string, index int) string {
+	if len(p.AnswerOptions) <= index || index < 0 {
+		return ""
+	}
+	o := p.AnswerOptions[index]
+	if !slices.Contains(o.Voter, userID) {
+		return ""
+	}
+	return o.Proxies[userID]
+}
+
+// removeProxy forgets who cast the vote of a given user for the answer option.
+func (o *AnswerOption) removeProxy(userID string) {
+	delete(o.Proxies, userID)
+	if len(o.Proxies) == 0 {
+		o.Proxies = nil
+	}
+}
@@ -0,0 +1,200 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestNewPollWithProxy(t *testing.T) {
+	var pf poll.Factory
+	pf.SetMillis(testutils.GetMillis)
+	pf.SetNewID(testutils.GetPollID)
+
+	p, errMsg := pf.NewPoll("userID1", "Question", []string{"Answer 1", "Answer 2"}, poll.Settings{MaxVotes: 2, Proxy: true})
+	require.Nil(t, errMsg)
+	assert.True(t, p.AllowsProxyVotes())
+
+	for name, settings := range map[string]poll.Settings{
+		"anonymous":  {MaxVotes: 1, Proxy: true, Anonymous: true},
+		"ranked":     {MaxVotes: 1, Proxy: true, Ranked: true},
+		"points":     {MaxVotes: 1, Proxy: true, Points: 3},
+		"scheduling": {MaxVotes: 1, Proxy: true, Scheduling: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, errMsg := pf.NewPoll("userID1", "Question", []string{"Answer 1", "Answer 2"}, settings)
+			assert.NotNil(t, errMsg)
+			assert.Nil(t, p)
+		})
+	}
+}
+
+func TestDelegate(t *testing.T) {
+	p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+
+	errMsg, err := p.Delegate("userID2", "userID3")
+	require.NoError(t, err)
+	require.Nil(t, errMsg)
+	assert.Equal(t, "userID3", p.GetDelegate("userID2"))
+
+	errMsg, err = p.Delegate("userID4", "userID3")
+	require.NoError(t, err)
+	require.Nil(t, errMsg)
+	assert.Equal(t, []string{"userID2", "userID4"}, p.GetDelegators("userID3"))
+	assert.Equal(t, []string{}, p.GetDelegators("userID2"))
+
+	errMsg, err = p.Delegate("userID2", "userID2")
+	require.NoError(t, err)
+	assert.Equal(t, "poll.delegate.self", errMsg.Message.ID)
+	assert.Equal(t, "userID3", p.GetDelegate("userID2"))
+
+	errMsg, err = p.Delegate("userID2", "")
+	require.NoError(t, err)
+	require.Nil(t, errMsg)
+	assert.Equal(t, "", p.GetDelegate("userID2"))
+	assert.Equal(t, []string{"userID4"}, p.GetDelegators("userID3"))
+
+	errMsg, err = p.Delegate("userID4", "")
+	require.NoError(t, err)
+	require.Nil(t, errMsg)
+	assert.Nil(t, p.Delegates)
+
+	t.Run("without proxy setting", func(t *testing.T) {
+		p := testutils.GetPoll()
+		_, err := p.Delegate("userID2", "userID3")
+		assert.Error(t, err)
+	})
+}
+
+func TestUpdateVoteByProxy(t *testing.T) {
+	t.Run("single vote", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+
+		msg, err := p.UpdateVoteByProxy("userID1", "userID2", 0)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		assert.Equal(t, []string{"userID2"}, p.AnswerOptions[0].Voter)
+		assert.Equal(t, "userID1", p.GetProxy("userID2", 0))
+
+		// Changing the vote by proxy moves the mark
+		msg, err = p.UpdateVoteByProxy("userID1", "userID2", 1)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		assert.Nil(t, p.AnswerOptions[0].Proxies)
+		assert.Equal(t, "userID1", p.GetProxy("userID2", 1))
+
+		// Voting by themselves removes the mark
+		msg, err = p.UpdateVote("userID2", 1)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		assert.Equal(t, "", p.GetProxy("userID2", 1))
+		assert.Nil(t, p.AnswerOptions[1].Proxies)
+	})
+	t.Run("multi vote", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 2, Proxy: true})
+
+		msg, err := p.UpdateVoteByProxy("userID1", "userID2", 0)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		msg, err = p.UpdateVoteByProxy("userID3", "userID2", 1)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		assert.Equal(t, "userID1", p.GetProxy("userID2", 0))
+		assert.Equal(t, "userID3", p.GetProxy("userID2", 1))
+
+		msg, err = p.UpdateVoteByProxy("userID1", "userID2", 2)
+		require.NoError(t, err)
+		assert.Equal(t, "poll.updateVote.maxVotes", msg.ID)
+		assert.Equal(t, "", p.GetProxy("userID2", 2))
+
+		p.ResetVotes("userID2")
+		assert.Equal(t, "", p.GetProxy("userID2", 1))
+		assert.Nil(t, p.AnswerOptions[1].Proxies)
+	})
+	t.Run("user voted by themselves", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+
+		msg, err := p.UpdateVote("userID2", 0)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+
+		msg, err = p.UpdateVoteByProxy("userID1", "userID2", 1)
+		require.NoError(t, err)
+		require.NotNil(t, msg)
+		assert.Equal(t, "poll.updateVoteByProxy.votedByThemselves", msg.ID)
+		assert.Equal(t, []string{"userID2"}, p.AnswerOptions[0].Voter)
+		assert.Equal(t, []string{}, p.AnswerOptions[1].Voter)
+		assert.Equal(t, "", p.GetProxy("userID2", 1))
+	})
+	t.Run("user voted by themselves in multi vote", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 2, Proxy: true})
+
+		msg, err := p.UpdateVoteByProxy("userID1", "userID2", 0)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		msg, err = p.UpdateVote("userID2", 1)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+
+		msg, err = p.UpdateVoteByProxy("userID1", "userID2", 0)
+		require.NoError(t, err)
+		require.NotNil(t, msg)
+		assert.Equal(t, "poll.updateVoteByProxy.votedByThemselves", msg.ID)
+		assert.Equal(t, "userID1", p.GetProxy("userID2", 0))
+	})
+	t.Run("proxy votes for themselves", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+
+		msg, err := p.UpdateVoteByProxy("userID1", "userID1", 0)
+		require.NoError(t, err)
+		require.Nil(t, msg)
+		assert.Equal(t, "", p.GetProxy("userID1", 0))
+	})
+	t.Run("without proxy setting", func(t *testing.T) {
+		p := testutils.GetPoll()
+		_, err := p.UpdateVoteByProxy("userID1", "userID2", 0)
+		assert.Error(t, err)
+		assert.Equal(t, []string{}, p.AnswerOptions[0].Voter)
+	})
+	t.Run("invalid index", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+		_, err := p.UpdateVoteByProxy("userID1", "userID2", 3)
+		assert.Error(t, err)
+		assert.Equal(t, "", p.GetProxy("userID2", 3))
+	})
+}
+
+func TestPollWithProxyVotes(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		return "@" + userID, nil
+	}
+	p := testutils.GetPollWithVotesAndSettings(poll.Settings{MaxVotes: 1, Proxy: true})
+	p.AnswerOptions[1].Proxies = map[string]string{"userID4": "userID1"}
+
+	t.Run("end post", func(t *testing.T) {
+		post, appErr := p.ToEndPollPost(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe", converter)
+		require.Nil(t, appErr)
+		fields := post.Attachments()[0].Fields
+		assert.Equal(t, "@userID1, @userID2 and @userID3", fields[0].Value)
+		assert.Equal(t, "@userID4 (by proxy: @userID1)", fields[1].Value)
+	})
+	t.Run("post actions", func(t *testing.T) {
+		attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+		var ids []string
+		for _, action := range attachments[0].Actions {
+			ids = append(ids, action.Id)
+		}
+		assert.Contains(t, ids, "delegateVote")
+		assert.Contains(t, ids, "proxyVote")
+
+		attachments = testutils.GetPollWithVotes().ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+		for _, action := range attachments[0].Actions {
+			assert.NotContains(t, []string{"delegateVote", "proxyVote"}, action.Id)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package poll
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// quizCorrectPrefix marks the correct answers of a quiz when creating it, e.g. "*Answer 1".
+const quizCorrectPrefix = "*"
+
+// Leaderboard keeps the quiz scores of all users in a channel.
+type Leaderboard struct {
+	// Scores maps user ids to their score.
+	Scores map[string]*Score `json:"scores"`
+}
+
+// Score is the result of a single user across all quizzes of a channel.
+type Score struct {
+	Correct  int `json:"correct"`
+	Answered int `json:"answered"`
+}
+
+// IsQuiz returns true if the poll is a quiz with correct answers
+func (p *Poll) IsQuiz() bool {
+	return p.Settings.Quiz
+}
+
+// parseQuizAnswer strips the prefix marking a correct answer from an answer option of a quiz.
+// It returns true if the answer option is correct.
+func parseQuizAnswer(answerOption string) (string, bool) {
+	answerOption = strings.TrimSpace(answerOption)
+	if strings.HasPrefix(answerOption, quizCorrectPrefix) {
+		return strings.TrimPrefix(answerOption, quizCorrectPrefix), true
+	}
+	return answerOption, false
+}
+
+// GetCorrectAnswers returns the correct answers of a quiz.
+func (p *Poll) GetCorrectAnswers() []string {
+	answers := []string{}
+	for _, o := range p.AnswerOptions {
+		if o.Correct {
+			answers = append(answers, o.Answer)
+		}
+	}
+	return answers
+}
+
+// AnsweredCorrectly returns true if a given user voted for a correct answer of a quiz.
+func (p *Poll) AnsweredCorrectly(userID string) bool {
+	for _, o := range p.AnswerOptions {
+		if !o.Correct {
+			continue
+		}
+		for _, v := range o.Voter {
+			if v == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewLeaderboard returns an empty leaderboard.
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{Scores: map[string]*Score{}}
+}
+
+// AddQuizResults counts the answers of every user who took part in a given quiz.
+func (l *Leaderboard) AddQuizResults(p *Poll) {
+	if l.Scores == nil {
+		l.Scores = map[string]*Score{}
+	}
+
+	for _, o := range p.AnswerOptions {
+		for _, v := range o.Voter {
+			score, ok := l.Scores[v]
+			if !ok {
+				score = &Score{}
+				l.Scores[v] = score
+			}
+			score.Answered++
+			if o.Correct {
+				score.Correct++
+			}
+		}
+	}
+}
+
+// Ranking returns the ids of all users on the leaderboard, sorted by the number of correct answers.
+// Users with the same number of correct answers are sorted by the number of answered quizzes, fewer first.
+func (l *Leaderboard) Ranking() []string {
+	userIDs := make([]string, 0, len(l.Scores))
+	for userID := range l.Scores {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		a, b := l.Scores[userIDs[i]], l.Scores[userIDs[j]]
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+		if a.Answered != b.Answered {
+			return a.Answered < b.Answered
+		}
+		return userIDs[i] < userIDs[j]
+	})
+	return userIDs
+}
+
+// EncodeToByte returns a leaderboard as a byte array
+func (l *Leaderboard) EncodeToByte() []byte {
+	b, _ := json.Marshal(l)
+	return b
+}
+
+// DecodeLeaderboardFromByte tries to create a leaderboard from a byte array
+func DecodeLeaderboardFromByte(b []byte) *Leaderboard {
+	l := Leaderboard{}
+	err := json.Unmarshal(b, &l)
+	if err != nil {
+		return nil
+	}
+	if l.Scores == nil {
+		l.Scores = map[string]*Score{}
+	}
+	return &l
+}
@@ -0,0 +1,85 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestNewQuiz(t *testing.T) {
+	var pf poll.Factory
+	pf.SetMillis(testutils.GetMillis)
+	pf.SetNewID(testutils.GetPollID)
+
+	p, errMsg := pf.NewPoll("userID1", "Question", []string{"*Answer 1", "Answer 2", " * Answer 3"}, poll.Settings{MaxVotes: 1, Quiz: true})
+	require.Nil(t, errMsg)
+	assert.Equal(t, []*poll.AnswerOption{
+		{Answer: "Answer 1", Voter: []string{}, Correct: true},
+		{Answer: "Answer 2", Voter: []string{}},
+		{Answer: "Answer 3", Voter: []string{}, Correct: true},
+	}, p.AnswerOptions)
+	assert.Equal(t, []string{"Answer 1", "Answer 3"}, p.GetCorrectAnswers())
+
+	t.Run("prefix is kept for regular polls", func(t *testing.T) {
+		p, errMsg := pf.NewPoll("userID1", "Question", []string{"*Answer 1", "Answer 2"}, poll.Settings{MaxVotes: 1})
+		require.Nil(t, errMsg)
+		assert.Equal(t, "*Answer 1", p.AnswerOptions[0].Answer)
+		assert.False(t, p.AnswerOptions[0].Correct)
+	})
+	t.Run("duplicate after removing the prefix", func(t *testing.T) {
+		p, errMsg := pf.NewPoll("userID1", "Question", []string{"*Answer 1", "Answer 1"}, poll.Settings{MaxVotes: 1, Quiz: true})
+		assert.NotNil(t, errMsg)
+		assert.Nil(t, p)
+	})
+}
+
+func TestAnsweredCorrectly(t *testing.T) {
+	p := testutils.GetQuizPollWithVotes()
+
+	assert.True(t, p.AnsweredCorrectly("userID1"))
+	assert.True(t, p.AnsweredCorrectly("userID2"))
+	assert.False(t, p.AnsweredCorrectly("userID3"))
+	assert.False(t, p.AnsweredCorrectly("userID4"))
+}
+
+func TestLeaderboard(t *testing.T) {
+	t.Run("add quiz results", func(t *testing.T) {
+		l := poll.NewLeaderboard()
+		l.AddQuizResults(testutils.GetQuizPollWithVotes())
+		l.AddQuizResults(testutils.GetQuizPollWithVotes())
+
+		assert.Equal(t, map[string]*poll.Score{
+			"userID1": {Correct: 2, Answered: 2},
+			"userID2": {Correct: 2, Answered: 2},
+			"userID3": {Correct: 0, Answered: 2},
+		}, l.Scores)
+	})
+	t.Run("add quiz results to decoded leaderboard without scores", func(t *testing.T) {
+		l := &poll.Leaderboard{}
+		l.AddQuizResults(testutils.GetQuizPollWithVotes())
+
+		assert.Len(t, l.Scores, 3)
+	})
+	t.Run("ranking", func(t *testing.T) {
+		l := &poll.Leaderboard{Scores: map[string]*poll.Score{
+			"userID1": {Correct: 1, Answered: 3},
+			"userID2": {Correct: 2, Answered: 3},
+			"userID3": {Correct: 1, Answered: 1},
+			"userID4": {Correct: 1, Answered: 3},
+		}}
+
+		assert.Equal(t, []string{"userID2", "userID3", "userID1", "userID4"}, l.Ranking())
+	})
+	t.Run("encode and decode", func(t *testing.T) {
+		l := poll.NewLeaderboard()
+		l.AddQuizResults(testutils.GetQuizPollWithVotes())
+
+		assert.Equal(t, l, poll.DecodeLeaderboardFromByte(l.EncodeToByte()))
+		assert.Equal(t, poll.NewLeaderboard(), poll.DecodeLeaderboardFromByte([]byte("{}")))
+		assert.Nil(t, poll.DecodeLeaderboardFromByte([]byte("invalid")))
+	})
+}
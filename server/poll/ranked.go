@@ -0,0 +1,165 @@
+package poll
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// RankedResult is the outcome of tallying a ranked-choice poll via instant-runoff.
+type RankedResult struct {
+	Rounds []*RankedRound
+	// Winners contains the indexes of the winning answer options.
+	// It contains more than one index if the last remaining options are tied
+	// and is empty if no ballot was submitted.
+	Winners []int
+}
+
+// RankedRound is a single round of an instant-runoff tally.
+type RankedRound struct {
+	// Counts maps the index of every answer option still in the race
+	// to the number of ballots ranking it highest among the remaining options.
+	Counts map[int]int
+	// Eliminated contains the indexes of the answer options eliminated after this round.
+	Eliminated []int
+}
+
+// IsRanked returns true if the poll is a ranked-choice poll
+func (p *Poll) IsRanked() bool {
+	return p.Settings.Ranked
+}
+
+// SubmitBallot replaces the ballot of a given user with a new ranking.
+// ranking contains the indexes of the ranked answer options, starting with the most preferred one.
+// Options that are not part of the ranking are left unranked.
+func (p *Poll) SubmitBallot(userID string, ranking []int) (*i18n.Message, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid userID")
+	}
+	if len(ranking) == 0 {
+		return &i18n.Message{
+			ID:    "poll.submitBallot.empty",
+			Other: "Please rank at least one option.",
+		}, nil
+	}
+
+	ranked := make(map[int]bool, len(ranking))
+	for _, index := range ranking {
+		if len(p.AnswerOptions) <= index || index < 0 {
+			return nil, fmt.Errorf("invalid index")
+		}
+		if ranked[index] {
+			return &i18n.Message{
+				ID:    "poll.submitBallot.duplicate",
+				Other: "You can rank every option only once.",
+			}, nil
+		}
+		ranked[index] = true
+	}
+
+	p.ResetVotes(userID)
+	for rank, index := range ranking {
+		o := p.AnswerOptions[index]
+		o.Voter = append(o.Voter, userID)
+		if o.Ranks == nil {
+			o.Ranks = map[string]int{}
+		}
+		o.Ranks[userID] = rank + 1
+	}
+	return nil, nil
+}
+
+// GetBallot returns the indexes of the answer options ranked by a given user, starting with the most preferred one.
+func (p *Poll) GetBallot(userID string) []int {
+	ballot := []int{}
+	for i, o := range p.AnswerOptions {
+		if _, ok := o.Ranks[userID]; ok {
+			ballot = append(ballot, i)
+		}
+	}
+	sort.SliceStable(ballot, func(i, j int) bool {
+		return p.AnswerOptions[ballot[i]].Ranks[userID] < p.AnswerOptions[ballot[j]].Ranks[userID]
+	})
+	return ballot
+}
+
+// GetBallotVoters returns the ids of all users who submitted a ballot, sorted in the order they first appear in the poll.
+func (p *Poll) GetBallotVoters() []string {
+	voters := []string{}
+	seen := map[string]bool{}
+	for _, o := range p.AnswerOptions {
+		for _, v := range o.Voter {
+			if _, ok := o.Ranks[v]; ok && !seen[v] {
+				seen[v] = true
+				voters = append(voters, v)
+			}
+		}
+	}
+	return voters
+}
+
+// TallyRanked counts the ballots of a ranked-choice poll using instant-runoff voting.
+// In every round each ballot counts for its highest ranked option that is still in the race.
+// An option supported by more than half of the counted ballots wins.
+// Otherwise all options with the fewest votes are eliminated and the next round starts.
+// If all remaining options have the same number of votes, they tie.
+// The result always contains at least one round.
+func (p *Poll) TallyRanked() *RankedResult {
+	ballots := [][]int{}
+	for _, v := range p.GetBallotVoters() {
+		ballots = append(ballots, p.GetBallot(v))
+	}
+
+	remaining := make(map[int]bool, len(p.AnswerOptions))
+	for i := range p.AnswerOptions {
+		remaining[i] = true
+	}
+
+	result := &RankedResult{Winners: []int{}}
+	for len(remaining) > 0 {
+		round := &RankedRound{Counts: make(map[int]int, len(remaining)), Eliminated: []int{}}
+		for i := range remaining {
+			round.Counts[i] = 0
+		}
+
+		total := 0
+		for _, ballot := range ballots {
+			for _, i := range ballot {
+				if remaining[i] {
+					round.Counts[i]++
+					total++
+					break
+				}
+			}
+		}
+		result.Rounds = append(result.Rounds, round)
+
+		if total == 0 {
+			break
+		}
+
+		lowest, highest := total, 0
+		for _, count := range round.Counts {
+			lowest = min(lowest, count)
+			highest = max(highest, count)
+		}
+
+		if highest*2 > total || lowest == highest {
+			for i := range p.AnswerOptions {
+				if count, ok := round.Counts[i]; ok && count == highest {
+					result.Winners = append(result.Winners, i)
+				}
+			}
+			break
+		}
+
+		for i := range p.AnswerOptions {
+			if count, ok := round.Counts[i]; ok && count == lowest {
+				round.Eliminated = append(round.Eliminated, i)
+				delete(remaining, i)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestSubmitBallot(t *testing.T) {
+	for name, test := range map[string]struct {
+		Poll          *poll.Poll
+		UserID        string
+		Ranking       []int
+		ExpectedPoll  *poll.Poll
+		Error         bool
+		ReturnMessage bool
+	}{
+		"first ballot": {
+			Poll:    testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Ranked: true}),
+			UserID:  "a",
+			Ranking: []int{2, 0},
+			ExpectedPoll: &poll.Poll{
+				ID:        testutils.GetPollID(),
+				PostID:    "postID1",
+				CreatedAt: testutils.GetMillis(),
+				Creator:   "userID1",
+				Question:  "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}, Ranks: map[string]int{"a": 2}},
+					{Answer: "Answer 2", Voter: []string{}},
+					{Answer: "Answer 3", Voter: []string{"a"}, Ranks: map[string]int{"a": 1}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+		},
+		"replace ballot": {
+			Poll:    testutils.GetRankedPollWithBallots(),
+			UserID:  "userID1",
+			Ranking: []int{2},
+			ExpectedPoll: &poll.Poll{
+				ID:        testutils.GetPollID(),
+				PostID:    "postID1",
+				CreatedAt: testutils.GetMillis(),
+				Creator:   "userID1",
+				Question:  "Question",
+				AnswerOptions: []*poll.AnswerOption{{
+					Answer: "Answer 1",
+					Voter:  []string{"userID2", "userID4"},
+					Ranks:  map[string]int{"userID2": 2, "userID4": 1},
+				}, {
+					Answer: "Answer 2",
+					Voter:  []string{"userID2", "userID3"},
+					Ranks:  map[string]int{"userID2": 1, "userID3": 2},
+				}, {
+					Answer: "Answer 3",
+					Voter:  []string{"userID3", "userID4", "userID1"},
+					Ranks:  map[string]int{"userID3": 1, "userID4": 2, "userID1": 1},
+				}},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+		},
+		"empty ranking": {
+			Poll:          testutils.GetRankedPollWithBallots(),
+			UserID:        "userID1",
+			Ranking:       []int{},
+			ExpectedPoll:  testutils.GetRankedPollWithBallots(),
+			ReturnMessage: true,
+		},
+		"duplicate option": {
+			Poll:          testutils.GetRankedPollWithBallots(),
+			UserID:        "userID1",
+			Ranking:       []int{1, 1},
+			ExpectedPoll:  testutils.GetRankedPollWithBallots(),
+			ReturnMessage: true,
+		},
+		"invalid index": {
+			Poll:         testutils.GetRankedPollWithBallots(),
+			UserID:       "userID1",
+			Ranking:      []int{0, 3},
+			ExpectedPoll: testutils.GetRankedPollWithBallots(),
+			Error:        true,
+		},
+		"invalid user id": {
+			Poll:         testutils.GetRankedPollWithBallots(),
+			UserID:       "",
+			Ranking:      []int{0},
+			ExpectedPoll: testutils.GetRankedPollWithBallots(),
+			Error:        true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			msg, err := test.Poll.SubmitBallot(test.UserID, test.Ranking)
+
+			if test.Error {
+				assert.NotNil(err)
+			} else {
+				assert.Nil(err)
+			}
+
+			if test.ReturnMessage {
+				assert.NotNil(msg)
+			} else {
+				assert.Nil(msg)
+			}
+			assert.Equal(test.ExpectedPoll, test.Poll)
+		})
+	}
+}
+
+func TestGetBallot(t *testing.T) {
+	p := testutils.GetRankedPollWithBallots()
+
+	assert.Equal(t, []int{0, 1}, p.GetBallot("userID1"))
+	assert.Equal(t, []int{1, 0}, p.GetBallot("userID2"))
+	assert.Equal(t, []int{2, 1}, p.GetBallot("userID3"))
+	assert.Equal(t, []int{}, p.GetBallot("userID5"))
+	assert.Equal(t, []string{"Answer 2", "Answer 1"}, p.GetVotedAnswers("userID2"))
+	assert.Equal(t, []string{"userID1", "userID2", "userID4", "userID3"}, p.GetBallotVoters())
+}
+
+func TestTallyRanked(t *testing.T) {
+	for name, test := range map[string]struct {
+		Poll           *poll.Poll
+		ExpectedResult *poll.RankedResult
+	}{
+		"no ballots": {
+			Poll: testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Ranked: true}),
+			ExpectedResult: &poll.RankedResult{
+				Rounds: []*poll.RankedRound{
+					{Counts: map[int]int{0: 0, 1: 0, 2: 0}, Eliminated: []int{}},
+				},
+				Winners: []int{},
+			},
+		},
+		"majority after elimination": {
+			Poll: testutils.GetRankedPollWithBallots(),
+			ExpectedResult: &poll.RankedResult{
+				Rounds: []*poll.RankedRound{
+					{Counts: map[int]int{0: 2, 1: 1, 2: 1}, Eliminated: []int{1, 2}},
+					{Counts: map[int]int{0: 3}, Eliminated: []int{}},
+				},
+				Winners: []int{0},
+			},
+		},
+		"majority in first round": {
+			Poll: &poll.Poll{
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a", "b"}, Ranks: map[string]int{"a": 1, "b": 1}},
+					{Answer: "Answer 2", Voter: []string{"c"}, Ranks: map[string]int{"c": 1}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+			ExpectedResult: &poll.RankedResult{
+				Rounds: []*poll.RankedRound{
+					{Counts: map[int]int{0: 2, 1: 1}, Eliminated: []int{}},
+				},
+				Winners: []int{0},
+			},
+		},
+		"tie": {
+			Poll: &poll.Poll{
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a", "b"}, Ranks: map[string]int{"a": 1, "b": 2}},
+					{Answer: "Answer 2", Voter: []string{"a", "b"}, Ranks: map[string]int{"a": 2, "b": 1}},
+					{Answer: "Answer 3", Voter: []string{}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+			},
+			ExpectedResult: &poll.RankedResult{
+				Rounds: []*poll.RankedRound{
+					{Counts: map[int]int{0: 1, 1: 1, 2: 0}, Eliminated: []int{2}},
+					{Counts: map[int]int{0: 1, 1: 1}, Eliminated: []int{}},
+				},
+				Winners: []int{0, 1},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.ExpectedResult, test.Poll.TallyRanked())
+		})
+	}
+}
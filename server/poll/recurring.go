@@ -0,0 +1,134 @@
+package poll
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// RecurringPoll is a poll, that is posted again and again on a schedule, e.g. every Friday at 10:00.
+type RecurringPoll struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	// Poll is the template of the posted polls. Its creator is the creator of the recurring poll.
+	Poll *Poll `json:"poll"`
+	// Daily is true if the poll is posted every day. Otherwise it's posted once a week on Weekday.
+	Daily   bool         `json:"daily,omitempty"`
+	Weekday time.Weekday `json:"weekday"`
+	Hour    int          `json:"hour"`
+	Minute  int          `json:"minute"`
+	// Timezone is the name of the time zone, in which Hour and Minute are given.
+	Timezone string `json:"timezone"`
+	// EndPrevious is true if the previously posted poll gets ended, when the next one is posted.
+	EndPrevious bool `json:"end_previous,omitempty"`
+	// LastPollID is the id of the poll posted last.
+	LastPollID string `json:"last_poll_id,omitempty"`
+	// NextRunAt is the time in milliseconds at which the poll is posted next.
+	NextRunAt int64 `json:"next_run_at"`
+}
+
+// recurringPollDaily is the day, that makes a recurring poll be posted every day.
+const recurringPollDaily = "daily"
+
+// NewRecurringPoll creates a new recurring poll, that posts a given poll in a channel.
+// day is either "daily" or a weekday like "friday" or "fri". clock is the time of day like "10:00" in the given time zone.
+func (fac *Factory) NewRecurringPoll(template *Poll, channelID, day, clock, timezone string, endPrevious bool) (*RecurringPoll, *utils.ErrorMessage) {
+	r := RecurringPoll{
+		ID:          fac.NewID(),
+		ChannelID:   channelID,
+		Poll:        template,
+		EndPrevious: endPrevious,
+	}
+
+	day = strings.ToLower(day)
+	if day == recurringPollDaily {
+		r.Daily = true
+	} else {
+		weekday, ok := parseWeekday(day)
+		if !ok {
+			return nil, &utils.ErrorMessage{
+				Message: &i18n.Message{
+					ID:    "poll.newRecurringPoll.day.invalid",
+					Other: `Invalid day "{{.Day}}". Use "daily" or a weekday like "friday".`,
+				},
+				Data: map[string]interface{}{
+					"Day": day,
+				},
+			}
+		}
+		r.Weekday = weekday
+	}
+
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newRecurringPoll.time.invalid",
+				Other: `Invalid time "{{.Time}}". Use the time of day like "10:00".`,
+			},
+			Data: map[string]interface{}{
+				"Time": clock,
+			},
+		}
+	}
+	r.Hour = t.Hour()
+	r.Minute = t.Minute()
+
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+		timezone = "UTC"
+	}
+	r.Timezone = timezone
+
+	r.NextRunAt = r.NextRun(fac.Millis())
+	return &r, nil
+}
+
+// parseWeekday parses the full or abbreviated english name of a weekday.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// location returns the time zone of the recurring poll.
+func (r *RecurringPoll) location() *time.Location {
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextRun returns the first time in milliseconds after a given time, at which the poll is posted.
+func (r *RecurringPoll) NextRun(after int64) int64 {
+	t := time.UnixMilli(after).In(r.location())
+	next := time.Date(t.Year(), t.Month(), t.Day(), r.Hour, r.Minute, 0, 0, t.Location())
+	for !next.After(t) || (!r.Daily && next.Weekday() != r.Weekday) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.UnixMilli()
+}
+
+// EncodeToByte returns a recurring poll as a byte array
+func (r *RecurringPoll) EncodeToByte() []byte {
+	b, _ := json.Marshal(r)
+	return b
+}
+
+// DecodeRecurringPollFromByte tries to create a recurring poll from a byte array
+func DecodeRecurringPollFromByte(b []byte) *RecurringPoll {
+	r := RecurringPoll{}
+	err := json.Unmarshal(b, &r)
+	if err != nil || r.Poll == nil {
+		return nil
+	}
+	return &r
+}
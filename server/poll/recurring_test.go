@@ -0,0 +1,160 @@
+package poll_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestNewRecurringPoll(t *testing.T) {
+	// Tuesday, 24 Dec 2024 12:00 UTC
+	now := time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)
+	var pf poll.Factory
+	pf.SetMillis(func() int64 { return now.UnixMilli() })
+	pf.SetNewID(func() string { return "recurringPollID" })
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		Day               string
+		Clock             string
+		Timezone          string
+		ExpectedDaily     bool
+		ExpectedWeekday   time.Weekday
+		ExpectedTimezone  string
+		ExpectedNextRunAt time.Time
+		ShouldError       bool
+	}{
+		"weekly": {
+			Day:               "Friday",
+			Clock:             "10:00",
+			Timezone:          "Europe/Berlin",
+			ExpectedWeekday:   time.Friday,
+			ExpectedTimezone:  "Europe/Berlin",
+			ExpectedNextRunAt: time.Date(2024, 12, 27, 10, 0, 0, 0, berlin),
+		},
+		"weekly, abbreviated weekday": {
+			Day:               "fri",
+			Clock:             "10:00",
+			Timezone:          "UTC",
+			ExpectedWeekday:   time.Friday,
+			ExpectedTimezone:  "UTC",
+			ExpectedNextRunAt: time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC),
+		},
+		"weekly, today but time passed": {
+			Day:               "tuesday",
+			Clock:             "11:59",
+			Timezone:          "UTC",
+			ExpectedWeekday:   time.Tuesday,
+			ExpectedTimezone:  "UTC",
+			ExpectedNextRunAt: time.Date(2024, 12, 31, 11, 59, 0, 0, time.UTC),
+		},
+		"daily, later today": {
+			Day:               "daily",
+			Clock:             "13:30",
+			Timezone:          "UTC",
+			ExpectedDaily:     true,
+			ExpectedTimezone:  "UTC",
+			ExpectedNextRunAt: time.Date(2024, 12, 24, 13, 30, 0, 0, time.UTC),
+		},
+		"daily, tomorrow": {
+			Day:               "Daily",
+			Clock:             "9:15",
+			Timezone:          "UTC",
+			ExpectedDaily:     true,
+			ExpectedTimezone:  "UTC",
+			ExpectedNextRunAt: time.Date(2024, 12, 25, 9, 15, 0, 0, time.UTC),
+		},
+		"unknown timezone falls back to UTC": {
+			Day:               "daily",
+			Clock:             "13:30",
+			Timezone:          "Unknown/Timezone",
+			ExpectedDaily:     true,
+			ExpectedTimezone:  "UTC",
+			ExpectedNextRunAt: time.Date(2024, 12, 24, 13, 30, 0, 0, time.UTC),
+		},
+		"empty timezone falls back to UTC": {
+			Day:               "daily",
+			Clock:             "13:30",
+			Timezone:          "",
+			ExpectedDaily:     true,
+			ExpectedTimezone:  "UTC",
+			ExpectedNextRunAt: time.Date(2024, 12, 24, 13, 30, 0, 0, time.UTC),
+		},
+		"invalid day": {
+			Day:         "someday",
+			Clock:       "10:00",
+			Timezone:    "UTC",
+			ShouldError: true,
+		},
+		"invalid time": {
+			Day:         "friday",
+			Clock:       "25:00",
+			Timezone:    "UTC",
+			ShouldError: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			r, errMsg := pf.NewRecurringPoll(testutils.GetPoll(), "channelID1", test.Day, test.Clock, test.Timezone, true)
+			if test.ShouldError {
+				assert.NotNil(errMsg)
+				assert.Nil(r)
+				return
+			}
+
+			require.Nil(t, errMsg)
+			assert.Equal("recurringPollID", r.ID)
+			assert.Equal("channelID1", r.ChannelID)
+			assert.Equal(testutils.GetPoll(), r.Poll)
+			assert.True(r.EndPrevious)
+			assert.Equal(test.ExpectedDaily, r.Daily)
+			assert.Equal(test.ExpectedWeekday, r.Weekday)
+			assert.Equal(test.ExpectedTimezone, r.Timezone)
+			assert.Equal(test.ExpectedNextRunAt.UnixMilli(), r.NextRunAt)
+		})
+	}
+}
+
+func TestRecurringPollNextRun(t *testing.T) {
+	t.Run("weekly", func(t *testing.T) {
+		r := &poll.RecurringPoll{Weekday: time.Friday, Hour: 10, Timezone: "UTC"}
+		friday := time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC)
+
+		assert.Equal(t, friday.UnixMilli(), r.NextRun(friday.Add(-time.Minute).UnixMilli()))
+		assert.Equal(t, friday.AddDate(0, 0, 7).UnixMilli(), r.NextRun(friday.UnixMilli()))
+	})
+	t.Run("keeps the time of day across daylight saving time changes", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		r := &poll.RecurringPoll{Daily: true, Hour: 10, Minute: 30, Timezone: "Europe/Berlin"}
+
+		next := r.NextRun(time.Date(2025, 3, 29, 12, 0, 0, 0, berlin).UnixMilli())
+		assert.Equal(t, time.Date(2025, 3, 30, 10, 30, 0, 0, berlin).UnixMilli(), next)
+		assert.Equal(t, time.Date(2025, 3, 30, 8, 30, 0, 0, time.UTC).UnixMilli(), next)
+	})
+}
+
+func TestRecurringPollEncodeDecode(t *testing.T) {
+	r := &poll.RecurringPoll{
+		ID:          "recurringPollID",
+		ChannelID:   "channelID1",
+		Poll:        testutils.GetPoll(),
+		Weekday:     time.Friday,
+		Hour:        10,
+		Timezone:    "Europe/Berlin",
+		EndPrevious: true,
+		LastPollID:  "lastPollID",
+		NextRunAt:   testutils.GetMillis(),
+	}
+	assert.Equal(t, r, poll.DecodeRecurringPollFromByte(r.EncodeToByte()))
+	assert.Nil(t, poll.DecodeRecurringPollFromByte([]byte{}))
+	assert.Nil(t, poll.DecodeRecurringPollFromByte([]byte(`{"id": "recurringPollID"}`)))
+}
@@ -0,0 +1,95 @@
+package poll
+
+import (
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// Rerun creates a new poll with the same question, answer options and settings as a given poll, but without any votes.
+// The given poll may be active or ended. A scheduled end is moved, so that the new poll runs as long as the given one.
+func (fac *Factory) Rerun(creator string, p *Poll) (*Poll, *utils.ErrorMessage) {
+	settings := fac.rerunSettings(p)
+	if p.IsSurvey() {
+		return fac.rerunSurvey(creator, p, settings)
+	}
+
+	return fac.newPollWithAnswerOptions(creator, p.Question, p.AnswerOptions, settings)
+}
+
+// Runoff creates a new poll between the tied answer options of a given ended poll.
+// It has the same question and settings, but every voter has a single vote and another tie is left as it is, so that runoffs don't repeat endlessly.
+func (fac *Factory) Runoff(p *Poll) (*Poll, *utils.ErrorMessage) {
+	settings := fac.rerunSettings(p)
+	settings.MaxVotes = 1
+	settings.TieBreak = TieBreakNone
+
+	winners := p.GetWinners()
+	answerOptions := make([]*AnswerOption, 0, len(winners))
+	for _, i := range winners {
+		answerOptions = append(answerOptions, p.AnswerOptions[i])
+	}
+	return fac.newPollWithAnswerOptions(p.Creator, p.Question, answerOptions, settings)
+}
+
+// newPollWithAnswerOptions creates a new poll with the given answer options, but without their votes.
+// Unlike NewPoll, the answers aren't parsed again, so that they keep their capacity and whether they are correct.
+func (fac *Factory) newPollWithAnswerOptions(creator, question string, answerOptions []*AnswerOption, settings Settings) (*Poll, *utils.ErrorMessage) {
+	p := Poll{
+		ID:        fac.NewID(),
+		CreatedAt: fac.Millis(),
+		Creator:   creator,
+		Question:  question,
+		Settings:  settings,
+	}
+	for _, o := range answerOptions {
+		p.AnswerOptions = append(p.AnswerOptions, &AnswerOption{
+			Answer:   o.Answer,
+			Voter:    []string{},
+			Correct:  o.Correct,
+			Capacity: o.Capacity,
+		})
+	}
+	p.SecretBallot = p.supportsSecretBallot()
+
+	if errMsg := p.validate(); errMsg != nil {
+		return nil, errMsg
+	}
+	return &p, nil
+}
+
+// rerunSettings returns the settings of a given poll for a new run. A scheduled end is moved, so that the new poll runs as long as the given one.
+func (fac *Factory) rerunSettings(p *Poll) Settings {
+	settings := p.Settings
+	if p.HasEndTime() {
+		settings.EndAt = fac.Millis() + p.Settings.EndAt - p.CreatedAt
+	}
+	return settings
+}
+
+// rerunSurvey creates a new survey with the same questions as a given survey.
+func (fac *Factory) rerunSurvey(creator string, p *Poll, settings Settings) (*Poll, *utils.ErrorMessage) {
+	survey := Poll{
+		ID:        fac.NewID(),
+		CreatedAt: fac.Millis(),
+		Creator:   creator,
+		Question:  p.Question,
+		Settings:  settings,
+	}
+	for _, q := range p.Questions {
+		question := &SurveyQuestion{
+			Question: q.Question,
+			Settings: q.Settings,
+		}
+		for _, o := range q.AnswerOptions {
+			question.AnswerOptions = append(question.AnswerOptions, &AnswerOption{
+				Answer: o.Answer,
+				Voter:  []string{},
+			})
+		}
+		survey.Questions = append(survey.Questions, question)
+	}
+
+	if errMsg := survey.validateSurvey(); errMsg != nil {
+		return nil, errMsg
+	}
+	return &survey, nil
+}
@@ -0,0 +1,145 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestRerun(t *testing.T) {
+	now := testutils.GetMillis() + 24*60*60*1000
+	var pf poll.Factory
+	pf.SetMillis(func() int64 { return now })
+	pf.SetNewID(func() string { return "newPollID" })
+
+	t.Run("poll with votes", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 2})
+		p.EditedAt = testutils.GetMillis()
+
+		rerun, errMsg := pf.Rerun("userID2", p)
+		require.Nil(t, errMsg)
+
+		assert.Equal(&poll.Poll{
+			ID:        "newPollID",
+			CreatedAt: now,
+			Creator:   "userID2",
+			Question:  "Question",
+			AnswerOptions: []*poll.AnswerOption{
+				{Answer: "Answer 1", Voter: []string{}},
+				{Answer: "Answer 2", Voter: []string{}},
+				{Answer: "Answer 3", Voter: []string{}},
+			},
+			Settings: poll.Settings{Progress: true, MaxVotes: 2},
+		}, rerun)
+		assert.Equal(testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, MaxVotes: 2}).AnswerOptions, p.AnswerOptions)
+	})
+	t.Run("secret ballot", func(t *testing.T) {
+		p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1})
+
+		rerun, errMsg := pf.Rerun("userID1", p)
+		require.Nil(t, errMsg)
+		assert.True(t, rerun.IsSecretBallot())
+		assert.Empty(t, rerun.GetVoters())
+		assert.Equal(t, 0, rerun.AnswerOptions[0].Votes)
+	})
+	t.Run("quiz", func(t *testing.T) {
+		rerun, errMsg := pf.Rerun("userID1", testutils.GetQuizPollWithVotes())
+		require.Nil(t, errMsg)
+		assert.Equal(t, []string{"Answer 1"}, rerun.GetCorrectAnswers())
+		assert.Equal(t, "Answer 1", rerun.AnswerOptions[0].Answer)
+	})
+	t.Run("free-text", func(t *testing.T) {
+		rerun, errMsg := pf.Rerun("userID1", testutils.GetFreeTextPollWithResponses())
+		require.Nil(t, errMsg)
+		assert.True(t, rerun.IsFreeText())
+		assert.Empty(t, rerun.Responses)
+	})
+	t.Run("end time", func(t *testing.T) {
+		p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: testutils.GetMillis() + 2*60*60*1000})
+
+		rerun, errMsg := pf.Rerun("userID1", p)
+		require.Nil(t, errMsg)
+		assert.Equal(t, now+2*60*60*1000, rerun.Settings.EndAt)
+	})
+	t.Run("capacities of single options", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Capacity: 3})
+		p.AnswerOptions[1].Voter = []string{"userID2"}
+
+		rerun, errMsg := pf.Rerun("userID1", p)
+		require.Nil(t, errMsg)
+		require.Len(t, rerun.AnswerOptions, 3)
+		assert.Equal(t, "Slot A", rerun.AnswerOptions[0].Answer)
+		assert.Equal(t, 2, rerun.AnswerOptions[0].Capacity)
+		assert.Equal(t, 1, rerun.AnswerOptions[1].Capacity)
+		assert.Equal(t, 3, rerun.AnswerOptions[2].Capacity)
+		assert.Empty(t, rerun.AnswerOptions[1].Voter)
+	})
+	t.Run("waitlist with capacities of single options only", func(t *testing.T) {
+		p := newSignUpSheet(t, poll.Settings{MaxVotes: 1, Waitlist: true})
+		p.AnswerOptions[1].Voter = []string{"userID2"}
+		p.AnswerOptions[1].Waitlist = []string{"userID3"}
+
+		rerun, errMsg := pf.Rerun("userID1", p)
+		require.Nil(t, errMsg)
+		require.Len(t, rerun.AnswerOptions, 3)
+		assert.Equal(t, 2, rerun.AnswerOptions[0].Capacity)
+		assert.Equal(t, 1, rerun.AnswerOptions[1].Capacity)
+		assert.Equal(t, 0, rerun.AnswerOptions[2].Capacity)
+		assert.Empty(t, rerun.AnswerOptions[1].Waitlist)
+	})
+	t.Run("survey", func(t *testing.T) {
+		assert := assert.New(t)
+
+		rerun, errMsg := pf.Rerun("userID2", testutils.GetSurveyWithAnswers())
+		require.Nil(t, errMsg)
+
+		assert.Equal("newPollID", rerun.ID)
+		assert.Equal("Survey", rerun.Question)
+		require.Len(t, rerun.Questions, 2)
+		assert.Equal("Question 2", rerun.Questions[1].Question)
+		assert.Equal(poll.Settings{MaxVotes: 2, Anonymous: true}, rerun.Questions[1].Settings)
+		assert.Len(rerun.Questions[1].AnswerOptions, 3)
+		assert.Empty(rerun.GetSurveyRespondents())
+	})
+}
+
+func TestRunoff(t *testing.T) {
+	now := testutils.GetMillis() + 24*60*60*1000
+	var pf poll.Factory
+	pf.SetMillis(func() int64 { return now })
+	pf.SetNewID(func() string { return "newPollID" })
+
+	t.Run("tied options", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{Progress: true, TieBreak: poll.TieBreakRunoff, MaxVotes: 2})
+		p.AnswerOptions[1].Voter = []string{"userID2", "userID3", "userID4"}
+
+		runoff, errMsg := pf.Runoff(p)
+		require.Nil(t, errMsg)
+		assert.Equal(t, &poll.Poll{
+			ID:        "newPollID",
+			CreatedAt: now,
+			Creator:   "userID1",
+			Question:  "Question",
+			AnswerOptions: []*poll.AnswerOption{
+				{Answer: "Answer 1", Voter: []string{}},
+				{Answer: "Answer 2", Voter: []string{}},
+			},
+			Settings: poll.Settings{Progress: true, MaxVotes: 1},
+		}, runoff)
+	})
+	t.Run("end time", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{TieBreak: poll.TieBreakRunoff, MaxVotes: 1, EndAt: testutils.GetMillis() + 2*60*60*1000})
+		p.AnswerOptions[1].Voter = []string{"userID2", "userID3", "userID4"}
+
+		runoff, errMsg := pf.Runoff(p)
+		require.Nil(t, errMsg)
+		assert.Equal(t, now+2*60*60*1000, runoff.Settings.EndAt)
+		assert.Len(t, runoff.AnswerOptions, 2)
+	})
+}
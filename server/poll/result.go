@@ -0,0 +1,123 @@
+package poll
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// TieBreak is the rule applied if several answer options tie for the win once a poll has ended.
+type TieBreak string
+
+const (
+	// TieBreakNone leaves a tie as it is.
+	TieBreakNone TieBreak = ""
+	// TieBreakCreator lets the creator of the poll pick the winner among the tied options.
+	TieBreakCreator TieBreak = "creator"
+	// TieBreakRunoff starts a new poll between the tied options.
+	TieBreakRunoff TieBreak = "runoff"
+)
+
+// isValid returns true if t is a known tie-break rule
+func (t TieBreak) isValid() bool {
+	return t == TieBreakNone || t == TieBreakCreator || t == TieBreakRunoff
+}
+
+// HasWinners returns true if the poll determines winning answer options.
+// Free-text, scheduling and quiz polls as well as surveys don't.
+func (p *Poll) HasWinners() bool {
+	return !p.IsFreeText() && !p.IsSurvey() && !p.IsScheduling() && !p.IsQuiz()
+}
+
+// GetWinners returns the indexes of the winning answer options.
+// For ranked-choice polls these are the winners of the instant-runoff tally, for all other polls the options with the most votes or points.
+// It contains more than one index if options are tied, unless the creator broke the tie, and is empty if nobody voted.
+func (p *Poll) GetWinners() []int {
+	if !p.HasWinners() {
+		return []int{}
+	}
+
+	var winners []int
+	if p.IsRanked() {
+		winners = p.TallyRanked().Winners
+	} else {
+		winners = []int{}
+		highest := 0
+		for i, o := range p.AnswerOptions {
+			count := p.countVotes(o)
+			if count > highest {
+				highest = count
+				winners = []int{i}
+			} else if count == highest && count > 0 {
+				winners = append(winners, i)
+			}
+		}
+	}
+
+	if p.TieBreakWinner != "" {
+		for _, i := range winners {
+			if p.AnswerOptions[i].Answer == p.TieBreakWinner {
+				return []int{i}
+			}
+		}
+	}
+	return winners
+}
+
+// IsTie returns true if several answer options won and the tie hasn't been broken
+func (p *Poll) IsTie() bool {
+	return len(p.GetWinners()) > 1
+}
+
+// IsTieBroken returns true if the creator picked the winner among tied answer options
+func (p *Poll) IsTieBroken() bool {
+	return p.TieBreakWinner != "" && len(p.GetWinners()) == 1
+}
+
+// NeedsTieBreak returns true if the creator has to pick the winner among tied answer options
+func (p *Poll) NeedsTieBreak() bool {
+	return p.Settings.TieBreak == TieBreakCreator && p.IsTie()
+}
+
+// NeedsRunoff returns true if a runoff poll between tied answer options has to be started
+func (p *Poll) NeedsRunoff() bool {
+	return p.Settings.TieBreak == TieBreakRunoff && p.IsTie()
+}
+
+// BreakTie makes the answer option with a given index the winner among the tied options of an ended poll.
+func (p *Poll) BreakTie(index int) (*i18n.Message, error) {
+	if len(p.AnswerOptions) <= index || index < 0 {
+		return nil, fmt.Errorf("invalid index")
+	}
+	if !p.NeedsTieBreak() {
+		return &i18n.Message{
+			ID:    "poll.breakTie.noTie",
+			Other: "There is no tie to break.",
+		}, nil
+	}
+	if !slices.Contains(p.GetWinners(), index) {
+		return nil, fmt.Errorf("answer option isn't tied")
+	}
+
+	p.TieBreakWinner = p.AnswerOptions[index].Answer
+	return nil, nil
+}
+
+// getShares returns the percentage of all votes, or points for point-budget polls, and the percentage of all voters an answer option got.
+func (p *Poll) getShares(o *AnswerOption, totalVotes, totalVoters int) (int, int) {
+	voters := len(o.Voter)
+	if p.IsSecretBallot() {
+		voters = o.Votes
+	}
+	return percentage(p.countVotes(o), totalVotes), percentage(voters, totalVoters)
+}
+
+// percentage returns n as rounded percentage of total
+func percentage(n, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(float64(n) * 100 / float64(total)))
+}
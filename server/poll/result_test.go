@@ -0,0 +1,128 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func getTiedPoll(tieBreak poll.TieBreak) *poll.Poll {
+	p := testutils.GetPollWithVotesAndSettings(poll.Settings{TieBreak: tieBreak, MaxVotes: 1})
+	p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+	p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+	return p
+}
+
+func TestPollGetWinners(t *testing.T) {
+	for name, test := range map[string]struct {
+		Poll            *poll.Poll
+		ExpectedWinners []int
+		ExpectedTie     bool
+	}{
+		"single winner": {
+			Poll:            testutils.GetPollWithVotes(),
+			ExpectedWinners: []int{0},
+		},
+		"tie": {
+			Poll:            getTiedPoll(poll.TieBreakNone),
+			ExpectedWinners: []int{0, 1},
+			ExpectedTie:     true,
+		},
+		"tie broken by the creator": {
+			Poll: func() *poll.Poll {
+				p := getTiedPoll(poll.TieBreakCreator)
+				p.TieBreakWinner = "Answer 2"
+				return p
+			}(),
+			ExpectedWinners: []int{1},
+		},
+		"no votes": {
+			Poll:            testutils.GetPoll(),
+			ExpectedWinners: []int{},
+		},
+		"points poll": {
+			Poll:            testutils.GetPointsPollWithVotes(),
+			ExpectedWinners: []int{0},
+		},
+		"secret ballot": {
+			Poll:            getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1}),
+			ExpectedWinners: []int{0},
+		},
+		"ranked poll": {
+			Poll:            testutils.GetRankedPollWithBallots(),
+			ExpectedWinners: []int{0},
+		},
+		"scheduling poll": {
+			Poll:            testutils.GetSchedulingPollWithVotes(),
+			ExpectedWinners: []int{},
+		},
+		"quiz": {
+			Poll:            testutils.GetQuizPollWithVotes(),
+			ExpectedWinners: []int{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.ExpectedWinners, test.Poll.GetWinners())
+			assert.Equal(t, test.ExpectedTie, test.Poll.IsTie())
+		})
+	}
+}
+
+func TestPollTieBreak(t *testing.T) {
+	assert.False(t, getTiedPoll(poll.TieBreakNone).NeedsTieBreak())
+	assert.False(t, getTiedPoll(poll.TieBreakNone).NeedsRunoff())
+	assert.True(t, getTiedPoll(poll.TieBreakCreator).NeedsTieBreak())
+	assert.False(t, getTiedPoll(poll.TieBreakCreator).NeedsRunoff())
+	assert.True(t, getTiedPoll(poll.TieBreakRunoff).NeedsRunoff())
+	assert.False(t, testutils.GetPollWithVotesAndSettings(poll.Settings{TieBreak: poll.TieBreakRunoff, MaxVotes: 1}).NeedsRunoff())
+}
+
+func TestPollBreakTie(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		p := getTiedPoll(poll.TieBreakCreator)
+
+		msg, err := p.BreakTie(1)
+		require.NoError(t, err)
+		assert.Nil(t, msg)
+		assert.Equal(t, "Answer 2", p.TieBreakWinner)
+		assert.True(t, p.IsTieBroken())
+		assert.False(t, p.NeedsTieBreak())
+		assert.Equal(t, []int{1}, p.GetWinners())
+	})
+	t.Run("tie already broken", func(t *testing.T) {
+		p := getTiedPoll(poll.TieBreakCreator)
+		p.TieBreakWinner = "Answer 1"
+
+		msg, err := p.BreakTie(1)
+		require.NoError(t, err)
+		assert.NotNil(t, msg)
+		assert.Equal(t, "Answer 1", p.TieBreakWinner)
+	})
+	t.Run("creator doesn't pick the winner", func(t *testing.T) {
+		p := getTiedPoll(poll.TieBreakRunoff)
+
+		msg, err := p.BreakTie(1)
+		require.NoError(t, err)
+		assert.NotNil(t, msg)
+		assert.Empty(t, p.TieBreakWinner)
+	})
+	t.Run("option isn't tied", func(t *testing.T) {
+		p := getTiedPoll(poll.TieBreakCreator)
+
+		msg, err := p.BreakTie(2)
+		assert.Error(t, err)
+		assert.Nil(t, msg)
+		assert.Empty(t, p.TieBreakWinner)
+	})
+	t.Run("invalid index", func(t *testing.T) {
+		p := getTiedPoll(poll.TieBreakCreator)
+
+		msg, err := p.BreakTie(3)
+		assert.Error(t, err)
+		assert.Nil(t, msg)
+	})
+}
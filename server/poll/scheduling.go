@@ -0,0 +1,91 @@
+package poll
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Availability is the answer of a voter for a single option of a scheduling poll.
+type Availability string
+
+const (
+	AvailabilityYes   Availability = "yes"
+	AvailabilityMaybe Availability = "maybe"
+	AvailabilityNo    Availability = "no"
+)
+
+// IsScheduling returns true if voters answer yes, maybe or no for every option of the poll
+func (p *Poll) IsScheduling() bool {
+	return p.Settings.Scheduling
+}
+
+// SetAvailability replaces the answers of a given user in a scheduling poll.
+// answers must have an entry for every answer option. An empty entry means that the user didn't answer for this option.
+func (p *Poll) SetAvailability(userID string, answers []Availability) error {
+	if userID == "" {
+		return fmt.Errorf("invalid userID")
+	}
+	if len(answers) != len(p.AnswerOptions) {
+		return fmt.Errorf("invalid number of answers")
+	}
+	for _, a := range answers {
+		switch a {
+		case AvailabilityYes, AvailabilityMaybe, AvailabilityNo, "":
+		default:
+			return fmt.Errorf("invalid availability %q", a)
+		}
+	}
+
+	p.ResetVotes(userID)
+	for i, a := range answers {
+		if a == "" {
+			continue
+		}
+		o := p.AnswerOptions[i]
+		o.Voter = append(o.Voter, userID)
+		if o.Availability == nil {
+			o.Availability = map[string]Availability{}
+		}
+		o.Availability[userID] = a
+	}
+	return nil
+}
+
+// CountAvailability returns how many voters gave a given answer for an answer option
+func (o *AnswerOption) CountAvailability(a Availability) int {
+	count := 0
+	for _, v := range o.Availability {
+		if v == a {
+			count++
+		}
+	}
+	return count
+}
+
+// GetVotersWithAvailability returns the voters who gave a given answer for an answer option in the order they voted
+func (o *AnswerOption) GetVotersWithAvailability(a Availability) []string {
+	voters := []string{}
+	for _, v := range o.Voter {
+		if o.Availability[v] == a {
+			voters = append(voters, v)
+		}
+	}
+	return voters
+}
+
+// RankByAvailability returns the indexes of the answer options,
+// sorted by the number of "yes" answers and then by the number of "maybe" answers.
+func (p *Poll) RankByAvailability() []int {
+	indexes := make([]int, len(p.AnswerOptions))
+	for i := range p.AnswerOptions {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := p.AnswerOptions[indexes[i]], p.AnswerOptions[indexes[j]]
+		if yesA, yesB := a.CountAvailability(AvailabilityYes), b.CountAvailability(AvailabilityYes); yesA != yesB {
+			return yesA > yesB
+		}
+		return a.CountAvailability(AvailabilityMaybe) > b.CountAvailability(AvailabilityMaybe)
+	})
+	return indexes
+}
@@ -0,0 +1,129 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestSetAvailability(t *testing.T) {
+	for name, test := range map[string]struct {
+		Poll         *poll.Poll
+		UserID       string
+		Answers      []poll.Availability
+		ExpectedPoll *poll.Poll
+		Error        bool
+	}{
+		"first answers": {
+			Poll:    testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Scheduling: true}),
+			UserID:  "a",
+			Answers: []poll.Availability{poll.AvailabilityYes, "", poll.AvailabilityNo},
+			ExpectedPoll: &poll.Poll{
+				ID:        testutils.GetPollID(),
+				PostID:    "postID1",
+				CreatedAt: testutils.GetMillis(),
+				Creator:   "userID1",
+				Question:  "Question",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{"a"}, Availability: map[string]poll.Availability{"a": poll.AvailabilityYes}},
+					{Answer: "Answer 2", Voter: []string{}},
+					{Answer: "Answer 3", Voter: []string{"a"}, Availability: map[string]poll.Availability{"a": poll.AvailabilityNo}},
+				},
+				Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+			},
+		},
+		"replace answers": {
+			Poll:    testutils.GetSchedulingPollWithVotes(),
+			UserID:  "userID1",
+			Answers: []poll.Availability{poll.AvailabilityNo, "", poll.AvailabilityYes},
+			ExpectedPoll: &poll.Poll{
+				ID:        testutils.GetPollID(),
+				PostID:    "postID1",
+				CreatedAt: testutils.GetMillis(),
+				Creator:   "userID1",
+				Question:  "Question",
+				AnswerOptions: []*poll.AnswerOption{{
+					Answer:       "Answer 1",
+					Voter:        []string{"userID2", "userID1"},
+					Availability: map[string]poll.Availability{"userID1": poll.AvailabilityNo, "userID2": poll.AvailabilityNo},
+				}, {
+					Answer:       "Answer 2",
+					Voter:        []string{"userID2"},
+					Availability: map[string]poll.Availability{"userID2": poll.AvailabilityYes},
+				}, {
+					Answer:       "Answer 3",
+					Voter:        []string{"userID2", "userID1"},
+					Availability: map[string]poll.Availability{"userID1": poll.AvailabilityYes, "userID2": poll.AvailabilityMaybe},
+				}},
+				Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+			},
+		},
+		"invalid availability": {
+			Poll:         testutils.GetSchedulingPollWithVotes(),
+			UserID:       "userID1",
+			Answers:      []poll.Availability{poll.AvailabilityNo, "perhaps", poll.AvailabilityYes},
+			ExpectedPoll: testutils.GetSchedulingPollWithVotes(),
+			Error:        true,
+		},
+		"invalid number of answers": {
+			Poll:         testutils.GetSchedulingPollWithVotes(),
+			UserID:       "userID1",
+			Answers:      []poll.Availability{poll.AvailabilityNo},
+			ExpectedPoll: testutils.GetSchedulingPollWithVotes(),
+			Error:        true,
+		},
+		"invalid user id": {
+			Poll:         testutils.GetSchedulingPollWithVotes(),
+			UserID:       "",
+			Answers:      []poll.Availability{poll.AvailabilityNo, poll.AvailabilityNo, poll.AvailabilityNo},
+			ExpectedPoll: testutils.GetSchedulingPollWithVotes(),
+			Error:        true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.Poll.SetAvailability(test.UserID, test.Answers)
+
+			if test.Error {
+				assert.NotNil(err)
+			} else {
+				assert.Nil(err)
+			}
+			assert.Equal(test.ExpectedPoll, test.Poll)
+		})
+	}
+}
+
+func TestCountAvailability(t *testing.T) {
+	p := testutils.GetSchedulingPollWithVotes()
+
+	assert.Equal(t, 1, p.AnswerOptions[0].CountAvailability(poll.AvailabilityYes))
+	assert.Equal(t, 0, p.AnswerOptions[0].CountAvailability(poll.AvailabilityMaybe))
+	assert.Equal(t, 1, p.AnswerOptions[0].CountAvailability(poll.AvailabilityNo))
+	assert.Equal(t, []string{"userID2"}, p.AnswerOptions[0].GetVotersWithAvailability(poll.AvailabilityNo))
+	assert.Equal(t, []string{"userID1", "userID2"}, p.AnswerOptions[2].GetVotersWithAvailability(poll.AvailabilityMaybe))
+	assert.Equal(t, []string{}, p.AnswerOptions[2].GetVotersWithAvailability(poll.AvailabilityYes))
+}
+
+func TestRankByAvailability(t *testing.T) {
+	assert.Equal(t, []int{1, 0, 2}, testutils.GetSchedulingPollWithVotes().RankByAvailability())
+
+	p := testutils.GetSchedulingPollWithVotes()
+	p.AnswerOptions[0].Availability["userID1"] = poll.AvailabilityNo
+	assert.Equal(t, []int{1, 2, 0}, p.RankByAvailability())
+
+	assert.Equal(t, []int{0, 1, 2}, testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Scheduling: true}).RankByAvailability())
+}
+
+func TestGetVotedAnswersScheduling(t *testing.T) {
+	p := testutils.GetSchedulingPollWithVotes()
+
+	assert.Equal(t, []string{"Answer 1", "Answer 2"}, p.GetVotedAnswers("userID1"))
+	assert.Equal(t, []string{"Answer 2"}, p.GetVotedAnswers("userID2"))
+	assert.True(t, p.HasVoted("userID2"))
+	assert.False(t, p.HasVoted("userID3"))
+}
@@ -0,0 +1,108 @@
+package poll
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"slices"
+	"sort"
+)
+
+// Secret ballots store the number of votes per answer option separately from the voters.
+// Voters are only known by a token, which is derived from their user id with a secret key.
+// This allows to prevent double voting and to reset votes, but the stored poll doesn't reveal who voted for what.
+//
+// All methods of a poll, which take a user id, expect the id returned by VoterID instead.
+
+// VoterID returns the id under which the votes of a given user are stored.
+// For secret ballots this is the voter token of the user, otherwise the user id itself.
+func (p *Poll) VoterID(key []byte, userID string) string {
+	if !p.IsSecretBallot() {
+		return userID
+	}
+	return voterToken(key, p.ID, userID)
+}
+
+// voterToken returns the token of a user for a secret ballot.
+// The token differs between polls, so votes of the same user can't be linked across polls.
+func voterToken(key []byte, pollID, userID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(pollID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SecretBallotKeyID returns an id for a secret ballot key. It allows to tell whether two servers use the same key without revealing it.
+func SecretBallotKeyID(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("key id"))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:12])
+}
+
+// IsSecretBallot returns true if the votes of the poll are stored without the ids of the voters
+func (p *Poll) IsSecretBallot() bool {
+	return p.SecretBallot
+}
+
+// supportsSecretBallot returns true if the poll is anonymous and can be stored as a secret ballot.
+// Ranked-choice, point-budget, scheduling, quiz, free-text polls and surveys need the ids of the voters and therefore aren't supported.
+func (p *Poll) supportsSecretBallot() bool {
+	return p.Settings.Anonymous && !p.IsRanked() && !p.HasPointBudget() && !p.IsScheduling() && !p.IsQuiz() && !p.IsFreeText() && !p.IsSurvey() && !p.hasCapacity()
+}
+
+// ConvertToSecretBallot turns the votes of an anonymous poll into a secret ballot.
+// It returns false if the poll doesn't support secret ballots or already is one.
+func (p *Poll) ConvertToSecretBallot(key []byte) bool {
+	if p.IsSecretBallot() || !p.supportsSecretBallot() {
+		return false
+	}
+
+	for i, o := range p.AnswerOptions {
+		for _, userID := range o.Voter {
+			p.addSecretVote(voterToken(key, p.ID, userID), i)
+		}
+		o.Voter = []string{}
+	}
+	p.SecretBallot = true
+	return true
+}
+
+// addSecretVote adds a vote of a given voter token for an answer option.
+func (p *Poll) addSecretVote(token string, index int) {
+	if p.Ballots == nil {
+		p.Ballots = map[string][]int{}
+	}
+	p.Ballots[token] = append(p.Ballots[token], index)
+	p.AnswerOptions[index].Votes++
+}
+
+// resetSecretVotes removes all votes of a given voter token.
+func (p *Poll) resetSecretVotes(token string) {
+	for _, index := range p.Ballots[token] {
+		p.AnswerOptions[index].Votes--
+	}
+	delete(p.Ballots, token)
+}
+
+// getSecretVotedAnswers returns the answers a given voter token voted for, in the order of the answer options.
+func (p *Poll) getSecretVotedAnswers(token string) []string {
+	indexes := slices.Clone(p.Ballots[token])
+	sort.Ints(indexes)
+
+	votedAnswers := []string{}
+	for _, index := range indexes {
+		votedAnswers = append(votedAnswers, p.AnswerOptions[index].Answer)
+	}
+	return votedAnswers
+}
+
+// getSecretVoters returns the tokens of all voters of a secret ballot in a stable order.
+func (p *Poll) getSecretVoters() []string {
+	tokens := []string{}
+	for token := range p.Ballots {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
@@ -0,0 +1,163 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+var secretBallotKey = []byte("secretBallotKey")
+
+// getSecretBallot returns an anonymous poll, which is stored as secret ballot and has votes of four users.
+func getSecretBallot(t *testing.T, settings poll.Settings) *poll.Poll {
+	p := testutils.GetPollWithVotesAndSettings(settings)
+	require.True(t, p.ConvertToSecretBallot(secretBallotKey))
+	return p
+}
+
+func TestNewPollSecretBallot(t *testing.T) {
+	for name, test := range map[string]struct {
+		Settings poll.Settings
+		Expected bool
+	}{
+		"anonymous":            {Settings: poll.Settings{Anonymous: true, MaxVotes: 1}, Expected: true},
+		"anonymous multi vote": {Settings: poll.Settings{Anonymous: true, MaxVotes: 0}, Expected: true},
+		"not anonymous":        {Settings: poll.Settings{MaxVotes: 1}, Expected: false},
+		"anonymous ranked":     {Settings: poll.Settings{Anonymous: true, Ranked: true, MaxVotes: 1}, Expected: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var pf poll.Factory
+			p, errMsg := pf.NewPoll("userID1", "Question", []string{"Answer 1", "Answer 2"}, test.Settings)
+			require.Nil(t, errMsg)
+			assert.Equal(t, test.Expected, p.IsSecretBallot())
+		})
+	}
+}
+
+func TestVoterID(t *testing.T) {
+	assert := assert.New(t)
+
+	p := testutils.GetPoll()
+	assert.Equal("userID1", p.VoterID(secretBallotKey, "userID1"))
+
+	s := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1})
+	token := s.VoterID(secretBallotKey, "userID1")
+	assert.NotEqual("userID1", token)
+	assert.Equal(token, s.VoterID(secretBallotKey, "userID1"))
+	assert.NotEqual(token, s.VoterID(secretBallotKey, "userID2"))
+	assert.NotEqual(token, s.VoterID([]byte("otherKey"), "userID1"))
+
+	other := s.Copy()
+	other.ID = "otherPollID"
+	assert.NotEqual(token, other.VoterID(secretBallotKey, "userID1"))
+}
+
+func TestConvertToSecretBallot(t *testing.T) {
+	t.Run("anonymous poll", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 0})
+
+		assert.True(p.IsSecretBallot())
+		for _, o := range p.AnswerOptions {
+			assert.Equal([]string{}, o.Voter)
+		}
+		assert.Equal([]int{3, 1, 0}, []int{p.AnswerOptions[0].Votes, p.AnswerOptions[1].Votes, p.AnswerOptions[2].Votes})
+		assert.Len(p.GetVoters(), 4)
+		assert.Equal([]string{"Answer 2"}, p.GetVotedAnswers(p.VoterID(secretBallotKey, "userID4")))
+		// userID1 is the creator of the poll
+		for _, userID := range []string{"userID2", "userID3", "userID4"} {
+			assert.NotContains(string(p.EncodeToByte()), userID)
+		}
+		assert.False(p.ConvertToSecretBallot(secretBallotKey))
+	})
+	t.Run("not anonymous", func(t *testing.T) {
+		p := testutils.GetPollWithVotes()
+		assert.False(t, p.ConvertToSecretBallot(secretBallotKey))
+		assert.Equal(t, testutils.GetPollWithVotes(), p)
+	})
+}
+
+func TestSecretBallotUpdateVote(t *testing.T) {
+	t.Run("single vote", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 1})
+		token := p.VoterID(secretBallotKey, "userID5")
+
+		msg, err := p.UpdateVote(token, 1)
+		require.Nil(t, err)
+		assert.Nil(msg)
+		assert.True(p.HasVoted(token))
+		assert.Equal([]string{"Answer 2"}, p.GetVotedAnswers(token))
+
+		msg, err = p.UpdateVote(token, 2)
+		require.Nil(t, err)
+		assert.Nil(msg)
+		assert.Equal([]string{"Answer 3"}, p.GetVotedAnswers(token))
+		assert.Equal(1, p.AnswerOptions[1].Votes)
+		assert.Equal(1, p.AnswerOptions[2].Votes)
+	})
+	t.Run("multi vote", func(t *testing.T) {
+		assert := assert.New(t)
+
+		p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 2})
+		token := p.VoterID(secretBallotKey, "userID5")
+
+		msg, err := p.UpdateVote(token, 2)
+		require.Nil(t, err)
+		assert.Nil(msg)
+		msg, err = p.UpdateVote(token, 2)
+		require.Nil(t, err)
+		assert.Equal("poll.updateVote.alreadyVoted", msg.ID)
+		msg, err = p.UpdateVote(token, 0)
+		require.Nil(t, err)
+		assert.Nil(msg)
+		msg, err = p.UpdateVote(token, 1)
+		require.Nil(t, err)
+		assert.Equal("poll.updateVote.maxVotes", msg.ID)
+
+		assert.Equal([]string{"Answer 1", "Answer 3"}, p.GetVotedAnswers(token))
+		assert.Equal([]int{4, 1, 1}, []int{p.AnswerOptions[0].Votes, p.AnswerOptions[1].Votes, p.AnswerOptions[2].Votes})
+	})
+}
+
+func TestSecretBallotResetVotes(t *testing.T) {
+	assert := assert.New(t)
+
+	p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 0})
+	token := p.VoterID(secretBallotKey, "userID2")
+
+	p.ResetVotes(token)
+
+	assert.False(p.HasVoted(token))
+	assert.Equal([]string{}, p.GetVotedAnswers(token))
+	assert.Equal([]int{2, 1, 0}, []int{p.AnswerOptions[0].Votes, p.AnswerOptions[1].Votes, p.AnswerOptions[2].Votes})
+	assert.Len(p.GetVoters(), 3)
+	assert.True(p.HasVoted(p.VoterID(secretBallotKey, "userID1")))
+}
+
+func TestSecretBallotCopy(t *testing.T) {
+	p := getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 0})
+	p2 := p.Copy()
+
+	assert.Equal(t, p, p2)
+	p2.ResetVotes(p2.VoterID(secretBallotKey, "userID1"))
+	assert.NotEqual(t, p, p2)
+	assert.Equal(t, getSecretBallot(t, poll.Settings{Anonymous: true, MaxVotes: 0}), p)
+}
+
+func TestSecretBallotToPostActions(t *testing.T) {
+	p := getSecretBallot(t, poll.Settings{Anonymous: true, Progress: true, MaxVotes: 0})
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "pluginID", "John Doe")
+
+	require.Len(t, attachments, 1)
+	assert.Contains(t, attachments[0].Text, "**Secret ballot**")
+	assert.Contains(t, attachments[0].Text, "**Total votes**: 4 (4 voters)")
+	assert.Equal(t, "Answer 1 (3)", attachments[0].Actions[0].Name)
+}
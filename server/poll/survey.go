@@ -0,0 +1,220 @@
+package poll
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// SurveyQuestion is a single question of a survey.
+type SurveyQuestion struct {
+	Question      string          `json:"question"`
+	AnswerOptions []*AnswerOption `json:"answer_options"`
+	// Settings only supports Anonymous and MaxVotes.
+	Settings Settings `json:"settings"`
+}
+
+// IsSurvey returns true if the poll consists of several questions, which are answered in one dialog
+func (p *Poll) IsSurvey() bool {
+	return len(p.Questions) > 0
+}
+
+// NewSurvey creates a new survey with the given title.
+// Every question is written in the syntax of the slash command, e.g. `"Question" "Option 1" "Option 2" --votes=2 --anonymous`.
+// Blank questions are skipped.
+func (fac *Factory) NewSurvey(creator, title string, questions []string, settings Settings) (*Poll, *utils.ErrorMessage) {
+	p := Poll{
+		ID:        fac.NewID(),
+		CreatedAt: fac.Millis(),
+		Creator:   creator,
+		Question:  title,
+		Settings:  settings,
+	}
+	for _, q := range questions {
+		if strings.TrimSpace(q) == "" {
+			continue
+		}
+		question, errMsg := newSurveyQuestion(q)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+		p.Questions = append(p.Questions, question)
+	}
+
+	if errMsg := p.validateSurvey(); errMsg != nil {
+		return nil, errMsg
+	}
+
+	return &p, nil
+}
+
+// newSurveyQuestion parses a single question of a survey.
+func newSurveyQuestion(s string) (*SurveyQuestion, *utils.ErrorMessage) {
+	question, options, settingStrs := utils.ParseInput(s, "")
+	question = strings.TrimSpace(question)
+	if question == "" || len(options) < 2 {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newSurvey.question.invalid",
+				Other: `Every question of a survey needs a text and at least two options, e.g. "Question" "Option 1" "Option 2". Invalid question: {{.Question}}`,
+			},
+			Data: map[string]interface{}{
+				"Question": strings.TrimSpace(s),
+			},
+		}
+	}
+
+	settings, errMsg := NewSettingsFromStrings(settingStrs, 0, time.UTC)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if settings != (Settings{Anonymous: settings.Anonymous, MaxVotes: settings.MaxVotes}) {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newSurvey.question.unsupportedSetting",
+				Other: "The questions of a survey only support the anonymous and votes setting. Invalid question: {{.Question}}",
+			},
+			Data: map[string]interface{}{
+				"Question": question,
+			},
+		}
+	}
+
+	// Reuse the checks of regular polls for the answer options and the votes setting
+	p := Poll{Settings: settings}
+	for _, o := range options {
+		if errMsg := p.AddAnswerOption(o); errMsg != nil {
+			return nil, errMsg
+		}
+	}
+	if errMsg := p.validate(); errMsg != nil {
+		return nil, errMsg
+	}
+
+	return &SurveyQuestion{
+		Question:      question,
+		AnswerOptions: p.AnswerOptions,
+		Settings:      settings,
+	}, nil
+}
+
+// validateSurvey checks if a survey is valid
+func (p *Poll) validateSurvey() *utils.ErrorMessage {
+	if !p.IsSurvey() {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newSurvey.noQuestions",
+				Other: "A survey needs at least one question.",
+			},
+		}
+	}
+	if p.Settings != (Settings{AnonymousCreator: p.Settings.AnonymousCreator, Progress: p.Settings.Progress, EndAt: p.Settings.EndAt, MaxVotes: 1}) {
+		return &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newSurvey.unsupportedSetting",
+				Other: "A survey only supports the anonymous-creator, progress and end setting. Set anonymous and votes for every question instead.",
+			},
+		}
+	}
+	return nil
+}
+
+// SubmitSurvey replaces the answers of a given user to all questions of a survey.
+// answers contains the indexes of the chosen answer options for every question. Questions without chosen options are left unanswered.
+// If too many options are chosen for a question, a message is returned for it, keyed by the index of the question.
+func (p *Poll) SubmitSurvey(userID string, answers [][]int) (map[int]*utils.ErrorMessage, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("invalid userID")
+	}
+	if len(answers) != len(p.Questions) {
+		return nil, fmt.Errorf("invalid number of answers")
+	}
+
+	errMsgs := map[int]*utils.ErrorMessage{}
+	for i, q := range p.Questions {
+		chosen := map[int]bool{}
+		for _, index := range answers[i] {
+			if len(q.AnswerOptions) <= index || index < 0 || chosen[index] {
+				return nil, fmt.Errorf("invalid index")
+			}
+			chosen[index] = true
+		}
+		if q.Settings.MaxVotes != 0 && len(answers[i]) > q.Settings.MaxVotes {
+			errMsgs[i] = &utils.ErrorMessage{
+				Message: &i18n.Message{
+					ID:    "poll.submitSurvey.maxVotes",
+					Other: "You can choose at most {{.MaxVotes}} options for this question.",
+				},
+				Data: map[string]interface{}{
+					"MaxVotes": q.Settings.MaxVotes,
+				},
+			}
+		}
+	}
+	if len(errMsgs) > 0 {
+		return errMsgs, nil
+	}
+
+	for i, q := range p.Questions {
+		q.resetVotes(userID)
+		for _, index := range answers[i] {
+			o := q.AnswerOptions[index]
+			o.Voter = append(o.Voter, userID)
+		}
+	}
+	return nil, nil
+}
+
+// GetSurveyAnswers returns the indexes of the answer options a given user chose for every question of a survey.
+func (p *Poll) GetSurveyAnswers(userID string) [][]int {
+	answers := make([][]int, len(p.Questions))
+	for i, q := range p.Questions {
+		answers[i] = []int{}
+		for j, o := range q.AnswerOptions {
+			if slices.Contains(o.Voter, userID) {
+				answers[i] = append(answers[i], j)
+			}
+		}
+	}
+	return answers
+}
+
+// GetSurveyRespondents returns the ids of all users who answered at least one question of a survey,
+// sorted in the order they first appear in the survey.
+func (p *Poll) GetSurveyRespondents() []string {
+	respondents := []string{}
+	seen := map[string]bool{}
+	for _, q := range p.Questions {
+		for _, o := range q.AnswerOptions {
+			for _, v := range o.Voter {
+				if !seen[v] {
+					seen[v] = true
+					respondents = append(respondents, v)
+				}
+			}
+		}
+	}
+	return respondents
+}
+
+// resetVotes removes the votes of a given user from a question.
+func (q *SurveyQuestion) resetVotes(userID string) {
+	for _, o := range q.AnswerOptions {
+		o.Voter = slices.DeleteFunc(o.Voter, func(v string) bool { return v == userID })
+	}
+}
+
+// hasVoted returns true if a given user answered a question.
+func (q *SurveyQuestion) hasVoted(userID string) bool {
+	for _, o := range q.AnswerOptions {
+		if slices.Contains(o.Voter, userID) {
+			return true
+		}
+	}
+	return false
+}
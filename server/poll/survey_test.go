@@ -0,0 +1,180 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestNewSurvey(t *testing.T) {
+	var pf poll.Factory
+	pf.SetMillis(testutils.GetMillis)
+	pf.SetNewID(testutils.GetPollID)
+
+	t.Run("all fine", func(t *testing.T) {
+		p, errMsg := pf.NewSurvey("userID1", "Survey", []string{
+			`"Question 1" "Answer 1" "Answer 2"`,
+			"",
+			`"Question 2" "Answer 1" "Answer 2" "Answer 3" --votes=2 --anonymous`,
+		}, poll.Settings{MaxVotes: 1, Progress: true})
+		require.Nil(t, errMsg)
+		assert.Equal(t, &poll.Poll{
+			ID:        testutils.GetPollID(),
+			CreatedAt: testutils.GetMillis(),
+			Creator:   "userID1",
+			Question:  "Survey",
+			Questions: []*poll.SurveyQuestion{{
+				Question: "Question 1",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{}},
+					{Answer: "Answer 2", Voter: []string{}},
+				},
+				Settings: poll.Settings{MaxVotes: 1},
+			}, {
+				Question: "Question 2",
+				AnswerOptions: []*poll.AnswerOption{
+					{Answer: "Answer 1", Voter: []string{}},
+					{Answer: "Answer 2", Voter: []string{}},
+					{Answer: "Answer 3", Voter: []string{}},
+				},
+				Settings: poll.Settings{MaxVotes: 2, Anonymous: true},
+			}},
+			Settings: poll.Settings{MaxVotes: 1, Progress: true},
+		}, p)
+		assert.True(t, p.IsSurvey())
+	})
+
+	for name, test := range map[string]struct {
+		Questions []string
+		Settings  poll.Settings
+	}{
+		"no questions": {
+			Questions: []string{"", " "},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"question without options": {
+			Questions: []string{`"Question 1"`},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"question with one option": {
+			Questions: []string{`"Question 1" "Answer 1"`},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"duplicate option": {
+			Questions: []string{`"Question 1" "Answer 1" "Answer 1"`},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"votes setting exceeds": {
+			Questions: []string{`"Question 1" "Answer 1" "Answer 2" --votes=3`},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"unsupported question setting": {
+			Questions: []string{`"Question 1" "Answer 1" "Answer 2" --ranked`},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"unrecognized question setting": {
+			Questions: []string{`"Question 1" "Answer 1" "Answer 2" --foo`},
+			Settings:  poll.Settings{MaxVotes: 1},
+		},
+		"unsupported survey setting": {
+			Questions: []string{`"Question 1" "Answer 1" "Answer 2"`},
+			Settings:  poll.Settings{MaxVotes: 1, Anonymous: true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, errMsg := pf.NewSurvey("userID1", "Survey", test.Questions, test.Settings)
+			assert.NotNil(t, errMsg)
+			assert.Nil(t, p)
+		})
+	}
+}
+
+func TestSubmitSurvey(t *testing.T) {
+	for name, test := range map[string]struct {
+		UserID          string
+		Answers         [][]int
+		ExpectedAnswers [][]int
+		Error           bool
+		ReturnMessages  []int
+	}{
+		"first answers": {
+			UserID:          "userID4",
+			Answers:         [][]int{{1}, {0, 2}},
+			ExpectedAnswers: [][]int{{1}, {0, 2}},
+		},
+		"replace answers": {
+			UserID:          "userID1",
+			Answers:         [][]int{{1}, {}},
+			ExpectedAnswers: [][]int{{1}, {}},
+		},
+		"too many options": {
+			UserID:          "userID1",
+			Answers:         [][]int{{0, 1}, {0, 1, 2}},
+			ExpectedAnswers: [][]int{{0}, {0, 1}},
+			ReturnMessages:  []int{0, 1},
+		},
+		"invalid index": {
+			UserID:          "userID1",
+			Answers:         [][]int{{2}, {}},
+			ExpectedAnswers: [][]int{{0}, {0, 1}},
+			Error:           true,
+		},
+		"duplicate index": {
+			UserID:          "userID1",
+			Answers:         [][]int{{}, {1, 1}},
+			ExpectedAnswers: [][]int{{0}, {0, 1}},
+			Error:           true,
+		},
+		"invalid number of answers": {
+			UserID:          "userID1",
+			Answers:         [][]int{{0}},
+			ExpectedAnswers: [][]int{{0}, {0, 1}},
+			Error:           true,
+		},
+		"invalid user id": {
+			UserID:          "",
+			Answers:         [][]int{{0}, {}},
+			ExpectedAnswers: [][]int{{}, {}},
+			Error:           true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			p := testutils.GetSurveyWithAnswers()
+
+			errMsgs, err := p.SubmitSurvey(test.UserID, test.Answers)
+
+			if test.Error {
+				assert.NotNil(err)
+			} else {
+				assert.Nil(err)
+			}
+
+			if test.ReturnMessages != nil {
+				assert.Len(errMsgs, len(test.ReturnMessages))
+				for _, i := range test.ReturnMessages {
+					assert.NotNil(errMsgs[i])
+				}
+			} else {
+				assert.Nil(errMsgs)
+			}
+			assert.Equal(test.ExpectedAnswers, p.GetSurveyAnswers(test.UserID))
+		})
+	}
+}
+
+func TestGetSurveyRespondents(t *testing.T) {
+	p := testutils.GetSurveyWithAnswers()
+
+	assert.Equal(t, []string{"userID1", "userID2", "userID3"}, p.GetSurveyRespondents())
+	assert.True(t, p.HasVoted("userID3"))
+	assert.False(t, p.HasVoted("userID4"))
+
+	p.ResetVotes("userID1")
+	assert.Equal(t, []string{"userID2", "userID3"}, p.GetSurveyRespondents())
+	assert.Equal(t, [][]int{{}, {}}, p.GetSurveyAnswers("userID1"))
+}
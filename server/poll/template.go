@@ -0,0 +1,90 @@
+package poll
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// Template is a named poll definition, that can be used to create new polls with the same question, options and settings.
+type Template struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// TeamID is the id of the team, the template is shared with. It's empty for personal templates.
+	TeamID string `json:"team_id,omitempty"`
+	// Poll is the saved poll definition. Its creator is the owner of the template.
+	Poll *Poll `json:"poll"`
+}
+
+// NewTemplate creates a new template with a given name from a poll.
+// If teamID is not empty, the template is shared with the team. Otherwise it's a personal template of the creator of the poll.
+func (fac *Factory) NewTemplate(name, teamID string, p *Poll) (*Template, *utils.ErrorMessage) {
+	if name == "" || strings.ContainsAny(name, " \t\n\"“”") {
+		return nil, &utils.ErrorMessage{
+			Message: &i18n.Message{
+				ID:    "poll.newTemplate.name.invalid",
+				Other: `Invalid template name "{{.Name}}". The name must be a single word like "standup".`,
+			},
+			Data: map[string]interface{}{
+				"Name": name,
+			},
+		}
+	}
+
+	return &Template{
+		ID:     fac.NewID(),
+		Name:   name,
+		TeamID: teamID,
+		Poll:   p,
+	}, nil
+}
+
+// IsShared returns true if the template is shared with a team.
+func (t *Template) IsShared() bool {
+	return t.TeamID != ""
+}
+
+// CanUse returns true if a user in a given team is allowed to use the template.
+func (t *Template) CanUse(userID, teamID string) bool {
+	if t.IsShared() {
+		return t.TeamID == teamID
+	}
+	return t.Poll.Creator == userID
+}
+
+// FindTemplate returns the template with a given name. Personal templates take precedence over shared ones.
+// Returns nil if there is no template with that name.
+func FindTemplate(templates []*Template, name string) *Template {
+	var shared *Template
+	for _, t := range templates {
+		if t.Name != name {
+			continue
+		}
+		if !t.IsShared() {
+			return t
+		}
+		if shared == nil {
+			shared = t
+		}
+	}
+	return shared
+}
+
+// EncodeToByte returns a template as a byte array
+func (t *Template) EncodeToByte() []byte {
+	b, _ := json.Marshal(t)
+	return b
+}
+
+// DecodeTemplateFromByte tries to create a template from a byte array
+func DecodeTemplateFromByte(b []byte) *Template {
+	t := Template{}
+	err := json.Unmarshal(b, &t)
+	if err != nil || t.Poll == nil {
+		return nil
+	}
+	return &t
+}
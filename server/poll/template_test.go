@@ -0,0 +1,70 @@
+package poll_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestNewTemplate(t *testing.T) {
+	var pf poll.Factory
+	pf.SetNewID(func() string { return "templateID" })
+
+	t.Run("personal template", func(t *testing.T) {
+		template, errMsg := pf.NewTemplate("standup", "", testutils.GetPoll())
+		require.Nil(t, errMsg)
+		assert.Equal(t, &poll.Template{ID: "templateID", Name: "standup", Poll: testutils.GetPoll()}, template)
+		assert.False(t, template.IsShared())
+	})
+	t.Run("shared template", func(t *testing.T) {
+		template, errMsg := pf.NewTemplate("standup", "teamID1", testutils.GetPoll())
+		require.Nil(t, errMsg)
+		assert.Equal(t, &poll.Template{ID: "templateID", Name: "standup", TeamID: "teamID1", Poll: testutils.GetPoll()}, template)
+		assert.True(t, template.IsShared())
+	})
+	for name, templateName := range map[string]string{
+		"empty name":       "",
+		"name with space":  "daily standup",
+		"name with quotes": `"standup"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			template, errMsg := pf.NewTemplate(templateName, "", testutils.GetPoll())
+			assert.NotNil(t, errMsg)
+			assert.Nil(t, template)
+		})
+	}
+}
+
+func TestTemplateCanUse(t *testing.T) {
+	personal := &poll.Template{Name: "standup", Poll: testutils.GetPoll()}
+	assert.True(t, personal.CanUse("userID1", "teamID1"))
+	assert.True(t, personal.CanUse("userID1", "teamID2"))
+	assert.False(t, personal.CanUse("userID2", "teamID1"))
+
+	shared := &poll.Template{Name: "standup", TeamID: "teamID1", Poll: testutils.GetPoll()}
+	assert.True(t, shared.CanUse("userID1", "teamID1"))
+	assert.True(t, shared.CanUse("userID2", "teamID1"))
+	assert.False(t, shared.CanUse("userID1", "teamID2"))
+}
+
+func TestFindTemplate(t *testing.T) {
+	personal := &poll.Template{ID: "templateID1", Name: "standup", Poll: testutils.GetPoll()}
+	shared := &poll.Template{ID: "templateID2", Name: "standup", TeamID: "teamID1", Poll: testutils.GetPoll()}
+	other := &poll.Template{ID: "templateID3", Name: "retro", TeamID: "teamID1", Poll: testutils.GetPoll()}
+
+	assert.Equal(t, personal, poll.FindTemplate([]*poll.Template{shared, personal, other}, "standup"))
+	assert.Equal(t, shared, poll.FindTemplate([]*poll.Template{shared, other}, "standup"))
+	assert.Equal(t, other, poll.FindTemplate([]*poll.Template{shared, personal, other}, "retro"))
+	assert.Nil(t, poll.FindTemplate([]*poll.Template{shared, personal, other}, "unknown"))
+}
+
+func TestTemplateEncodeDecode(t *testing.T) {
+	template := &poll.Template{ID: "templateID", Name: "standup", TeamID: "teamID1", Poll: testutils.GetPoll()}
+	assert.Equal(t, template, poll.DecodeTemplateFromByte(template.EncodeToByte()))
+	assert.Nil(t, poll.DecodeTemplateFromByte([]byte{}))
+	assert.Nil(t, poll.DecodeTemplateFromByte([]byte(`{"id": "templateID"}`)))
+}
@@ -2,7 +2,9 @@ package poll
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/matterpoll/matterpoll/server/utils"
 )
 
+// timeFormat is the layout used to display times in poll posts, e.g. the time at which a poll ends.
+const timeFormat = "Mon, 02 Jan 2006 15:04 MST"
+
 // IDToNameConverter converts a given userID to a human readable name.
 type IDToNameConverter func(userID string) (string, *model.AppError)
 
@@ -19,10 +24,37 @@ var (
 		ID:    "poll.message.pollSettings",
 		Other: "**Poll Settings**: {{.Settings}}",
 	}
+	pollMessageEndAt = &i18n.Message{
+		ID:    "poll.message.endAt",
+		Other: "**Poll ends at**: {{.EndAt}}",
+	}
 	pollMessageTotalVotes = &i18n.Message{
 		ID:    "poll.message.totalVotes",
 		Other: "**Total votes**: {{.TotalVotes}}",
 	}
+	pollMessageTotalPoints = &i18n.Message{
+		ID:    "poll.message.totalPoints",
+		One:   "**Total points**: {{.TotalPoints}} ({{ .TotalVoters }} voter)",
+		Few:   "**Total points**: {{.TotalPoints}} ({{ .TotalVoters }} voters)",
+		Many:  "**Total points**: {{.TotalPoints}} ({{ .TotalVoters }} voters)",
+		Other: "**Total points**: {{.TotalPoints}} ({{ .TotalVoters }} voters)",
+	}
+	pollMessageTotalResponses = &i18n.Message{
+		ID:    "poll.message.totalResponses",
+		Other: "**Total responses**: {{.TotalResponses}}",
+	}
+	pollMessageTotalRespondents = &i18n.Message{
+		ID:    "poll.message.totalRespondents",
+		Other: "**Total respondents**: {{.TotalRespondents}}",
+	}
+	pollMessageEditedAt = &i18n.Message{
+		ID:    "poll.message.editedAt",
+		Other: "**Edited**: {{.EditedAt}}",
+	}
+	pollMessageSecretBallot = &i18n.Message{
+		ID:    "poll.message.secretBallot",
+		Other: "**Secret ballot**: Votes are stored without the names of the voters",
+	}
 	pollMessageTotalVotesMultiSetting = &i18n.Message{
 		ID:    "poll.message.totalVotesMulti",
 		One:   "**Total votes**: {{.TotalVotes}} ({{ .TotalVoters }} voter)",
@@ -46,6 +78,49 @@ var (
 		Many:  "{{.Answer}} ({{.Count}} votes)",
 		Other: "{{.Answer}} ({{.Count}} votes)",
 	}
+	pollEndPostAnswerPointsHeading = &i18n.Message{
+		ID:    "poll.endPost.answer.pointsHeading",
+		One:   "{{.Answer}} ({{.Count}} point)",
+		Few:   "{{.Answer}} ({{.Count}} points)",
+		Many:  "{{.Answer}} ({{.Count}} points)",
+		Other: "{{.Answer}} ({{.Count}} points)",
+	}
+	pollEndPostProxyVote = &i18n.Message{
+		ID:    "poll.endPost.proxyVote",
+		Other: "{{.Voter}} (by proxy: {{.Proxy}})",
+	}
+	pollEndPostCorrectAnswer = &i18n.Message{
+		ID:    "poll.endPost.answer.correct",
+		Other: "✅ {{.Answer}}",
+	}
+	pollEndPostWinningAnswer = &i18n.Message{
+		ID:    "poll.endPost.answer.winning",
+		Other: "🏆 {{.Answer}}",
+	}
+	pollEndPostAnswerShare = &i18n.Message{
+		ID:    "poll.endPost.answer.share",
+		Other: "{{.Heading}} · {{.VotesShare}}%",
+	}
+	pollEndPostAnswerShareMulti = &i18n.Message{
+		ID:    "poll.endPost.answer.shareMulti",
+		Other: "{{.Heading}} · {{.VotesShare}}% of votes, {{.VotersShare}}% of voters",
+	}
+	pollEndPostAnswerSharePoints = &i18n.Message{
+		ID:    "poll.endPost.answer.sharePoints",
+		Other: "{{.Heading}} · {{.VotesShare}}% of points, {{.VotersShare}}% of voters",
+	}
+	pollEndPostTieBreakCreator = &i18n.Message{
+		ID:    "poll.endPost.tieBreak.creator",
+		Other: "The creator of the poll picks the winner.",
+	}
+	pollEndPostTieBreakRunoff = &i18n.Message{
+		ID:    "poll.endPost.tieBreak.runoff",
+		Other: "A runoff poll between the tied options has been started.",
+	}
+	pollEndPostTieBroken = &i18n.Message{
+		ID:    "poll.endPost.tieBreak.broken",
+		Other: "The creator of the poll broke the tie.",
+	}
 	rhsCardPollVoterSeparator = &i18n.Message{
 		ID:    "rhs.card.poll.voter.separator",
 		Other: "and",
@@ -61,6 +136,100 @@ var (
 		Many:  "{{.Answer}} ({{.Count}} votes)",
 		Other: "{{.Answer}} ({{.Count}} votes)",
 	}
+	rhsCardPollAnswerPointsHeading = &i18n.Message{
+		ID:    "rhs.card.poll.answer.pointsHeading",
+		One:   "{{.Answer}} ({{.Count}} point)",
+		Few:   "{{.Answer}} ({{.Count}} points)",
+		Many:  "{{.Answer}} ({{.Count}} points)",
+		Other: "{{.Answer}} ({{.Count}} points)",
+	}
+
+	schedulingAnswerHeading = &i18n.Message{
+		ID:    "poll.scheduling.answer.heading",
+		Other: "{{.Answer}} ({{.Yes}} yes, {{.Maybe}} maybe, {{.No}} no)",
+	}
+	schedulingVotersYes = &i18n.Message{
+		ID:    "poll.scheduling.voters.yes",
+		Other: "Yes: {{.Voters}}",
+	}
+	schedulingVotersMaybe = &i18n.Message{
+		ID:    "poll.scheduling.voters.maybe",
+		Other: "Maybe: {{.Voters}}",
+	}
+	schedulingVotersNo = &i18n.Message{
+		ID:    "poll.scheduling.voters.no",
+		Other: "No: {{.Voters}}",
+	}
+
+	freeTextResponsesHeading = &i18n.Message{
+		ID:    "poll.freeText.responses.heading",
+		Other: "Responses",
+	}
+	freeTextNoResponses = &i18n.Message{
+		ID:    "poll.freeText.noResponses",
+		Other: "No responses have been submitted.",
+	}
+
+	leaderboardHeading = &i18n.Message{
+		ID:    "poll.leaderboard.heading",
+		Other: "Quiz Leaderboard",
+	}
+	leaderboardTableHeader = &i18n.Message{
+		ID:    "poll.leaderboard.table.header",
+		Other: "| Rank | User | Correct answers | Answered quizzes |",
+	}
+
+	voteHistoryEmpty = &i18n.Message{
+		ID:    "poll.voteHistory.empty",
+		Other: "Nobody has voted yet.",
+	}
+	voteHistoryVote = &i18n.Message{
+		ID:    "poll.voteHistory.vote",
+		Other: "{{.Time}}: **{{.User}}** voted for {{.Answers}}",
+	}
+	voteHistoryChange = &i18n.Message{
+		ID:    "poll.voteHistory.change",
+		Other: "{{.Time}}: **{{.User}}** changed their vote to {{.Answers}}",
+	}
+	voteHistoryReset = &i18n.Message{
+		ID:    "poll.voteHistory.reset",
+		Other: "{{.Time}}: **{{.User}}** reset their vote",
+	}
+	voteHistoryByProxy = &i18n.Message{
+		ID:    "poll.voteHistory.byProxy",
+		Other: "{{.Event}}, recorded by **{{.Proxy}}**",
+	}
+
+	rankedRoundHeading = &i18n.Message{
+		ID:    "poll.ranked.round.heading",
+		Other: "Round {{.Round}}",
+	}
+	rankedRoundCount = &i18n.Message{
+		ID:    "poll.ranked.round.count",
+		One:   "{{.Answer}}: {{.Count}} vote",
+		Few:   "{{.Answer}}: {{.Count}} votes",
+		Many:  "{{.Answer}}: {{.Count}} votes",
+		Other: "{{.Answer}}: {{.Count}} votes",
+	}
+	rankedRoundEliminated = &i18n.Message{
+		ID:    "poll.ranked.round.eliminated",
+		Other: "Eliminated: {{.Answers}}",
+	}
+	pollWinnerHeading = &i18n.Message{
+		ID:    "poll.winner.heading",
+		One:   "Winner",
+		Few:   "Tie",
+		Many:  "Tie",
+		Other: "Tie",
+	}
+	rankedNoBallots = &i18n.Message{
+		ID:    "poll.ranked.noBallots",
+		Other: "No ballots have been submitted.",
+	}
+	rankedBallotsHeading = &i18n.Message{
+		ID:    "poll.ranked.ballots.heading",
+		Other: "Ballots",
+	}
 )
 
 // ToPostActions returns the poll as a message
@@ -70,14 +239,24 @@ func (p *Poll) ToPostActions(bundle *utils.Bundle, pluginID, authorName string)
 	voters := make(map[string]struct{})
 	actions := []*model.PostAction{}
 
+	var progress []int
+	if p.Settings.Progress {
+		progress = p.countProgress()
+	}
+
 	for i, o := range p.AnswerOptions {
-		numberOfVotes += len(o.Voter)
+		numberOfVotes += p.countVotes(o) - p.countIgnoredVotes(o)
 		for _, v := range o.Voter {
-			voters[v] = struct{}{}
+			if !p.ignoredVoters[v] {
+				voters[v] = struct{}{}
+			}
 		}
 		answer := o.Answer
-		if p.Settings.Progress {
-			answer = fmt.Sprintf("%s (%d)", answer, len(o.Voter))
+		if o.Capacity > 0 {
+			// The remaining spots already show the progress
+			answer = o.makeCapacityLabel()
+		} else if p.Settings.Progress {
+			answer = fmt.Sprintf("%s (%d)", answer, progress[i])
 		}
 		actions = append(actions, &model.PostAction{
 			Id:    fmt.Sprintf("vote%v", i),
@@ -90,8 +269,39 @@ func (p *Poll) ToPostActions(bundle *utils.Bundle, pluginID, authorName string)
 		})
 	}
 
-	actions = append(actions,
-		&model.PostAction{
+	if p.IsFreeText() {
+		actions = append(actions, &model.PostAction{
+			Id: "respond",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.respond",
+				Other: "Submit Your Answer",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "default",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/response/request", pluginID, p.ID),
+			},
+		})
+	}
+
+	if p.IsSurvey() {
+		actions = append(actions, &model.PostAction{
+			Id: "answerSurvey",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.answerSurvey",
+				Other: "Answer Survey",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "default",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/survey/request", pluginID, p.ID),
+			},
+		})
+	}
+
+	if !p.IsQuiz() && !p.IsSurvey() && !p.IsFinal() {
+		// Quiz answers and final votes can't be reset. Survey answers are changed in the survey dialog.
+		actions = append(actions, &model.PostAction{
 			Id: "resetVote",
 			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
 				DefaultMessage: &i18n.Message{
@@ -108,10 +318,52 @@ func (p *Poll) ToPostActions(bundle *utils.Bundle, pluginID, authorName string)
 			Integration: &model.PostActionIntegration{
 				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/votes/reset", pluginID, p.ID),
 			},
-		},
-	)
-	actions = append(actions,
-		&model.PostAction{
+		})
+	}
+	if p.AllowsProxyVotes() {
+		actions = append(actions, &model.PostAction{
+			Id: "delegateVote",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.delegateVote",
+				Other: "Delegate Your Vote",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "primary",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/delegate/request", pluginID, p.ID),
+			},
+		}, &model.PostAction{
+			Id: "proxyVote",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.proxyVote",
+				Other: "Vote by Proxy",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "primary",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/proxy/request", pluginID, p.ID),
+			},
+		})
+	}
+	if p.HasPointBudget() {
+		actions = append(actions,
+			&model.PostAction{
+				Id: "editPoints",
+				Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+					ID:    "poll.button.editPoints",
+					Other: "Edit Your Points",
+				}}),
+				Type:  model.PostActionTypeButton,
+				Style: "primary",
+				Integration: &model.PostActionIntegration{
+					URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/points/request", pluginID, p.ID),
+				},
+			},
+		)
+	}
+	if !p.IsFreeText() && !p.IsSurvey() {
+		// Free-text polls and surveys have no answer options
+		actions = append(actions, &model.PostAction{
 			Id: "addOption",
 			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
 				ID:    "poll.button.addOption",
@@ -123,6 +375,49 @@ func (p *Poll) ToPostActions(bundle *utils.Bundle, pluginID, authorName string)
 				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/add/request", pluginID, p.ID),
 			},
 		}, &model.PostAction{
+			Id: "manageOptions",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.manageOptions",
+				Other: "Manage Options",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "primary",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/option/manage/request", pluginID, p.ID),
+			},
+		})
+	}
+	actions = append(actions,
+		&model.PostAction{
+			Id: "editPoll",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.editPoll",
+				Other: "Edit Poll",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "primary",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/edit/request", pluginID, p.ID),
+			},
+		},
+	)
+	if p.HasVoteHistory() {
+		actions = append(actions, &model.PostAction{
+			Id: "viewHistory",
+			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+				ID:    "poll.button.viewHistory",
+				Other: "View History",
+			}}),
+			Type:  model.PostActionTypeButton,
+			Style: "primary",
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/history/request", pluginID, p.ID),
+			},
+		})
+	}
+	actions = append(actions,
+		p.makeRerunAction(bundle, pluginID),
+		&model.PostAction{
 			Id: "endPoll",
 			Name: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
 				ID:    "poll.button.endPoll",
@@ -147,14 +442,27 @@ func (p *Poll) ToPostActions(bundle *utils.Bundle, pluginID, authorName string)
 		},
 	)
 
+	if p.IsRanked() || p.IsScheduling() {
+		// Every voter submits exactly one ballot
+		numberOfVotes = len(voters)
+	}
+	for token := range p.Ballots {
+		voters[token] = struct{}{}
+	}
+
 	if p.Settings.AnonymousCreator {
 		authorName = ""
 	}
 
+	text := p.makeAdditionalText(bundle, numberOfVotes, len(voters))
+	if p.IsSurvey() {
+		text = p.makeSurveyText() + "\n" + text
+	}
+
 	return []*model.MessageAttachment{{
 		AuthorName: authorName,
 		Title:      p.Question,
-		Text:       p.makeAdditionalText(bundle, numberOfVotes, len(voters)),
+		Text:       text,
 		Actions:    actions,
 	}}
 }
@@ -173,7 +481,54 @@ func (p *Poll) makeAdditionalText(bundle *utils.Bundle, numberOfVotes, numberOfV
 		}))
 	}
 
-	if p.IsMultiVote() {
+	if p.IsRestricted() {
+		lines = append(lines, p.makeVotersText(bundle, localizer))
+	}
+
+	if p.HasEndTime() {
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: pollMessageEndAt,
+			TemplateData:   map[string]interface{}{"EndAt": time.UnixMilli(p.Settings.EndAt).UTC().Format(timeFormat)},
+		}))
+	}
+
+	if p.IsEdited() {
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: pollMessageEditedAt,
+			TemplateData:   map[string]interface{}{"EditedAt": time.UnixMilli(p.EditedAt).UTC().Format(timeFormat)},
+		}))
+	}
+
+	if p.IsSecretBallot() {
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: pollMessageSecretBallot}))
+	}
+
+	if p.IsSurvey() {
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: pollMessageTotalRespondents,
+			TemplateData:   map[string]interface{}{"TotalRespondents": p.countParticipants(p.GetSurveyRespondents())},
+		}))
+	} else if p.IsFreeText() {
+		responses := 0
+		for _, r := range p.Responses {
+			if !p.ignoredVoters[r.UserID] {
+				responses++
+			}
+		}
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: pollMessageTotalResponses,
+			TemplateData:   map[string]interface{}{"TotalResponses": responses},
+		}))
+	} else if p.HasPointBudget() {
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: pollMessageTotalPoints,
+			TemplateData: map[string]interface{}{
+				"TotalPoints": numberOfVotes,
+				"TotalVoters": numberOfVoters,
+			},
+			PluralCount: numberOfVoters,
+		}))
+	} else if p.IsMultiVote() {
 		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
 			DefaultMessage: pollMessageTotalVotesMultiSetting,
 			TemplateData: map[string]interface{}{
@@ -192,56 +547,160 @@ func (p *Poll) makeAdditionalText(bundle *utils.Bundle, numberOfVotes, numberOfV
 }
 
 // ToEndPollPost returns the poll end message
-func (p *Poll) ToEndPollPost(bundle *utils.Bundle, authorName string, convert IDToNameConverter) (*model.Post, *model.AppError) {
+func (p *Poll) ToEndPollPost(bundle *utils.Bundle, pluginID, authorName string, convert IDToNameConverter) (*model.Post, *model.AppError) {
 	localizer := bundle.GetServerLocalizer()
 	post := &model.Post{}
+
+	var fields []*model.MessageAttachmentField
+	var err *model.AppError
+	if p.IsRanked() {
+		fields, err = p.makeRankedEndPollFields(bundle, convert)
+	} else if p.IsScheduling() {
+		fields, err = p.makeSchedulingEndPollFields(bundle, convert)
+	} else if p.IsFreeText() {
+		fields, err = p.makeFreeTextEndPollFields(bundle, convert)
+	} else if p.IsSurvey() {
+		fields, err = p.makeSurveyEndPollFields(bundle, convert)
+	} else {
+		fields, err = p.makeEndPollFields(bundle, convert)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Settings.AnonymousCreator {
+		authorName = ""
+	}
+
+	attachments := []*model.MessageAttachment{{
+		AuthorName: authorName,
+		Title:      p.Question,
+		Text:       bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: pollEndPostText}),
+		Fields:     fields,
+		Actions:    []*model.PostAction{p.makeRerunAction(bundle, pluginID)},
+	}}
+
+	model.ParseMessageAttachment(post, attachments)
+	post.AddProp("poll_id", p.ID)
+
+	return post, nil
+}
+
+// makeRerunAction returns the action to run a poll again. It's part of active and ended polls.
+func (p *Poll) makeRerunAction(bundle *utils.Bundle, pluginID string) *model.PostAction {
+	return &model.PostAction{
+		Id: "rerunPoll",
+		Name: bundle.LocalizeWithConfig(bundle.GetServerLocalizer(), &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{
+			ID:    "poll.button.rerunPoll",
+			Other: "Run Again",
+		}}),
+		Type:  model.PostActionTypeButton,
+		Style: "primary",
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("/plugins/%s/api/v1/polls/%s/rerun/request", pluginID, p.ID),
+		},
+	}
+}
+
+// makeEndPollFields returns a field per answer option with its votes and, unless the poll is anonymous, its voters.
+func (p *Poll) makeEndPollFields(bundle *utils.Bundle, convert IDToNameConverter) ([]*model.MessageAttachmentField, *model.AppError) {
+	localizer := bundle.GetServerLocalizer()
 	fields := []*model.MessageAttachmentField{}
 
+	heading := pollEndPostAnswerHeading
+	shareHeading := pollEndPostAnswerShare
+	if p.HasPointBudget() {
+		heading = pollEndPostAnswerPointsHeading
+		shareHeading = pollEndPostAnswerSharePoints
+	} else if p.IsMultiVote() {
+		shareHeading = pollEndPostAnswerShareMulti
+	}
+
+	totalVotes := 0
 	for _, o := range p.AnswerOptions {
+		totalVotes += p.countVotes(o)
+	}
+	totalVoters := len(p.GetVoters())
+	winners := p.GetWinners()
+
+	for i, o := range p.AnswerOptions {
 		var voter string
 		if !p.Settings.Anonymous {
-			for i := 0; i < len(o.Voter); i++ {
-				displayName, err := convert(o.Voter[i])
+			for j := 0; j < len(o.Voter); j++ {
+				displayName, err := convert(o.Voter[j])
 				if err != nil {
 					return nil, err
 				}
-				if i+1 == len(o.Voter) && len(o.Voter) > 1 {
+				if j+1 == len(o.Voter) && len(o.Voter) > 1 {
 					voter += " " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: pollEndPostSeparator}) + " "
-				} else if i != 0 {
+				} else if j != 0 {
 					voter += ", "
 				}
+				if proxyID := o.Proxies[o.Voter[j]]; proxyID != "" {
+					proxyName, err := convert(proxyID)
+					if err != nil {
+						return nil, err
+					}
+					displayName = bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+						DefaultMessage: pollEndPostProxyVote,
+						TemplateData:   map[string]interface{}{"Voter": displayName, "Proxy": proxyName},
+					})
+				}
 				voter += displayName
+				if p.HasPointBudget() {
+					voter += fmt.Sprintf(" (%d)", o.Points[o.Voter[j]])
+				}
 			}
 		}
 
-		fields = append(fields, &model.MessageAttachmentField{
-			Short: true,
-			Title: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
-				DefaultMessage: pollEndPostAnswerHeading,
+		title := bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: heading,
+			TemplateData: map[string]interface{}{
+				"Answer": o.Answer,
+				"Count":  p.countVotes(o),
+			},
+			PluralCount: p.countVotes(o),
+		})
+		if totalVotes > 0 {
+			votesShare, votersShare := p.getShares(o, totalVotes, totalVoters)
+			title = bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+				DefaultMessage: shareHeading,
 				TemplateData: map[string]interface{}{
-					"Answer": o.Answer,
-					"Count":  len(o.Voter),
+					"Heading":     title,
+					"VotesShare":  votesShare,
+					"VotersShare": votersShare,
 				},
-				PluralCount: len(o.Voter),
-			}),
+			})
+		}
+		if p.IsQuiz() && o.Correct {
+			title = bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+				DefaultMessage: pollEndPostCorrectAnswer,
+				TemplateData:   map[string]interface{}{"Answer": title},
+			})
+		}
+		if slices.Contains(winners, i) {
+			title = bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+				DefaultMessage: pollEndPostWinningAnswer,
+				TemplateData:   map[string]interface{}{"Answer": title},
+			})
+		}
+
+		fields = append(fields, &model.MessageAttachmentField{
+			Short: true,
+			Title: title,
 			Value: voter,
 		})
 	}
 
-	if p.Settings.AnonymousCreator {
-		authorName = ""
+	if len(winners) > 0 {
+		title, value := p.makeWinner(bundle, winners)
+		fields = append(fields, &model.MessageAttachmentField{
+			Title: title,
+			Value: value + p.makeTieBreakNote(bundle),
+		})
 	}
 
-	attachments := []*model.MessageAttachment{{
-		AuthorName: authorName,
-		Title:      p.Question,
-		Text:       bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: pollEndPostText}),
-		Fields:     fields,
-	}}
-
-	model.ParseMessageAttachment(post, attachments)
-
-	return post, nil
+	return fields, nil
 }
 
 // ToCard return the poll for rhs card
@@ -254,6 +713,24 @@ func (p *Poll) ToCard(bundle *utils.Bundle, convert IDToNameConverter) string {
 		s += fmt.Sprintf(bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: rhsCardPollCreatedBy})+" %s\n", creatorName)
 	}
 
+	if p.IsRanked() {
+		return s + p.makeRankedCard(bundle, convert)
+	}
+	if p.IsScheduling() {
+		return s + p.makeSchedulingCard(bundle, convert)
+	}
+	if p.IsFreeText() {
+		return s + p.makeFreeTextCard(bundle, convert)
+	}
+	if p.IsSurvey() {
+		return s + p.makeSurveyCard(bundle, convert)
+	}
+
+	heading := rhsCardPollAnswerHeading
+	if p.HasPointBudget() {
+		heading = rhsCardPollAnswerPointsHeading
+	}
+
 	const comma = ", "
 	for _, o := range p.AnswerOptions {
 		var voter string
@@ -269,17 +746,554 @@ func (p *Poll) ToCard(bundle *utils.Bundle, convert IDToNameConverter) string {
 					voter += comma
 				}
 				voter += displayName
+				if p.HasPointBudget() {
+					voter += fmt.Sprintf(" (%d)", o.Points[o.Voter[i]])
+				}
 			}
 		}
 
 		s += "### " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
-			DefaultMessage: rhsCardPollAnswerHeading,
+			DefaultMessage: heading,
 			TemplateData: map[string]interface{}{
 				"Answer": o.Answer,
-				"Count":  len(o.Voter),
+				"Count":  p.countVotes(o),
 			},
-			PluralCount: len(o.Voter),
+			PluralCount: p.countVotes(o),
 		}) + "\n" + voter + "\n"
 	}
 	return s
 }
+
+// countProgress returns the number shown next to every answer option as progress.
+func (p *Poll) countProgress() []int {
+	var firstChoices map[int]int
+	if p.IsRanked() {
+		firstChoices = p.TallyRanked().Rounds[0].Counts
+	}
+
+	counts := make([]int, len(p.AnswerOptions))
+	for i, o := range p.AnswerOptions {
+		switch {
+		case p.IsRanked():
+			// Ranked-choice polls show how often an option was ranked first
+			counts[i] = firstChoices[i]
+		case p.IsScheduling():
+			// Scheduling polls show how many voters are available
+			counts[i] = o.CountAvailability(AvailabilityYes)
+		default:
+			counts[i] = p.countVotes(o)
+		}
+	}
+	return counts
+}
+
+// countVotes returns the number of votes of an answer option or, for point-budget polls, the number of points.
+func (p *Poll) countVotes(o *AnswerOption) int {
+	if p.HasPointBudget() {
+		return o.TotalPoints()
+	}
+	if p.IsSecretBallot() {
+		return o.Votes
+	}
+	return len(o.Voter)
+}
+
+// countIgnoredVotes returns the number of votes or points of an answer option, that were cast by ignored voters.
+func (p *Poll) countIgnoredVotes(o *AnswerOption) int {
+	n := 0
+	for _, v := range o.Voter {
+		if !p.ignoredVoters[v] {
+			continue
+		}
+		if p.HasPointBudget() {
+			n += o.Points[v]
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// countParticipants returns the number of given users, that aren't ignored voters.
+func (p *Poll) countParticipants(userIDs []string) int {
+	n := 0
+	for _, userID := range userIDs {
+		if !p.ignoredVoters[userID] {
+			n++
+		}
+	}
+	return n
+}
+
+// makeSchedulingEndPollFields returns a field per answer option, sorted by availability.
+// Unless the poll is anonymous, the fields list who answered yes, maybe or no.
+func (p *Poll) makeSchedulingEndPollFields(bundle *utils.Bundle, convert IDToNameConverter) ([]*model.MessageAttachmentField, *model.AppError) {
+	fields := []*model.MessageAttachmentField{}
+	for _, i := range p.RankByAvailability() {
+		lines, err := p.makeSchedulingVoterLines(bundle, p.AnswerOptions[i], convert)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &model.MessageAttachmentField{
+			Short: true,
+			Title: p.makeSchedulingHeading(bundle, p.AnswerOptions[i]),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+	return fields, nil
+}
+
+// makeSchedulingCard returns the answer options of a scheduling poll for the rhs card, sorted by availability.
+func (p *Poll) makeSchedulingCard(bundle *utils.Bundle, convert IDToNameConverter) string {
+	var s string
+	for _, i := range p.RankByAvailability() {
+		lines, err := p.makeSchedulingVoterLines(bundle, p.AnswerOptions[i], convert)
+		if err != nil {
+			return ""
+		}
+		s += "### " + p.makeSchedulingHeading(bundle, p.AnswerOptions[i]) + "\n"
+		for _, line := range lines {
+			s += line + "\n"
+		}
+	}
+	return s
+}
+
+// makeSchedulingHeading returns the answer of an answer option together with the number of yes, maybe and no answers.
+func (p *Poll) makeSchedulingHeading(bundle *utils.Bundle, o *AnswerOption) string {
+	return bundle.LocalizeWithConfig(bundle.GetServerLocalizer(), &i18n.LocalizeConfig{
+		DefaultMessage: schedulingAnswerHeading,
+		TemplateData: map[string]interface{}{
+			"Answer": o.Answer,
+			"Yes":    o.CountAvailability(AvailabilityYes),
+			"Maybe":  o.CountAvailability(AvailabilityMaybe),
+			"No":     o.CountAvailability(AvailabilityNo),
+		},
+	})
+}
+
+// makeSchedulingVoterLines returns a line per answer listing the voters who gave it.
+// It returns no lines for anonymous polls.
+func (p *Poll) makeSchedulingVoterLines(bundle *utils.Bundle, o *AnswerOption, convert IDToNameConverter) ([]string, *model.AppError) {
+	localizer := bundle.GetServerLocalizer()
+	lines := []string{}
+	if p.Settings.Anonymous {
+		return lines, nil
+	}
+
+	for _, a := range []struct {
+		availability Availability
+		message      *i18n.Message
+	}{
+		{AvailabilityYes, schedulingVotersYes},
+		{AvailabilityMaybe, schedulingVotersMaybe},
+		{AvailabilityNo, schedulingVotersNo},
+	} {
+		voters := o.GetVotersWithAvailability(a.availability)
+		if len(voters) == 0 {
+			continue
+		}
+
+		names := make([]string, len(voters))
+		for i, v := range voters {
+			displayName, err := convert(v)
+			if err != nil {
+				return nil, err
+			}
+			names[i] = displayName
+		}
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: a.message,
+			TemplateData:   map[string]interface{}{"Voters": strings.Join(names, ", ")},
+		}))
+	}
+	return lines, nil
+}
+
+// makeRankedEndPollFields returns a field per instant-runoff round, the winners and, unless the poll is anonymous, the ballots.
+func (p *Poll) makeRankedEndPollFields(bundle *utils.Bundle, convert IDToNameConverter) ([]*model.MessageAttachmentField, *model.AppError) {
+	localizer := bundle.GetServerLocalizer()
+	result := p.TallyRanked()
+	fields := []*model.MessageAttachmentField{}
+
+	for i, round := range result.Rounds {
+		fields = append(fields, &model.MessageAttachmentField{
+			Short: true,
+			Title: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+				DefaultMessage: rankedRoundHeading,
+				TemplateData:   map[string]interface{}{"Round": i + 1},
+			}),
+			Value: strings.Join(p.makeRankedRoundLines(bundle, round), "\n"),
+		})
+	}
+
+	title, value := p.makeRankedWinner(bundle, result)
+	fields = append(fields, &model.MessageAttachmentField{
+		Title: title,
+		Value: value + p.makeTieBreakNote(bundle),
+	})
+
+	if !p.Settings.Anonymous {
+		lines, err := p.makeBallotLines(convert)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) > 0 {
+			fields = append(fields, &model.MessageAttachmentField{
+				Title: bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: rankedBallotsHeading}),
+				Value: strings.Join(lines, "\n"),
+			})
+		}
+	}
+	return fields, nil
+}
+
+// makeRankedCard returns the current standings of a ranked-choice poll for the rhs card.
+func (p *Poll) makeRankedCard(bundle *utils.Bundle, convert IDToNameConverter) string {
+	localizer := bundle.GetServerLocalizer()
+	result := p.TallyRanked()
+	var s string
+
+	for i, round := range result.Rounds {
+		s += "### " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: rankedRoundHeading,
+			TemplateData:   map[string]interface{}{"Round": i + 1},
+		}) + "\n"
+		for _, line := range p.makeRankedRoundLines(bundle, round) {
+			s += "- " + line + "\n"
+		}
+	}
+
+	title, value := p.makeRankedWinner(bundle, result)
+	if title != "" {
+		s += "### " + title + "\n"
+	}
+	s += value + "\n"
+
+	if !p.Settings.Anonymous {
+		lines, err := p.makeBallotLines(convert)
+		if err != nil {
+			return ""
+		}
+		if len(lines) > 0 {
+			s += "### " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: rankedBallotsHeading}) + "\n"
+			for _, line := range lines {
+				s += "- " + line + "\n"
+			}
+		}
+	}
+	return s
+}
+
+// makeRankedRoundLines returns a line per answer option still in the race and the eliminated options of a round.
+func (p *Poll) makeRankedRoundLines(bundle *utils.Bundle, round *RankedRound) []string {
+	localizer := bundle.GetServerLocalizer()
+	lines := []string{}
+	for i, o := range p.AnswerOptions {
+		count, ok := round.Counts[i]
+		if !ok {
+			continue
+		}
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: rankedRoundCount,
+			TemplateData: map[string]interface{}{
+				"Answer": o.Answer,
+				"Count":  count,
+			},
+			PluralCount: count,
+		}))
+	}
+
+	if len(round.Eliminated) > 0 {
+		lines = append(lines, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: rankedRoundEliminated,
+			TemplateData:   map[string]interface{}{"Answers": p.joinAnswers(round.Eliminated)},
+		}))
+	}
+	return lines
+}
+
+// makeRankedWinner returns a heading and the winning answer options of a ranked-choice poll.
+// If no ballot was submitted, the heading is empty.
+func (p *Poll) makeRankedWinner(bundle *utils.Bundle, result *RankedResult) (string, string) {
+	localizer := bundle.GetServerLocalizer()
+	if len(result.Winners) == 0 {
+		return "", bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: rankedNoBallots})
+	}
+
+	return p.makeWinner(bundle, p.GetWinners())
+}
+
+// makeWinner returns a heading and the given winning answer options.
+func (p *Poll) makeWinner(bundle *utils.Bundle, winners []int) (string, string) {
+	title := bundle.LocalizeWithConfig(bundle.GetServerLocalizer(), &i18n.LocalizeConfig{
+		DefaultMessage: pollWinnerHeading,
+		PluralCount:    len(winners),
+	})
+	return title, p.joinAnswers(winners)
+}
+
+// makeTieBreakNote returns a line describing how a tie of an ended poll gets broken, prefixed with a newline.
+// If the poll isn't tied, it returns an empty string.
+func (p *Poll) makeTieBreakNote(bundle *utils.Bundle) string {
+	var note *i18n.Message
+	switch {
+	case p.IsTieBroken():
+		note = pollEndPostTieBroken
+	case p.NeedsTieBreak():
+		note = pollEndPostTieBreakCreator
+	case p.NeedsRunoff():
+		note = pollEndPostTieBreakRunoff
+	default:
+		return ""
+	}
+	return "\n" + bundle.LocalizeWithConfig(bundle.GetServerLocalizer(), &i18n.LocalizeConfig{DefaultMessage: note})
+}
+
+// makeBallotLines returns a line per voter listing their ranking, e.g. "@user: Answer 2 > Answer 1".
+func (p *Poll) makeBallotLines(convert IDToNameConverter) ([]string, *model.AppError) {
+	lines := []string{}
+	for _, userID := range p.GetBallotVoters() {
+		displayName, err := convert(userID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", displayName, strings.Join(p.GetVotedAnswers(userID), " > ")))
+	}
+	return lines, nil
+}
+
+// joinAnswers returns the answers of the answer options with the given indexes as a comma separated list.
+func (p *Poll) joinAnswers(indexes []int) string {
+	answers := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		answers = append(answers, p.AnswerOptions[i].Answer)
+	}
+	return strings.Join(answers, ", ")
+}
+
+// makeFreeTextEndPollFields returns a field per response of a free-text poll.
+// The fields are titled with the name of the voter, unless the poll is anonymous.
+func (p *Poll) makeFreeTextEndPollFields(bundle *utils.Bundle, convert IDToNameConverter) ([]*model.MessageAttachmentField, *model.AppError) {
+	if len(p.Responses) == 0 {
+		return []*model.MessageAttachmentField{{
+			Value: bundle.LocalizeWithConfig(bundle.GetServerLocalizer(), &i18n.LocalizeConfig{DefaultMessage: freeTextNoResponses}),
+		}}, nil
+	}
+
+	fields := []*model.MessageAttachmentField{}
+	for _, r := range p.Responses {
+		var title string
+		if !p.Settings.Anonymous {
+			displayName, err := convert(r.UserID)
+			if err != nil {
+				return nil, err
+			}
+			title = displayName
+		}
+		fields = append(fields, &model.MessageAttachmentField{
+			Title: title,
+			Value: r.Text,
+		})
+	}
+	return fields, nil
+}
+
+// makeFreeTextCard returns the responses of a free-text poll for the rhs card.
+func (p *Poll) makeFreeTextCard(bundle *utils.Bundle, convert IDToNameConverter) string {
+	localizer := bundle.GetServerLocalizer()
+	s := "### " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: freeTextResponsesHeading}) + "\n"
+	for _, r := range p.Responses {
+		if p.Settings.Anonymous {
+			s += fmt.Sprintf("- %s\n", r.Text)
+			continue
+		}
+		displayName, err := convert(r.UserID)
+		if err != nil {
+			return ""
+		}
+		s += fmt.Sprintf("- %s: %s\n", displayName, r.Text)
+	}
+	return s
+}
+
+// makeSurveyText lists the questions of a survey with their settings and answer options.
+// If the progress setting is set, the number of votes of every answer option is shown.
+func (p *Poll) makeSurveyText() string {
+	lines := []string{}
+	for i, q := range p.Questions {
+		line := fmt.Sprintf("**%d. %s**", i+1, q.Question)
+		if settingsText := q.Settings.String(); settingsText != "" {
+			line += fmt.Sprintf(" _(%s)_", settingsText)
+		}
+
+		answers := make([]string, len(q.AnswerOptions))
+		for j, o := range q.AnswerOptions {
+			answers[j] = o.Answer
+			if p.Settings.Progress {
+				answers[j] = fmt.Sprintf("%s (%d)", o.Answer, len(o.Voter))
+			}
+		}
+		lines = append(lines, line, strings.Join(answers, " · "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// makeSurveyEndPollFields returns a field per question of a survey with the votes of every answer option.
+// Unless the question is anonymous, the voters are listed as well.
+func (p *Poll) makeSurveyEndPollFields(bundle *utils.Bundle, convert IDToNameConverter) ([]*model.MessageAttachmentField, *model.AppError) {
+	fields := []*model.MessageAttachmentField{}
+	for i, q := range p.Questions {
+		lines, err := q.makeAnswerLines(bundle, pollEndPostAnswerHeading, pollEndPostSeparator, convert)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &model.MessageAttachmentField{
+			Title: fmt.Sprintf("%d. %s", i+1, q.Question),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+	return fields, nil
+}
+
+// makeSurveyCard returns the questions of a survey for the rhs card.
+func (p *Poll) makeSurveyCard(bundle *utils.Bundle, convert IDToNameConverter) string {
+	s := ""
+	for i, q := range p.Questions {
+		lines, err := q.makeAnswerLines(bundle, rhsCardPollAnswerHeading, rhsCardPollVoterSeparator, convert)
+		if err != nil {
+			return ""
+		}
+		s += fmt.Sprintf("### %d. %s\n", i+1, q.Question) + strings.Join(lines, "\n") + "\n"
+	}
+	return s
+}
+
+// makeAnswerLines returns a list item per answer option of a survey question with its votes and, unless the question is anonymous, its voters.
+func (q *SurveyQuestion) makeAnswerLines(bundle *utils.Bundle, heading, separator *i18n.Message, convert IDToNameConverter) ([]string, *model.AppError) {
+	localizer := bundle.GetServerLocalizer()
+	lines := []string{}
+	for _, o := range q.AnswerOptions {
+		line := "- " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: heading,
+			TemplateData: map[string]interface{}{
+				"Answer": o.Answer,
+				"Count":  len(o.Voter),
+			},
+			PluralCount: len(o.Voter),
+		})
+		if !q.Settings.Anonymous && len(o.Voter) > 0 {
+			names := make([]string, len(o.Voter))
+			for i, v := range o.Voter {
+				displayName, err := convert(v)
+				if err != nil {
+					return nil, err
+				}
+				names[i] = displayName
+			}
+			voter := names[0]
+			if len(names) > 1 {
+				voter = strings.Join(names[:len(names)-1], ", ") + " " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: separator}) + " " + names[len(names)-1]
+			}
+			line += ": " + voter
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// ToMarkdown returns the leaderboard as a markdown table, ranking the users by their number of correct answers.
+// Users with the same score share a rank.
+func (l *Leaderboard) ToMarkdown(bundle *utils.Bundle, convert IDToNameConverter) (string, *model.AppError) {
+	localizer := bundle.GetServerLocalizer()
+	lines := []string{
+		"#### " + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: leaderboardHeading}),
+		bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: leaderboardTableHeader}),
+		"|---:|:---|---:|---:|",
+	}
+
+	rank := 0
+	var previous *Score
+	for i, userID := range l.Ranking() {
+		score := l.Scores[userID]
+		if previous == nil || *score != *previous {
+			rank = i + 1
+		}
+		previous = score
+
+		displayName, err := convert(userID)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, fmt.Sprintf("| %d | %s | %d | %d |", rank, displayName, score.Correct, score.Answered))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ToMarkdown returns a single line describing when a recurring poll is posted, e.g. for listing the recurring polls of a channel.
+func (r *RecurringPoll) ToMarkdown(bundle *utils.Bundle, localizer *i18n.Localizer) string {
+	message := &i18n.Message{
+		ID:    "recurringPoll.weekly",
+		Other: "**{{.Question}}** every {{.Weekday}} at {{.Time}} ({{.Timezone}}), next on {{.NextRun}}",
+	}
+	if r.Daily {
+		message = &i18n.Message{
+			ID:    "recurringPoll.daily",
+			Other: "**{{.Question}}** every day at {{.Time}} ({{.Timezone}}), next on {{.NextRun}}",
+		}
+	}
+
+	return fmt.Sprintf("`%s`: ", r.ID) + bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+		DefaultMessage: message,
+		TemplateData: map[string]interface{}{
+			"Question": r.Poll.Question,
+			"Weekday":  r.Weekday.String(),
+			"Time":     fmt.Sprintf("%02d:%02d", r.Hour, r.Minute),
+			"Timezone": r.Timezone,
+			"NextRun":  time.UnixMilli(r.NextRunAt).In(r.location()).Format(timeFormat),
+		},
+	})
+}
+
+// ToMarkdown returns the vote history as a markdown list, with the times in a given time zone.
+func (h VoteHistory) ToMarkdown(bundle *utils.Bundle, localizer *i18n.Localizer, loc *time.Location, convert IDToNameConverter) (string, *model.AppError) {
+	if len(h) == 0 {
+		return bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{DefaultMessage: voteHistoryEmpty}), nil
+	}
+
+	lines := []string{}
+	for _, e := range h {
+		displayName, err := convert(e.UserID)
+		if err != nil {
+			return "", err
+		}
+
+		message := voteHistoryChange
+		switch e.Type {
+		case VoteEventVote:
+			message = voteHistoryVote
+		case VoteEventReset:
+			message = voteHistoryReset
+		}
+		line := bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: message,
+			TemplateData: map[string]interface{}{
+				"Time":    time.UnixMilli(e.At).In(loc).Format(timeFormat),
+				"User":    displayName,
+				"Answers": strings.Join(e.Answers, ", "),
+			},
+		})
+		if e.ProxyID != "" {
+			proxyName, err := convert(e.ProxyID)
+			if err != nil {
+				return "", err
+			}
+			line = bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+				DefaultMessage: voteHistoryByProxy,
+				TemplateData:   map[string]interface{}{"Event": line, "Proxy": proxyName},
+			})
+		}
+		lines = append(lines, "- "+line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
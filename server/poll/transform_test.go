@@ -3,6 +3,7 @@ package poll_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,17 +41,20 @@ func TestPollToEndPollPost(t *testing.T) {
 				Title:      "Question",
 				Text:       "This poll has ended. The results are:",
 				Fields: []*model.MessageAttachmentField{{
-					Title: "Answer 1 (3 votes)",
+					Title: "🏆 Answer 1 (3 votes) · 75%",
 					Value: "@user1, @user2 and @user3",
 					Short: true,
 				}, {
-					Title: "Answer 2 (1 vote)",
+					Title: "Answer 2 (1 vote) · 25%",
 					Value: "@user4",
 					Short: true,
 				}, {
-					Title: "Answer 3 (0 votes)",
+					Title: "Answer 3 (0 votes) · 0%",
 					Value: "",
 					Short: true,
+				}, {
+					Title: "Winner",
+					Value: "Answer 1",
 				}},
 			}},
 		},
@@ -61,17 +65,20 @@ func TestPollToEndPollPost(t *testing.T) {
 				Title:      "Question",
 				Text:       "This poll has ended. The results are:",
 				Fields: []*model.MessageAttachmentField{{
-					Title: "Answer 1 (3 votes)",
+					Title: "🏆 Answer 1 (3 votes) · 75% of votes, 75% of voters",
 					Value: "",
 					Short: true,
 				}, {
-					Title: "Answer 2 (1 vote)",
+					Title: "Answer 2 (1 vote) · 25% of votes, 25% of voters",
 					Value: "",
 					Short: true,
 				}, {
-					Title: "Answer 3 (0 votes)",
+					Title: "Answer 3 (0 votes) · 0% of votes, 0% of voters",
 					Value: "",
 					Short: true,
+				}, {
+					Title: "Winner",
+					Value: "Answer 1",
 				}},
 			}},
 		},
@@ -82,13 +89,202 @@ func TestPollToEndPollPost(t *testing.T) {
 				Title:      "Question",
 				Text:       "This poll has ended. The results are:",
 				Fields: []*model.MessageAttachmentField{{
-					Title: "Answer 1 (3 votes)",
+					Title: "🏆 Answer 1 (3 votes) · 75% of votes, 75% of voters",
 					Value: "@user1, @user2 and @user3",
 					Short: true,
 				}, {
-					Title: "Answer 2 (1 vote)",
+					Title: "Answer 2 (1 vote) · 25% of votes, 25% of voters",
 					Value: "@user4",
 					Short: true,
+				}, {
+					Title: "Answer 3 (0 votes) · 0% of votes, 0% of voters",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Winner",
+					Value: "Answer 1",
+				}},
+			}},
+		},
+		"Points poll": {
+			Poll: testutils.GetPointsPollWithVotes(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "🏆 Answer 1 (4 points) · 67% of points, 100% of voters",
+					Value: "@user1 (3) and @user2 (1)",
+					Short: true,
+				}, {
+					Title: "Answer 2 (2 points) · 33% of points, 50% of voters",
+					Value: "@user1 (2)",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 points) · 0% of points, 0% of voters",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Winner",
+					Value: "Answer 1",
+				}},
+			}},
+		},
+		"Quiz": {
+			Poll: testutils.GetQuizPollWithVotes(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "✅ Answer 1 (2 votes) · 67%",
+					Value: "@user1 and @user2",
+					Short: true,
+				}, {
+					Title: "Answer 2 (1 vote) · 33%",
+					Value: "@user3",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 votes) · 0%",
+					Value: "",
+					Short: true,
+				}},
+			}},
+		},
+		"Tie": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotes()
+				p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+				p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "🏆 Answer 1 (2 votes) · 50%",
+					Value: "@user1 and @user2",
+					Short: true,
+				}, {
+					Title: "🏆 Answer 2 (2 votes) · 50%",
+					Value: "@user3 and @user4",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 votes) · 0%",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Tie",
+					Value: "Answer 1, Answer 2",
+				}},
+			}},
+		},
+		"Tie, creator picks the winner": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotes()
+				p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+				p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+				p.Settings.TieBreak = poll.TieBreakCreator
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "🏆 Answer 1 (2 votes) · 50%",
+					Value: "@user1 and @user2",
+					Short: true,
+				}, {
+					Title: "🏆 Answer 2 (2 votes) · 50%",
+					Value: "@user3 and @user4",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 votes) · 0%",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Tie",
+					Value: "Answer 1, Answer 2\nThe creator of the poll picks the winner.",
+				}},
+			}},
+		},
+		"Tie, broken by the creator": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotes()
+				p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+				p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+				p.Settings.TieBreak = poll.TieBreakCreator
+				p.TieBreakWinner = "Answer 2"
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "Answer 1 (2 votes) · 50%",
+					Value: "@user1 and @user2",
+					Short: true,
+				}, {
+					Title: "🏆 Answer 2 (2 votes) · 50%",
+					Value: "@user3 and @user4",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 votes) · 0%",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Winner",
+					Value: "Answer 2\nThe creator of the poll broke the tie.",
+				}},
+			}},
+		},
+		"Tie, runoff": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetPollWithVotes()
+				p.AnswerOptions[0].Voter = []string{"userID1", "userID2"}
+				p.AnswerOptions[1].Voter = []string{"userID3", "userID4"}
+				p.Settings.TieBreak = poll.TieBreakRunoff
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "🏆 Answer 1 (2 votes) · 50%",
+					Value: "@user1 and @user2",
+					Short: true,
+				}, {
+					Title: "🏆 Answer 2 (2 votes) · 50%",
+					Value: "@user3 and @user4",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 votes) · 0%",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Tie",
+					Value: "Answer 1, Answer 2\nA runoff poll between the tied options has been started.",
+				}},
+			}},
+		},
+		"No votes": {
+			Poll: testutils.GetPoll(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "Answer 1 (0 votes)",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Answer 2 (0 votes)",
+					Value: "",
+					Short: true,
 				}, {
 					Title: "Answer 3 (0 votes)",
 					Value: "",
@@ -96,12 +292,170 @@ func TestPollToEndPollPost(t *testing.T) {
 				}},
 			}},
 		},
+		"Scheduling poll": {
+			Poll: testutils.GetSchedulingPollWithVotes(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "Answer 2 (2 yes, 0 maybe, 0 no)",
+					Value: "Yes: @user1, @user2",
+					Short: true,
+				}, {
+					Title: "Answer 1 (1 yes, 0 maybe, 1 no)",
+					Value: "Yes: @user1\nNo: @user2",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 yes, 2 maybe, 0 no)",
+					Value: "Maybe: @user1, @user2",
+					Short: true,
+				}},
+			}},
+		},
+		"Free-text poll": {
+			Poll: testutils.GetFreeTextPollWithResponses(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "@user1",
+					Value: "First response",
+				}, {
+					Title: "@user2",
+					Value: "Second response",
+				}},
+			}},
+		},
+		"Free-text poll, anonymous": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetFreeTextPollWithResponses()
+				p.Settings.Anonymous = true
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Value: "First response",
+				}, {
+					Value: "Second response",
+				}},
+			}},
+		},
+		"Free-text poll without responses": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetFreeTextPollWithResponses()
+				p.Responses = nil
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Value: "No responses have been submitted.",
+				}},
+			}},
+		},
+		"Survey": {
+			Poll: testutils.GetSurveyWithAnswers(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Survey",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "1. Question 1",
+					Value: "- Answer 1 (2 votes): @user1 and @user2\n- Answer 2 (1 vote): @user3",
+				}, {
+					Title: "2. Question 2",
+					Value: "- Answer 1 (1 vote)\n- Answer 2 (1 vote)\n- Answer 3 (0 votes)",
+				}},
+			}},
+		},
+		"Scheduling poll, anonymous": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetSchedulingPollWithVotes()
+				p.Settings.Anonymous = true
+				return p
+			}(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "Answer 2 (2 yes, 0 maybe, 0 no)",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Answer 1 (1 yes, 0 maybe, 1 no)",
+					Value: "",
+					Short: true,
+				}, {
+					Title: "Answer 3 (0 yes, 2 maybe, 0 no)",
+					Value: "",
+					Short: true,
+				}},
+			}},
+		},
+		"Ranked poll": {
+			Poll: testutils.GetRankedPollWithBallots(),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "Round 1",
+					Value: "Answer 1: 2 votes\nAnswer 2: 1 vote\nAnswer 3: 1 vote\nEliminated: Answer 2, Answer 3",
+					Short: true,
+				}, {
+					Title: "Round 2",
+					Value: "Answer 1: 3 votes",
+					Short: true,
+				}, {
+					Title: "Winner",
+					Value: "Answer 1",
+				}, {
+					Title: "Ballots",
+					Value: "@user1: Answer 1 > Answer 2\n@user2: Answer 2 > Answer 1\n@user4: Answer 1 > Answer 3\n@user3: Answer 3 > Answer 2",
+				}},
+			}},
+		},
+		"Ranked poll, anonymous and without ballots": {
+			Poll: testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Ranked: true, Anonymous: true}),
+			ExpectedAttachments: []*model.MessageAttachment{{
+				AuthorName: "John Doe",
+				Title:      "Question",
+				Text:       "This poll has ended. The results are:",
+				Fields: []*model.MessageAttachmentField{{
+					Title: "Round 1",
+					Value: "Answer 1: 0 votes\nAnswer 2: 0 votes\nAnswer 3: 0 votes",
+					Short: true,
+				}, {
+					Title: "",
+					Value: "No ballots have been submitted.",
+				}},
+			}},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
+			// Every ended poll can be run again
+			test.ExpectedAttachments[0].Actions = []*model.PostAction{{
+				Id:    "rerunPoll",
+				Name:  "Run Again",
+				Type:  model.PostActionTypeButton,
+				Style: "primary",
+				Integration: &model.PostActionIntegration{
+					URL: fmt.Sprintf("/plugins/com.github.matterpoll.matterpoll/api/v1/polls/%s/rerun/request", test.Poll.ID),
+				},
+			}}
 			expectedPost := &model.Post{}
 			model.ParseMessageAttachment(expectedPost, test.ExpectedAttachments)
+			expectedPost.AddProp("poll_id", test.Poll.ID)
 
-			post, err := test.Poll.ToEndPollPost(testutils.GetBundle(), "John Doe", converter)
+			post, err := test.Poll.ToEndPollPost(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe", converter)
 
 			require.Nil(t, err)
 			assert.Equal(t, expectedPost, post)
@@ -114,7 +468,7 @@ func TestPollToEndPollPost(t *testing.T) {
 		}
 		poll := testutils.GetPollWithVotes()
 
-		post, err := poll.ToEndPollPost(testutils.GetBundle(), "John Doe", converter)
+		post, err := poll.ToEndPollPost(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe", converter)
 
 		assert.NotNil(t, err)
 		require.Nil(t, post)
@@ -183,6 +537,38 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "viewHistory",
+					Name:  "View History",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/history/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -249,6 +635,38 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "viewHistory",
+					Name:  "View History",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/history/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -315,6 +733,30 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -381,6 +823,30 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -447,6 +913,38 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "viewHistory",
+					Name:  "View History",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/history/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -513,6 +1011,38 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "viewHistory",
+					Name:  "View History",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/history/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -579,6 +1109,38 @@ func TestPollToPostActions(t *testing.T) {
 					Integration: &model.PostActionIntegration{
 						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/add/request", PluginID, currentAPIVersion, testutils.GetPollID()),
 					},
+				}, {
+					Id:    "manageOptions",
+					Name:  "Manage Options",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/option/manage/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "editPoll",
+					Name:  "Edit Poll",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/edit/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "viewHistory",
+					Name:  "View History",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/history/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
+				}, {
+					Id:    "rerunPoll",
+					Name:  "Run Again",
+					Type:  model.PostActionTypeButton,
+					Style: "primary",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("/plugins/%s/api/%s/polls/%s/rerun/request", PluginID, currentAPIVersion, testutils.GetPollID()),
+					},
 				}, {
 					Id:    "endPoll",
 					Name:  "End Poll",
@@ -662,9 +1224,338 @@ func TestToCard(t *testing.T) {
 			Poll:             testutils.GetPollWithVoteUnknownUser(),
 			ExpectedMarkdown: "",
 		},
+		"Points poll": {
+			Poll: testutils.GetPointsPollWithVotes(),
+			ExpectedMarkdown: "# Question\n" +
+				"Created by @user1\n" +
+				"### Answer 1 (4 points)" +
+				"\n@user1 (3) and @user2 (1)\n" +
+				"### Answer 2 (2 points)" +
+				"\n@user1 (2)\n" +
+				"### Answer 3 (0 points)" +
+				"\n\n",
+		},
+		"Scheduling poll": {
+			Poll: testutils.GetSchedulingPollWithVotes(),
+			ExpectedMarkdown: "# Question\n" +
+				"Created by @user1\n" +
+				"### Answer 2 (2 yes, 0 maybe, 0 no)\n" +
+				"Yes: @user1, @user2\n" +
+				"### Answer 1 (1 yes, 0 maybe, 1 no)\n" +
+				"Yes: @user1\n" +
+				"No: @user2\n" +
+				"### Answer 3 (0 yes, 2 maybe, 0 no)\n" +
+				"Maybe: @user1, @user2\n",
+		},
+		"Free-text poll": {
+			Poll: testutils.GetFreeTextPollWithResponses(),
+			ExpectedMarkdown: "# Question\n" +
+				"Created by @user1\n" +
+				"### Responses\n" +
+				"- @user1: First response\n" +
+				"- @user2: Second response\n",
+		},
+		"Survey": {
+			Poll: testutils.GetSurveyWithAnswers(),
+			ExpectedMarkdown: "# Survey\n" +
+				"Created by @user1\n" +
+				"### 1. Question 1\n" +
+				"- Answer 1 (2 votes): @user1 and @user2\n" +
+				"- Answer 2 (1 vote): @user3\n" +
+				"### 2. Question 2\n" +
+				"- Answer 1 (1 vote)\n" +
+				"- Answer 2 (1 vote)\n" +
+				"- Answer 3 (0 votes)\n",
+		},
+		"Free-text poll, anonymous": {
+			Poll: func() *poll.Poll {
+				p := testutils.GetFreeTextPollWithResponses()
+				p.Settings.Anonymous = true
+				return p
+			}(),
+			ExpectedMarkdown: "# Question\n" +
+				"Created by @user1\n" +
+				"### Responses\n" +
+				"- First response\n" +
+				"- Second response\n",
+		},
+		"Ranked poll": {
+			Poll: testutils.GetRankedPollWithBallots(),
+			ExpectedMarkdown: "# Question\n" +
+				"Created by @user1\n" +
+				"### Round 1\n" +
+				"- Answer 1: 2 votes\n" +
+				"- Answer 2: 1 vote\n" +
+				"- Answer 3: 1 vote\n" +
+				"- Eliminated: Answer 2, Answer 3\n" +
+				"### Round 2\n" +
+				"- Answer 1: 3 votes\n" +
+				"### Winner\n" +
+				"Answer 1\n" +
+				"### Ballots\n" +
+				"- @user1: Answer 1 > Answer 2\n" +
+				"- @user2: Answer 2 > Answer 1\n" +
+				"- @user4: Answer 1 > Answer 3\n" +
+				"- @user3: Answer 3 > Answer 2\n",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert.Equal(t, test.ExpectedMarkdown, test.Poll.ToCard(testutils.GetBundle(), converter))
 		})
 	}
 }
+
+func TestPollToPostActionsWithEndTime(t *testing.T) {
+	p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, EndAt: 1893596645000})
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll ends at**: Wed, 02 Jan 2030 15:04 UTC\n**Total votes**: 0", attachments[0].Text)
+}
+
+func TestPollToPostActionsWithVoters(t *testing.T) {
+	p := testutils.GetPollWithSettings(poll.Settings{MaxVotes: 1, Voters: &poll.Voters{
+		Names:    []string{"user1", "developers"},
+		Roles:    []string{"team_admin", "channel_admin"},
+		Channels: []string{"town-square"},
+	}})
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Eligible voters**: @user1, @developers, team admins, channel admins, members of ~town-square\n**Total votes**: 0", attachments[0].Text)
+}
+
+func TestPollToPostActionsWithIgnoredVoters(t *testing.T) {
+	t.Run("multi vote", func(t *testing.T) {
+		p := testutils.GetPollWithVotesAndSettings(poll.Settings{ExcludeGuests: true, MaxVotes: 0})
+		p.IgnoreVoters([]string{"userID1"})
+
+		attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "---\n**Poll Settings**: exclude-guests, votes=unlimited\n**Total votes**: 3 (3 voters)", attachments[0].Text)
+	})
+
+	t.Run("points", func(t *testing.T) {
+		p := testutils.GetPointsPollWithVotes()
+		p.IgnoreVoters([]string{"userID1"})
+
+		attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "---\n**Poll Settings**: points=5\n**Total points**: 1 (1 voter)", attachments[0].Text)
+	})
+}
+
+func TestPollToPostActionsRanked(t *testing.T) {
+	p := testutils.GetRankedPollWithBallots()
+	p.Settings.Progress = true
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll Settings**: progress, ranked\n**Total votes**: 4", attachments[0].Text)
+	assert.Equal(t, "Answer 1 (2)", attachments[0].Actions[0].Name)
+	assert.Equal(t, "Answer 2 (1)", attachments[0].Actions[1].Name)
+	assert.Equal(t, "Answer 3 (1)", attachments[0].Actions[2].Name)
+}
+
+func TestPollToPostActionsPoints(t *testing.T) {
+	p := testutils.GetPointsPollWithVotes()
+	p.Settings.Progress = true
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll Settings**: progress, points=5\n**Total points**: 6 (2 voters)", attachments[0].Text)
+	assert.Equal(t, "Answer 1 (4)", attachments[0].Actions[0].Name)
+	assert.Equal(t, "Answer 2 (2)", attachments[0].Actions[1].Name)
+	assert.Equal(t, "Answer 3 (0)", attachments[0].Actions[2].Name)
+	assert.Equal(t, "editPoints", attachments[0].Actions[4].Id)
+	assert.Equal(t, "/plugins/com.github.matterpoll.matterpoll/api/v1/polls/1234567890abcdefghij/points/request", attachments[0].Actions[4].Integration.URL)
+}
+
+func TestPollToPostActionsScheduling(t *testing.T) {
+	p := testutils.GetSchedulingPollWithVotes()
+	p.Settings.Progress = true
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll Settings**: progress, scheduling\n**Total votes**: 2", attachments[0].Text)
+	assert.Equal(t, "Answer 1 (1)", attachments[0].Actions[0].Name)
+	assert.Equal(t, "Answer 2 (2)", attachments[0].Actions[1].Name)
+	assert.Equal(t, "Answer 3 (0)", attachments[0].Actions[2].Name)
+}
+
+func TestPollToPostActionsFreeText(t *testing.T) {
+	p := testutils.GetFreeTextPollWithResponses()
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll Settings**: free-text\n**Total responses**: 2", attachments[0].Text)
+	require.Len(t, attachments[0].Actions, 7)
+	assert.Equal(t, "respond", attachments[0].Actions[0].Id)
+	assert.Equal(t, "Submit Your Answer", attachments[0].Actions[0].Name)
+	assert.Equal(t, "/plugins/com.github.matterpoll.matterpoll/api/v1/polls/1234567890abcdefghij/response/request", attachments[0].Actions[0].Integration.URL)
+	assert.Equal(t, "resetVote", attachments[0].Actions[1].Id)
+}
+
+func TestPollToPostActionsSurvey(t *testing.T) {
+	p := testutils.GetSurveyWithAnswers()
+	p.Settings.Progress = true
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "Survey", attachments[0].Title)
+	assert.Equal(t, "**1. Question 1**\n"+
+		"Answer 1 (2) · Answer 2 (1)\n"+
+		"**2. Question 2** _(anonymous, votes=2)_\n"+
+		"Answer 1 (1) · Answer 2 (1) · Answer 3 (0)\n"+
+		"---\n**Poll Settings**: progress\n**Total respondents**: 3", attachments[0].Text)
+	require.Len(t, attachments[0].Actions, 6)
+	assert.Equal(t, "answerSurvey", attachments[0].Actions[0].Id)
+	assert.Equal(t, "Answer Survey", attachments[0].Actions[0].Name)
+	assert.Equal(t, "/plugins/com.github.matterpoll.matterpoll/api/v1/polls/1234567890abcdefghij/survey/request", attachments[0].Actions[0].Integration.URL)
+	assert.Equal(t, "editPoll", attachments[0].Actions[1].Id)
+	assert.Equal(t, "viewHistory", attachments[0].Actions[2].Id)
+	assert.Equal(t, "rerunPoll", attachments[0].Actions[3].Id)
+	assert.Equal(t, "endPoll", attachments[0].Actions[4].Id)
+	assert.Equal(t, "deletePoll", attachments[0].Actions[5].Id)
+}
+
+func TestPollToPostActionsQuiz(t *testing.T) {
+	p := testutils.GetQuizPollWithVotes()
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll Settings**: quiz\n**Total votes**: 3", attachments[0].Text)
+	require.Len(t, attachments[0].Actions, 10)
+	for _, action := range attachments[0].Actions {
+		assert.NotEqual(t, "resetVote", action.Id)
+	}
+}
+
+func TestPollToPostActionsFinal(t *testing.T) {
+	p := testutils.GetPollWithVotesAndSettings(poll.Settings{Final: true, MaxVotes: 1})
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Poll Settings**: final\n**Total votes**: 4", attachments[0].Text)
+	require.Len(t, attachments[0].Actions, 10)
+	for _, action := range attachments[0].Actions {
+		assert.NotEqual(t, "resetVote", action.Id)
+	}
+}
+
+func TestPollToPostActionsEdited(t *testing.T) {
+	p := testutils.GetPollWithVotes()
+	p.EditedAt = 1577880000000
+
+	attachments := p.ToPostActions(testutils.GetBundle(), "com.github.matterpoll.matterpoll", "John Doe")
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "---\n**Edited**: Wed, 01 Jan 2020 12:00 UTC\n**Total votes**: 4", attachments[0].Text)
+}
+
+func TestLeaderboardToMarkdown(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		switch userID {
+		case "userID1":
+			return "@user1", nil
+		case "userID2":
+			return "@user2", nil
+		case "userID3":
+			return "@user3", nil
+		default:
+			return "", &model.AppError{}
+		}
+	}
+
+	t.Run("all fine", func(t *testing.T) {
+		l := &poll.Leaderboard{Scores: map[string]*poll.Score{
+			"userID1": {Correct: 2, Answered: 3},
+			"userID2": {Correct: 3, Answered: 3},
+			"userID3": {Correct: 2, Answered: 3},
+		}}
+
+		markdown, err := l.ToMarkdown(testutils.GetBundle(), converter)
+		require.Nil(t, err)
+		assert.Equal(t, "#### Quiz Leaderboard\n"+
+			"| Rank | User | Correct answers | Answered quizzes |\n"+
+			"|---:|:---|---:|---:|\n"+
+			"| 1 | @user2 | 3 | 3 |\n"+
+			"| 2 | @user1 | 2 | 3 |\n"+
+			"| 2 | @user3 | 2 | 3 |", markdown)
+	})
+	t.Run("converter fails", func(t *testing.T) {
+		l := &poll.Leaderboard{Scores: map[string]*poll.Score{
+			"userID4": {Correct: 2, Answered: 3},
+		}}
+
+		markdown, err := l.ToMarkdown(testutils.GetBundle(), converter)
+		assert.NotNil(t, err)
+		assert.Equal(t, "", markdown)
+	})
+}
+
+func TestRecurringPollToMarkdown(t *testing.T) {
+	r := &poll.RecurringPoll{
+		ID:        "recurringPollID",
+		Poll:      testutils.GetPoll(),
+		Weekday:   time.Friday,
+		Hour:      10,
+		Minute:    5,
+		Timezone:  "Europe/Berlin",
+		NextRunAt: time.Date(2024, 12, 27, 9, 5, 0, 0, time.UTC).UnixMilli(),
+	}
+	bundle := testutils.GetBundle()
+	localizer := bundle.GetServerLocalizer()
+
+	assert.Equal(t, "`recurringPollID`: **Question** every Friday at 10:05 (Europe/Berlin), next on Fri, 27 Dec 2024 10:05 CET", r.ToMarkdown(bundle, localizer))
+
+	r.Daily = true
+	assert.Equal(t, "`recurringPollID`: **Question** every day at 10:05 (Europe/Berlin), next on Fri, 27 Dec 2024 10:05 CET", r.ToMarkdown(bundle, localizer))
+}
+
+func TestVoteHistoryToMarkdown(t *testing.T) {
+	converter := func(userID string) (string, *model.AppError) {
+		return "@" + userID, nil
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("all fine", func(t *testing.T) {
+		history := poll.VoteHistory{
+			{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1", "Answer 2"}, At: testutils.GetMillis()},
+			{Type: poll.VoteEventChange, UserID: "userID1", Answers: []string{"Answer 3"}, At: testutils.GetMillis() + 60*1000},
+			{Type: poll.VoteEventReset, UserID: "userID1", At: testutils.GetMillis() + 2*60*1000},
+		}
+
+		message, appErr := history.ToMarkdown(testutils.GetBundle(), testutils.GetLocalizer(), berlin, converter)
+		require.Nil(t, appErr)
+		assert.Equal(t, "- Thu, 15 Jan 1970 07:56 CET: **@userID1** voted for Answer 1, Answer 2\n"+
+			"- Thu, 15 Jan 1970 07:57 CET: **@userID1** changed their vote to Answer 3\n"+
+			"- Thu, 15 Jan 1970 07:58 CET: **@userID1** reset their vote", message)
+	})
+	t.Run("proxy votes", func(t *testing.T) {
+		history := poll.VoteHistory{
+			{Type: poll.VoteEventVote, UserID: "userID2", Answers: []string{"Answer 1"}, At: testutils.GetMillis(), ProxyID: "userID1"},
+			{Type: poll.VoteEventChange, UserID: "userID2", Answers: []string{"Answer 2"}, At: testutils.GetMillis() + 60*1000},
+		}
+
+		message, appErr := history.ToMarkdown(testutils.GetBundle(), testutils.GetLocalizer(), berlin, converter)
+		require.Nil(t, appErr)
+		assert.Equal(t, "- Thu, 15 Jan 1970 07:56 CET: **@userID2** voted for Answer 1, recorded by **@userID1**\n"+
+			"- Thu, 15 Jan 1970 07:57 CET: **@userID2** changed their vote to Answer 2", message)
+	})
+	t.Run("empty history", func(t *testing.T) {
+		message, appErr := poll.VoteHistory{}.ToMarkdown(testutils.GetBundle(), testutils.GetLocalizer(), time.UTC, converter)
+		require.Nil(t, appErr)
+		assert.Equal(t, "Nobody has voted yet.", message)
+	})
+	t.Run("converter fails", func(t *testing.T) {
+		history := poll.VoteHistory{{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1"}, At: testutils.GetMillis()}}
+		failingConverter := func(userID string) (string, *model.AppError) {
+			return "", &model.AppError{}
+		}
+
+		message, appErr := history.ToMarkdown(testutils.GetBundle(), testutils.GetLocalizer(), time.UTC, failingConverter)
+		assert.NotNil(t, appErr)
+		assert.Equal(t, "", message)
+	})
+}
@@ -0,0 +1,124 @@
+package poll
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/matterpoll/matterpoll/server/utils"
+)
+
+// Voters restricts who is allowed to vote in a poll. Users are eligible if they match any of the entries.
+type Voters struct {
+	// Names are the usernames of eligible users and the names of Mattermost groups, whose members are eligible.
+	Names []string `json:"names,omitempty"`
+	// Roles are the team and channel roles, whose holders are eligible, i.e. team_admin or channel_admin.
+	Roles []string `json:"roles,omitempty"`
+	// Channels are the names of channels in the team of the poll, whose members are eligible.
+	Channels []string `json:"channels,omitempty"`
+}
+
+var (
+	pollMessageVoters             = &i18n.Message{ID: "poll.message.voters", Other: "**Eligible voters**: {{.Voters}}"}
+	pollMessageVotersTeamAdmin    = &i18n.Message{ID: "poll.message.voters.teamAdmin", Other: "team admins"}
+	pollMessageVotersChannelAdmin = &i18n.Message{ID: "poll.message.voters.channelAdmin", Other: "channel admins"}
+	pollMessageVotersChannel      = &i18n.Message{ID: "poll.message.voters.channel", Other: "members of ~{{.Channel}}"}
+)
+
+// parseVoters parses a list of eligible voters like "@alice, @developers, channel_admin, ~town-square".
+// Entries are separated by commas or spaces.
+func parseVoters(s string) (*Voters, *utils.ErrorMessage) {
+	entries := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(entries) == 0 {
+		return nil, newInvalidVoterError(s)
+	}
+
+	voters := &Voters{}
+	for _, entry := range entries {
+		switch {
+		case strings.HasPrefix(entry, "@") && len(entry) > 1:
+			voters.Names = append(voters.Names, strings.ToLower(entry[1:]))
+		case strings.HasPrefix(entry, "~") && len(entry) > 1:
+			voters.Channels = append(voters.Channels, strings.ToLower(entry[1:]))
+		case entry == model.TeamAdminRoleId || entry == model.ChannelAdminRoleId:
+			voters.Roles = append(voters.Roles, entry)
+		default:
+			return nil, newInvalidVoterError(entry)
+		}
+	}
+	return voters, nil
+}
+
+func newInvalidVoterError(s string) *utils.ErrorMessage {
+	return &utils.ErrorMessage{
+		Message: &i18n.Message{
+			ID:    "poll.newPoll.votersSetting.invalid",
+			Other: `Invalid voter "{{.Voter}}". Use "@username", "@group", "~channel", "team_admin" or "channel_admin".`,
+		},
+		Data: map[string]interface{}{
+			"Voter": s,
+		},
+	}
+}
+
+// HasRole returns true if the holders of a given team or channel role are eligible.
+func (v *Voters) HasRole(role string) bool {
+	return slices.Contains(v.Roles, role)
+}
+
+// copy deep copies the eligible voters. It returns nil if no voters are set.
+func (v *Voters) copy() *Voters {
+	if v == nil {
+		return nil
+	}
+	return &Voters{
+		Names:    slices.Clone(v.Names),
+		Roles:    slices.Clone(v.Roles),
+		Channels: slices.Clone(v.Channels),
+	}
+}
+
+// makeVotersText describes who is allowed to vote in the poll.
+func (p *Poll) makeVotersText(bundle *utils.Bundle, localizer *i18n.Localizer) string {
+	var entries []string
+	for _, name := range p.Settings.Voters.Names {
+		entries = append(entries, "@"+name)
+	}
+	for _, role := range p.Settings.Voters.Roles {
+		switch role {
+		case model.TeamAdminRoleId:
+			entries = append(entries, bundle.LocalizeDefaultMessage(localizer, pollMessageVotersTeamAdmin))
+		case model.ChannelAdminRoleId:
+			entries = append(entries, bundle.LocalizeDefaultMessage(localizer, pollMessageVotersChannelAdmin))
+		}
+	}
+	for _, channel := range p.Settings.Voters.Channels {
+		entries = append(entries, bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+			DefaultMessage: pollMessageVotersChannel,
+			TemplateData:   map[string]interface{}{"Channel": channel},
+		}))
+	}
+
+	return bundle.LocalizeWithConfig(localizer, &i18n.LocalizeConfig{
+		DefaultMessage: pollMessageVoters,
+		TemplateData:   map[string]interface{}{"Voters": strings.Join(entries, ", ")},
+	})
+}
+
+// IsRestricted returns true if only some users are allowed to vote in the poll.
+func (p *Poll) IsRestricted() bool {
+	return p.Settings.Voters != nil
+}
+
+// IgnoreVoters leaves the votes of the given users out of the participation counts of the poll post,
+// e.g. because they are guests and guests are excluded from the poll. Secret ballots can't be attributed to users and are always counted.
+func (p *Poll) IgnoreVoters(userIDs []string) {
+	p.ignoredVoters = make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		p.ignoredVoters[userID] = true
+	}
+}
@@ -0,0 +1,87 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+)
+
+// VoteHistoryStore allows to access the vote histories of polls in the KV Store.
+type VoteHistoryStore struct {
+	api plugin.API
+}
+
+const (
+	voteHistoryPrefix = "vote_history_"
+	// appendRetries is the number of times Append retries, if the history was changed concurrently.
+	appendRetries = 5
+	// maxVoteHistoryEvents is the number of events a vote history keeps. Older events are dropped,
+	// so that the history, which is rewritten with every event, doesn't grow without bounds.
+	maxVoteHistoryEvents = 1000
+)
+
+// Get returns the vote history of a poll. Returns an empty history if no votes were recorded yet.
+func (s *VoteHistoryStore) Get(pollID string) (poll.VoteHistory, error) {
+	b, appErr := s.api.KVGet(voteHistoryPrefix + pollID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	history := poll.VoteHistory{}
+	if b == nil {
+		return history, nil
+	}
+	if err := json.Unmarshal(b, &history); err != nil {
+		return nil, errors.New("failed to decode vote history")
+	}
+	return history, nil
+}
+
+// Append adds a vote event to the end of the vote history of a poll. If the history is full, the oldest event is dropped.
+func (s *VoteHistoryStore) Append(pollID string, event *poll.VoteEvent) error {
+	for i := 0; i < appendRetries; i++ {
+		old, appErr := s.api.KVGet(voteHistoryPrefix + pollID)
+		if appErr != nil {
+			return appErr
+		}
+
+		history := poll.VoteHistory{}
+		if old != nil {
+			if err := json.Unmarshal(old, &history); err != nil {
+				return errors.New("failed to decode vote history")
+			}
+		}
+		history = append(history, event)
+		if len(history) > maxVoteHistoryEvents {
+			history = history[len(history)-maxVoteHistoryEvents:]
+		}
+		b, _ := json.Marshal(history)
+
+		opt := model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: old,
+		}
+		ok, appErr := s.api.KVSetWithOptions(voteHistoryPrefix+pollID, b, opt)
+		if appErr != nil {
+			return appErr
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return errors.New("vote history was changed concurrently")
+}
+
+// Delete deletes the vote history of a poll from the KV Store.
+func (s *VoteHistoryStore) Delete(pollID string) error {
+	if err := s.api.KVDelete(voteHistoryPrefix + pollID); err != nil {
+		return err
+	}
+
+	return nil
+}
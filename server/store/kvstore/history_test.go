@@ -0,0 +1,174 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func encodeVoteHistory(history poll.VoteHistory) []byte {
+	b, _ := json.Marshal(history)
+	return b
+}
+
+func TestVoteHistoryStoreGet(t *testing.T) {
+	history := poll.VoteHistory{
+		{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1"}, At: testutils.GetMillis()},
+		{Type: poll.VoteEventReset, UserID: "userID1", At: testutils.GetMillis() + 1},
+	}
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", voteHistoryPrefix+testutils.GetPollID()).Return(encodeVoteHistory(history), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		h, err := store.VoteHistory().Get(testutils.GetPollID())
+		require.NoError(t, err)
+		assert.Equal(t, history, h)
+	})
+	t.Run("no history", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", voteHistoryPrefix+testutils.GetPollID()).Return(nil, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		h, err := store.VoteHistory().Get(testutils.GetPollID())
+		require.NoError(t, err)
+		assert.Equal(t, poll.VoteHistory{}, h)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", voteHistoryPrefix+testutils.GetPollID()).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		h, err := store.VoteHistory().Get(testutils.GetPollID())
+		assert.Error(t, err)
+		assert.Nil(t, h)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", voteHistoryPrefix+testutils.GetPollID()).Return([]byte("foo"), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		h, err := store.VoteHistory().Get(testutils.GetPollID())
+		assert.Error(t, err)
+		assert.Nil(t, h)
+	})
+}
+
+func TestVoteHistoryStoreAppend(t *testing.T) {
+	e1 := &poll.VoteEvent{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1"}, At: testutils.GetMillis()}
+	e2 := &poll.VoteEvent{Type: poll.VoteEventChange, UserID: "userID1", Answers: []string{"Answer 2"}, At: testutils.GetMillis() + 1}
+	key := voteHistoryPrefix + testutils.GetPollID()
+
+	t.Run("first event", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+		api.On("KVSetWithOptions", key, encodeVoteHistory(poll.VoteHistory{e1}), model.PluginKVSetOptions{Atomic: true}).Return(true, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e1)
+		assert.NoError(t, err)
+	})
+	t.Run("existing history", func(t *testing.T) {
+		old := encodeVoteHistory(poll.VoteHistory{e1})
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(old, nil)
+		api.On("KVSetWithOptions", key, encodeVoteHistory(poll.VoteHistory{e1, e2}), model.PluginKVSetOptions{Atomic: true, OldValue: old}).Return(true, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e2)
+		assert.NoError(t, err)
+	})
+	t.Run("full history drops the oldest event", func(t *testing.T) {
+		full := make(poll.VoteHistory, maxVoteHistoryEvents)
+		for i := range full {
+			full[i] = &poll.VoteEvent{Type: poll.VoteEventVote, UserID: "userID1", Answers: []string{"Answer 1"}, At: int64(i)}
+		}
+		old := encodeVoteHistory(full)
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(old, nil)
+		api.On("KVSetWithOptions", key, encodeVoteHistory(append(full[1:], e2)), model.PluginKVSetOptions{Atomic: true, OldValue: old}).Return(true, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e2)
+		assert.NoError(t, err)
+	})
+	t.Run("history changed concurrently", func(t *testing.T) {
+		old := encodeVoteHistory(poll.VoteHistory{e1})
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil).Once()
+		api.On("KVSetWithOptions", key, encodeVoteHistory(poll.VoteHistory{e2}), model.PluginKVSetOptions{Atomic: true}).Return(false, nil).Once()
+		api.On("KVGet", key).Return(old, nil).Once()
+		api.On("KVSetWithOptions", key, encodeVoteHistory(poll.VoteHistory{e1, e2}), model.PluginKVSetOptions{Atomic: true, OldValue: old}).Return(true, nil).Once()
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e2)
+		assert.NoError(t, err)
+	})
+	t.Run("history keeps changing", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil).Times(appendRetries)
+		api.On("KVSetWithOptions", key, encodeVoteHistory(poll.VoteHistory{e1}), model.PluginKVSetOptions{Atomic: true}).Return(false, nil).Times(appendRetries)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e1)
+		assert.Error(t, err)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e1)
+		assert.Error(t, err)
+	})
+	t.Run("KVSetWithOptions() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+		api.On("KVSetWithOptions", key, encodeVoteHistory(poll.VoteHistory{e1}), model.PluginKVSetOptions{Atomic: true}).Return(false, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Append(testutils.GetPollID(), e1)
+		assert.Error(t, err)
+	})
+}
+
+func TestVoteHistoryStoreDelete(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", voteHistoryPrefix+testutils.GetPollID()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Delete(testutils.GetPollID())
+		assert.NoError(t, err)
+	})
+	t.Run("KVDelete() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", voteHistoryPrefix+testutils.GetPollID()).Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.VoteHistory().Delete(testutils.GetPollID())
+		assert.Error(t, err)
+	})
+}
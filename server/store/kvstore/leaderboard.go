@@ -0,0 +1,71 @@
+package kvstore
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+)
+
+// LeaderboardStore allows to access the quiz leaderboards of channels in the KV Store.
+type LeaderboardStore struct {
+	api plugin.API
+}
+
+const (
+	leaderboardPrefix = "leaderboard_"
+	// addQuizResultsRetries is the number of times AddQuizResults retries, if the leaderboard was changed concurrently.
+	addQuizResultsRetries = 5
+)
+
+// Get returns the leaderboard of a given channel. Returns an empty leaderboard if no quiz was scored in the channel yet.
+func (s *LeaderboardStore) Get(channelID string) (*poll.Leaderboard, error) {
+	b, err := s.api.KVGet(leaderboardPrefix + channelID)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return poll.NewLeaderboard(), nil
+	}
+
+	leaderboard := poll.DecodeLeaderboardFromByte(b)
+	if leaderboard == nil {
+		return nil, errors.New("failed to decode leaderboard")
+	}
+
+	return leaderboard, nil
+}
+
+// AddQuizResults scores the answers of a given quiz on the leaderboard of a given channel.
+func (s *LeaderboardStore) AddQuizResults(channelID string, p *poll.Poll) error {
+	for i := 0; i < addQuizResultsRetries; i++ {
+		old, appErr := s.api.KVGet(leaderboardPrefix + channelID)
+		if appErr != nil {
+			return appErr
+		}
+
+		leaderboard := poll.NewLeaderboard()
+		if old != nil {
+			if leaderboard = poll.DecodeLeaderboardFromByte(old); leaderboard == nil {
+				return errors.New("failed to decode leaderboard")
+			}
+		}
+		leaderboard.AddQuizResults(p)
+
+		opt := model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: old,
+		}
+		ok, appErr := s.api.KVSetWithOptions(leaderboardPrefix+channelID, leaderboard.EncodeToByte(), opt)
+		if appErr != nil {
+			return appErr
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return errors.New("leaderboard was changed concurrently")
+}
@@ -0,0 +1,134 @@
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func TestLeaderboardStoreGet(t *testing.T) {
+	leaderboard := poll.NewLeaderboard()
+	leaderboard.AddQuizResults(testutils.GetQuizPollWithVotes())
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", leaderboardPrefix+"channelID1").Return(leaderboard.EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rleaderboard, err := store.Leaderboard().Get("channelID1")
+		require.NoError(t, err)
+		assert.Equal(t, leaderboard, rleaderboard)
+	})
+	t.Run("no leaderboard yet", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", leaderboardPrefix+"channelID1").Return(nil, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rleaderboard, err := store.Leaderboard().Get("channelID1")
+		require.NoError(t, err)
+		assert.Equal(t, poll.NewLeaderboard(), rleaderboard)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", leaderboardPrefix+"channelID1").Return([]byte{}, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rleaderboard, err := store.Leaderboard().Get("channelID1")
+		assert.Error(t, err)
+		assert.Nil(t, rleaderboard)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", leaderboardPrefix+"channelID1").Return([]byte{}, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rleaderboard, err := store.Leaderboard().Get("channelID1")
+		assert.Error(t, err)
+		assert.Nil(t, rleaderboard)
+	})
+}
+
+func TestLeaderboardStoreAddQuizResults(t *testing.T) {
+	quiz := testutils.GetQuizPollWithVotes()
+	leaderboard := poll.NewLeaderboard()
+	leaderboard.AddQuizResults(quiz)
+	twiceScored := poll.NewLeaderboard()
+	twiceScored.AddQuizResults(quiz)
+	twiceScored.AddQuizResults(quiz)
+	key := leaderboardPrefix + "channelID1"
+
+	t.Run("first quiz", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+		api.On("KVSetWithOptions", key, leaderboard.EncodeToByte(), model.PluginKVSetOptions{Atomic: true}).Return(true, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Leaderboard().AddQuizResults("channelID1", quiz)
+		require.NoError(t, err)
+	})
+	t.Run("existing leaderboard", func(t *testing.T) {
+		old := leaderboard.EncodeToByte()
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(old, nil)
+		api.On("KVSetWithOptions", key, twiceScored.EncodeToByte(), model.PluginKVSetOptions{Atomic: true, OldValue: old}).Return(true, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Leaderboard().AddQuizResults("channelID1", quiz)
+		require.NoError(t, err)
+	})
+	t.Run("leaderboard changed concurrently", func(t *testing.T) {
+		old := leaderboard.EncodeToByte()
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil).Once()
+		api.On("KVSetWithOptions", key, leaderboard.EncodeToByte(), model.PluginKVSetOptions{Atomic: true}).Return(false, nil).Once()
+		api.On("KVGet", key).Return(old, nil).Once()
+		api.On("KVSetWithOptions", key, twiceScored.EncodeToByte(), model.PluginKVSetOptions{Atomic: true, OldValue: old}).Return(true, nil).Once()
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Leaderboard().AddQuizResults("channelID1", quiz)
+		require.NoError(t, err)
+	})
+	t.Run("leaderboard keeps changing", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil).Times(addQuizResultsRetries)
+		api.On("KVSetWithOptions", key, leaderboard.EncodeToByte(), model.PluginKVSetOptions{Atomic: true}).Return(false, nil).Times(addQuizResultsRetries)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Leaderboard().AddQuizResults("channelID1", quiz)
+		assert.Error(t, err)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return([]byte{}, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Leaderboard().AddQuizResults("channelID1", quiz)
+		assert.Error(t, err)
+	})
+	t.Run("KVSetWithOptions() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", key).Return(nil, nil)
+		api.On("KVSetWithOptions", key, leaderboard.EncodeToByte(), model.PluginKVSetOptions{Atomic: true}).Return(false, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Leaderboard().AddQuizResults("channelID1", quiz)
+		assert.Error(t, err)
+	})
+}
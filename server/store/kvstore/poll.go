@@ -2,11 +2,13 @@ package kvstore
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 
 	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/store"
 )
 
 // PollStore allows to access polls in the KV Store.
@@ -14,7 +16,13 @@ type PollStore struct {
 	api plugin.API
 }
 
-const pollPrefix = "poll_"
+const (
+	pollPrefix      = "poll_"
+	endedPollPrefix = "ended_poll_"
+)
+
+// errDecodePoll is returned if a stored poll can't be decoded, e.g. because it was written by an incompatible version.
+var errDecodePoll = errors.New("failed to decode poll")
 
 // Get returns the poll for a given id. Returns an error if the poll doesn't exist or a KV Store error occurred.
 func (s *PollStore) Get(id string) (*poll.Poll, error) {
@@ -25,7 +33,7 @@ func (s *PollStore) Get(id string) (*poll.Poll, error) {
 
 	poll := poll.DecodePollFromByte(b)
 	if poll == nil {
-		return nil, errors.New("failed to decode poll")
+		return nil, errDecodePoll
 	}
 
 	return poll, nil
@@ -84,3 +92,116 @@ func (s *PollStore) Delete(poll *poll.Poll) error {
 
 	return nil
 }
+
+// End moves a poll to the ended polls in the KV Store and records the time it ended, if it isn't set yet.
+// The active poll is deleted first, only if it's unchanged, so that a poll can't be ended twice at once.
+func (s *PollStore) End(poll *poll.Poll) error {
+	opt := model.PluginKVSetOptions{
+		Atomic:   true,
+		OldValue: poll.EncodeToByte(),
+	}
+	ok, err := s.api.KVSetWithOptions(pollPrefix+poll.ID, nil, opt)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		b, err := s.api.KVGet(pollPrefix + poll.ID)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return store.ErrPollAlreadyEnded
+		}
+		return errors.New("poll was changed in the meantime")
+	}
+
+	endedPoll := poll.Copy()
+	if endedPoll.EndedAt == 0 {
+		endedPoll.EndedAt = model.GetMillis()
+	}
+	if err := s.api.KVSet(endedPollPrefix+poll.ID, endedPoll.EncodeToByte()); err != nil {
+		// Restore the active poll, so that it doesn't get lost
+		if restoreErr := s.api.KVSet(pollPrefix+poll.ID, poll.EncodeToByte()); restoreErr != nil {
+			s.api.LogWarn("Failed to restore poll", "pollID", poll.ID, "error", restoreErr.Error())
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetEnded returns the ended poll for a given id. Returns an error if the ended poll doesn't exist or a KV Store error occurred.
+func (s *PollStore) GetEnded(id string) (*poll.Poll, error) {
+	b, err := s.api.KVGet(endedPollPrefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	poll := poll.DecodePollFromByte(b)
+	if poll == nil {
+		return nil, errDecodePoll
+	}
+
+	return poll, nil
+}
+
+// SaveEnded stores an ended poll in the KV Store. Overwrittes any existing ended poll with the same id.
+func (s *PollStore) SaveEnded(poll *poll.Poll) error {
+	if err := s.api.KVSet(endedPollPrefix+poll.ID, poll.EncodeToByte()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteEnded deletes an ended poll from the KV Store.
+func (s *PollStore) DeleteEnded(poll *poll.Poll) error {
+	if err := s.api.KVDelete(endedPollPrefix + poll.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List returns all active polls.
+func (s *PollStore) List() ([]*poll.Poll, error) {
+	return s.list(pollPrefix, s.Get)
+}
+
+// ListEnded returns all ended polls.
+func (s *PollStore) ListEnded() ([]*poll.Poll, error) {
+	return s.list(endedPollPrefix, s.GetEnded)
+}
+
+func (s *PollStore) list(prefix string, get func(id string) (*poll.Poll, error)) ([]*poll.Poll, error) {
+	polls := []*poll.Poll{}
+	for i := 0; ; i++ {
+		keys, appErr := s.api.KVList(i, perPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+
+		for _, k := range keys {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			p, err := get(strings.TrimPrefix(k, prefix))
+			if errors.Is(err, errDecodePoll) {
+				// A single broken poll must not prevent listing all others
+				s.api.LogWarn("skipping poll that can't be decoded", "key", k)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			polls = append(polls, p)
+		}
+
+		if len(keys) < perPage {
+			break
+		}
+	}
+
+	return polls, nil
+}
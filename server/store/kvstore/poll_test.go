@@ -4,11 +4,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
 
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/store"
 	"github.com/matterpoll/matterpoll/server/utils/testutils"
 )
 
@@ -167,3 +170,271 @@ func TestPollStoreDelete(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestPollStoreEnd(t *testing.T) {
+	endedPoll := testutils.GetPoll()
+	endedPoll.EndedAt = testutils.GetMillis()
+	claim := model.PluginKVSetOptions{Atomic: true, OldValue: endedPoll.EncodeToByte()}
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), claim).Return(true, nil)
+		api.On("KVSet", endedPollPrefix+testutils.GetPollID(), endedPoll.EncodeToByte()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().End(endedPoll)
+		require.NoError(t, err)
+	})
+	t.Run("end time recorded", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), model.PluginKVSetOptions{Atomic: true, OldValue: testutils.GetPoll().EncodeToByte()}).Return(true, nil)
+		api.On("KVSet", endedPollPrefix+testutils.GetPollID(), mock.MatchedBy(func(b []byte) bool {
+			p := poll.DecodePollFromByte(b)
+			return p != nil && p.EndedAt > 0
+		})).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		p := testutils.GetPoll()
+		err := store.Poll().End(p)
+		require.NoError(t, err)
+		assert.Equal(t, testutils.GetPoll(), p)
+	})
+	t.Run("poll already ended", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), claim).Return(false, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(nil, nil)
+		defer api.AssertExpectations(t)
+
+		err := setupTestStore(api).Poll().End(endedPoll)
+		assert.ErrorIs(t, err, store.ErrPollAlreadyEnded)
+	})
+	t.Run("poll changed in the meantime", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), claim).Return(false, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(testutils.GetPollWithVotes().EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+
+		err := setupTestStore(api).Poll().End(endedPoll)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, store.ErrPollAlreadyEnded)
+	})
+	t.Run("KVSetWithOptions() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), claim).Return(false, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().End(endedPoll)
+		require.Error(t, err)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), claim).Return(false, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().End(endedPoll)
+		require.Error(t, err)
+	})
+	t.Run("KVSet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSetWithOptions", pollPrefix+testutils.GetPollID(), []byte(nil), claim).Return(true, nil)
+		api.On("KVSet", endedPollPrefix+testutils.GetPollID(), endedPoll.EncodeToByte()).Return(&model.AppError{})
+		api.On("KVSet", pollPrefix+testutils.GetPollID(), endedPoll.EncodeToByte()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().End(endedPoll)
+		require.Error(t, err)
+	})
+}
+
+func TestPollStoreGetEnded(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", endedPollPrefix+testutils.GetPollID()).Return(testutils.GetPoll().EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rpoll, err := store.Poll().GetEnded(testutils.GetPollID())
+		require.NoError(t, err)
+		assert.Equal(t, testutils.GetPoll(), rpoll)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", endedPollPrefix+testutils.GetPollID()).Return([]byte{}, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rpoll, err := store.Poll().GetEnded(testutils.GetPollID())
+		assert.Error(t, err)
+		assert.Nil(t, rpoll)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", endedPollPrefix+testutils.GetPollID()).Return(nil, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rpoll, err := store.Poll().GetEnded(testutils.GetPollID())
+		assert.Error(t, err)
+		assert.Nil(t, rpoll)
+	})
+}
+
+func TestPollStoreSaveEnded(t *testing.T) {
+	poll := testutils.GetPollWithVotes()
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSet", endedPollPrefix+testutils.GetPollID(), poll.EncodeToByte()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().SaveEnded(poll)
+		assert.NoError(t, err)
+	})
+	t.Run("KVSet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSet", endedPollPrefix+testutils.GetPollID(), poll.EncodeToByte()).Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().SaveEnded(poll)
+		assert.Error(t, err)
+	})
+}
+
+func TestPollStoreDeleteEnded(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", endedPollPrefix+testutils.GetPollID()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().DeleteEnded(testutils.GetPoll())
+		require.NoError(t, err)
+	})
+	t.Run("KVDelete() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", endedPollPrefix+testutils.GetPollID()).Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Poll().DeleteEnded(testutils.GetPoll())
+		require.Error(t, err)
+	})
+}
+
+func TestPollStoreList(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{
+			pollPrefix + testutils.GetPollID(),
+			endedPollPrefix + "pollID2",
+			recurringPollPrefix + "recurringPollID1",
+		}, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(testutils.GetPoll().EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().List()
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Poll{testutils.GetPoll()}, polls)
+	})
+	t.Run("several pages", func(t *testing.T) {
+		keys := make([]string, perPage)
+		for i := range keys {
+			keys[i] = recurringPollPrefix + model.NewId()
+		}
+		keys[0] = pollPrefix + testutils.GetPollID()
+
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(keys, nil)
+		api.On("KVList", 1, perPage).Return([]string{pollPrefix + "pollID2"}, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(testutils.GetPoll().EncodeToByte(), nil)
+		api.On("KVGet", pollPrefix+"pollID2").Return(testutils.GetPollWithVotes().EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().List()
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Poll{testutils.GetPoll(), testutils.GetPollWithVotes()}, polls)
+	})
+	t.Run("KVList() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().List()
+		assert.Error(t, err)
+		assert.Nil(t, polls)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{pollPrefix + testutils.GetPollID()}, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().List()
+		assert.Error(t, err)
+		assert.Nil(t, polls)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{pollPrefix + testutils.GetPollID()}, nil)
+		api.On("KVGet", pollPrefix+testutils.GetPollID()).Return(nil, nil)
+		api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().List()
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Poll{}, polls)
+	})
+}
+
+func TestPollStoreListEnded(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{
+			pollPrefix + "pollID2",
+			endedPollPrefix + testutils.GetPollID(),
+		}, nil)
+		api.On("KVGet", endedPollPrefix+testutils.GetPollID()).Return(testutils.GetPoll().EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().ListEnded()
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Poll{testutils.GetPoll()}, polls)
+	})
+	t.Run("no ended polls", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{}, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().ListEnded()
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Poll{}, polls)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{endedPollPrefix + "pollID2", endedPollPrefix + testutils.GetPollID()}, nil)
+		api.On("KVGet", endedPollPrefix+"pollID2").Return([]byte("{"), nil)
+		api.On("KVGet", endedPollPrefix+testutils.GetPollID()).Return(testutils.GetPoll().EncodeToByte(), nil)
+		api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		polls, err := store.Poll().ListEnded()
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Poll{testutils.GetPoll()}, polls)
+	})
+}
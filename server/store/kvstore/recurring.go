@@ -0,0 +1,80 @@
+package kvstore
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+)
+
+// RecurringPollStore allows to access recurring polls in the KV Store.
+type RecurringPollStore struct {
+	api plugin.API
+}
+
+const recurringPollPrefix = "recurring_poll_"
+
+// Get returns the recurring poll for a given id. Returns an error if the recurring poll doesn't exist or a KV Store error occurred.
+func (s *RecurringPollStore) Get(id string) (*poll.RecurringPoll, error) {
+	b, err := s.api.KVGet(recurringPollPrefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	r := poll.DecodeRecurringPollFromByte(b)
+	if r == nil {
+		return nil, errors.New("failed to decode recurring poll")
+	}
+
+	return r, nil
+}
+
+// List returns all recurring polls, that are posted in a given channel.
+func (s *RecurringPollStore) List(channelID string) ([]*poll.RecurringPoll, error) {
+	recurringPolls := []*poll.RecurringPoll{}
+	for i := 0; ; i++ {
+		keys, appErr := s.api.KVList(i, perPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+
+		for _, k := range keys {
+			if !strings.HasPrefix(k, recurringPollPrefix) {
+				continue
+			}
+			r, err := s.Get(strings.TrimPrefix(k, recurringPollPrefix))
+			if err != nil {
+				return nil, err
+			}
+			if r.ChannelID == channelID {
+				recurringPolls = append(recurringPolls, r)
+			}
+		}
+
+		if len(keys) < perPage {
+			break
+		}
+	}
+
+	return recurringPolls, nil
+}
+
+// Save stores a recurring poll in the KV Store. Overwrittes any existing recurring poll with the same id.
+func (s *RecurringPollStore) Save(r *poll.RecurringPoll) error {
+	if err := s.api.KVSet(recurringPollPrefix+r.ID, r.EncodeToByte()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a recurring poll from the KV Store.
+func (s *RecurringPollStore) Delete(r *poll.RecurringPoll) error {
+	if err := s.api.KVDelete(recurringPollPrefix + r.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package kvstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func getRecurringPoll(id, channelID string) *poll.RecurringPoll {
+	return &poll.RecurringPoll{
+		ID:        id,
+		ChannelID: channelID,
+		Poll:      testutils.GetPoll(),
+		Weekday:   time.Friday,
+		Hour:      10,
+		Timezone:  "UTC",
+		NextRunAt: testutils.GetMillis(),
+	}
+}
+
+func TestRecurringPollStoreGet(t *testing.T) {
+	r := getRecurringPoll("recurringPollID", "channelID1")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", recurringPollPrefix+"recurringPollID").Return(r.EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rr, err := store.RecurringPoll().Get("recurringPollID")
+		require.NoError(t, err)
+		assert.Equal(t, r, rr)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", recurringPollPrefix+"recurringPollID").Return([]byte{}, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rr, err := store.RecurringPoll().Get("recurringPollID")
+		assert.Error(t, err)
+		assert.Nil(t, rr)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", recurringPollPrefix+"recurringPollID").Return(nil, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rr, err := store.RecurringPoll().Get("recurringPollID")
+		assert.Error(t, err)
+		assert.Nil(t, rr)
+	})
+}
+
+func TestRecurringPollStoreList(t *testing.T) {
+	r1 := getRecurringPoll("recurringPollID1", "channelID1")
+	r2 := getRecurringPoll("recurringPollID2", "channelID2")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{
+			pollPrefix + testutils.GetPollID(),
+			recurringPollPrefix + "recurringPollID1",
+			recurringPollPrefix + "recurringPollID2",
+		}, nil)
+		api.On("KVGet", recurringPollPrefix+"recurringPollID1").Return(r1.EncodeToByte(), nil)
+		api.On("KVGet", recurringPollPrefix+"recurringPollID2").Return(r2.EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		recurringPolls, err := store.RecurringPoll().List("channelID1")
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.RecurringPoll{r1}, recurringPolls)
+	})
+	t.Run("several pages", func(t *testing.T) {
+		keys := make([]string, perPage)
+		for i := range keys {
+			keys[i] = pollPrefix + model.NewId()
+		}
+		keys[0] = recurringPollPrefix + "recurringPollID1"
+
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(keys, nil)
+		api.On("KVList", 1, perPage).Return([]string{recurringPollPrefix + "recurringPollID2"}, nil)
+		api.On("KVGet", recurringPollPrefix+"recurringPollID1").Return(r1.EncodeToByte(), nil)
+		api.On("KVGet", recurringPollPrefix+"recurringPollID2").Return(getRecurringPoll("recurringPollID2", "channelID1").EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		recurringPolls, err := store.RecurringPoll().List("channelID1")
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.RecurringPoll{r1, getRecurringPoll("recurringPollID2", "channelID1")}, recurringPolls)
+	})
+	t.Run("no recurring polls", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{}, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		recurringPolls, err := store.RecurringPoll().List("channelID1")
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.RecurringPoll{}, recurringPolls)
+	})
+	t.Run("KVList() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		recurringPolls, err := store.RecurringPoll().List("channelID1")
+		assert.Error(t, err)
+		assert.Nil(t, recurringPolls)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{recurringPollPrefix + "recurringPollID1"}, nil)
+		api.On("KVGet", recurringPollPrefix+"recurringPollID1").Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		recurringPolls, err := store.RecurringPoll().List("channelID1")
+		assert.Error(t, err)
+		assert.Nil(t, recurringPolls)
+	})
+}
+
+func TestRecurringPollStoreSave(t *testing.T) {
+	r := getRecurringPoll("recurringPollID", "channelID1")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSet", recurringPollPrefix+"recurringPollID", r.EncodeToByte()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.RecurringPoll().Save(r)
+		assert.NoError(t, err)
+	})
+	t.Run("KVSet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSet", recurringPollPrefix+"recurringPollID", r.EncodeToByte()).Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.RecurringPoll().Save(r)
+		assert.Error(t, err)
+	})
+}
+
+func TestRecurringPollStoreDelete(t *testing.T) {
+	r := getRecurringPoll("recurringPollID", "channelID1")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", recurringPollPrefix+"recurringPollID").Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.RecurringPoll().Delete(r)
+		assert.NoError(t, err)
+	})
+	t.Run("KVDelete() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", recurringPollPrefix+"recurringPollID").Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.RecurringPoll().Delete(r)
+		assert.Error(t, err)
+	})
+}
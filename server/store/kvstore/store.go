@@ -1,6 +1,8 @@
 package kvstore
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/mattermost/mattermost/server/public/plugin"
 
 	"github.com/matterpoll/matterpoll/server/store"
@@ -8,21 +10,38 @@ import (
 
 // Store is an interface to interact with the KV Store.
 type Store struct {
-	api         plugin.API
-	pollStore   PollStore
-	systemStore SystemStore
-	upgrades    []*upgrade
+	api                plugin.API
+	pollStore          PollStore
+	leaderboardStore   LeaderboardStore
+	recurringPollStore RecurringPollStore
+	templateStore      TemplateStore
+	voteHistoryStore   VoteHistoryStore
+	systemStore        SystemStore
+	upgrades           []*upgrade
+	// secretBallotKey is used to derive the voter tokens when migrating polls to secret ballots.
+	secretBallotKey []byte
 }
 
 // NewStore returns a fresh store and upgrades the db from the given schema version.
 func NewStore(api plugin.API, pluginVersion string) (store.Store, error) {
 	store := Store{
-		api:         api,
-		pollStore:   PollStore{api: api},
-		systemStore: SystemStore{api: api},
-		upgrades:    getUpgrades(),
+		api:                api,
+		pollStore:          PollStore{api: api},
+		leaderboardStore:   LeaderboardStore{api: api},
+		recurringPollStore: RecurringPollStore{api: api},
+		templateStore:      TemplateStore{api: api},
+		voteHistoryStore:   VoteHistoryStore{api: api},
+		systemStore:        SystemStore{api: api},
+		upgrades:           getUpgrades(),
 	}
-	err := store.UpdateDatabase(pluginVersion)
+
+	secretBallotKey, err := store.systemStore.GetSecretBallotKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret ballot key")
+	}
+	store.secretBallotKey = secretBallotKey
+
+	err = store.UpdateDatabase(pluginVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -33,5 +52,17 @@ func NewStore(api plugin.API, pluginVersion string) (store.Store, error) {
 // Poll returns the Poll Store
 func (s *Store) Poll() store.PollStore { return &s.pollStore }
 
+// Leaderboard returns the Leaderboard Store
+func (s *Store) Leaderboard() store.LeaderboardStore { return &s.leaderboardStore }
+
+// RecurringPoll returns the Recurring Poll Store
+func (s *Store) RecurringPoll() store.RecurringPollStore { return &s.recurringPollStore }
+
+// Template returns the Template Store
+func (s *Store) Template() store.TemplateStore { return &s.templateStore }
+
+// VoteHistory returns the Vote History Store
+func (s *Store) VoteHistory() store.VoteHistoryStore { return &s.voteHistoryStore }
+
 // System returns the System Store
 func (s *Store) System() store.SystemStore { return &s.systemStore }
@@ -10,7 +10,7 @@ import (
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
 )
 
-const latestVersion = "1.8.0"
+const latestVersion = "1.10.0"
 
 func setupTestStore(api plugin.API) *Store {
 	store := Store{
@@ -18,6 +18,18 @@ func setupTestStore(api plugin.API) *Store {
 		pollStore: PollStore{
 			api: api,
 		},
+		leaderboardStore: LeaderboardStore{
+			api: api,
+		},
+		recurringPollStore: RecurringPollStore{
+			api: api,
+		},
+		templateStore: TemplateStore{
+			api: api,
+		},
+		voteHistoryStore: VoteHistoryStore{
+			api: api,
+		},
 		systemStore: SystemStore{
 			api: api,
 		},
@@ -29,6 +41,7 @@ func setupTestStore(api plugin.API) *Store {
 func TestNewStore(t *testing.T) {
 	t.Run("all fine", func(t *testing.T) {
 		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(testConfig("atRestEncryptKey"))
 		api.On("KVGet", versionKey).Return([]byte(latestVersion), nil)
 		defer api.AssertExpectations(t)
 
@@ -36,8 +49,18 @@ func TestNewStore(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, store)
 	})
+	t.Run("getting secret ballot key fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(testConfig(""))
+		defer api.AssertExpectations(t)
+
+		store, err := NewStore(api, latestVersion)
+		assert.NotNil(t, err)
+		assert.Nil(t, store)
+	})
 	t.Run("UpdateDatabase() fails", func(t *testing.T) {
 		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(testConfig("atRestEncryptKey"))
 		api.On("KVGet", versionKey).Return([]byte{}, &model.AppError{})
 		defer api.AssertExpectations(t)
 
@@ -46,3 +69,7 @@ func TestNewStore(t *testing.T) {
 		assert.Nil(t, store)
 	})
 }
+
+func testConfig(atRestEncryptKey string) *model.Config {
+	return &model.Config{SqlSettings: model.SqlSettings{AtRestEncryptKey: model.NewPointer(atRestEncryptKey)}}
+}
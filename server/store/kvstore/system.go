@@ -1,6 +1,11 @@
 package kvstore
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+
 	"github.com/mattermost/mattermost/server/public/plugin"
 )
 
@@ -9,7 +14,11 @@ type SystemStore struct {
 	api plugin.API
 }
 
-const versionKey = "version"
+const (
+	versionKey = "version"
+	// secretBallotKeyContext separates the secret ballot key from other keys derived from the same server secret.
+	secretBallotKeyContext = "matterpoll secret ballot key"
+)
 
 // GetVersion returns the db schema version.
 func (s *SystemStore) GetVersion() (string, error) {
@@ -28,3 +37,16 @@ func (s *SystemStore) SaveVersion(version string) error {
 	}
 	return nil
 }
+
+// GetSecretBallotKey returns the key used to derive the voter tokens of secret ballots.
+// The key is derived from the at rest encryption key of the server, so that it isn't stored next to the ballots.
+func (s *SystemStore) GetSecretBallotKey() ([]byte, error) {
+	config := s.api.GetUnsanitizedConfig()
+	if config == nil || config.SqlSettings.AtRestEncryptKey == nil || *config.SqlSettings.AtRestEncryptKey == "" {
+		return nil, errors.New("at rest encryption key of the server is not set")
+	}
+
+	mac := hmac.New(sha256.New, []byte(*config.SqlSettings.AtRestEncryptKey))
+	mac.Write([]byte(secretBallotKeyContext))
+	return mac.Sum(nil), nil
+}
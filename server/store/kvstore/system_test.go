@@ -53,3 +53,54 @@ func TestSystemStoreSetVersion(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+
+func TestSystemStoreGetSecretBallotKey(t *testing.T) {
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(testConfig("atRestEncryptKey"))
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		key, err := store.System().GetSecretBallotKey()
+		require.Nil(t, err)
+		assert.Len(t, key, 32)
+		assert.NotContains(t, string(key), "atRestEncryptKey")
+
+		key2, err := store.System().GetSecretBallotKey()
+		require.Nil(t, err)
+		assert.Equal(t, key, key2)
+	})
+	t.Run("different server secret", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(testConfig("atRestEncryptKey")).Once()
+		api.On("GetUnsanitizedConfig").Return(testConfig("otherAtRestEncryptKey")).Once()
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		key, err := store.System().GetSecretBallotKey()
+		require.Nil(t, err)
+		key2, err := store.System().GetSecretBallotKey()
+		require.Nil(t, err)
+		assert.NotEqual(t, key, key2)
+	})
+	t.Run("at rest encryption key not set", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(testConfig(""))
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		key, err := store.System().GetSecretBallotKey()
+		assert.NotNil(t, err)
+		assert.Nil(t, key)
+	})
+	t.Run("no config", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("GetUnsanitizedConfig").Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		key, err := store.System().GetSecretBallotKey()
+		assert.NotNil(t, err)
+		assert.Nil(t, key)
+	})
+}
@@ -0,0 +1,84 @@
+package kvstore
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+)
+
+// TemplateStore allows to access poll templates in the KV Store.
+type TemplateStore struct {
+	api plugin.API
+}
+
+const templatePrefix = "template_"
+
+// Get returns the template for a given id. Returns an error if the template doesn't exist or a KV Store error occurred.
+func (s *TemplateStore) Get(id string) (*poll.Template, error) {
+	b, err := s.api.KVGet(templatePrefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	t := poll.DecodeTemplateFromByte(b)
+	if t == nil {
+		return nil, errors.New("failed to decode template")
+	}
+
+	return t, nil
+}
+
+// List returns the personal templates of a given user and the templates shared with a given team, sorted by name.
+func (s *TemplateStore) List(userID, teamID string) ([]*poll.Template, error) {
+	templates := []*poll.Template{}
+	for i := 0; ; i++ {
+		keys, appErr := s.api.KVList(i, perPage)
+		if appErr != nil {
+			return nil, appErr
+		}
+
+		for _, k := range keys {
+			if !strings.HasPrefix(k, templatePrefix) {
+				continue
+			}
+			t, err := s.Get(strings.TrimPrefix(k, templatePrefix))
+			if err != nil {
+				return nil, err
+			}
+			if t.CanUse(userID, teamID) {
+				templates = append(templates, t)
+			}
+		}
+
+		if len(keys) < perPage {
+			break
+		}
+	}
+
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// Save stores a template in the KV Store. Overwrittes any existing template with the same id.
+func (s *TemplateStore) Save(t *poll.Template) error {
+	if err := s.api.KVSet(templatePrefix+t.ID, t.EncodeToByte()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a template from the KV Store.
+func (s *TemplateStore) Delete(t *poll.Template) error {
+	if err := s.api.KVDelete(templatePrefix + t.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package kvstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+
+	"github.com/matterpoll/matterpoll/server/poll"
+	"github.com/matterpoll/matterpoll/server/utils/testutils"
+)
+
+func getTemplate(id, name, teamID string) *poll.Template {
+	return &poll.Template{
+		ID:     id,
+		Name:   name,
+		TeamID: teamID,
+		Poll:   testutils.GetPoll(),
+	}
+}
+
+func TestTemplateStoreGet(t *testing.T) {
+	template := getTemplate("templateID", "standup", "")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", templatePrefix+"templateID").Return(template.EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rt, err := store.Template().Get("templateID")
+		require.NoError(t, err)
+		assert.Equal(t, template, rt)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", templatePrefix+"templateID").Return([]byte{}, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rt, err := store.Template().Get("templateID")
+		assert.Error(t, err)
+		assert.Nil(t, rt)
+	})
+	t.Run("Decode fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVGet", templatePrefix+"templateID").Return(nil, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		rt, err := store.Template().Get("templateID")
+		assert.Error(t, err)
+		assert.Nil(t, rt)
+	})
+}
+
+func TestTemplateStoreList(t *testing.T) {
+	personal := getTemplate("templateID1", "standup", "")
+	shared := getTemplate("templateID2", "retro", "teamID1")
+	otherTeam := getTemplate("templateID3", "lunch", "teamID2")
+	otherUser := getTemplate("templateID4", "review", "")
+	otherUser.Poll.Creator = "userID2"
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{
+			pollPrefix + testutils.GetPollID(),
+			templatePrefix + "templateID1",
+			templatePrefix + "templateID2",
+			templatePrefix + "templateID3",
+			templatePrefix + "templateID4",
+		}, nil)
+		api.On("KVGet", templatePrefix+"templateID1").Return(personal.EncodeToByte(), nil)
+		api.On("KVGet", templatePrefix+"templateID2").Return(shared.EncodeToByte(), nil)
+		api.On("KVGet", templatePrefix+"templateID3").Return(otherTeam.EncodeToByte(), nil)
+		api.On("KVGet", templatePrefix+"templateID4").Return(otherUser.EncodeToByte(), nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		templates, err := store.Template().List("userID1", "teamID1")
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Template{shared, personal}, templates)
+	})
+	t.Run("no templates", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{}, nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		templates, err := store.Template().List("userID1", "teamID1")
+		require.NoError(t, err)
+		assert.Equal(t, []*poll.Template{}, templates)
+	})
+	t.Run("KVList() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		templates, err := store.Template().List("userID1", "teamID1")
+		assert.Error(t, err)
+		assert.Nil(t, templates)
+	})
+	t.Run("KVGet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return([]string{templatePrefix + "templateID1"}, nil)
+		api.On("KVGet", templatePrefix+"templateID1").Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		templates, err := store.Template().List("userID1", "teamID1")
+		assert.Error(t, err)
+		assert.Nil(t, templates)
+	})
+}
+
+func TestTemplateStoreSave(t *testing.T) {
+	template := getTemplate("templateID", "standup", "")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSet", templatePrefix+"templateID", template.EncodeToByte()).Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Template().Save(template)
+		assert.NoError(t, err)
+	})
+	t.Run("KVSet() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVSet", templatePrefix+"templateID", template.EncodeToByte()).Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Template().Save(template)
+		assert.Error(t, err)
+	})
+}
+
+func TestTemplateStoreDelete(t *testing.T) {
+	template := getTemplate("templateID", "standup", "")
+
+	t.Run("all fine", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", templatePrefix+"templateID").Return(nil)
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Template().Delete(template)
+		assert.NoError(t, err)
+	})
+	t.Run("KVDelete() fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVDelete", templatePrefix+"templateID").Return(&model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		err := store.Template().Delete(template)
+		assert.Error(t, err)
+	})
+}
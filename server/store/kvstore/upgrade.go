@@ -1,12 +1,16 @@
 package kvstore
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/blang/semver/v4"
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
+
+	"github.com/matterpoll/matterpoll/server/poll"
 )
 
 const (
@@ -41,6 +45,8 @@ func getUpgrades() []*upgrade {
 		{toVersion: "1.7.1", upgradeFunc: nil},
 		{toVersion: "1.7.2", upgradeFunc: upgradeTo17_2},
 		{toVersion: "1.8.0", upgradeFunc: upgradeTo18},
+		{toVersion: "1.9.0", upgradeFunc: upgradeTo19},
+		{toVersion: "1.10.0", upgradeFunc: upgradeTo110},
 	}
 }
 
@@ -218,3 +224,79 @@ func upgradeTo18(s *Store) (migrationResults, error) {
 	})
 	return status, err
 }
+
+// upgradeTo19 converts the votes of running anonymous polls to secret ballots,
+// so that the ids of their voters aren't stored next to their votes anymore.
+// The secret ballot marker is added to the poll post with the next vote.
+func upgradeTo19(s *Store) (migrationResults, error) {
+	status := migrationResults{}
+	err := s.applyUpgradeFunc(func(pollId string) error {
+		poll, err := s.Poll().Get(pollId)
+		if err != nil {
+			status.failed++
+			return errors.Wrap(err, "Failed to get poll for migration")
+		}
+
+		if !poll.ConvertToSecretBallot(s.secretBallotKey) {
+			status.skipped++
+			return nil
+		}
+
+		err = s.Poll().Save(poll)
+		if err != nil {
+			status.failed++
+			return errors.Wrap(err, "Failed to save poll after migration")
+		}
+
+		status.processed++
+		return nil
+	})
+	return status, err
+}
+
+// upgradeTo110 rewrites the stored polls in the format of v1.10.0.
+//
+// Polls stored by older versions rely on the zero values of the fields added in v1.10.0, e.g. no vote times and no end time,
+// and may store answer options without any voter as null. Atomic updates compare the stored poll with the encoded one,
+// so that every poll, which isn't stored exactly like v1.10.0 encodes it, is saved again.
+func upgradeTo110(s *Store) (migrationResults, error) {
+	status := migrationResults{}
+	err := s.applyUpgradeFunc(func(pollId string) error {
+		b, appErr := s.api.KVGet(pollPrefix + pollId)
+		if appErr != nil {
+			status.failed++
+			return errors.Wrap(appErr, "Failed to get poll for migration")
+		}
+		p := poll.DecodePollFromByte(b)
+		if p == nil {
+			status.failed++
+			return errors.Wrap(errDecodePoll, "Failed to get poll for migration")
+		}
+
+		options := slices.Clone(p.AnswerOptions)
+		for _, q := range p.Questions {
+			options = append(options, q.AnswerOptions...)
+		}
+		for _, o := range options {
+			if o.Voter == nil {
+				o.Voter = []string{}
+			}
+		}
+
+		if bytes.Equal(b, p.EncodeToByte()) {
+			// Already migrated
+			status.skipped++
+			return nil
+		}
+
+		err := s.Poll().Save(p)
+		if err != nil {
+			status.failed++
+			return errors.Wrap(err, "Failed to save poll after migration")
+		}
+
+		status.processed++
+		return nil
+	})
+	return status, err
+}
@@ -215,3 +215,161 @@ func TestUpgradeTo14(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestUpgradeTo19(t *testing.T) {
+	t.Run("KVList succeeds", func(t *testing.T) {
+		key := []byte("secretBallotKey")
+
+		anonymousPoll := poll.Poll{
+			ID: model.NewId(),
+			AnswerOptions: []*poll.AnswerOption{
+				{Answer: "Answer 1", Voter: []string{"userID1", "userID2"}},
+				{Answer: "Answer 2", Voter: []string{"userID1"}},
+			},
+			Settings: poll.Settings{Anonymous: true, MaxVotes: 0},
+		}
+		migratedPoll := anonymousPoll.Copy()
+		require.True(t, migratedPoll.ConvertToSecretBallot(key))
+
+		publicPoll := poll.Poll{
+			ID:            model.NewId(),
+			AnswerOptions: []*poll.AnswerOption{{Answer: "Answer 1", Voter: []string{"userID1"}}},
+			Settings:      poll.Settings{MaxVotes: 1},
+		}
+
+		failGetPoll := poll.Poll{
+			ID: model.NewId(),
+		}
+
+		failSavePoll := poll.Poll{
+			ID:            model.NewId(),
+			AnswerOptions: []*poll.AnswerOption{{Answer: "Answer 1", Voter: []string{"userID1"}}},
+			Settings:      poll.Settings{Anonymous: true, MaxVotes: 1},
+		}
+		migratedFailSavePoll := failSavePoll.Copy()
+		require.True(t, migratedFailSavePoll.ConvertToSecretBallot(key))
+
+		keys := []string{
+			"foo",
+			pollPrefix + anonymousPoll.ID,
+			pollPrefix + publicPoll.ID,
+			pollPrefix + failGetPoll.ID,
+			pollPrefix + failSavePoll.ID,
+		}
+
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(keys, nil)
+
+		api.On("KVGet", pollPrefix+anonymousPoll.ID).Return(anonymousPoll.EncodeToByte(), nil)
+		api.On("KVGet", pollPrefix+publicPoll.ID).Return(publicPoll.EncodeToByte(), nil)
+		api.On("KVGet", pollPrefix+failGetPoll.ID).Return(nil, &model.AppError{})
+		api.On("KVGet", pollPrefix+failSavePoll.ID).Return(failSavePoll.EncodeToByte(), nil)
+
+		api.On("KVSet", pollPrefix+anonymousPoll.ID, migratedPoll.EncodeToByte()).Return(nil)
+		api.On("KVSet", pollPrefix+failSavePoll.ID, migratedFailSavePoll.EncodeToByte()).Return(&model.AppError{})
+
+		api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return(nil)
+
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+		store.secretBallotKey = key
+
+		status, err := upgradeTo19(store)
+
+		require.NoError(t, err)
+		assert.Equal(t, migrationResults{processed: 1, skipped: 1, failed: 2}, status)
+		assert.NotContains(t, string(migratedPoll.EncodeToByte()), "userID1")
+	})
+
+	t.Run("KVList fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		_, err := upgradeTo19(store)
+
+		require.Error(t, err)
+	})
+}
+
+func TestUpgradeTo110(t *testing.T) {
+	t.Run("KVList succeeds", func(t *testing.T) {
+		// Stored by an older version, with an answer option without voters stored as null
+		oldPollID := model.NewId()
+		oldPoll := []byte(`{"ID":"` + oldPollID + `","post_id":"postID1","CreatedAt":1234567890,"Creator":"userID1","Question":"Question",` +
+			`"AnswerOptions":[{"Answer":"Answer 1","Voter":["userID1"]},{"Answer":"Answer 2","Voter":null}],` +
+			`"Settings":{"Anonymous":false,"Progress":false,"PublicAddOption":false,"max_votes":1}}`)
+		migratedPoll := &poll.Poll{
+			ID:        oldPollID,
+			PostID:    "postID1",
+			CreatedAt: 1234567890,
+			Creator:   "userID1",
+			Question:  "Question",
+			AnswerOptions: []*poll.AnswerOption{
+				{Answer: "Answer 1", Voter: []string{"userID1"}},
+				{Answer: "Answer 2", Voter: []string{}},
+			},
+			Settings: poll.Settings{MaxVotes: 1},
+		}
+
+		currentPoll := testutils.GetPollWithVotes()
+		currentPoll.ID = model.NewId()
+
+		failGetPoll := poll.Poll{
+			ID: model.NewId(),
+		}
+
+		failDecodePollID := model.NewId()
+
+		failSavePoll := poll.Poll{
+			ID:            model.NewId(),
+			AnswerOptions: []*poll.AnswerOption{{Answer: "Answer 1"}},
+			Settings:      poll.Settings{MaxVotes: 1},
+		}
+		migratedFailSavePoll := failSavePoll.Copy()
+		migratedFailSavePoll.AnswerOptions[0].Voter = []string{}
+
+		keys := []string{
+			"foo",
+			pollPrefix + oldPollID,
+			pollPrefix + currentPoll.ID,
+			pollPrefix + failGetPoll.ID,
+			pollPrefix + failDecodePollID,
+			pollPrefix + failSavePoll.ID,
+		}
+
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(keys, nil)
+
+		api.On("KVGet", pollPrefix+oldPollID).Return(oldPoll, nil)
+		api.On("KVGet", pollPrefix+currentPoll.ID).Return(currentPoll.EncodeToByte(), nil)
+		api.On("KVGet", pollPrefix+failGetPoll.ID).Return(nil, &model.AppError{})
+		api.On("KVGet", pollPrefix+failDecodePollID).Return([]byte("{"), nil)
+		api.On("KVGet", pollPrefix+failSavePoll.ID).Return(failSavePoll.EncodeToByte(), nil)
+
+		api.On("KVSet", pollPrefix+oldPollID, migratedPoll.EncodeToByte()).Return(nil)
+		api.On("KVSet", pollPrefix+failSavePoll.ID, migratedFailSavePoll.EncodeToByte()).Return(&model.AppError{})
+
+		api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return(nil)
+
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		status, err := upgradeTo110(store)
+
+		require.NoError(t, err)
+		assert.Equal(t, migrationResults{processed: 1, skipped: 1, failed: 3}, status)
+	})
+
+	t.Run("KVList fails", func(t *testing.T) {
+		api := &plugintest.API{}
+		api.On("KVList", 0, perPage).Return(nil, &model.AppError{})
+		defer api.AssertExpectations(t)
+		store := setupTestStore(api)
+
+		_, err := upgradeTo110(store)
+
+		require.Error(t, err)
+	})
+}
@@ -66,6 +66,98 @@ func (_c *PollStore_Delete_Call) RunAndReturn(run func(*poll.Poll) error) *PollS
 	return _c
 }
 
+// DeleteEnded provides a mock function with given fields: _a0
+func (_m *PollStore) DeleteEnded(_a0 *poll.Poll) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEnded")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*poll.Poll) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PollStore_DeleteEnded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEnded'
+type PollStore_DeleteEnded_Call struct {
+	*mock.Call
+}
+
+// DeleteEnded is a helper method to define mock.On call
+//   - _a0 *poll.Poll
+func (_e *PollStore_Expecter) DeleteEnded(_a0 interface{}) *PollStore_DeleteEnded_Call {
+	return &PollStore_DeleteEnded_Call{Call: _e.mock.On("DeleteEnded", _a0)}
+}
+
+func (_c *PollStore_DeleteEnded_Call) Run(run func(_a0 *poll.Poll)) *PollStore_DeleteEnded_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*poll.Poll))
+	})
+	return _c
+}
+
+func (_c *PollStore_DeleteEnded_Call) Return(_a0 error) *PollStore_DeleteEnded_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PollStore_DeleteEnded_Call) RunAndReturn(run func(*poll.Poll) error) *PollStore_DeleteEnded_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// End provides a mock function with given fields: _a0
+func (_m *PollStore) End(_a0 *poll.Poll) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for End")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*poll.Poll) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PollStore_End_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'End'
+type PollStore_End_Call struct {
+	*mock.Call
+}
+
+// End is a helper method to define mock.On call
+//   - _a0 *poll.Poll
+func (_e *PollStore_Expecter) End(_a0 interface{}) *PollStore_End_Call {
+	return &PollStore_End_Call{Call: _e.mock.On("End", _a0)}
+}
+
+func (_c *PollStore_End_Call) Run(run func(_a0 *poll.Poll)) *PollStore_End_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*poll.Poll))
+	})
+	return _c
+}
+
+func (_c *PollStore_End_Call) Return(_a0 error) *PollStore_End_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PollStore_End_Call) RunAndReturn(run func(*poll.Poll) error) *PollStore_End_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function with given fields: id
 func (_m *PollStore) Get(id string) (*poll.Poll, error) {
 	ret := _m.Called(id)
@@ -124,6 +216,64 @@ func (_c *PollStore_Get_Call) RunAndReturn(run func(string) (*poll.Poll, error))
 	return _c
 }
 
+// GetEnded provides a mock function with given fields: id
+func (_m *PollStore) GetEnded(id string) (*poll.Poll, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEnded")
+	}
+
+	var r0 *poll.Poll
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*poll.Poll, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *poll.Poll); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*poll.Poll)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PollStore_GetEnded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEnded'
+type PollStore_GetEnded_Call struct {
+	*mock.Call
+}
+
+// GetEnded is a helper method to define mock.On call
+//   - id string
+func (_e *PollStore_Expecter) GetEnded(id interface{}) *PollStore_GetEnded_Call {
+	return &PollStore_GetEnded_Call{Call: _e.mock.On("GetEnded", id)}
+}
+
+func (_c *PollStore_GetEnded_Call) Run(run func(id string)) *PollStore_GetEnded_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *PollStore_GetEnded_Call) Return(_a0 *poll.Poll, _a1 error) *PollStore_GetEnded_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PollStore_GetEnded_Call) RunAndReturn(run func(string) (*poll.Poll, error)) *PollStore_GetEnded_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Insert provides a mock function with given fields: _a0
 func (_m *PollStore) Insert(_a0 *poll.Poll) error {
 	ret := _m.Called(_a0)
@@ -170,6 +320,120 @@ func (_c *PollStore_Insert_Call) RunAndReturn(run func(*poll.Poll) error) *PollS
 	return _c
 }
 
+// List provides a mock function with no fields
+func (_m *PollStore) List() ([]*poll.Poll, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*poll.Poll
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*poll.Poll, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*poll.Poll); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*poll.Poll)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PollStore_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type PollStore_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+func (_e *PollStore_Expecter) List() *PollStore_List_Call {
+	return &PollStore_List_Call{Call: _e.mock.On("List")}
+}
+
+func (_c *PollStore_List_Call) Run(run func()) *PollStore_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *PollStore_List_Call) Return(_a0 []*poll.Poll, _a1 error) *PollStore_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PollStore_List_Call) RunAndReturn(run func() ([]*poll.Poll, error)) *PollStore_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListEnded provides a mock function with no fields
+func (_m *PollStore) ListEnded() ([]*poll.Poll, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEnded")
+	}
+
+	var r0 []*poll.Poll
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*poll.Poll, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*poll.Poll); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*poll.Poll)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PollStore_ListEnded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEnded'
+type PollStore_ListEnded_Call struct {
+	*mock.Call
+}
+
+// ListEnded is a helper method to define mock.On call
+func (_e *PollStore_Expecter) ListEnded() *PollStore_ListEnded_Call {
+	return &PollStore_ListEnded_Call{Call: _e.mock.On("ListEnded")}
+}
+
+func (_c *PollStore_ListEnded_Call) Run(run func()) *PollStore_ListEnded_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *PollStore_ListEnded_Call) Return(_a0 []*poll.Poll, _a1 error) *PollStore_ListEnded_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PollStore_ListEnded_Call) RunAndReturn(run func() ([]*poll.Poll, error)) *PollStore_ListEnded_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function with given fields: _a0
 func (_m *PollStore) Save(_a0 *poll.Poll) error {
 	ret := _m.Called(_a0)
@@ -216,6 +480,52 @@ func (_c *PollStore_Save_Call) RunAndReturn(run func(*poll.Poll) error) *PollSto
 	return _c
 }
 
+// SaveEnded provides a mock function with given fields: _a0
+func (_m *PollStore) SaveEnded(_a0 *poll.Poll) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEnded")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*poll.Poll) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PollStore_SaveEnded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveEnded'
+type PollStore_SaveEnded_Call struct {
+	*mock.Call
+}
+
+// SaveEnded is a helper method to define mock.On call
+//   - _a0 *poll.Poll
+func (_e *PollStore_Expecter) SaveEnded(_a0 interface{}) *PollStore_SaveEnded_Call {
+	return &PollStore_SaveEnded_Call{Call: _e.mock.On("SaveEnded", _a0)}
+}
+
+func (_c *PollStore_SaveEnded_Call) Run(run func(_a0 *poll.Poll)) *PollStore_SaveEnded_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*poll.Poll))
+	})
+	return _c
+}
+
+func (_c *PollStore_SaveEnded_Call) Return(_a0 error) *PollStore_SaveEnded_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PollStore_SaveEnded_Call) RunAndReturn(run func(*poll.Poll) error) *PollStore_SaveEnded_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function with given fields: oldPoll, newPoll
 func (_m *PollStore) Update(oldPoll *poll.Poll, newPoll *poll.Poll) error {
 	ret := _m.Called(oldPoll, newPoll)
@@ -8,18 +8,38 @@ import (
 
 // Store is a mock store
 type Store struct {
-	PollStore   PollStore
-	SystemStore SystemStore
+	PollStore          PollStore
+	LeaderboardStore   LeaderboardStore
+	RecurringPollStore RecurringPollStore
+	TemplateStore      TemplateStore
+	VoteHistoryStore   VoteHistoryStore
+	SystemStore        SystemStore
 }
 
 // Poll returns the Poll Store
 func (s *Store) Poll() store.PollStore { return &s.PollStore }
 
+// Leaderboard returns the Leaderboard Store
+func (s *Store) Leaderboard() store.LeaderboardStore { return &s.LeaderboardStore }
+
+// RecurringPoll returns the Recurring Poll Store
+func (s *Store) RecurringPoll() store.RecurringPollStore { return &s.RecurringPollStore }
+
+// Template returns the Template Store
+func (s *Store) Template() store.TemplateStore { return &s.TemplateStore }
+
+// VoteHistory returns the Vote History Store
+func (s *Store) VoteHistory() store.VoteHistoryStore { return &s.VoteHistoryStore }
+
 // System returns the System Store
 func (s *Store) System() store.SystemStore { return &s.SystemStore }
 
 // AssertExpectations makes sure the expectations of all stores are meet
 func (s *Store) AssertExpectations(t mock.TestingT) {
 	s.PollStore.AssertExpectations(t)
+	s.LeaderboardStore.AssertExpectations(t)
+	s.RecurringPollStore.AssertExpectations(t)
+	s.TemplateStore.AssertExpectations(t)
+	s.VoteHistoryStore.AssertExpectations(t)
 	s.SystemStore.AssertExpectations(t)
 }
@@ -17,6 +17,63 @@ func (_m *SystemStore) EXPECT() *SystemStore_Expecter {
 	return &SystemStore_Expecter{mock: &_m.Mock}
 }
 
+// GetSecretBallotKey provides a mock function with no fields
+func (_m *SystemStore) GetSecretBallotKey() ([]byte, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSecretBallotKey")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]byte, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SystemStore_GetSecretBallotKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSecretBallotKey'
+type SystemStore_GetSecretBallotKey_Call struct {
+	*mock.Call
+}
+
+// GetSecretBallotKey is a helper method to define mock.On call
+func (_e *SystemStore_Expecter) GetSecretBallotKey() *SystemStore_GetSecretBallotKey_Call {
+	return &SystemStore_GetSecretBallotKey_Call{Call: _e.mock.On("GetSecretBallotKey")}
+}
+
+func (_c *SystemStore_GetSecretBallotKey_Call) Run(run func()) *SystemStore_GetSecretBallotKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *SystemStore_GetSecretBallotKey_Call) Return(_a0 []byte, _a1 error) *SystemStore_GetSecretBallotKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SystemStore_GetSecretBallotKey_Call) RunAndReturn(run func() ([]byte, error)) *SystemStore_GetSecretBallotKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetVersion provides a mock function with no fields
 func (_m *SystemStore) GetVersion() (string, error) {
 	ret := _m.Called()
@@ -1,12 +1,21 @@
 package store
 
 import (
+	"errors"
+
 	"github.com/matterpoll/matterpoll/server/poll"
 )
 
+// ErrPollAlreadyEnded is returned when ending a poll, that was ended in the meantime, e.g. manually and by its end time at once.
+var ErrPollAlreadyEnded = errors.New("poll has already ended")
+
 // Store allows the interaction with some kind of store.
 type Store interface {
 	Poll() PollStore
+	Leaderboard() LeaderboardStore
+	RecurringPoll() RecurringPollStore
+	Template() TemplateStore
+	VoteHistory() VoteHistoryStore
 	System() SystemStore
 }
 
@@ -17,10 +26,55 @@ type PollStore interface {
 	Save(*poll.Poll) error
 	Update(oldPoll *poll.Poll, newPoll *poll.Poll) error
 	Delete(*poll.Poll) error
+	// End moves a poll to the ended polls and records the time it ended. Ended polls can't be voted on anymore, but they can be run again.
+	// Only one caller can end a poll, all others get ErrPollAlreadyEnded.
+	End(*poll.Poll) error
+	GetEnded(id string) (*poll.Poll, error)
+	// SaveEnded stores an ended poll, e.g. once the creator broke a tie. Overwrites the existing ended poll.
+	SaveEnded(*poll.Poll) error
+	DeleteEnded(*poll.Poll) error
+	// List returns all active polls.
+	List() ([]*poll.Poll, error)
+	// ListEnded returns all ended polls.
+	ListEnded() ([]*poll.Poll, error)
+}
+
+// LeaderboardStore allows to access the quiz leaderboards of channels in the store.
+type LeaderboardStore interface {
+	Get(channelID string) (*poll.Leaderboard, error)
+	AddQuizResults(channelID string, p *poll.Poll) error
+}
+
+// RecurringPollStore allows to access recurring polls in the store.
+type RecurringPollStore interface {
+	Get(id string) (*poll.RecurringPoll, error)
+	// List returns all recurring polls, that are posted in a given channel.
+	List(channelID string) ([]*poll.RecurringPoll, error)
+	Save(*poll.RecurringPoll) error
+	Delete(*poll.RecurringPoll) error
+}
+
+// TemplateStore allows to access poll templates in the store.
+type TemplateStore interface {
+	Get(id string) (*poll.Template, error)
+	// List returns the personal templates of a given user and the templates shared with a given team.
+	List(userID, teamID string) ([]*poll.Template, error)
+	Save(*poll.Template) error
+	Delete(*poll.Template) error
+}
+
+// VoteHistoryStore allows to access the vote histories of polls in the store.
+type VoteHistoryStore interface {
+	Get(pollID string) (poll.VoteHistory, error)
+	// Append adds an event to the vote history of a poll. Only the latest events are kept.
+	Append(pollID string, event *poll.VoteEvent) error
+	Delete(pollID string) error
 }
 
 // SystemStore allows to access system information in the store.
 type SystemStore interface {
 	GetVersion() (string, error)
 	SaveVersion(version string) error
+	// GetSecretBallotKey returns the key used to derive the voter tokens of secret ballots.
+	GetSecretBallotKey() ([]byte, error)
 }
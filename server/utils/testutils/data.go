@@ -141,6 +141,149 @@ func GetPollWithVotesAndSettings(settings poll.Settings) *poll.Poll {
 	return poll
 }
 
+// GetRankedPollWithBallots returns a ranked-choice Poll with three Options and four ballots.
+// "Answer 1" wins in the second round of the instant-runoff tally.
+func GetRankedPollWithBallots() *poll.Poll {
+	return &poll.Poll{
+		ID:        GetPollID(),
+		PostID:    "postID1",
+		CreatedAt: GetMillis(),
+		Creator:   "userID1",
+		Question:  "Question",
+		AnswerOptions: []*poll.AnswerOption{{
+			Answer: "Answer 1",
+			Voter:  []string{"userID1", "userID2", "userID4"},
+			Ranks:  map[string]int{"userID1": 1, "userID2": 2, "userID4": 1},
+		}, {
+			Answer: "Answer 2",
+			Voter:  []string{"userID1", "userID2", "userID3"},
+			Ranks:  map[string]int{"userID1": 2, "userID2": 1, "userID3": 2},
+		}, {
+			Answer: "Answer 3",
+			Voter:  []string{"userID3", "userID4"},
+			Ranks:  map[string]int{"userID3": 1, "userID4": 2},
+		}},
+		Settings: poll.Settings{MaxVotes: 1, Ranked: true},
+	}
+}
+
+// GetPointsPollWithVotes returns a point-budget Poll with three Options, a budget of five points and some points spent.
+func GetPointsPollWithVotes() *poll.Poll {
+	return &poll.Poll{
+		ID:        GetPollID(),
+		PostID:    "postID1",
+		CreatedAt: GetMillis(),
+		Creator:   "userID1",
+		Question:  "Question",
+		AnswerOptions: []*poll.AnswerOption{{
+			Answer: "Answer 1",
+			Voter:  []string{"userID1", "userID2"},
+			Points: map[string]int{"userID1": 3, "userID2": 1},
+		}, {
+			Answer: "Answer 2",
+			Voter:  []string{"userID1"},
+			Points: map[string]int{"userID1": 2},
+		}, {
+			Answer: "Answer 3",
+			Voter:  []string{},
+		}},
+		Settings: poll.Settings{MaxVotes: 1, Points: 5},
+	}
+}
+
+// GetSchedulingPollWithVotes returns a scheduling Poll with three Options and some availabilities.
+// "Answer 2" has the most yes answers, "Answer 3" the most maybe answers.
+func GetSchedulingPollWithVotes() *poll.Poll {
+	return &poll.Poll{
+		ID:        GetPollID(),
+		PostID:    "postID1",
+		CreatedAt: GetMillis(),
+		Creator:   "userID1",
+		Question:  "Question",
+		AnswerOptions: []*poll.AnswerOption{{
+			Answer:       "Answer 1",
+			Voter:        []string{"userID1", "userID2"},
+			Availability: map[string]poll.Availability{"userID1": poll.AvailabilityYes, "userID2": poll.AvailabilityNo},
+		}, {
+			Answer:       "Answer 2",
+			Voter:        []string{"userID1", "userID2"},
+			Availability: map[string]poll.Availability{"userID1": poll.AvailabilityYes, "userID2": poll.AvailabilityYes},
+		}, {
+			Answer:       "Answer 3",
+			Voter:        []string{"userID1", "userID2"},
+			Availability: map[string]poll.Availability{"userID1": poll.AvailabilityMaybe, "userID2": poll.AvailabilityMaybe},
+		}},
+		Settings: poll.Settings{MaxVotes: 1, Scheduling: true},
+	}
+}
+
+// GetQuizPollWithVotes returns a quiz with three Options and some votes. "Answer 1" is the only correct answer.
+func GetQuizPollWithVotes() *poll.Poll {
+	return &poll.Poll{
+		ID:        GetPollID(),
+		PostID:    "postID1",
+		CreatedAt: GetMillis(),
+		Creator:   "userID1",
+		Question:  "Question",
+		AnswerOptions: []*poll.AnswerOption{{
+			Answer:  "Answer 1",
+			Voter:   []string{"userID1", "userID2"},
+			Correct: true,
+		}, {
+			Answer: "Answer 2",
+			Voter:  []string{"userID3"},
+		}, {
+			Answer: "Answer 3",
+			Voter:  []string{},
+		}},
+		Settings: poll.Settings{MaxVotes: 1, Quiz: true},
+	}
+}
+
+// GetFreeTextPollWithResponses returns a free-text Poll with responses of two users.
+func GetFreeTextPollWithResponses() *poll.Poll {
+	return &poll.Poll{
+		ID:        GetPollID(),
+		PostID:    "postID1",
+		CreatedAt: GetMillis(),
+		Creator:   "userID1",
+		Question:  "Question",
+		Responses: []*poll.Response{
+			{UserID: "userID1", Text: "First response"},
+			{UserID: "userID2", Text: "Second response"},
+		},
+		Settings: poll.Settings{MaxVotes: 1, FreeText: true},
+	}
+}
+
+// GetSurveyWithAnswers returns a survey with two questions and some answers. The second question is anonymous and allows two votes.
+func GetSurveyWithAnswers() *poll.Poll {
+	return &poll.Poll{
+		ID:        GetPollID(),
+		PostID:    "postID1",
+		CreatedAt: GetMillis(),
+		Creator:   "userID1",
+		Question:  "Survey",
+		Questions: []*poll.SurveyQuestion{{
+			Question: "Question 1",
+			AnswerOptions: []*poll.AnswerOption{
+				{Answer: "Answer 1", Voter: []string{"userID1", "userID2"}},
+				{Answer: "Answer 2", Voter: []string{"userID3"}},
+			},
+			Settings: poll.Settings{MaxVotes: 1},
+		}, {
+			Question: "Question 2",
+			AnswerOptions: []*poll.AnswerOption{
+				{Answer: "Answer 1", Voter: []string{"userID1"}},
+				{Answer: "Answer 2", Voter: []string{"userID1"}},
+				{Answer: "Answer 3", Voter: []string{}},
+			},
+			Settings: poll.Settings{MaxVotes: 2, Anonymous: true},
+		}},
+		Settings: poll.Settings{MaxVotes: 1},
+	}
+}
+
 // GetPollTwoOptions returns a Poll with two Options, "Yes" and "No", no votes and no Poll Settings.
 func GetPollTwoOptions() *poll.Poll {
 	return &poll.Poll{
@@ -11,6 +11,8 @@ export const websocketHasVoted = (data) => async (dispatch) => {
             can_manage_poll: data.can_manage_poll,
             setting_progress: data.setting_progress,
             setting_public_add_option: data.setting_public_add_option,
+            setting_results_after_vote: data.setting_results_after_vote,
+            results: data.results,
         },
     });
 };
@@ -21,6 +21,14 @@ export default class ActionView extends React.PureComponent {
         this.props.actions.fetchPollMetadata(this.props.siteUrl, this.props.post.props.poll_id);
     }
 
+    componentDidUpdate(prevProps) {
+        // The progress of polls with '--results-after-vote' isn't part of the post, so refetch it when somebody votes
+        const metadata = (this.props.pollMetadata || {})[this.props.post.props.poll_id];
+        if (metadata?.setting_results_after_vote && prevProps.post.update_at !== this.props.post.update_at) {
+            this.props.actions.fetchPollMetadata(this.props.siteUrl, this.props.post.props.poll_id);
+        }
+    }
+
     /**
      * return true if the user has permission for adding option. if not, return false.
      * In details, return true in the following cases
@@ -50,8 +58,27 @@ export default class ActionView extends React.PureComponent {
         if (this.isAddOptionAction(action) || this.isPollManagementAction(action) || !metadata.voted_answers) {
             return false;
         }
-        const name = metadata.setting_progress ? action.name?.replace(/ \([0-9]+\)$/, '') : action.name;
-        return metadata.voted_answers?.indexOf(name) >= 0;
+        let name = metadata.setting_progress ? action.name?.replace(/ \([0-9]+\)$/, '') : action.name;
+        if (metadata.voted_answers.indexOf(name) < 0) {
+            // Options with a capacity show the remaining spots instead, e.g. "Slot A (3/5)"
+            name = action.name?.replace(/ \([0-9]+\/[0-9]+\)$/, '');
+        }
+        return metadata.voted_answers.indexOf(name) >= 0;
+    }
+
+    /**
+     * return the action with the progress appended to its name, if the progress is only shown to the user
+     * because of '--results-after-vote'. Otherwise the action is returned as it is.
+     * @param {object} action
+     * @param {object} metadata metadata for poll
+     * @return {object} action to display
+     */
+    withResults(action, metadata) {
+        const match = (/^vote([0-9]+)$/).exec(action.id);
+        if (!match || !metadata.results || typeof metadata.results[match[1]] === 'undefined') {
+            return action;
+        }
+        return {...action, name: `${action.name} (${metadata.results[match[1]]})`};
     }
 
     isAddOptionAction(action) {
@@ -59,7 +86,7 @@ export default class ActionView extends React.PureComponent {
     }
 
     isPollManagementAction(action) {
-        return action && (action.id === 'endPoll' || action.id === 'deletePoll');
+        return action && (action.id === 'manageOptions' || action.id === 'editPoll' || action.id === 'viewHistory' || action.id === 'rerunPoll' || action.id === 'endPoll' || action.id === 'deletePoll');
     }
 
     render() {
@@ -89,7 +116,7 @@ export default class ActionView extends React.PureComponent {
                 content.push(
                     <ActionButton
                         key={action.id}
-                        action={action}
+                        action={this.withResults(action, metadata)}
                         postId={this.props.post.id}
                         hasVoted={this.hasVoted(action, metadata)}
                     />,
@@ -3,6 +3,7 @@ import {shallow} from 'enzyme';
 
 import {ActionButtonType} from '@/utils/constants';
 
+import ActionButton from '@/components/post_type/action_view/action_button';
 import ActionView from '@/components/post_type/action_view/action_view';
 
 describe('components/post_type/action_view/ActionView', () => {
@@ -118,6 +119,79 @@ describe('components/post_type/action_view/ActionView', () => {
         const wrapper = shallow(<ActionView {...newProps}/>);
         expect(wrapper).toMatchSnapshot();
     });
+    test('should append the results to the options with setting_results_after_vote', () => {
+        const newProps = {
+            ...baseProps,
+            attachment: {
+                actions: [
+                    {id: 'vote0', name: 'answer1', type: ActionButtonType.BUTTON},
+                    {id: 'vote1', name: 'answer2', type: ActionButtonType.BUTTON},
+                    {id: 'resetVote', name: 'Reset Your Vote', type: ActionButtonType.BUTTON},
+                ],
+            },
+            pollMetadata: {
+                samplepollid1: {
+                    voted_answers: ['answer1'],
+                    poll_id: samplePollId,
+                    user_id: 'user_id1',
+                    can_manage_poll: false,
+                    setting_progress: false,
+                    setting_public_add_option: false,
+                    setting_results_after_vote: true,
+                    results: [1, 12],
+                },
+            },
+        };
+        const wrapper = shallow(<ActionView {...newProps}/>);
+        const buttons = wrapper.find(ActionButton);
+        expect(buttons.map((button) => button.prop('action').name)).toEqual(['answer1 (1)', 'answer2 (12)', 'Reset Your Vote']);
+        expect(buttons.map((button) => button.prop('hasVoted'))).toEqual([true, false, false]);
+    });
+    test('should mark voted options with a capacity', () => {
+        const newProps = {
+            ...baseProps,
+            attachment: {
+                actions: [
+                    {id: 'vote0', name: 'Slot A (3/5)', type: ActionButtonType.BUTTON},
+                    {id: 'vote1', name: 'Slot B (0/2)', type: ActionButtonType.BUTTON},
+                    {id: 'resetVote', name: 'Reset Your Vote', type: ActionButtonType.BUTTON},
+                ],
+            },
+            pollMetadata: {
+                samplepollid1: {
+                    voted_answers: ['Slot A'],
+                    poll_id: samplePollId,
+                    user_id: 'user_id1',
+                    can_manage_poll: false,
+                    setting_progress: false,
+                    setting_public_add_option: false,
+                },
+            },
+        };
+        const wrapper = shallow(<ActionView {...newProps}/>);
+        const buttons = wrapper.find(ActionButton);
+        expect(buttons.map((button) => button.prop('hasVoted'))).toEqual([true, false, false]);
+    });
+    test('should refetch the metadata with setting_results_after_vote when the post gets updated', () => {
+        const newProps = {
+            ...baseProps,
+            pollMetadata: {
+                samplepollid1: {
+                    poll_id: samplePollId,
+                    setting_results_after_vote: true,
+                },
+            },
+            actions: {
+                fetchPollMetadata: jest.fn(),
+            },
+        };
+        const wrapper = shallow(<ActionView {...newProps}/>);
+        expect(newProps.actions.fetchPollMetadata).toHaveBeenCalledTimes(1);
+
+        wrapper.setProps({post: {...newProps.post, update_at: 1}});
+        expect(newProps.actions.fetchPollMetadata).toHaveBeenCalledTimes(2);
+        expect(newProps.actions.fetchPollMetadata).toHaveBeenLastCalledWith('http://localhost:8065', samplePollId);
+    });
     test('should match snapshot without any actions', () => {
         const newProps = {
             ...baseProps,